@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/server"
+)
+
+// configFile is a tool configuration file, as read from disk.
+type configFile struct {
+	path      string
+	toolsFile ToolsFile
+	// raw is the contents of the file, with environment variables replaced.
+	raw map[string]any
+}
+
+// readConfigFiles reads the files at paths and the files they include. Each
+// file comes before the files it includes, and is read only once even if it's
+// included several times.
+func readConfigFiles(ctx context.Context, paths []string, seen map[string]bool) ([]configFile, error) {
+	var files []configFile
+	for _, p := range paths {
+		key := filepath.Clean(p)
+		if abs, err := filepath.Abs(p); err == nil {
+			key = abs
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		buf, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read tool file at %q: %w", p, err)
+		}
+		toolsFile, err := parseToolsFile(ctx, buf)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse tool file at %q: %w", p, err)
+		}
+		f := configFile{path: p, toolsFile: toolsFile}
+		if err := yaml.Unmarshal([]byte(parseEnv(string(buf))), &f.raw); err != nil {
+			return nil, fmt.Errorf("unable to parse tool file at %q: %w", p, err)
+		}
+		files = append(files, f)
+
+		included, err := resolveIncludes(p, toolsFile.Include)
+		if err != nil {
+			return nil, err
+		}
+		more, err := readConfigFiles(ctx, included, seen)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, more...)
+	}
+	return files, nil
+}
+
+// resolveIncludes returns the files matched by the include patterns of the
+// file at path. Relative patterns are relative to the directory of the file.
+func resolveIncludes(path string, patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include %q in %q: %w", pattern, path, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("include %q in %q matches no files", pattern, path)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// applyEnvironment merges the overlays of the given environment into the
+// configuration merged from files. The overlays of each file are applied in
+// order, so that one of a later file takes precedence. An overlay of a
+// resource is merged into its definition field by field, recursively, and
+// defines the resource if it doesn't exist yet.
+func applyEnvironment(ctx context.Context, merged *ToolsFile, files []configFile, environment string) error {
+	overlaid := make(map[string]any)
+	found := false
+	for _, f := range files {
+		overlay, ok := f.toolsFile.Environments[environment]
+		if !ok {
+			continue
+		}
+		found = true
+		for _, section := range slices.Sorted(maps.Keys(overlay)) {
+			value := overlay[section]
+			switch {
+			case section == "include" || section == "environments" || !slices.Contains(topLevelKeys, section):
+				return fmt.Errorf("environment %q in %q: unknown section %q", environment, f.path, section)
+			case section == "anonymousAccess":
+				base, ok := overlaid[section]
+				if !ok {
+					base = rawResource(files, section, "")
+				}
+				overlaid[section] = mergeRaw(base, value)
+			default:
+				resources, ok := value.(map[string]any)
+				if !ok {
+					return fmt.Errorf("environment %q in %q: %q must be a map of resources", environment, f.path, section)
+				}
+				current, _ := overlaid[section].(map[string]any)
+				if current == nil {
+					current = make(map[string]any)
+					overlaid[section] = current
+				}
+				for name, v := range resources {
+					base, ok := current[name]
+					if !ok {
+						base = rawResource(files, section, name)
+					}
+					current[name] = mergeRaw(base, v)
+				}
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("environment %q is not defined in the tool configuration", environment)
+	}
+
+	b, err := yaml.Marshal(overlaid)
+	if err != nil {
+		return fmt.Errorf("unable to apply environment %q: %w", environment, err)
+	}
+	var overlay ToolsFile
+	if err := yaml.UnmarshalContext(ctx, b, &overlay, yaml.Strict()); err != nil {
+		return fmt.Errorf("unable to apply environment %q: %w", environment, err)
+	}
+	maps.Copy(merged.Sources, overlay.Sources)
+	if len(overlay.AuthSources) > 0 {
+		if merged.AuthSources == nil {
+			merged.AuthSources = make(server.AuthServiceConfigs)
+		}
+		maps.Copy(merged.AuthSources, overlay.AuthSources)
+	}
+	maps.Copy(merged.AuthServices, overlay.AuthServices)
+	maps.Copy(merged.Tools, overlay.Tools)
+	maps.Copy(merged.Toolsets, overlay.Toolsets)
+	maps.Copy(merged.Policies, overlay.Policies)
+	maps.Copy(merged.Schedules, overlay.Schedules)
+	if overlay.AnonymousAccess != nil {
+		merged.AnonymousAccess = overlay.AnonymousAccess
+	}
+	return nil
+}
+
+// rawResource returns the definition of the named resource of a section as
+// written in files, or the whole section if name is empty.
+func rawResource(files []configFile, section, name string) any {
+	for _, f := range files {
+		value, ok := f.raw[section]
+		if !ok {
+			continue
+		}
+		if name == "" {
+			return value
+		}
+		if resources, ok := value.(map[string]any); ok {
+			if r, ok := resources[name]; ok {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// mergeRaw merges overlay into base. Maps are merged key by key, and any
+// other value of overlay replaces the one of base.
+func mergeRaw(base, overlay any) any {
+	b, ok := base.(map[string]any)
+	o, ook := overlay.(map[string]any)
+	if !ok || !ook {
+		return overlay
+	}
+	merged := maps.Clone(b)
+	for k, v := range o {
+		merged[k] = mergeRaw(b[k], v)
+	}
+	return merged
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		f := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(f), 0o700); err != nil {
+			t.Fatalf("unable to create directory: %s", err)
+		}
+		if err := os.WriteFile(f, testutils.FormatYaml(content), 0o600); err != nil {
+			t.Fatalf("unable to write file: %s", err)
+		}
+	}
+	return dir
+}
+
+func TestLoadIncludesAndEnvironments(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	dir := writeConfigFiles(t, map[string]string{
+		"tools.yaml": `
+			include:
+				- tools/*.yaml
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					database: db
+					user: u
+					password: p
+			environments:
+				prod:
+					sources:
+						my-pg:
+							host: prod-host
+							database: prod_db
+		`,
+		"tools/hotels.yaml": `
+			include:
+				- ../tools.yaml
+			tools:
+				search_hotels:
+					kind: postgres-sql
+					source: my-pg
+					description: Search hotels.
+					statement: SELECT * FROM hotels
+		`,
+		"tools/flights.yaml": `
+			tools:
+				search_flights:
+					kind: postgres-sql
+					source: my-pg
+					description: Search flights.
+					statement: SELECT * FROM flights
+		`,
+		"prod.yaml": `
+			environments:
+				prod:
+					sources:
+						my-pg:
+							database: overridden_db
+		`,
+	})
+	paths := []string{filepath.Join(dir, "tools.yaml"), filepath.Join(dir, "prod.yaml")}
+
+	toolsFile, loaded, err := loadAndMergeToolsFiles(ctx, paths, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var names []string
+	for _, f := range loaded {
+		names = append(names, strings.TrimPrefix(f, dir+string(filepath.Separator)))
+	}
+	// each file is loaded once, after the file including it
+	wantFiles := []string{"tools.yaml", filepath.Join("tools", "flights.yaml"), filepath.Join("tools", "hotels.yaml"), "prod.yaml"}
+	if !slices.Equal(names, wantFiles) {
+		t.Errorf("unexpected files loaded: got %q, want %q", names, wantFiles)
+	}
+	if _, ok := toolsFile.Tools["search_hotels"]; !ok {
+		t.Errorf("tool of an included file is missing")
+	}
+	if _, ok := toolsFile.Tools["search_flights"]; !ok {
+		t.Errorf("tool of an included file is missing")
+	}
+	if got := toolsFile.Sources["my-pg"].(postgres.Config); got.Host != "127.0.0.1" || got.Database != "db" {
+		t.Errorf("unexpected source without environment: %+v", got)
+	}
+
+	toolsFile, _, err = loadAndMergeToolsFiles(ctx, paths, "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got := toolsFile.Sources["my-pg"].(postgres.Config)
+	// fields not overlaid are kept, and later files take precedence
+	if got.Host != "prod-host" || got.Database != "overridden_db" || got.User != "u" || got.Name != "my-pg" {
+		t.Errorf("unexpected source in prod: %+v", got)
+	}
+
+	if _, _, err := loadAndMergeToolsFiles(ctx, paths, "staging"); err == nil || !strings.Contains(err.Error(), `environment "staging" is not defined`) {
+		t.Errorf("expected an error for an undefined environment, got %v", err)
+	}
+}
+
+func TestLoadIncludeErrors(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc  string
+		files map[string]string
+		want  string
+	}{
+		{
+			desc: "missing include",
+			files: map[string]string{"tools.yaml": `
+				include:
+					- missing.yaml
+			`},
+			want: "matches no files",
+		},
+		{
+			desc: "conflict with an included file",
+			files: map[string]string{
+				"tools.yaml": `
+					include:
+						- other.yaml
+					toolsets:
+						my-toolset: []
+				`,
+				"other.yaml": `
+					toolsets:
+						my-toolset: []
+				`,
+			},
+			want: "toolset 'my-toolset' (file #2)",
+		},
+		{
+			desc: "unknown section in environment",
+			files: map[string]string{"tools.yaml": `
+				environments:
+					prod:
+						include:
+							- other.yaml
+			`},
+			want: `unknown section "include"`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			dir := writeConfigFiles(t, tc.files)
+			_, _, err := loadAndMergeToolsFiles(ctx, []string{filepath.Join(dir, "tools.yaml")}, "prod")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -19,13 +19,11 @@ import (
 	_ "embed"
 	"fmt"
 	"io"
-	"maps"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"regexp"
 	"runtime"
-	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -54,13 +52,24 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/dataplex/dataplexsearchentries"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/dgraph"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/duckdbsql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/duckdbvectorsearch"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/elasticsearch/elasticsearchquery"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/exasol/exasolsql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/federatedsql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/firebolt/fireboltsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestoredeletedocuments"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestoregetdocuments"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestoregetrules"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestorelistcollections"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestorequerycollection"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/firestore/firestorevalidaterules"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/greenplum/greenplumlisttables"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/hive/hiveexecutesql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/hive/hivesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/http"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/kafka/kafkapublish"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/kafka/kafkatail"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/ldap/ldapsearch"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetdimensions"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetexplores"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookergetfilters"
@@ -71,6 +80,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerquery"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerquerysql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/looker/lookerrunlook"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mock/mocktool"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbaggregate"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbdeletemany"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbdeleteone"
@@ -80,6 +90,8 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbinsertone"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbupdatemany"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mongodb/mongodbupdateone"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mqtt/mqttlatest"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mqtt/mqttrecent"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mssql/mssqlexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mssql/mssqlsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlexecutesql"
@@ -87,13 +99,23 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/neo4j/neo4jcypher"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/neo4j/neo4jexecutecypher"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/neo4j/neo4jschema"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/nl2sql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/redis"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/redis/rediscommand"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/singlestore/singlestorelisttables"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/spanner/spannerexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/spanner/spannersql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/sqlitesql"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidblisttables"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/tidb/tidbsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/alloydbwaitforoperation"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/jobcancel"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/jobstatus"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/llmgenerate"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/queuestatus"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/usagestats"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/utility/wait"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/valkey"
 
@@ -109,17 +131,31 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/sources/dataplex"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/dgraph"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/duckdb"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/elasticsearch"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/exasol"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/firebolt"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/firestore"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/greenplum"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/hive"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/http"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/ldap"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/looker"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/mock"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mongodb"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/mqtt"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mssql"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/neo4j"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/neon"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/planetscale"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/singlestore"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/spanner"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/sqlite"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/supabase"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/tidb"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/valkey"
 )
 
@@ -168,6 +204,7 @@ type Command struct {
 	tools_files    []string
 	tools_folder   string
 	prebuiltConfig string
+	environment    string
 	inStream       io.Reader
 	outStream      io.Writer
 	errStream      io.Writer
@@ -206,6 +243,7 @@ func NewCommand(opts ...Option) *Command {
 	flags := cmd.Flags()
 	flags.StringVarP(&cmd.cfg.Address, "address", "a", "127.0.0.1", "Address of the interface the server will listen on.")
 	flags.IntVarP(&cmd.cfg.Port, "port", "p", 5000, "Port the server will listen on.")
+	flags.IntVar(&cmd.cfg.GrpcPort, "grpc-port", 0, "Port the gRPC server will listen on. 0 disables the gRPC server.")
 
 	flags.StringVar(&cmd.tools_file, "tools_file", "", "File path specifying the tool configuration. Cannot be used with --prebuilt.")
 	// deprecate tools_file
@@ -218,13 +256,23 @@ func NewCommand(opts ...Option) *Command {
 	flags.BoolVar(&cmd.cfg.TelemetryGCP, "telemetry-gcp", false, "Enable exporting directly to Google Cloud Monitoring.")
 	flags.StringVar(&cmd.cfg.TelemetryOTLP, "telemetry-otlp", "", "Enable exporting using OpenTelemetry Protocol (OTLP) to the specified endpoint (e.g. 'http://127.0.0.1:4318')")
 	flags.StringVar(&cmd.cfg.TelemetryServiceName, "telemetry-service-name", "toolbox", "Sets the value of the service.name resource attribute for telemetry data.")
+	flags.StringVar(&cmd.environment, "environment", "", "Name of the environment, e.g. 'prod', whose overlays under 'environments' in the tool configuration are merged into it. Cannot be used with --prebuilt.")
 	flags.StringVar(&cmd.prebuiltConfig, "prebuilt", "", "Use a prebuilt tool configuration by source type. Cannot be used with --tools-file. Allowed: 'alloydb-postgres-admin', alloydb-postgres', 'bigquery', 'cloud-sql-mysql', 'cloud-sql-postgres', 'cloud-sql-mssql', 'dataplex', 'firestore', 'mssql', 'mysql', 'postgres', 'spanner', 'spanner-postgres'.")
 	flags.BoolVar(&cmd.cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
+	flags.StringVar(&cmd.cfg.StdioToolset, "toolset", "", "Name of the toolset to serve via MCP STDIO, with the server name, version, and instructions it configures. Requires --stdio. Defaults to all tools.")
 	flags.BoolVar(&cmd.cfg.DisableReload, "disable-reload", false, "Disables dynamic reloading of tools file.")
+	flags.IntVar(&cmd.cfg.MaxConcurrentInvocations, "max-concurrent-invocations", 0, "Maximum number of tool invocations that run at once. Additional invocations are queued. 0 means no limit.")
+	flags.DurationVar(&cmd.cfg.StreamStallTimeout, "stream-stall-timeout", server.DefaultStreamStallTimeout, "How long a client may stop reading a streamed result before its invocation is cancelled. 0 disables the timeout.")
+	flags.DurationVar(&cmd.cfg.DrainTimeout, "drain-timeout", server.DefaultDrainTimeout, "How long in-flight invocations may run after a SIGTERM or SIGINT before they are cancelled and the sources are closed.")
+	flags.StringVar(&cmd.cfg.AdminToken, "admin-token", "", "Bearer token required by the admin API under /admin. Empty disables the admin API.")
+	flags.StringVar(&cmd.cfg.ApprovalWebhook, "approval-webhook", "", "URL notified of invocations of tools that require approval. Empty disables notifications.")
+	flags.DurationVar(&cmd.cfg.ApprovalTimeout, "approval-timeout", tools.DefaultApprovalTimeout, "How long invocations of tools that require approval wait for it before they are rejected.")
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
 
+	baseCmd.AddCommand(newValidateCommand(cmd))
+
 	return cmd
 }
 
@@ -234,6 +282,18 @@ type ToolsFile struct {
 	AuthServices server.AuthServiceConfigs `yaml:"authServices"`
 	Tools        server.ToolConfigs        `yaml:"tools"`
 	Toolsets     server.ToolsetConfigs     `yaml:"toolsets"`
+	Policies     server.PolicyConfigs      `yaml:"policies"`
+	Schedules    server.ScheduleConfigs    `yaml:"schedules"`
+
+	AnonymousAccess *server.AnonymousAccessConfig `yaml:"anonymousAccess"`
+
+	// Include lists other files, or glob patterns, loaded along with this one.
+	// Relative paths are relative to the directory of this file.
+	Include []string `yaml:"include"`
+	// Environments maps the name of an environment, e.g. "prod", to the
+	// overlay merged into the configuration when it's selected with
+	// --environment.
+	Environments map[string]map[string]any `yaml:"environments"`
 }
 
 // parseEnv replaces environment variables ${ENV_NAME} with their values.
@@ -278,6 +338,8 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 		AuthServices: make(server.AuthServiceConfigs),
 		Tools:        make(server.ToolConfigs),
 		Toolsets:     make(server.ToolsetConfigs),
+		Policies:     make(server.PolicyConfigs),
+		Schedules:    make(server.ScheduleConfigs),
 	}
 
 	var conflicts []string
@@ -297,6 +359,9 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 			if _, exists := merged.AuthSources[name]; exists {
 				conflicts = append(conflicts, fmt.Sprintf("authSource '%s' (file #%d)", name, fileIndex+1))
 			} else {
+				if merged.AuthSources == nil {
+					merged.AuthSources = make(server.AuthServiceConfigs)
+				}
 				merged.AuthSources[name] = authSource
 			}
 		}
@@ -327,6 +392,33 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 				merged.Toolsets[name] = toolset
 			}
 		}
+
+		// Check for conflicts and merge policies
+		for name, policy := range file.Policies {
+			if _, exists := merged.Policies[name]; exists {
+				conflicts = append(conflicts, fmt.Sprintf("policy '%s' (file #%d)", name, fileIndex+1))
+			} else {
+				merged.Policies[name] = policy
+			}
+		}
+
+		// Check for conflicts and merge schedules
+		for name, schedule := range file.Schedules {
+			if _, exists := merged.Schedules[name]; exists {
+				conflicts = append(conflicts, fmt.Sprintf("schedule '%s' (file #%d)", name, fileIndex+1))
+			} else {
+				merged.Schedules[name] = schedule
+			}
+		}
+
+		// anonymous access is server-wide, so only one file may configure it
+		if file.AnonymousAccess != nil {
+			if merged.AnonymousAccess != nil {
+				conflicts = append(conflicts, fmt.Sprintf("anonymousAccess (file #%d)", fileIndex+1))
+			} else {
+				merged.AnonymousAccess = file.AnonymousAccess
+			}
+		}
 	}
 
 	// If conflicts were detected, return an error
@@ -337,66 +429,68 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 	return merged, nil
 }
 
-// loadAndMergeToolsFiles loads multiple YAML files and merges them
-func loadAndMergeToolsFiles(ctx context.Context, filePaths []string) (ToolsFile, error) {
-	var toolsFiles []ToolsFile
-
-	for _, filePath := range filePaths {
-		buf, err := os.ReadFile(filePath)
-		if err != nil {
-			return ToolsFile{}, fmt.Errorf("unable to read tool file at %q: %w", filePath, err)
-		}
-
-		toolsFile, err := parseToolsFile(ctx, buf)
-		if err != nil {
-			return ToolsFile{}, fmt.Errorf("unable to parse tool file at %q: %w", filePath, err)
-		}
-
-		toolsFiles = append(toolsFiles, toolsFile)
+// loadAndMergeToolsFiles loads multiple YAML files, and the files they
+// include, and merges them. If environment is set, its overlays are merged
+// last. It also returns the paths of the files loaded.
+func loadAndMergeToolsFiles(ctx context.Context, filePaths []string, environment string) (ToolsFile, []string, error) {
+	files, err := readConfigFiles(ctx, filePaths, make(map[string]bool))
+	if err != nil {
+		return ToolsFile{}, nil, err
+	}
+	toolsFiles := make([]ToolsFile, 0, len(files))
+	loaded := make([]string, 0, len(files))
+	for _, f := range files {
+		toolsFiles = append(toolsFiles, f.toolsFile)
+		loaded = append(loaded, f.path)
 	}
 
 	mergedFile, err := mergeToolsFiles(toolsFiles...)
 	if err != nil {
-		return ToolsFile{}, fmt.Errorf("unable to merge tools files: %w", err)
+		return ToolsFile{}, nil, fmt.Errorf("unable to merge tools files: %w", err)
+	}
+	if environment != "" {
+		if err := applyEnvironment(ctx, &mergedFile, files, environment); err != nil {
+			return ToolsFile{}, nil, err
+		}
 	}
 
-	return mergedFile, nil
+	return mergedFile, loaded, nil
 }
 
 // loadAndMergeToolsFolder loads all YAML files from a directory and merges them
-func loadAndMergeToolsFolder(ctx context.Context, folderPath string) (ToolsFile, error) {
+func loadAndMergeToolsFolder(ctx context.Context, folderPath string, environment string) (ToolsFile, []string, error) {
 	// Check if directory exists
 	info, err := os.Stat(folderPath)
 	if err != nil {
-		return ToolsFile{}, fmt.Errorf("unable to access tools folder at %q: %w", folderPath, err)
+		return ToolsFile{}, nil, fmt.Errorf("unable to access tools folder at %q: %w", folderPath, err)
 	}
 	if !info.IsDir() {
-		return ToolsFile{}, fmt.Errorf("path %q is not a directory", folderPath)
+		return ToolsFile{}, nil, fmt.Errorf("path %q is not a directory", folderPath)
 	}
 
 	// Find all YAML files in the directory
 	pattern := filepath.Join(folderPath, "*.yaml")
 	yamlFiles, err := filepath.Glob(pattern)
 	if err != nil {
-		return ToolsFile{}, fmt.Errorf("error finding YAML files in %q: %w", folderPath, err)
+		return ToolsFile{}, nil, fmt.Errorf("error finding YAML files in %q: %w", folderPath, err)
 	}
 
 	// Also find .yml files
 	ymlPattern := filepath.Join(folderPath, "*.yml")
 	ymlFiles, err := filepath.Glob(ymlPattern)
 	if err != nil {
-		return ToolsFile{}, fmt.Errorf("error finding YML files in %q: %w", folderPath, err)
+		return ToolsFile{}, nil, fmt.Errorf("error finding YML files in %q: %w", folderPath, err)
 	}
 
 	// Combine both file lists
 	allFiles := append(yamlFiles, ymlFiles...)
 
 	if len(allFiles) == 0 {
-		return ToolsFile{}, fmt.Errorf("no YAML files found in directory %q", folderPath)
+		return ToolsFile{}, nil, fmt.Errorf("no YAML files found in directory %q", folderPath)
 	}
 
 	// Use existing loadAndMergeToolsFiles function
-	return loadAndMergeToolsFiles(ctx, allFiles)
+	return loadAndMergeToolsFiles(ctx, allFiles, environment)
 }
 
 func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Server) error {
@@ -405,21 +499,45 @@ func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Ser
 		panic(err)
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile)
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile, s.ResourceMgr)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to validate reloaded edits: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
 		return err
 	}
 
+	// approvals and the anonymous access tier are validated against the
+	// reloaded tools before any of them are served
+	prevSources := s.ResourceMgr.GetSourcesMap()
+	if err := s.CheckApprovals(toolsMap); err != nil {
+		logger.WarnContext(ctx, err.Error())
+		// the sources opened for the reload are never served
+		server.CloseNewSources(ctx, sourcesMap, prevSources)
+		return err
+	}
+	if err := s.SetAnonymousAccess(toolsFile.AnonymousAccess, toolsMap); err != nil {
+		logger.WarnContext(ctx, err.Error())
+		// the sources opened for the reload are never served
+		server.CloseNewSources(ctx, sourcesMap, prevSources)
+		return err
+	}
 	s.ResourceMgr.SetResources(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	s.ResourceMgr.SetSourceConfigs(toolsFile.Sources)
+	// sources that weren't reused are closed once the invocations that may
+	// still use them are done
+	go s.CloseReplacedSources(ctx, prevSources)
+	if err := s.SetSchedules(ctx, toolsFile.Schedules); err != nil {
+		logger.WarnContext(ctx, err.Error())
+		return err
+	}
 
 	return nil
 }
 
-// validateReloadEdits checks that the reloaded tools file configs can initialized without failing
+// validateReloadEdits checks that the reloaded tools file configs can initialized without failing.
+// Sources of prev whose config is unchanged are reused rather than reconnected.
 func validateReloadEdits(
-	ctx context.Context, toolsFile ToolsFile,
+	ctx context.Context, toolsFile ToolsFile, prev *server.ResourceManager,
 ) (map[string]sources.Source, map[string]auth.AuthService, map[string]tools.Tool, map[string]tools.Toolset, error,
 ) {
 	logger, err := util.LoggerFromContext(ctx)
@@ -443,9 +561,11 @@ func validateReloadEdits(
 		AuthServiceConfigs: toolsFile.AuthServices,
 		ToolConfigs:        toolsFile.Tools,
 		ToolsetConfigs:     toolsFile.Toolsets,
+		PolicyConfigs:      toolsFile.Policies,
+		ScheduleConfigs:    toolsFile.Schedules,
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := server.InitializeConfigs(ctx, reloadedConfig)
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := server.ReconcileConfigs(ctx, reloadedConfig, prev)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to initialize reloaded configs: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
@@ -455,8 +575,13 @@ func validateReloadEdits(
 	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
 }
 
-// watchChanges checks for changes in the provided yaml tools file(s) or folder.
-func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, s *server.Server) {
+// loadFunc loads the tool configuration, and returns the paths of the files
+// it was loaded from.
+type loadFunc func(ctx context.Context) (ToolsFile, []string, error)
+
+// watchChanges checks for changes in the provided yaml tools file(s) or folder,
+// and in the files they include, and reloads the configuration with load.
+func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles map[string]bool, load loadFunc, s *server.Server) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		panic(err)
@@ -498,6 +623,27 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 		logger.DebugContext(ctx, fmt.Sprintf("Added directory %s to watcher.", dir))
 	}
 
+	// watch the included files too, which may be anywhere
+	watchLoaded := func(loaded []string) {
+		for _, f := range loaded {
+			cleanFile := filepath.Clean(f)
+			watchedFiles[cleanFile] = true
+			dir := filepath.Dir(cleanFile)
+			if watchDirs[dir] {
+				continue
+			}
+			if err := w.Add(dir); err != nil {
+				logger.WarnContext(ctx, fmt.Sprintf("Error adding path %s to watcher: %s", dir, err))
+				continue
+			}
+			watchDirs[dir] = true
+			logger.DebugContext(ctx, fmt.Sprintf("Added directory %s to watcher.", dir))
+		}
+	}
+	if _, loaded, err := load(ctx); err == nil {
+		watchLoaded(loaded)
+	}
+
 	// debounce timer is used to prevent multiple writes triggering multiple reloads
 	debounceDelay := 100 * time.Millisecond
 	debounce := time.NewTimer(1 * time.Minute)
@@ -533,7 +679,7 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 			cleanedFilename := filepath.Clean(e.Name)
 			logger.DebugContext(ctx, fmt.Sprintf("%s event detected in %s", e.Op, cleanedFilename))
 
-			folderChanged := watchingFolder &&
+			folderChanged := watchingFolder && filepath.Dir(cleanedFilename) == folderToWatch &&
 				(strings.HasSuffix(cleanedFilename, ".yaml") || strings.HasSuffix(cleanedFilename, ".yml"))
 
 			if folderChanged || watchedFiles[cleanedFilename] {
@@ -543,27 +689,22 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 
 		case <-debounce.C:
 			debounce.Stop()
-			var reloadedToolsFile ToolsFile
 
 			if watchingFolder {
 				logger.DebugContext(ctx, "Reloading tools folder.")
-				reloadedToolsFile, err = loadAndMergeToolsFolder(ctx, folderToWatch)
-				if err != nil {
-					logger.WarnContext(ctx, "error loading tools folder %s", err)
-					continue
-				}
 			} else {
 				logger.DebugContext(ctx, "Reloading tools file(s).")
-				reloadedToolsFile, err = loadAndMergeToolsFiles(ctx, slices.Collect(maps.Keys(watchedFiles)))
-				if err != nil {
-					logger.WarnContext(ctx, "error loading tools files %s", err)
-					continue
-				}
 			}
+			reloadedToolsFile, loaded, err := load(ctx)
+			if err != nil {
+				logger.WarnContext(ctx, fmt.Sprintf("error loading tools file(s): %s", err))
+				continue
+			}
+			watchLoaded(loaded)
 
 			err = handleDynamicReload(ctx, reloadedToolsFile, s)
 			if err != nil {
-				errMsg := fmt.Errorf("unable to parse reloaded tools file at %q: %w", reloadedToolsFile, err)
+				errMsg := fmt.Errorf("unable to apply reloaded tools file(s): %w", err)
 				logger.WarnContext(ctx, errMsg.Error())
 				continue
 			}
@@ -613,6 +754,9 @@ func resolveWatcherInputs(toolsFile string, toolsFiles []string, toolsFolder str
 }
 
 func run(cmd *Command) error {
+	if cmd.cfg.StdioToolset != "" && !cmd.cfg.Stdio {
+		return fmt.Errorf("--toolset requires --stdio")
+	}
 	if updateLogLevel(cmd.cfg.Stdio, cmd.cfg.LogLevel.String()) {
 		cmd.cfg.LogLevel = server.StringLevel(log.Warn)
 	}
@@ -676,6 +820,7 @@ func run(cmd *Command) error {
 	}()
 
 	var toolsFile ToolsFile
+	var load loadFunc
 
 	if cmd.prebuiltConfig != "" {
 		// Make sure --prebuilt and --tools-file/--tools-files/--tools-folder flags are mutually exclusive
@@ -684,6 +829,11 @@ func run(cmd *Command) error {
 			cmd.logger.ErrorContext(ctx, errMsg.Error())
 			return errMsg
 		}
+		if cmd.environment != "" {
+			errMsg := fmt.Errorf("--environment cannot be used with --prebuilt")
+			cmd.logger.ErrorContext(ctx, errMsg.Error())
+			return errMsg
+		}
 		// Use prebuilt tools
 		buf, err := prebuiltconfigs.Get(cmd.prebuiltConfig)
 		if err != nil {
@@ -701,6 +851,8 @@ func run(cmd *Command) error {
 			cmd.logger.ErrorContext(ctx, errMsg.Error())
 			return errMsg
 		}
+		prebuilt := toolsFile
+		load = func(context.Context) (ToolsFile, []string, error) { return prebuilt, nil, nil }
 	} else if len(cmd.tools_files) > 0 {
 		// Make sure --tools-file, --tools-files, and --tools-folder flags are mutually exclusive
 		if cmd.tools_file != "" || cmd.tools_folder != "" {
@@ -711,8 +863,11 @@ func run(cmd *Command) error {
 
 		// Use multiple tools files
 		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging %d tool configuration files", len(cmd.tools_files)))
+		load = func(ctx context.Context) (ToolsFile, []string, error) {
+			return loadAndMergeToolsFiles(ctx, cmd.tools_files, cmd.environment)
+		}
 		var err error
-		toolsFile, err = loadAndMergeToolsFiles(ctx, cmd.tools_files)
+		toolsFile, _, err = load(ctx)
 		if err != nil {
 			cmd.logger.ErrorContext(ctx, err.Error())
 			return err
@@ -727,8 +882,11 @@ func run(cmd *Command) error {
 
 		// Use tools folder
 		cmd.logger.InfoContext(ctx, fmt.Sprintf("Loading and merging all YAML files from directory: %s", cmd.tools_folder))
+		load = func(ctx context.Context) (ToolsFile, []string, error) {
+			return loadAndMergeToolsFolder(ctx, cmd.tools_folder, cmd.environment)
+		}
 		var err error
-		toolsFile, err = loadAndMergeToolsFolder(ctx, cmd.tools_folder)
+		toolsFile, _, err = load(ctx)
 		if err != nil {
 			cmd.logger.ErrorContext(ctx, err.Error())
 			return err
@@ -739,23 +897,22 @@ func run(cmd *Command) error {
 			cmd.tools_file = "tools.yaml"
 		}
 
-		// Read single tool file contents
-		buf, err := os.ReadFile(cmd.tools_file)
-		if err != nil {
-			errMsg := fmt.Errorf("unable to read tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
+		// Read single tool file contents, and the files it includes
+		load = func(ctx context.Context) (ToolsFile, []string, error) {
+			return loadAndMergeToolsFiles(ctx, []string{cmd.tools_file}, cmd.environment)
 		}
-
-		toolsFile, err = parseToolsFile(ctx, buf)
+		var err error
+		toolsFile, _, err = load(ctx)
 		if err != nil {
-			errMsg := fmt.Errorf("unable to parse tool file at %q: %w", cmd.tools_file, err)
-			cmd.logger.ErrorContext(ctx, errMsg.Error())
-			return errMsg
+			cmd.logger.ErrorContext(ctx, err.Error())
+			return err
 		}
 	}
 
 	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
+	cmd.cfg.PolicyConfigs = toolsFile.Policies
+	cmd.cfg.ScheduleConfigs = toolsFile.Schedules
+	cmd.cfg.AnonymousAccess = toolsFile.AnonymousAccess
 	authSourceConfigs := toolsFile.AuthSources
 	if authSourceConfigs != nil {
 		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
@@ -811,7 +968,7 @@ func run(cmd *Command) error {
 
 	if !cmd.cfg.DisableReload {
 		// start watching the file(s) or folder for changes to trigger dynamic reloading
-		go watchChanges(ctx, watchDirs, watchedFiles, s)
+		go watchChanges(ctx, watchDirs, watchedFiles, load, s)
 	}
 
 	// wait for either the server to error out or the command's context to be canceled
@@ -823,7 +980,7 @@ func run(cmd *Command) error {
 			return errMsg
 		}
 	case <-ctx.Done():
-		shutdownContext, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		shutdownContext, cancel := context.WithTimeout(context.Background(), cmd.cfg.DrainTimeout)
 		defer cancel()
 		cmd.logger.WarnContext(shutdownContext, "Shutting down gracefully...")
 		err := s.Shutdown(shutdownContext)
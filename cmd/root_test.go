@@ -60,6 +60,15 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.TelemetryServiceName == "" {
 		c.TelemetryServiceName = "toolbox"
 	}
+	if c.StreamStallTimeout == 0 {
+		c.StreamStallTimeout = server.DefaultStreamStallTimeout
+	}
+	if c.DrainTimeout == 0 {
+		c.DrainTimeout = server.DefaultDrainTimeout
+	}
+	if c.ApprovalTimeout == 0 {
+		c.ApprovalTimeout = tools.DefaultApprovalTimeout
+	}
 	return c
 }
 
@@ -184,6 +193,14 @@ func TestServerConfigFlags(t *testing.T) {
 				Stdio: true,
 			}),
 		},
+		{
+			desc: "stdio toolset",
+			args: []string{"--stdio", "--toolset", "analyst"},
+			want: withDefaults(server.ServerConfig{
+				Stdio:        true,
+				StdioToolset: "analyst",
+			}),
+		},
 		{
 			desc: "disable reload",
 			args: []string{"--disable-reload"},
@@ -191,6 +208,42 @@ func TestServerConfigFlags(t *testing.T) {
 				DisableReload: true,
 			}),
 		},
+		{
+			desc: "grpc port",
+			args: []string{"--grpc-port", "5001"},
+			want: withDefaults(server.ServerConfig{
+				GrpcPort: 5001,
+			}),
+		},
+		{
+			desc: "max concurrent invocations",
+			args: []string{"--max-concurrent-invocations", "4"},
+			want: withDefaults(server.ServerConfig{
+				MaxConcurrentInvocations: 4,
+			}),
+		},
+		{
+			desc: "stream stall timeout",
+			args: []string{"--stream-stall-timeout", "5s"},
+			want: withDefaults(server.ServerConfig{
+				StreamStallTimeout: 5 * time.Second,
+			}),
+		},
+		{
+			desc: "drain timeout",
+			args: []string{"--drain-timeout", "30s"},
+			want: withDefaults(server.ServerConfig{
+				DrainTimeout: 30 * time.Second,
+			}),
+		},
+		{
+			desc: "approval webhook",
+			args: []string{"--approval-webhook", "https://example.com/approvals", "--approval-timeout", "1h"},
+			want: withDefaults(server.ServerConfig{
+				ApprovalWebhook: "https://example.com/approvals",
+				ApprovalTimeout: time.Hour,
+			}),
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -365,6 +418,19 @@ func TestFailServerConfigFlags(t *testing.T) {
 	}
 }
 
+func TestToolsetFlagRequiresStdio(t *testing.T) {
+	c := NewCommand()
+	c.SetArgs([]string{"--toolset", "analyst"})
+	c.SilenceUsage = true
+	c.SilenceErrors = true
+	c.SetOut(new(bytes.Buffer))
+	c.SetErr(new(bytes.Buffer))
+	err := c.Execute()
+	if err == nil || err.Error() != "--toolset requires --stdio" {
+		t.Fatalf("expected --toolset to require --stdio, got %v", err)
+	}
+}
+
 func TestDefaultLoggingFormat(t *testing.T) {
 	c, _, err := invokeCommand([]string{})
 	if err != nil {
@@ -461,6 +527,440 @@ func TestParseToolFile(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "tool with retry policy",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					retry:
+						maxAttempts: 4
+						initialBackoff: 50ms
+						retryOn:
+							- deadlock
+							- serializationFailure
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.RetryToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+						Retry: tools.RetryPolicy{
+							MaxAttempts:    4,
+							InitialBackoff: "50ms",
+							RetryOn:        []string{"deadlock", "serializationFailure"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "tool with binary handling",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					binary:
+						maxInlineBytes: 1024
+						artifactStore:
+							kind: gcs
+							bucket: my-bucket
+							prefix: blobs/
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.BinaryToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+						Binary: tools.BinaryHandling{
+							MaxInlineBytes: 1024,
+							ArtifactStore: &tools.ArtifactStoreConfig{
+								Kind:   "gcs",
+								Bucket: "my-bucket",
+								Prefix: "blobs/",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "tool with export",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					export:
+						destination: gs://my-bucket/reports
+						formats:
+							- parquet
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.ExportToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+						Export: tools.ExportConfig{
+							Destination: "gs://my-bucket/reports",
+							Formats:     []string{"parquet"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "tool with journal",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					journal:
+						path: /var/lib/toolbox/journal.jsonl
+						idempotencyKeyTtl: 1h
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.JournalToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+						Journal: tools.JournalConfig{
+							Path:              "/var/lib/toolbox/journal.jsonl",
+							IdempotencyKeyTTL: "1h",
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "tool with policies",
+			in: `
+			policies:
+				same_tenant:
+					condition: claims.google.tenant == params.tenant_id
+					predicate:
+						sql: tenant_id = :tenant
+						bind:
+							tenant: claims.google.tenant
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					policies:
+						- same_tenant
+			`,
+			wantToolsFile: ToolsFile{
+				Policies: server.PolicyConfigs{
+					"same_tenant": tools.PolicyConfig{
+						Condition: "claims.google.tenant == params.tenant_id",
+						Predicate: &tools.PredicateConfig{
+							SQL:  "tenant_id = :tenant",
+							Bind: map[string]string{"tenant": "claims.google.tenant"},
+						},
+					},
+				},
+				Tools: server.ToolConfigs{
+					"example_tool": tools.PolicyToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+						Names:               []string{"same_tenant"},
+						PredicatesSupported: true,
+						Statement:           "SELECT * FROM SQL_STATEMENT;\n",
+					},
+				},
+			},
+		},
+		{
+			description: "schedules",
+			in: `
+			schedules:
+				nightly_summary:
+					tool: summarize_orders
+					cron: 0 3 * * *
+					timeout: 10m
+					params:
+						region: emea
+					sink:
+						kind: webhook
+						url: https://example.com/hooks/summary
+						headers:
+							Authorization: Bearer token
+			`,
+			wantToolsFile: ToolsFile{
+				Schedules: server.ScheduleConfigs{
+					"nightly_summary": tools.ScheduleConfig{
+						Tool:    "summarize_orders",
+						Cron:    "0 3 * * *",
+						Timeout: "10m",
+						Params:  map[string]any{"region": "emea"},
+						Sink: &tools.SinkConfig{
+							Kind:    tools.SinkWebhook,
+							URL:     "https://example.com/hooks/summary",
+							Headers: map[string]string{"Authorization": "Bearer token"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "tool with response budget",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					responseBudget:
+						maxTokens: 2000
+						reducer: head
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.BudgetToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+						Budget: tools.ResponseBudget{
+							MaxTokens: 2000,
+							Reducer:   "head",
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "async tool",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					async: true
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.AsyncToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "tool requiring approval",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						DELETE FROM orders WHERE id = $1;
+					requiresApproval: true
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.ApprovalToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "DELETE FROM orders WHERE id = $1;\n",
+							AuthRequired: []string{},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "tool with query tags",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					queryTags:
+						team: finance
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.QueryTagsToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{},
+						},
+						Tags: map[string]string{"team": "finance"},
+					},
+				},
+			},
+		},
+		{
+			description: "tool with dictionary",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM orders;
+					dictionary:
+						- orders
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": tools.DictionaryToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM orders;\n",
+							AuthRequired: []string{},
+						},
+						Source: "my-pg-instance",
+						Tables: []string{"orders"},
+					},
+				},
+			},
+		},
+		{
+			description: "toolset with server info",
+			in: `
+			toolsets:
+				analyst:
+					tools:
+						- example_tool
+					server:
+						name: analytics
+						version: 2.1.0
+						instructions: Answers questions about sales.
+				short:
+					- example_tool
+			`,
+			wantToolsFile: ToolsFile{
+				Toolsets: server.ToolsetConfigs{
+					"analyst": tools.ToolsetConfig{
+						Name:      "analyst",
+						ToolNames: []string{"example_tool"},
+						Server: tools.ServerInfo{
+							Name:         "analytics",
+							Version:      "2.1.0",
+							Instructions: "Answers questions about sales.",
+						},
+					},
+					"short": tools.ToolsetConfig{
+						Name:      "short",
+						ToolNames: []string{"example_tool"},
+					},
+				},
+			},
+		},
+		{
+			description: "anonymous access",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+			anonymousAccess:
+				tools:
+					- example_tool
+				rateLimit:
+					requestsPerMinute: 30
+			`,
+			wantToolsFile: ToolsFile{
+				Tools: server.ToolConfigs{
+					"example_tool": postgressql.Config{
+						Name:         "example_tool",
+						Kind:         "postgres-sql",
+						Source:       "my-pg-instance",
+						Description:  "some description",
+						Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+						AuthRequired: []string{},
+					},
+				},
+				AnonymousAccess: &server.AnonymousAccessConfig{
+					Tools:     []string{"example_tool"},
+					RateLimit: server.AnonymousRateLimit{RequestsPerMinute: 30},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -480,6 +980,9 @@ func TestParseToolFile(t *testing.T) {
 			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets); diff != "" {
 				t.Fatalf("incorrect tools parse: diff %v", diff)
 			}
+			if diff := cmp.Diff(tc.wantToolsFile.AnonymousAccess, toolsFile.AnonymousAccess); diff != "" {
+				t.Fatalf("incorrect anonymousAccess parse: diff %v", diff)
+			}
 		})
 	}
 
@@ -1132,7 +1635,10 @@ func TestSingleEdit(t *testing.T) {
 	watchedFiles := map[string]bool{cleanFileToWatch: true}
 	watchDirs := map[string]bool{watchDir: true}
 
-	go watchChanges(ctx, watchDirs, watchedFiles, mockServer)
+	load := func(context.Context) (ToolsFile, []string, error) {
+		return ToolsFile{}, nil, fmt.Errorf("not a tools file")
+	}
+	go watchChanges(ctx, watchDirs, watchedFiles, load, mockServer)
 
 	// escape backslash so regex doesn't fail on windows filepaths
 	regexEscapedPathFile := strings.ReplaceAll(cleanFileToWatch, `\`, `\\\\*\\`)
@@ -0,0 +1,596 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// problem is an error found in a configuration file.
+type problem struct {
+	file string
+	line int
+	msg  string
+}
+
+func (p problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.file, p.line, p.msg)
+}
+
+// entry is a named resource of a configuration file, such as a tool.
+type entry struct {
+	file string
+	key  ast.Node
+	node ast.Node
+	raw  map[string]any
+}
+
+func (e entry) line() int {
+	return e.key.GetToken().Position.Line
+}
+
+// validator collects the resources of configuration files and the problems
+// found in them.
+type validator struct {
+	ctx      context.Context
+	problems []problem
+
+	sources      map[string]entry
+	authServices map[string]entry
+	tools        map[string]entry
+	toolsets     map[string]entry
+	schedules    map[string]entry
+}
+
+func newValidateCommand(cmd *Command) *cobra.Command {
+	var toolsFile, toolsFolder string
+	var toolsFiles []string
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate tool configuration files without starting the server",
+		Long: "Validate parses tool configuration files and reports every problem found, " +
+			"with its line number: invalid or unknown kinds, missing sources and auth services, " +
+			"colliding parameter names, and template placeholders without a templateParameter. " +
+			"It doesn't connect to sources.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, _ []string) error {
+			files, err := configFiles(toolsFile, toolsFiles, toolsFolder)
+			if err != nil {
+				return err
+			}
+			logger, err := log.NewStdLogger(cmd.outStream, cmd.errStream, "WARN")
+			if err != nil {
+				return fmt.Errorf("unable to initialize logger: %w", err)
+			}
+			problems := validateFiles(util.WithLogger(c.Context(), logger), files)
+			out := c.OutOrStdout()
+			for _, p := range problems {
+				fmt.Fprintln(out, p)
+			}
+			if len(problems) > 0 {
+				noun := "problems"
+				if len(problems) == 1 {
+					noun = "problem"
+				}
+				err := fmt.Errorf("found %d %s in the tool configuration", len(problems), noun)
+				fmt.Fprintln(out, err)
+				return err
+			}
+			fmt.Fprintln(out, "The tool configuration is valid.")
+			return nil
+		},
+	}
+	flags := validateCmd.Flags()
+	flags.StringVar(&toolsFile, "tools-file", "", "File path specifying the tool configuration. Cannot be used with --tools-files, or --tools-folder.")
+	flags.StringSliceVar(&toolsFiles, "tools-files", []string{}, "Multiple file paths specifying tool configurations, validated as if merged. Cannot be used with --tools-file, or --tools-folder.")
+	flags.StringVar(&toolsFolder, "tools-folder", "", "Directory path containing YAML tool configuration files. Cannot be used with --tools-file, or --tools-files.")
+	return validateCmd
+}
+
+// configFiles returns the files selected by the flags, like the flags of the
+// server.
+func configFiles(toolsFile string, toolsFiles []string, toolsFolder string) ([]string, error) {
+	set := 0
+	for _, s := range []bool{toolsFile != "", len(toolsFiles) > 0, toolsFolder != ""} {
+		if s {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("--tools-file, --tools-files, and --tools-folder flags cannot be used simultaneously")
+	}
+	switch {
+	case len(toolsFiles) > 0:
+		return toolsFiles, nil
+	case toolsFolder != "":
+		var files []string
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(toolsFolder, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("error finding YAML files in %q: %w", toolsFolder, err)
+			}
+			files = append(files, matches...)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no YAML files found in directory %q", toolsFolder)
+		}
+		return files, nil
+	case toolsFile != "":
+		return []string{toolsFile}, nil
+	default:
+		return []string{"tools.yaml"}, nil
+	}
+}
+
+// validateFiles validates the configuration merged from files, and returns
+// the problems found sorted by file and line.
+func validateFiles(ctx context.Context, files []string) []problem {
+	v := &validator{
+		ctx:          ctx,
+		sources:      make(map[string]entry),
+		authServices: make(map[string]entry),
+		tools:        make(map[string]entry),
+		toolsets:     make(map[string]entry),
+		schedules:    make(map[string]entry),
+	}
+	files = slices.Clone(files)
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		seen[filepath.Clean(f)] = true
+	}
+	// files grows with the files included by the ones validated
+	for i := 0; i < len(files); i++ {
+		f := files[i]
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			v.problems = append(v.problems, problem{file: f, msg: fmt.Sprintf("unable to read file: %s", err)})
+			continue
+		}
+		v.addFile(f, raw)
+
+		var includes struct {
+			Include []string `yaml:"include"`
+		}
+		if err := yaml.Unmarshal([]byte(parseEnv(string(raw))), &includes); err != nil {
+			continue
+		}
+		included, err := resolveIncludes(f, includes.Include)
+		if err != nil {
+			v.problems = append(v.problems, problem{file: f, msg: err.Error()})
+			continue
+		}
+		for _, inc := range included {
+			if !seen[filepath.Clean(inc)] {
+				seen[filepath.Clean(inc)] = true
+				files = append(files, inc)
+			}
+		}
+	}
+	v.checkReferences()
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		a, b := v.problems[i], v.problems[j]
+		if a.file != b.file {
+			return slices.Index(files, a.file) < slices.Index(files, b.file)
+		}
+		return a.line < b.line
+	})
+	return v.problems
+}
+
+func (v *validator) report(file string, node ast.Node, format string, args ...any) {
+	line := 0
+	if node != nil {
+		line = node.GetToken().Position.Line
+	}
+	v.problems = append(v.problems, problem{file: file, line: line, msg: fmt.Sprintf(format, args...)})
+}
+
+// topLevelKeys are the sections of a configuration file.
+var topLevelKeys = yamlKeys(reflect.TypeOf(ToolsFile{}))
+
+func yamlKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if k, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// addFile decodes the resources of a configuration file one at a time, so
+// that a problem in one doesn't hide the problems in the others.
+func (v *validator) addFile(file string, raw []byte) {
+	raw = []byte(parseEnv(string(raw)))
+	f, err := parser.ParseBytes(raw, 0)
+	if err != nil {
+		v.problems = append(v.problems, problem{file: file, line: errorLine(err), msg: firstLine(err)})
+		return
+	}
+	for _, doc := range f.Docs {
+		for _, section := range pairs(doc.Body) {
+			name := keyString(section.Key)
+			switch name {
+			case "sources":
+				v.addEntries(file, "source", "sources", section.Value, v.sources)
+			case "authServices", "authSources":
+				v.addEntries(file, "auth service", "authServices", section.Value, v.authServices)
+			case "tools":
+				v.addEntries(file, "tool", "tools", section.Value, v.tools)
+			case "toolsets":
+				v.addEntries(file, "toolset", "toolsets", section.Value, v.toolsets)
+			case "schedules":
+				v.addEntries(file, "schedule", "schedules", section.Value, v.schedules)
+			default:
+				if !slices.Contains(topLevelKeys, name) {
+					v.report(file, section.Key, "unknown section %q, must be one of %q", name, topLevelKeys)
+					continue
+				}
+				var value any
+				if err := yaml.NodeToValue(section.Value, &value); err != nil {
+					v.report(file, section.Key, "%s", firstLine(err))
+					continue
+				}
+				v.decode(file, section.Key, map[string]any{name: value}, name)
+			}
+		}
+	}
+}
+
+// addEntries decodes each resource of a section on its own, records it in
+// entries, and reports the names defined more than once.
+func (v *validator) addEntries(file, what, sectionKey string, section ast.Node, entries map[string]entry) {
+	for _, p := range pairs(section) {
+		name := keyString(p.Key)
+		var value any
+		if err := yaml.NodeToValue(p.Value, &value); err != nil {
+			v.report(file, p.Key, "%s %q: %s", what, name, firstLine(err))
+			continue
+		}
+		e := entry{file: file, key: p.Key, node: p.Value}
+		e.raw, _ = value.(map[string]any)
+		if prev, ok := entries[name]; ok {
+			v.report(file, p.Key, "%s %q is already defined at %s:%d", what, name, prev.file, prev.line())
+			continue
+		}
+		entries[name] = e
+		if msg := v.decode(file, nil, map[string]any{sectionKey: map[string]any{name: value}}, ""); msg != "" {
+			v.report(file, problemNode(p, msg), "%s %q: %s", what, name, msg)
+		}
+	}
+}
+
+// decode decodes doc as a configuration file. It reports the problem at
+// node and returns "" if node is set, and returns the problem otherwise.
+func (v *validator) decode(file string, node ast.Node, doc map[string]any, section string) string {
+	b, err := yaml.Marshal(doc)
+	if err == nil {
+		var tf ToolsFile
+		err = yaml.UnmarshalContext(v.ctx, b, &tf, yaml.Strict())
+	}
+	if err == nil {
+		return ""
+	}
+	msg := firstLine(err)
+	if node != nil {
+		v.report(file, node, "%s: %s", section, msg)
+		return ""
+	}
+	return msg
+}
+
+var unknownFieldRe = regexp.MustCompile(`unknown field "(\w+)"`)
+
+// problemNode returns the node of an entry that msg is about: the field it
+// names, the kind if unknown, and the entry itself otherwise.
+func problemNode(p *ast.MappingValueNode, msg string) ast.Node {
+	if m := unknownFieldRe.FindStringSubmatch(msg); m != nil {
+		if n := field(p.Value, m[1]); n != nil {
+			return n
+		}
+	}
+	if strings.Contains(msg, "unknown") && strings.Contains(msg, "kind") {
+		if n := field(p.Value, "kind"); n != nil {
+			return n
+		}
+	}
+	return p.Key
+}
+
+// checkReferences reports the references to resources that don't exist, and
+// the problems with the parameters of tools.
+func (v *validator) checkReferences() {
+	for _, name := range sortedNames(v.tools) {
+		e := v.tools[name]
+		if src, ok := e.raw["source"].(string); ok {
+			if _, ok := v.sources[src]; !ok {
+				v.report(e.file, field(e.node, "source"), "tool %q: source %q does not exist", name, src)
+			}
+		}
+		for _, a := range stringList(e.raw["authRequired"]) {
+			if _, ok := v.authServices[a]; !ok {
+				v.report(e.file, field(e.node, "authRequired"), "tool %q: auth service %q does not exist", name, a)
+			}
+		}
+		v.checkParameters(name, e)
+	}
+	for _, name := range sortedNames(v.toolsets) {
+		e := v.toolsets[name]
+		list := e.node
+		if n := fieldValue(e.node, "tools"); n != nil {
+			list = n
+		}
+		seq, ok := list.(*ast.SequenceNode)
+		if !ok {
+			continue
+		}
+		for _, item := range seq.Values {
+			var t string
+			if err := yaml.NodeToValue(item, &t); err != nil {
+				continue
+			}
+			if _, ok := v.tools[t]; !ok {
+				v.report(e.file, item, "toolset %q: tool %q does not exist", name, t)
+			}
+		}
+	}
+	for _, name := range sortedNames(v.schedules) {
+		e := v.schedules[name]
+		if t, ok := e.raw["tool"].(string); ok {
+			if _, ok := v.tools[t]; !ok {
+				v.report(e.file, field(e.node, "tool"), "schedule %q: tool %q does not exist", name, t)
+			}
+		}
+		sink, _ := e.raw["sink"].(map[string]any)
+		if t, ok := sink["tool"].(string); ok {
+			if _, ok := v.tools[t]; !ok {
+				v.report(e.file, field(fieldValue(e.node, "sink"), "tool"), "schedule %q: sink tool %q does not exist", name, t)
+			}
+		}
+	}
+}
+
+// namedPlaceholderKinds are the tool kinds that bind named placeholders, e.g.
+// ":id", in their statements.
+var namedPlaceholderKinds = map[string]bool{
+	"bigquery-sql": true,
+	"duckdb-sql":   true,
+	"exasol-sql":   true,
+	"firebolt-sql": true,
+	"hive-sql":     true,
+	"mssql-sql":    true,
+	"mysql-sql":    true,
+	"postgres-sql": true,
+	"spanner-sql":  true,
+	"sqlite-sql":   true,
+	"tidb-sql":     true,
+}
+
+// checkParameters reports the parameters of a tool sharing a name, the auth
+// services of parameters that don't exist, and the placeholders of its
+// statement without a matching parameter or template parameter.
+func (v *validator) checkParameters(name string, e entry) {
+	seen := make(map[string]bool)
+	templateNames := make(map[string]bool)
+	for _, list := range []string{"parameters", "templateParameters"} {
+		seq, ok := fieldValue(e.node, list).(*ast.SequenceNode)
+		if !ok {
+			continue
+		}
+		for _, item := range seq.Values {
+			var p struct {
+				Name         string `yaml:"name"`
+				AuthServices []struct {
+					Name string `yaml:"name"`
+				} `yaml:"authServices"`
+			}
+			if err := yaml.NodeToValue(item, &p); err != nil || p.Name == "" {
+				continue
+			}
+			if seen[p.Name] {
+				v.report(e.file, field(item, "name"), "tool %q: parameter name %q is used more than once", name, p.Name)
+			}
+			seen[p.Name] = true
+			if list == "templateParameters" {
+				templateNames[p.Name] = true
+			}
+			for _, a := range p.AuthServices {
+				if _, ok := v.authServices[a.Name]; !ok {
+					v.report(e.file, field(item, "authServices"), "tool %q: auth service %q of parameter %q does not exist", name, a.Name, p.Name)
+				}
+			}
+		}
+	}
+
+	statement, ok := e.raw["statement"].(string)
+	if !ok {
+		return
+	}
+	if kind, _ := e.raw["kind"].(string); namedPlaceholderKinds[kind] {
+		var missing []string
+		for _, n := range tools.NamedPlaceholders(statement) {
+			if (!seen[n] || templateNames[n]) && !slices.Contains(missing, n) {
+				missing = append(missing, n)
+			}
+		}
+		for _, n := range missing {
+			v.report(e.file, field(e.node, "statement"), "tool %q: named placeholder :%s has no matching parameter", name, n)
+		}
+	}
+	if !strings.Contains(statement, "{{") {
+		return
+	}
+	t, err := template.New("statement").Funcs(template.FuncMap{"array": tools.ConvertArrayParamToString}).Parse(statement)
+	if err != nil {
+		v.report(e.file, field(e.node, "statement"), "tool %q: invalid statement template: %s", name, err)
+		return
+	}
+	var missing []string
+	for _, f := range templateFields(t.Root) {
+		if !templateNames[f] && !slices.Contains(missing, f) {
+			missing = append(missing, f)
+		}
+	}
+	for _, f := range missing {
+		v.report(e.file, field(e.node, "statement"), "tool %q: statement placeholder {{.%s}} has no matching templateParameter", name, f)
+	}
+}
+
+// templateFields returns the names of the fields of the data referenced in a
+// template, e.g. "table" for {{.table}}.
+func templateFields(node parse.Node) []string {
+	var out []string
+	var walk func(parse.Node)
+	walkPipe := func(p *parse.PipeNode) {
+		if p == nil {
+			return
+		}
+		for _, c := range p.Cmds {
+			for _, a := range c.Args {
+				walk(a)
+			}
+		}
+	}
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *parse.ActionNode:
+			walkPipe(n.Pipe)
+		case *parse.PipeNode:
+			walkPipe(n)
+		case *parse.FieldNode:
+			out = append(out, n.Ident[0])
+		case *parse.IfNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		}
+	}
+	walk(node)
+	return out
+}
+
+func pairs(node ast.Node) []*ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	default:
+		return nil
+	}
+}
+
+// field returns the key node of name in a mapping, to report problems with
+// its value at, or nil.
+func field(node ast.Node, name string) ast.Node {
+	for _, p := range pairs(node) {
+		if keyString(p.Key) == name {
+			return p.Key
+		}
+	}
+	return nil
+}
+
+// fieldValue returns the value node of name in a mapping, or nil.
+func fieldValue(node ast.Node, name string) ast.Node {
+	for _, p := range pairs(node) {
+		if keyString(p.Key) == name {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+func keyString(k ast.MapKeyNode) string {
+	if s, ok := k.(*ast.StringNode); ok {
+		return s.Value
+	}
+	return k.GetToken().Value
+}
+
+func stringList(v any) []string {
+	l, _ := v.([]any)
+	var out []string
+	for _, e := range l {
+		if s, ok := e.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func sortedNames(m map[string]entry) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var positionRe = regexp.MustCompile(`\[(\d+):\d+\]\s*`)
+
+// firstLine returns the first line of the message of err, without the
+// positions of YAML errors, which are relative to the decoded resource.
+func firstLine(err error) string {
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+	return strings.TrimSpace(positionRe.ReplaceAllString(msg, ""))
+}
+
+// errorLine returns the line of a YAML syntax error, or 0.
+func errorLine(err error) int {
+	m := positionRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(m[1])
+	return line
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestValidateFiles(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc  string
+		files []string
+		// validate is the number of files validated, if not all of them
+		validate int
+		want     []string
+	}{
+		{
+			desc: "valid",
+			files: []string{`
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					database: db
+					user: u
+					password: p
+			authServices:
+				my-google:
+					kind: google
+					clientId: abc
+			tools:
+				search:
+					kind: postgres-sql
+					source: my-pg
+					description: d
+					statement: SELECT * FROM {{.table}} WHERE a = $1
+					authRequired: [my-google]
+					parameters:
+						- name: a
+						  type: string
+						  description: a
+					templateParameters:
+						- name: table
+						  type: string
+						  description: t
+			toolsets:
+				ts:
+					- search
+			`},
+		},
+		{
+			desc: "every problem reported",
+			files: []string{`
+			sources:
+				bad-src:
+					kind: nosuchdb
+				typo-src:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					database: db
+					user: u
+					password: p
+					pasword: p
+			tools:
+				search:
+					kind: postgres-sql
+					source: typo-src
+					description: d
+					statement: SELECT * FROM {{.table}} WHERE a = $1
+					authRequired: [my-google]
+					parameters:
+						- name: a
+						  type: string
+						  description: a
+						- name: a
+						  type: string
+						  description: again
+				orphan:
+					kind: postgres-sql
+					source: nowhere
+					description: d
+					statement: SELECT 1
+			toolsets:
+				ts:
+					tools: [search, ghost]
+			schedules:
+				nightly:
+					tool: ghost
+					cron: "@daily"
+					sink:
+						kind: tool
+						tool: phantom
+						param: result
+			bogus: 1
+			`},
+			want: []string{
+				`0.yaml:4: source "bad-src": unknown source kind: "nosuchdb"`,
+				`0.yaml:12: source "typo-src": unable to parse source "typo-src" as "postgres": unknown field "pasword"`,
+				`0.yaml:18: tool "search": statement placeholder {{.table}} has no matching templateParameter`,
+				`0.yaml:19: tool "search": auth service "my-google" does not exist`,
+				`0.yaml:24: tool "search": parameter name "a" is used more than once`,
+				`0.yaml:29: tool "orphan": source "nowhere" does not exist`,
+				`0.yaml:34: toolset "ts": tool "ghost" does not exist`,
+				`0.yaml:37: schedule "nightly": tool "ghost" does not exist`,
+				`0.yaml:41: schedule "nightly": sink tool "phantom" does not exist`,
+				`0.yaml:43: unknown section "bogus", must be one of ["sources" "authSources" "authServices" "tools" "toolsets" "policies" "schedules" "anonymousAccess" "include" "environments"]`,
+			},
+		},
+		{
+			desc: "resources defined in two files",
+			files: []string{`
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					database: db
+					user: u
+					password: p
+			`, `
+			tools:
+				t:
+					kind: postgres-sql
+					source: my-pg
+					description: d
+					statement: SELECT 1
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					database: db
+					user: u
+					password: p
+			`},
+			want: []string{
+				`1.yaml:9: source "my-pg" is already defined at 0.yaml:3`,
+			},
+		},
+		{
+			desc: "named placeholder without parameter",
+			files: []string{`
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					database: db
+					user: u
+					password: p
+			tools:
+				search:
+					kind: postgres-sql
+					source: my-pg
+					description: d
+					statement: SELECT * FROM t WHERE id = :id AND name = :nmae AND t::text = ':x'
+					parameters:
+						- name: id
+						  type: integer
+						  description: id
+						- name: name
+						  type: string
+						  description: name
+			`},
+			want: []string{
+				`0.yaml:15: tool "search": named placeholder :nmae has no matching parameter`,
+			},
+		},
+		{
+			desc: "included file",
+			files: []string{`
+			include:
+				- 1.yaml
+			tools:
+				search:
+					kind: postgres-sql
+					source: my-pg
+					description: d
+					statement: SELECT 1
+			`, `
+			tools:
+				lookup:
+					kind: postgres-sql
+					source: other-pg
+					description: d
+					statement: SELECT 1
+			sources:
+				my-pg:
+					kind: postgres
+					host: 127.0.0.1
+					port: 5432
+					database: db
+					user: u
+					password: p
+			`},
+			validate: 1,
+			want: []string{
+				`1.yaml:5: tool "lookup": source "other-pg" does not exist`,
+			},
+		},
+		{
+			desc:  "syntax error",
+			files: []string{"tools:\n  t: [\n"},
+			want:  []string{`0.yaml:2: sequence end token ']' not found`},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			dir := t.TempDir()
+			var files []string
+			for i, content := range tc.files {
+				f := filepath.Join(dir, string(rune('0'+i))+".yaml")
+				if err := os.WriteFile(f, testutils.FormatYaml(content), 0o600); err != nil {
+					t.Fatalf("unable to write file: %s", err)
+				}
+				files = append(files, f)
+			}
+			var got []string
+			if tc.validate > 0 {
+				// the other files are included
+				files = files[:tc.validate]
+			}
+			for _, p := range validateFiles(ctx, files) {
+				got = append(got, strings.ReplaceAll(p.String(), dir+string(filepath.Separator), ""))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect problems (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "tools.yaml")
+	content := testutils.FormatYaml(`
+	tools:
+		t:
+			kind: postgres-sql
+			source: my-pg
+			description: d
+			statement: SELECT 1
+	`)
+	if err := os.WriteFile(f, content, 0o600); err != nil {
+		t.Fatalf("unable to write file: %s", err)
+	}
+
+	_, out, err := invokeCommand([]string{"validate", "--tools-file", f})
+	if err == nil {
+		t.Fatalf("expected validation to fail")
+	}
+	want := f + ":5: tool \"t\": source \"my-pg\" does not exist\nfound 1 problem in the tool configuration\n"
+	if out != want {
+		t.Fatalf("unexpected output: got %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+)
+
+// OnBehalfOfHeader returns the header an allowed delegate uses to pass the
+// end-user assertion for the named auth service.
+func OnBehalfOfHeader(name string) string {
+	return name + "_on_behalf_of"
+}
+
+// Delegate is an auth service whose verified callers may act on behalf of end
+// users.
+type Delegate struct {
+	// AuthService is the name of the auth service that verifies the delegate.
+	AuthService string `yaml:"authService" validate:"required"`
+	// Subjects optionally restricts the delegates to tokens whose `sub` or
+	// `email` claim is listed.
+	Subjects []string `yaml:"subjects"`
+}
+
+// OnBehalfOfConfig configures which delegates may pass end-user assertions.
+type OnBehalfOfConfig struct {
+	Delegates []Delegate `yaml:"delegates" validate:"required,dive"`
+}
+
+// Identity returns the `sub` or `email` claim identifying the bearer of a
+// token, or an empty string.
+func Identity(claims map[string]any) string {
+	for _, field := range []string{"sub", "email"} {
+		if v, ok := claims[field].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// AllowedDelegate returns the first delegate whose verified claims, keyed by
+// auth service name, allow it to act on behalf of end users.
+func (c OnBehalfOfConfig) AllowedDelegate(claimsFromAuth map[string]map[string]any) (string, bool) {
+	for _, d := range c.Delegates {
+		claims, ok := claimsFromAuth[d.AuthService]
+		if !ok {
+			continue
+		}
+		if len(d.Subjects) == 0 {
+			return d.AuthService, true
+		}
+		for _, field := range []string{"sub", "email"} {
+			if v, ok := claims[field].(string); ok && slices.Contains(d.Subjects, v) {
+				return d.AuthService, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DelegatingAuthServiceConfig wraps an AuthServiceConfig so the auth service
+// also accepts end-user assertions from allowed delegates.
+type DelegatingAuthServiceConfig struct {
+	AuthServiceConfig
+	OnBehalfOf OnBehalfOfConfig
+}
+
+// validate interface
+var _ AuthServiceConfig = DelegatingAuthServiceConfig{}
+
+func (cfg DelegatingAuthServiceConfig) Initialize() (AuthService, error) {
+	a, err := cfg.AuthServiceConfig.Initialize()
+	if err != nil {
+		return nil, err
+	}
+	return DelegatingAuthService{AuthService: a, OnBehalfOf: cfg.OnBehalfOf}, nil
+}
+
+// DelegatingAuthService is an AuthService that accepts end-user assertions.
+type DelegatingAuthService struct {
+	AuthService
+	OnBehalfOf OnBehalfOfConfig
+}
+
+// GetClaimsFromAssertion verifies an end-user assertion the same way as a
+// token sent directly to the auth service.
+func (a DelegatingAuthService) GetClaimsFromAssertion(ctx context.Context, assertion string) (map[string]any, error) {
+	h := make(http.Header)
+	h.Set(a.GetName()+"_token", assertion)
+	return a.GetClaimsFromHeader(ctx, h)
+}
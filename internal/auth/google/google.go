@@ -18,13 +18,23 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/googleapis/genai-toolbox/internal/auth"
-	"google.golang.org/api/idtoken"
 )
 
 const AuthServiceKind string = "google"
 
+// Behaviors when the Google key endpoint can't be reached
+const (
+	KeysUnavailableFail          string = "fail"
+	KeysUnavailableUseCachedKeys string = "useCachedKeys"
+)
+
+// defaultMaxStaleKeyAge bounds how long last known keys are used when
+// maxStaleKeyAge is not configured.
+const defaultMaxStaleKeyAge = time.Hour
+
 // validate interface
 var _ auth.AuthServiceConfig = Config{}
 
@@ -32,7 +42,22 @@ var _ auth.AuthServiceConfig = Config{}
 type Config struct {
 	Name     string `yaml:"name" validate:"required"`
 	Kind     string `yaml:"kind" validate:"required"`
-	ClientID string `yaml:"clientId" validate:"required"`
+	ClientID string `yaml:"clientId"`
+	// Audiences are further client IDs whose tokens are accepted.
+	Audiences []string `yaml:"audiences"`
+	// ClockSkew is the leeway allowed when checking token timestamps, e.g. "30s".
+	ClockSkew string `yaml:"clockSkew"`
+	// JWKSCacheTTL overrides how long fetched keys are trusted. Defaults to
+	// the max-age sent by the key endpoint.
+	JWKSCacheTTL string `yaml:"jwksCacheTtl"`
+	// JWKSRefreshInterval is the age after which keys are refreshed in the
+	// background. Defaults to 80% of the keys' lifetime.
+	JWKSRefreshInterval string `yaml:"jwksRefreshInterval"`
+	// OnKeysUnavailable is either "fail" (default) or "useCachedKeys".
+	OnKeysUnavailable string `yaml:"onKeysUnavailable"`
+	// MaxStaleKeyAge bounds how old the last known keys may be when
+	// OnKeysUnavailable is "useCachedKeys", e.g. "30m". Defaults to 1h.
+	MaxStaleKeyAge string `yaml:"maxStaleKeyAge"`
 }
 
 // Returns the auth service kind
@@ -40,12 +65,72 @@ func (cfg Config) AuthServiceConfigKind() string {
 	return AuthServiceKind
 }
 
+// parseDuration parses an optional, non-negative duration field.
+func parseDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %s %q: %w", field, value, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative, got %q", field, value)
+	}
+	return d, nil
+}
+
 // Initialize a Google auth service
 func (cfg Config) Initialize() (auth.AuthService, error) {
+	audiences := make([]string, 0, len(cfg.Audiences)+1)
+	if cfg.ClientID != "" {
+		audiences = append(audiences, cfg.ClientID)
+	}
+	audiences = append(audiences, cfg.Audiences...)
+	if len(audiences) == 0 {
+		return nil, fmt.Errorf("at least one of clientId or audiences is required")
+	}
+
+	clockSkew, err := parseDuration("clockSkew", cfg.ClockSkew)
+	if err != nil {
+		return nil, err
+	}
+	ksCfg := keySetConfig{authName: cfg.Name, url: googleCertsURL}
+	if ksCfg.ttl, err = parseDuration("jwksCacheTtl", cfg.JWKSCacheTTL); err != nil {
+		return nil, err
+	}
+	if ksCfg.refreshInterval, err = parseDuration("jwksRefreshInterval", cfg.JWKSRefreshInterval); err != nil {
+		return nil, err
+	}
+	if ksCfg.ttl > 0 && ksCfg.refreshInterval >= ksCfg.ttl {
+		return nil, fmt.Errorf("jwksRefreshInterval %s must be less than jwksCacheTtl %s", ksCfg.refreshInterval, ksCfg.ttl)
+	}
+
+	switch cfg.OnKeysUnavailable {
+	case "", KeysUnavailableFail:
+		if cfg.MaxStaleKeyAge != "" {
+			return nil, fmt.Errorf("maxStaleKeyAge requires onKeysUnavailable to be %q", KeysUnavailableUseCachedKeys)
+		}
+	case KeysUnavailableUseCachedKeys:
+		ksCfg.useCachedKeys = true
+		if ksCfg.maxStale, err = parseDuration("maxStaleKeyAge", cfg.MaxStaleKeyAge); err != nil {
+			return nil, err
+		}
+		if ksCfg.maxStale == 0 {
+			ksCfg.maxStale = defaultMaxStaleKeyAge
+		}
+	default:
+		return nil, fmt.Errorf("invalid onKeysUnavailable %q: must be %q or %q", cfg.OnKeysUnavailable, KeysUnavailableFail, KeysUnavailableUseCachedKeys)
+	}
+
 	a := &AuthService{
-		Name:     cfg.Name,
-		Kind:     AuthServiceKind,
-		ClientID: cfg.ClientID,
+		Name:      cfg.Name,
+		Kind:      AuthServiceKind,
+		ClientID:  cfg.ClientID,
+		audiences: audiences,
+		clockSkew: clockSkew,
+		keys:      newKeySet(ksCfg),
+		now:       time.Now,
 	}
 	return a, nil
 }
@@ -57,6 +142,11 @@ type AuthService struct {
 	Name     string `yaml:"name"`
 	Kind     string `yaml:"kind"`
 	ClientID string `yaml:"clientId"`
+
+	audiences []string
+	clockSkew time.Duration
+	keys      *keySet
+	now       func() time.Time
 }
 
 // Returns the auth service kind
@@ -72,11 +162,11 @@ func (a AuthService) GetName() string {
 // Verifies Google ID token and return claims
 func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
 	if token := h.Get(a.Name + "_token"); token != "" {
-		payload, err := idtoken.Validate(ctx, token, a.ClientID)
+		claims, err := a.verify(ctx, token)
 		if err != nil {
 			return nil, fmt.Errorf("Google ID token verification failure: %w", err) //nolint:staticcheck
 		}
-		return payload.Claims, nil
+		return claims, nil
 	}
 	return nil, nil
 }
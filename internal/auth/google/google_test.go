@@ -0,0 +1,271 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeKeyEndpoint serves the public halves of its keys as a JWKS.
+type fakeKeyEndpoint struct {
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	down    bool
+	fetches int
+}
+
+func (f *fakeKeyEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches++
+	if f.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	keys := make([]map[string]string, 0, len(f.keys))
+	for kid, k := range f.keys {
+		keys = append(keys, map[string]string{
+			"kid": kid,
+			"kty": "RSA",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		})
+	}
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+}
+
+func (f *fakeKeyEndpoint) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *fakeKeyEndpoint) fetchCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetches
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	enc := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("unable to marshal: %s", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + enc(claims)
+	hashed := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("unable to sign token: %s", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func newTestAuthService(t *testing.T, cfg Config, endpoint *fakeKeyEndpoint, now *time.Time) *AuthService {
+	t.Helper()
+	srv := httptest.NewServer(endpoint)
+	t.Cleanup(srv.Close)
+	a, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	s := a.(*AuthService)
+	s.keys.cfg.url = srv.URL
+	s.keys.now = func() time.Time { return *now }
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestVerify(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	endpoint := &fakeKeyEndpoint{keys: map[string]*rsa.PrivateKey{"k1": key1}}
+	now := time.Now()
+	a := newTestAuthService(t, Config{
+		Name:      "my-google-auth",
+		Kind:      AuthServiceKind,
+		ClientID:  "client-a",
+		Audiences: []string{"client-b"},
+		ClockSkew: "30s",
+	}, endpoint, &now)
+
+	claims := func(aud string, exp time.Time) map[string]any {
+		return map[string]any{
+			"iss":   "https://accounts.google.com",
+			"aud":   aud,
+			"sub":   "1234",
+			"email": "user@example.com",
+			"iat":   now.Add(-time.Minute).Unix(),
+			"exp":   exp.Unix(),
+		}
+	}
+	ctx := context.Background()
+
+	tcs := []struct {
+		desc    string
+		token   string
+		wantErr bool
+	}{
+		{desc: "client id", token: signToken(t, key1, "k1", claims("client-a", now.Add(time.Hour)))},
+		{desc: "additional audience", token: signToken(t, key1, "k1", claims("client-b", now.Add(time.Hour)))},
+		{desc: "expired within clock skew", token: signToken(t, key1, "k1", claims("client-a", now.Add(-10*time.Second)))},
+		{desc: "expired", token: signToken(t, key1, "k1", claims("client-a", now.Add(-time.Minute))), wantErr: true},
+		{desc: "wrong audience", token: signToken(t, key1, "k1", claims("client-c", now.Add(time.Hour))), wantErr: true},
+		{desc: "wrong signature", token: signToken(t, key2, "k1", claims("client-a", now.Add(time.Hour))), wantErr: true},
+		{desc: "malformed", token: "not-a-token", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := a.verify(ctx, tc.token)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && got["email"] != "user@example.com" {
+				t.Fatalf("unexpected claims: %v", got)
+			}
+		})
+	}
+	if got := endpoint.fetchCount(); got != 1 {
+		t.Fatalf("expected keys to be fetched once, got %d fetches", got)
+	}
+
+	// a token signed with a rotated-in key triggers a fetch
+	endpoint.mu.Lock()
+	endpoint.keys["k2"] = key2
+	endpoint.mu.Unlock()
+	now = now.Add(time.Minute)
+	if _, err := a.verify(ctx, signToken(t, key2, "k2", claims("client-a", now.Add(time.Hour)))); err != nil {
+		t.Fatalf("unexpected error after key rotation: %s", err)
+	}
+	if got := endpoint.fetchCount(); got != 2 {
+		t.Fatalf("expected keys to be fetched again after rotation, got %d fetches", got)
+	}
+	// unknown key ids don't cause another fetch right away
+	if _, err := a.verify(ctx, signToken(t, key2, "k3", claims("client-a", now.Add(time.Hour)))); err == nil {
+		t.Fatalf("expected error for unknown key id")
+	}
+	if got := endpoint.fetchCount(); got != 2 {
+		t.Fatalf("expected unknown key ids to be rate limited, got %d fetches", got)
+	}
+}
+
+func TestVerifyKeysUnavailable(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	tcs := []struct {
+		desc        string
+		cfg         Config
+		wantStaleOk bool
+	}{
+		{
+			desc: "fail",
+			cfg:  Config{Name: "a", Kind: AuthServiceKind, ClientID: "c"},
+		},
+		{
+			desc:        "use cached keys",
+			cfg:         Config{Name: "a", Kind: AuthServiceKind, ClientID: "c", OnKeysUnavailable: KeysUnavailableUseCachedKeys, MaxStaleKeyAge: "2h"},
+			wantStaleOk: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			endpoint := &fakeKeyEndpoint{keys: map[string]*rsa.PrivateKey{"k1": key}}
+			now := time.Now()
+			a := newTestAuthService(t, tc.cfg, endpoint, &now)
+			token := func() string {
+				return signToken(t, key, "k1", map[string]any{
+					"iss": "accounts.google.com",
+					"aud": "c",
+					"exp": now.Add(time.Hour).Unix(),
+				})
+			}
+			ctx := context.Background()
+			if _, err := a.verify(ctx, token()); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			endpoint.setDown(true)
+			// the keys expire after the endpoint's max-age of 1h
+			now = now.Add(90 * time.Minute)
+			_, err := a.verify(ctx, token())
+			if (err == nil) != tc.wantStaleOk {
+				t.Fatalf("unexpected result while endpoint is down: %v", err)
+			}
+
+			// last known keys are only used up to maxStaleKeyAge
+			now = now.Add(time.Hour)
+			if _, err := a.verify(ctx, token()); err == nil {
+				t.Fatalf("expected error once last known keys are too old")
+			}
+
+			endpoint.setDown(false)
+			now = now.Add(time.Minute)
+			if _, err := a.verify(ctx, token()); err != nil {
+				t.Fatalf("unexpected error after endpoint recovered: %s", err)
+			}
+		})
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		cfg     Config
+		wantErr bool
+	}{
+		{desc: "default", cfg: Config{Name: "a", Kind: AuthServiceKind, ClientID: "c"}},
+		{desc: "use cached keys", cfg: Config{Name: "a", Kind: AuthServiceKind, ClientID: "c", OnKeysUnavailable: KeysUnavailableUseCachedKeys, MaxStaleKeyAge: "15m"}},
+		{desc: "invalid behavior", cfg: Config{Name: "a", Kind: AuthServiceKind, ClientID: "c", OnKeysUnavailable: "ignore"}, wantErr: true},
+		{desc: "invalid age", cfg: Config{Name: "a", Kind: AuthServiceKind, ClientID: "c", OnKeysUnavailable: KeysUnavailableUseCachedKeys, MaxStaleKeyAge: "soon"}, wantErr: true},
+		{desc: "age without cached keys", cfg: Config{Name: "a", Kind: AuthServiceKind, ClientID: "c", MaxStaleKeyAge: "15m"}, wantErr: true},
+		{desc: "audiences only", cfg: Config{Name: "a", Kind: AuthServiceKind, Audiences: []string{"c"}}},
+		{desc: "no audience", cfg: Config{Name: "a", Kind: AuthServiceKind}, wantErr: true},
+		{desc: "refresh after ttl", cfg: Config{Name: "a", Kind: AuthServiceKind, ClientID: "c", JWKSCacheTTL: "10m", JWKSRefreshInterval: "10m"}, wantErr: true},
+		{desc: "invalid clock skew", cfg: Config{Name: "a", Kind: AuthServiceKind, ClientID: "c", ClockSkew: "-1s"}, wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
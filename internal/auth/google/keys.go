@@ -0,0 +1,280 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// googleCertsURL serves the keys Google signs ID tokens with.
+const googleCertsURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+const (
+	// defaultKeyTTL is used when the key endpoint doesn't send a max-age.
+	defaultKeyTTL = time.Hour
+	// minRefetchInterval rate limits fetches caused by tokens signed with an
+	// unknown key, so forged key IDs can't be used to flood the endpoint.
+	minRefetchInterval = 10 * time.Second
+	// staleRetryAfter is how long last known keys are used before the key
+	// endpoint is tried again during an outage.
+	staleRetryAfter = 30 * time.Second
+	// fetchTimeout bounds background refreshes.
+	fetchTimeout = 10 * time.Second
+)
+
+type keySetConfig struct {
+	authName string
+	url      string
+	// ttl overrides the endpoint's max-age when set.
+	ttl time.Duration
+	// refreshInterval is the age after which keys are refreshed in the
+	// background while the current keys are still being used. A value of 0
+	// refreshes at 80% of the keys' lifetime.
+	refreshInterval time.Duration
+	// useCachedKeys keeps using the last known keys, for at most maxStale
+	// after they were fetched, while the key endpoint is unreachable.
+	useCachedKeys bool
+	maxStale      time.Duration
+}
+
+// keySet caches the RSA keys published by a JWKS endpoint.
+type keySet struct {
+	cfg    keySetConfig
+	client *http.Client
+	now    func() time.Time
+
+	fetchMu sync.Mutex // serializes fetches
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	expiresAt   time.Time
+	lastAttempt time.Time
+	refreshing  bool
+	generation  uint64 // incremented by every successful fetch
+}
+
+func newKeySet(cfg keySetConfig) *keySet {
+	return &keySet{
+		cfg:    cfg,
+		client: &http.Client{Timeout: fetchTimeout},
+		now:    time.Now,
+		keys:   make(map[string]*rsa.PublicKey),
+	}
+}
+
+// key returns the key with the given ID, fetching the key set if it is
+// expired or doesn't contain the key, which happens after key rotation.
+func (k *keySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	k.mu.Lock()
+	now := k.now()
+	pk, ok := k.keys[kid]
+	fresh := now.Before(k.expiresAt)
+	recentAttempt := now.Sub(k.lastAttempt) < minRefetchInterval
+	if fresh && ok {
+		if !k.refreshing && now.Sub(k.fetchedAt) >= k.refreshAfter() {
+			k.refreshing = true
+			go k.backgroundRefresh()
+		}
+		k.mu.Unlock()
+		return pk, nil
+	}
+	staleUsable := !fresh && k.cfg.useCachedKeys && len(k.keys) > 0 &&
+		now.Sub(k.lastAttempt) < staleRetryAfter && now.Sub(k.fetchedAt) <= k.cfg.maxStale
+	k.mu.Unlock()
+
+	if fresh && recentAttempt {
+		return nil, fmt.Errorf("no key with id %q", kid)
+	}
+	if staleUsable {
+		// the last fetch failed moments ago; don't wait on the endpoint again
+		return k.staleKey(ctx, kid, fmt.Errorf("key endpoint %s is unavailable", k.cfg.url))
+	}
+	if err := k.refresh(ctx); err != nil {
+		return k.staleKey(ctx, kid, err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if pk, ok := k.keys[kid]; ok {
+		return pk, nil
+	}
+	return nil, fmt.Errorf("no key with id %q", kid)
+}
+
+// refreshAfter must be called with k.mu held.
+func (k *keySet) refreshAfter() time.Duration {
+	if k.cfg.refreshInterval > 0 {
+		return k.cfg.refreshInterval
+	}
+	return k.expiresAt.Sub(k.fetchedAt) * 4 / 5
+}
+
+func (k *keySet) backgroundRefresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	// failures are retried by the next verification once the keys expire
+	_ = k.refresh(ctx)
+	k.mu.Lock()
+	k.refreshing = false
+	k.mu.Unlock()
+}
+
+// staleKey falls back on the last known keys after a failed fetch, if the auth
+// service is configured to do so.
+func (k *keySet) staleKey(ctx context.Context, kid string, cause error) (*rsa.PublicKey, error) {
+	k.mu.Lock()
+	pk, ok := k.keys[kid]
+	age := k.now().Sub(k.fetchedAt)
+	hasKeys := len(k.keys) > 0
+	k.mu.Unlock()
+
+	if !k.cfg.useCachedKeys || !hasKeys {
+		return nil, fmt.Errorf("unable to fetch keys: %w", cause)
+	}
+	if age > k.cfg.maxStale {
+		return nil, fmt.Errorf("unable to fetch keys: %w (last known keys are %s old, older than the %s allowed)", cause, age.Round(time.Second), k.cfg.maxStale)
+	}
+	if logger, err := util.LoggerFromContext(ctx); err == nil {
+		logger.WarnContext(ctx, fmt.Sprintf("auth service %q: %s; using keys fetched %s ago", k.cfg.authName, cause, age.Round(time.Second)))
+	}
+	if !ok {
+		return nil, fmt.Errorf("no key with id %q in the last known keys", kid)
+	}
+	return pk, nil
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (k *keySet) refresh(ctx context.Context) error {
+	k.mu.Lock()
+	generation := k.generation
+	k.mu.Unlock()
+
+	k.fetchMu.Lock()
+	defer k.fetchMu.Unlock()
+
+	k.mu.Lock()
+	if k.generation != generation {
+		// another caller refreshed the keys while this one was waiting
+		k.mu.Unlock()
+		return nil
+	}
+	k.lastAttempt = k.now()
+	k.mu.Unlock()
+
+	keys, endpointTTL, err := k.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	ttl := k.cfg.ttl
+	if ttl == 0 {
+		ttl = endpointTTL
+	}
+	if ttl <= 0 {
+		ttl = defaultKeyTTL
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = keys
+	k.fetchedAt = k.now()
+	k.expiresAt = k.fetchedAt.Add(ttl)
+	k.generation++
+	return nil
+}
+
+func (k *keySet) fetch(ctx context.Context) (map[string]*rsa.PublicKey, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.cfg.url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("key endpoint %s returned status code %d", k.cfg.url, resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, 0, fmt.Errorf("unable to decode keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(body.Keys))
+	for _, j := range body.Keys {
+		if j.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to decode modulus of key %q: %w", j.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, 0, fmt.Errorf("unable to decode exponent of key %q: %w", j.Kid, err)
+		}
+		keys[j.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, 0, fmt.Errorf("key endpoint %s returned no RSA keys", k.cfg.url)
+	}
+	return keys, maxAge(resp.Header), nil
+}
+
+// maxAge returns the remaining lifetime of a response from its Cache-Control
+// and Age headers, or 0 if it isn't cacheable.
+func maxAge(h http.Header) time.Duration {
+	var seconds int
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || name != "max-age" {
+			continue
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return 0
+		}
+		seconds = v
+	}
+	if age, err := strconv.Atoi(h.Get("Age")); err == nil {
+		seconds -= age
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// issuers are the accepted values of the iss claim of Google ID tokens.
+var issuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+// verify checks the signature and standard claims of a Google ID token and
+// returns all of its claims.
+func (a AuthService) verify(ctx context.Context, token string) (map[string]any, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, fmt.Errorf("invalid token: must have three segments, found %d", len(segments))
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(segments[0], &header); err != nil {
+		return nil, fmt.Errorf("unable to decode token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("expected a token signed with RS256 but found %q", header.Alg)
+	}
+	var claims map[string]any
+	if err := decodeSegment(segments[1], &claims); err != nil {
+		return nil, fmt.Errorf("unable to decode token payload: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode token signature: %w", err)
+	}
+
+	// check the claims before the signature so malformed or expired tokens
+	// never cause a key fetch
+	if err := a.checkClaims(claims); err != nil {
+		return nil, err
+	}
+
+	key, err := a.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	hashed := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig); err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+	return claims, nil
+}
+
+func (a AuthService) checkClaims(claims map[string]any) error {
+	if iss, _ := claims["iss"].(string); !slices.Contains(issuers, iss) {
+		return fmt.Errorf("unexpected issuer %q", iss)
+	}
+	if !a.audienceAllowed(claims["aud"]) {
+		return fmt.Errorf("audience %v is not allowed", claims["aud"])
+	}
+
+	now := a.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("token has no expiry")
+	}
+	if now.Add(-a.clockSkew).After(time.Unix(int64(exp), 0)) {
+		return fmt.Errorf("token expired: now=%v, expires=%v", now.Unix(), int64(exp))
+	}
+	for _, claim := range []string{"iat", "nbf"} {
+		if v, ok := claims[claim].(float64); ok && now.Add(a.clockSkew).Before(time.Unix(int64(v), 0)) {
+			return fmt.Errorf("token is not valid yet: now=%v, %s=%v", now.Unix(), claim, int64(v))
+		}
+	}
+	return nil
+}
+
+func (a AuthService) audienceAllowed(aud any) bool {
+	switch v := aud.(type) {
+	case string:
+		return slices.Contains(a.audiences, v)
+	case []any:
+		for _, s := range v {
+			if s, ok := s.(string); ok && slices.Contains(a.audiences, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(s string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
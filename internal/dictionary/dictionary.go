@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dictionary describes the tables and columns of a source in business
+// terms, so that agents querying the source understand what its data means.
+package dictionary
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Dictionary describes tables by name. Names may be qualified, e.g.
+// "public.orders", or not, in which case they match the table in any schema
+// or dataset.
+type Dictionary map[string]Table
+
+// Table describes a table and its columns.
+type Table struct {
+	// Description explains what a row of the table is.
+	Description string `yaml:"description"`
+	// Synonyms are other names users call the table by.
+	Synonyms []string          `yaml:"synonyms"`
+	Columns  map[string]Column `yaml:"columns"`
+}
+
+// Column describes a column.
+type Column struct {
+	Description string   `yaml:"description"`
+	Synonyms    []string `yaml:"synonyms"`
+	// Unit is the unit of the column's values, e.g. "USD" or "ms".
+	Unit string `yaml:"unit"`
+}
+
+// Provider is implemented by sources with a data dictionary.
+type Provider interface {
+	DataDictionary() Dictionary
+}
+
+// FromSource returns the data dictionary of a source, if it has one.
+func FromSource(s any) Dictionary {
+	if p, ok := s.(Provider); ok {
+		return p.DataDictionary()
+	}
+	return nil
+}
+
+// Table returns the description of a table. A qualified name also matches an
+// unqualified entry, and an unqualified name an entry in any schema.
+func (d Dictionary) Table(name string) (Table, bool) {
+	if t, ok := d[name]; ok {
+		return t, true
+	}
+	for _, k := range slices.Sorted(maps.Keys(d)) {
+		if unqualified(k) == unqualified(name) {
+			return d[k], true
+		}
+	}
+	return Table{}, false
+}
+
+func unqualified(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// Describe returns the descriptions of tables, or of every table if none are
+// given, one table per paragraph. It is meant to be read by a model, e.g. in
+// a tool description or prompt.
+func (d Dictionary) Describe(tables ...string) (string, error) {
+	if len(tables) == 0 {
+		tables = slices.Sorted(maps.Keys(d))
+	}
+	paragraphs := make([]string, 0, len(tables))
+	for _, name := range tables {
+		t, ok := d.Table(name)
+		if !ok {
+			return "", fmt.Errorf("no table %q in the data dictionary", name)
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "Table %s", name)
+		if s := t.Annotate(""); s != "" {
+			fmt.Fprintf(&b, ": %s", s)
+		}
+		for _, c := range slices.Sorted(maps.Keys(t.Columns)) {
+			fmt.Fprintf(&b, "\n  %s: %s", c, t.Columns[c].Annotate(""))
+		}
+		paragraphs = append(paragraphs, b.String())
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+// Column returns the description of a column of the table.
+func (t Table) Column(name string) (Column, bool) {
+	c, ok := t.Columns[name]
+	return c, ok
+}
+
+// Annotate returns comment, the table's comment in the database, merged with
+// the table's description. The description is only used if there is no
+// comment, while synonyms are always added.
+func (t Table) Annotate(comment string) string {
+	return annotate(comment, t.Description, "", t.Synonyms)
+}
+
+// Annotate returns comment, the column's comment in the database, merged with
+// the column's description. The description is only used if there is no
+// comment, while the unit and synonyms are always added.
+func (c Column) Annotate(comment string) string {
+	return annotate(comment, c.Description, c.Unit, c.Synonyms)
+}
+
+func annotate(comment, description, unit string, synonyms []string) string {
+	var parts []string
+	if comment = strings.TrimSpace(comment); comment != "" {
+		parts = append(parts, sentence(comment))
+	} else if description != "" {
+		parts = append(parts, sentence(description))
+	}
+	if unit != "" {
+		parts = append(parts, fmt.Sprintf("Unit: %s.", unit))
+	}
+	if len(synonyms) > 0 {
+		parts = append(parts, fmt.Sprintf("Also called: %s.", strings.Join(synonyms, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// sentence returns s on a single line, ending with a period.
+func sentence(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if !strings.HasSuffix(s, ".") {
+		s += "."
+	}
+	return s
+}
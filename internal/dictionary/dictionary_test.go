@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dictionary_test
+
+import (
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
+)
+
+var testDictionary = dictionary.Dictionary{
+	"orders": {
+		Description: "One row per order placed in the web store",
+		Synonyms:    []string{"purchases", "sales"},
+		Columns: map[string]dictionary.Column{
+			"total":  {Description: "Order total including tax.", Unit: "USD"},
+			"status": {Description: "Fulfilment status", Synonyms: []string{"state"}},
+		},
+	},
+	"public.customers": {
+		Description: "People who placed at least one order.",
+	},
+}
+
+func TestTable(t *testing.T) {
+	tcs := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{name: "orders", want: "One row per order placed in the web store", ok: true},
+		{name: "sales.orders", want: "One row per order placed in the web store", ok: true},
+		{name: "customers", want: "People who placed at least one order.", ok: true},
+		{name: "public.customers", want: "People who placed at least one order.", ok: true},
+		{name: "refunds"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := testDictionary.Table(tc.name)
+			if ok != tc.ok || got.Description != tc.want {
+				t.Fatalf("incorrect table: got %q (%t), want %q (%t)", got.Description, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	total := testDictionary["orders"].Columns["total"]
+	if got, want := total.Annotate(""), "Order total including tax. Unit: USD."; got != want {
+		t.Fatalf("incorrect annotation: got %q, want %q", got, want)
+	}
+	if got, want := total.Annotate("Charged\namount"), "Charged amount. Unit: USD."; got != want {
+		t.Fatalf("incorrect annotation: got %q, want %q", got, want)
+	}
+	if got := (dictionary.Column{}).Annotate(""); got != "" {
+		t.Fatalf("expected no annotation, got %q", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	got, err := testDictionary.Describe("orders")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := `Table orders: One row per order placed in the web store. Also called: purchases, sales.
+  status: Fulfilment status. Also called: state.
+  total: Order total including tax. Unit: USD.`
+	if got != want {
+		t.Fatalf("incorrect description:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	all, err := testDictionary.Describe()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := want + "\n\nTable public.customers: People who placed at least one order."; all != want {
+		t.Fatalf("incorrect description:\ngot:\n%s\nwant:\n%s", all, want)
+	}
+
+	if _, err := testDictionary.Describe("refunds"); err == nil {
+		t.Fatalf("expected an error for a table missing from the dictionary")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embeddings embeds text with a hosted embedding model, so tools can
+// accept text where they need a vector.
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+// Kinds of embedding providers.
+const (
+	KindVertexAI string = "vertexai"
+	KindOpenAI   string = "openai"
+)
+
+const (
+	defaultVertexAILocation = "us-central1"
+	defaultOpenAIBaseURL    = "https://api.openai.com/v1"
+)
+
+// Config configures the embedding model a tool uses.
+type Config struct {
+	// Kind is either "vertexai" or "openai".
+	Kind string `yaml:"kind" validate:"required"`
+	// Model is the name of the embedding model, e.g. "text-embedding-005".
+	Model string `yaml:"model" validate:"required"`
+	// Dimensions, if set, asks the model for embeddings of this size.
+	Dimensions int `yaml:"dimensions"`
+
+	// Project is the Google Cloud project of a vertexai model.
+	Project string `yaml:"project"`
+	// Location is the region of a vertexai model. Defaults to us-central1.
+	Location string `yaml:"location"`
+
+	// APIKey authenticates requests to an openai model.
+	APIKey string `yaml:"apiKey"`
+	// BaseURL overrides the URL of the OpenAI API, for compatible providers.
+	BaseURL string `yaml:"baseUrl"`
+}
+
+// Embedder embeds text into a vector.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// Initialize returns an Embedder for the configured model. Vertex AI models
+// are called with Application Default Credentials.
+func (c Config) Initialize(ctx context.Context) (Embedder, error) {
+	if c.Dimensions < 0 {
+		return nil, fmt.Errorf("dimensions must not be negative")
+	}
+	switch c.Kind {
+	case KindVertexAI:
+		if c.Project == "" {
+			return nil, fmt.Errorf("a vertexai embedding model requires a project")
+		}
+		location := c.Location
+		if location == "" {
+			location = defaultVertexAILocation
+		}
+		client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform")
+		if err != nil {
+			return nil, fmt.Errorf("unable to find default credentials: %w", err)
+		}
+		url := fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:predict", location, c.Project, location, c.Model)
+		return vertexAIEmbedder{client: client, url: url, dimensions: c.Dimensions}, nil
+	case KindOpenAI:
+		if c.APIKey == "" {
+			return nil, fmt.Errorf("an openai embedding model requires an apiKey")
+		}
+		baseURL := c.BaseURL
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+		return openAIEmbedder{
+			client:     http.DefaultClient,
+			url:        strings.TrimSuffix(baseURL, "/") + "/embeddings",
+			apiKey:     c.APIKey,
+			model:      c.Model,
+			dimensions: c.Dimensions,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown embedding model kind %q, must be %q or %q", c.Kind, KindVertexAI, KindOpenAI)
+	}
+}
+
+type vertexAIEmbedder struct {
+	client     *http.Client
+	url        string
+	dimensions int
+}
+
+func (e vertexAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	req := map[string]any{
+		"instances": []map[string]any{{"content": text}},
+	}
+	if e.dimensions > 0 {
+		req["parameters"] = map[string]any{"outputDimensionality": e.dimensions}
+	}
+	var resp struct {
+		Predictions []struct {
+			Embeddings struct {
+				Values []float32 `json:"values"`
+			} `json:"embeddings"`
+		} `json:"predictions"`
+	}
+	if err := post(ctx, e.client, e.url, nil, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Predictions) == 0 {
+		return nil, fmt.Errorf("embedding response has no predictions")
+	}
+	return resp.Predictions[0].Embeddings.Values, nil
+}
+
+type openAIEmbedder struct {
+	client     *http.Client
+	url        string
+	apiKey     string
+	model      string
+	dimensions int
+}
+
+func (e openAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	req := map[string]any{"model": e.model, "input": text}
+	if e.dimensions > 0 {
+		req["dimensions"] = e.dimensions
+	}
+	var resp struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	header := http.Header{"Authorization": []string{"Bearer " + e.apiKey}}
+	if err := post(ctx, e.client, e.url, header, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("embedding response has no data")
+	}
+	return resp.Data[0].Embedding, nil
+}
+
+// post sends body as JSON to url and decodes the JSON response into out.
+func post(ctx context.Context, client *http.Client, url string, header http.Header, body, out any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to call embedding model: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read embedding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("embedding model returned status %d: %s", resp.StatusCode, respBody)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("unable to parse embedding response: %w", err)
+	}
+	return nil
+}
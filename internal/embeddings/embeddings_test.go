@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embeddings_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/embeddings"
+)
+
+func TestOpenAIEmbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected authorization %q", got)
+		}
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unable to decode request: %s", err)
+		}
+		want := map[string]any{"model": "text-embedding-3-small", "input": "hello", "dimensions": float64(3)}
+		if diff := cmp.Diff(want, req); diff != "" {
+			t.Errorf("unexpected request: diff %v", diff)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"embedding":[0.1,0.2,0.3]}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := embeddings.Config{
+		Kind:       embeddings.KindOpenAI,
+		Model:      "text-embedding-3-small",
+		Dimensions: 3,
+		APIKey:     "secret",
+		BaseURL:    srv.URL + "/v1/",
+	}
+	e, err := cfg.Initialize(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, err := e.Embed(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]float32{0.1, 0.2, 0.3}, got); diff != "" {
+		t.Fatalf("incorrect embedding: diff %v", diff)
+	}
+}
+
+func TestOpenAIEmbedError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	cfg := embeddings.Config{Kind: embeddings.KindOpenAI, Model: "m", APIKey: "k", BaseURL: srv.URL}
+	e, err := cfg.Initialize(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := e.Embed(context.Background(), "hello"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestInitializeInvalid(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  embeddings.Config
+	}{
+		{desc: "unknown kind", cfg: embeddings.Config{Kind: "cohere", Model: "m"}},
+		{desc: "openai without key", cfg: embeddings.Config{Kind: embeddings.KindOpenAI, Model: "m"}},
+		{desc: "vertexai without project", cfg: embeddings.Config{Kind: embeddings.KindVertexAI, Model: "m"}},
+		{desc: "negative dimensions", cfg: embeddings.Config{Kind: embeddings.KindOpenAI, Model: "m", APIKey: "k", Dimensions: -1}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := tc.cfg.Initialize(context.Background()); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
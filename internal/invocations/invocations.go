@@ -0,0 +1,601 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invocations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the state of a tracked invocation.
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusRunning Status = "running"
+)
+
+// Exempt is implemented by tools that must never wait for an invocation slot,
+// e.g. tools that report on the invocation queue itself.
+type Exempt interface {
+	QueueExempt() bool
+}
+
+// Invocation is a single queued or running tool invocation.
+type Invocation struct {
+	ID         string
+	Tool       string
+	Caller     string
+	Status     Status
+	EnqueuedAt time.Time
+	StartedAt  time.Time
+
+	exempt bool
+	err    error
+	cancel context.CancelCauseFunc
+	// done is closed once the invocation is no longer in flight.
+	done chan struct{}
+}
+
+// ErrToolDisabled is returned when beginning an invocation of a disabled tool.
+var ErrToolDisabled = errors.New("tool is disabled")
+
+// ErrShuttingDown is returned when beginning an invocation while the tracker
+// is draining, and is the cause of invocations cancelled by Drain.
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// drainCancelGrace is how long Drain waits for invocations to return once it
+// cancelled them.
+const drainCancelGrace = 5 * time.Second
+
+// Tracker keeps track of in-flight tool invocations and optionally limits how
+// many of them may run concurrently. Should be instantiated with NewTracker().
+type Tracker struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	slots         chan struct{}
+	nextID        uint64
+	inflight      map[string]*Invocation
+	avgDuration   time.Duration
+
+	// latencies is a ring buffer of the most recent invocation durations,
+	// used to compute latency percentiles.
+	latencies   []latencySample
+	nextLatency int
+
+	// history is a ring buffer of the most recently finished invocations.
+	history    []Record
+	nextRecord int
+	disabled   map[string]Disabled
+
+	// usage is a ring buffer of finished invocations, used to compute the
+	// usage statistics of each tool.
+	usage     []usageSample
+	nextUsage int
+
+	// draining is closed once Drain is called, and idle once draining and
+	// no invocation is in flight.
+	draining chan struct{}
+	idle     chan struct{}
+}
+
+type latencySample struct {
+	duration time.Duration
+	finished time.Time
+}
+
+const (
+	// maxLatencySamples is the number of recent durations kept for computing
+	// latency percentiles.
+	maxLatencySamples = 1024
+	// LatencyWindow is how far back invocation durations are considered when
+	// computing latency percentiles.
+	LatencyWindow = 5 * time.Minute
+	// maxHistory is the number of finished invocations kept.
+	maxHistory = 200
+)
+
+// NewTracker returns a Tracker. A maxConcurrent value of 0 or less means
+// invocations are never queued.
+func NewTracker(maxConcurrent int) *Tracker {
+	t := &Tracker{
+		maxConcurrent: maxConcurrent,
+		inflight:      make(map[string]*Invocation),
+		disabled:      make(map[string]Disabled),
+		draining:      make(chan struct{}),
+		idle:          make(chan struct{}),
+	}
+	if maxConcurrent > 0 {
+		t.slots = make(chan struct{}, maxConcurrent)
+	}
+	return t
+}
+
+// MaxConcurrent returns the configured concurrency limit. A value of 0 or less
+// means invocations are never queued.
+func (t *Tracker) MaxConcurrent() int {
+	return t.maxConcurrent
+}
+
+type invocationKey struct{}
+
+// Begin registers a new invocation of the given tool and blocks until a slot
+// is available to run it. The returned context carries the invocation, and the
+// returned function must be called once the invocation has finished.
+func (t *Tracker) Begin(ctx context.Context, tool, caller string) (context.Context, func(), error) {
+	return t.begin(ctx, tool, caller, false)
+}
+
+// BeginExempt registers a new invocation that runs immediately without
+// waiting for, or occupying, an invocation slot. Exempt invocations are not
+// counted in snapshots.
+func (t *Tracker) BeginExempt(ctx context.Context, tool, caller string) (context.Context, func(), error) {
+	return t.begin(ctx, tool, caller, true)
+}
+
+func (t *Tracker) begin(ctx context.Context, tool, caller string, exempt bool) (context.Context, func(), error) {
+	t.mu.Lock()
+	if t.isDraining() {
+		t.mu.Unlock()
+		return ctx, nil, fmt.Errorf("%w: invocation of tool %q was rejected", ErrShuttingDown, tool)
+	}
+	if d, ok := t.disabled[tool]; ok {
+		t.mu.Unlock()
+		if d.Reason != "" {
+			return ctx, nil, fmt.Errorf("%w: %q was disabled by an administrator: %s", ErrToolDisabled, tool, d.Reason)
+		}
+		return ctx, nil, fmt.Errorf("%w: %q was disabled by an administrator", ErrToolDisabled, tool)
+	}
+	t.nextID++
+	inv := &Invocation{
+		ID:         fmt.Sprintf("inv-%d", t.nextID),
+		Tool:       tool,
+		Caller:     caller,
+		Status:     StatusQueued,
+		EnqueuedAt: time.Now(),
+		exempt:     exempt,
+		done:       make(chan struct{}),
+	}
+	t.inflight[inv.ID] = inv
+	t.mu.Unlock()
+
+	if t.slots != nil && !exempt {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			t.remove(inv.ID)
+			return ctx, nil, fmt.Errorf("invocation of tool %q was cancelled while queued: %w", tool, ctx.Err())
+		case <-t.draining:
+			t.remove(inv.ID)
+			return ctx, nil, fmt.Errorf("%w: invocation of tool %q was cancelled while queued", ErrShuttingDown, tool)
+		}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	t.mu.Lock()
+	inv.cancel = cancel
+	inv.Status = StatusRunning
+	inv.StartedAt = time.Now()
+	running := *inv
+	t.mu.Unlock()
+
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			cancel(nil)
+			t.finish(inv)
+			if t.slots != nil && !exempt {
+				<-t.slots
+			}
+		})
+	}
+	return context.WithValue(ctx, invocationKey{}, running), done, nil
+}
+
+// BeginTool calls BeginExempt for tools implementing Exempt, and Begin for
+// every other tool.
+func (t *Tracker) BeginTool(ctx context.Context, name string, tool any, caller string) (context.Context, func(), error) {
+	if e, ok := tool.(Exempt); ok && e.QueueExempt() {
+		return t.BeginExempt(ctx, name, caller)
+	}
+	return t.Begin(ctx, name, caller)
+}
+
+func (t *Tracker) remove(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delete(id)
+}
+
+// delete removes an invocation from the in-flight ones, and reports the
+// tracker idle once it's draining and none are left. t.mu must be held.
+func (t *Tracker) delete(id string) {
+	if inv, ok := t.inflight[id]; ok {
+		close(inv.done)
+	}
+	delete(t.inflight, id)
+	if len(t.inflight) == 0 && t.isDraining() {
+		select {
+		case <-t.idle:
+		default:
+			close(t.idle)
+		}
+	}
+}
+
+// isDraining reports whether Drain was called. t.mu must be held.
+func (t *Tracker) isDraining() bool {
+	select {
+	case <-t.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until every invocation in flight when it's called, queued or
+// running, has finished, or ctx is done. Invocations that begin in the
+// meantime aren't waited for.
+func (t *Tracker) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	pending := make([]chan struct{}, 0, len(t.inflight))
+	for _, inv := range t.inflight {
+		pending = append(pending, inv.done)
+	}
+	t.mu.Unlock()
+
+	for _, done := range pending {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Drain rejects new invocations with ErrShuttingDown, along with the ones
+// still queued, and waits for the running ones to finish. If ctx is done
+// first, the remaining invocations are cancelled with ErrShuttingDown as
+// their cause, and Drain returns the error of ctx once they returned, or
+// after a grace period.
+func (t *Tracker) Drain(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.isDraining() {
+		close(t.draining)
+		if len(t.inflight) == 0 {
+			close(t.idle)
+		}
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.idle:
+		return nil
+	case <-ctx.Done():
+	}
+
+	t.mu.Lock()
+	for _, inv := range t.inflight {
+		if inv.cancel != nil {
+			inv.cancel(ErrShuttingDown)
+		}
+	}
+	t.mu.Unlock()
+	select {
+	case <-t.idle:
+	case <-time.After(drainCancelGrace):
+	}
+	return ctx.Err()
+}
+
+func (t *Tracker) finish(inv *Invocation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delete(inv.ID)
+	if inv.exempt {
+		return
+	}
+	d := time.Since(inv.StartedAt)
+	r := Record{
+		ID:        inv.ID,
+		Tool:      inv.Tool,
+		Caller:    inv.Caller,
+		StartedAt: inv.StartedAt,
+		Queued:    inv.StartedAt.Sub(inv.EnqueuedAt).Round(time.Millisecond).String(),
+		Duration:  d.Round(time.Millisecond).String(),
+	}
+	if inv.err != nil {
+		r.Error = inv.err.Error()
+	}
+	if len(t.history) < maxHistory {
+		t.history = append(t.history, r)
+	} else {
+		t.history[t.nextRecord] = r
+		t.nextRecord = (t.nextRecord + 1) % maxHistory
+	}
+	// exponentially weighted moving average of invocation durations
+	if t.avgDuration == 0 {
+		t.avgDuration = d
+	} else {
+		t.avgDuration = (t.avgDuration*4 + d) / 5
+	}
+	sample := latencySample{duration: d, finished: time.Now()}
+	if len(t.latencies) < maxLatencySamples {
+		t.latencies = append(t.latencies, sample)
+	} else {
+		t.latencies[t.nextLatency] = sample
+		t.nextLatency = (t.nextLatency + 1) % maxLatencySamples
+	}
+	t.recordUsage(usageSample{tool: inv.Tool, caller: inv.Caller, latencySample: sample, failed: inv.err != nil})
+}
+
+// RecordError records that the invocation carried by ctx failed with err, so
+// it's reported in the history once it finishes.
+func (t *Tracker) RecordError(ctx context.Context, err error) {
+	running, ok := FromContext(ctx)
+	if !ok || err == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if inv, ok := t.inflight[running.ID]; ok {
+		inv.err = err
+	}
+}
+
+// FromContext returns the invocation tracked in the context, if any.
+func FromContext(ctx context.Context) (Invocation, bool) {
+	inv, ok := ctx.Value(invocationKey{}).(Invocation)
+	return inv, ok
+}
+
+// InvocationStatus describes a single invocation as reported to callers.
+type InvocationStatus struct {
+	ID            string `json:"id"`
+	Tool          string `json:"tool"`
+	Caller        string `json:"caller,omitempty"`
+	Status        Status `json:"status"`
+	Elapsed       string `json:"elapsed"`
+	QueuePosition int    `json:"queuePosition,omitempty"`
+	EstimatedWait string `json:"estimatedWait,omitempty"`
+}
+
+// Snapshot is a point-in-time view of the invocation queue.
+type Snapshot struct {
+	MaxConcurrent int                `json:"maxConcurrentInvocations"`
+	Running       int                `json:"running"`
+	Queued        int                `json:"queued"`
+	AvgDuration   string             `json:"averageDuration"`
+	EstimatedWait string             `json:"estimatedWait"`
+	Invocations   []InvocationStatus `json:"invocations"`
+}
+
+// Snapshot returns the current state of the queue. Only invocations made by
+// the given caller are listed individually; an empty caller is anonymous and
+// only sees aggregate counts.
+func (t *Tracker) Snapshot(caller string) Snapshot {
+	if caller == "" {
+		return t.snapshot(nil)
+	}
+	return t.snapshot(func(inv *Invocation) bool { return inv.Caller == caller })
+}
+
+// SnapshotAll returns a Snapshot that lists the invocations of every caller.
+func (t *Tracker) SnapshotAll() Snapshot {
+	return t.snapshot(func(*Invocation) bool { return true })
+}
+
+// snapshot lists the invocations for which match returns true. A nil match
+// lists none.
+func (t *Tracker) snapshot(match func(*Invocation) bool) Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	queued := make([]*Invocation, 0)
+	running := 0
+	for _, inv := range t.inflight {
+		if inv.exempt {
+			continue
+		}
+		switch inv.Status {
+		case StatusQueued:
+			queued = append(queued, inv)
+		case StatusRunning:
+			running++
+		}
+	}
+	sort.Slice(queued, func(i, j int) bool { return queued[i].EnqueuedAt.Before(queued[j].EnqueuedAt) })
+	positions := make(map[string]int, len(queued))
+	for i, inv := range queued {
+		positions[inv.ID] = i + 1
+	}
+
+	s := Snapshot{
+		MaxConcurrent: t.maxConcurrent,
+		Running:       running,
+		Queued:        len(queued),
+		AvgDuration:   t.avgDuration.String(),
+		Invocations:   make([]InvocationStatus, 0),
+	}
+	// estimated wait for a new invocation joining the back of the queue
+	newPosition := 0
+	if t.maxConcurrent > 0 && running >= t.maxConcurrent {
+		newPosition = len(queued) + 1
+	}
+	s.EstimatedWait = t.estimateWait(newPosition).String()
+
+	if match == nil {
+		return s
+	}
+	mine := make([]*Invocation, 0)
+	for _, inv := range t.inflight {
+		if match(inv) && !inv.exempt {
+			mine = append(mine, inv)
+		}
+	}
+	sort.Slice(mine, func(i, j int) bool { return mine[i].EnqueuedAt.Before(mine[j].EnqueuedAt) })
+	for _, inv := range mine {
+		is := InvocationStatus{
+			ID:     inv.ID,
+			Tool:   inv.Tool,
+			Caller: inv.Caller,
+			Status: inv.Status,
+		}
+		if inv.Status == StatusQueued {
+			is.Elapsed = now.Sub(inv.EnqueuedAt).Round(time.Millisecond).String()
+			is.QueuePosition = positions[inv.ID]
+			is.EstimatedWait = t.estimateWait(is.QueuePosition).String()
+		} else {
+			is.Elapsed = now.Sub(inv.StartedAt).Round(time.Millisecond).String()
+		}
+		s.Invocations = append(s.Invocations, is)
+	}
+	return s
+}
+
+// estimateWait approximates how long an invocation at the given (1-based)
+// queue position waits before it starts running.
+func (t *Tracker) estimateWait(position int) time.Duration {
+	if position <= 0 || t.maxConcurrent <= 0 {
+		return 0
+	}
+	rounds := (position + t.maxConcurrent - 1) / t.maxConcurrent
+	return (time.Duration(rounds) * t.avgDuration).Round(time.Millisecond)
+}
+
+// Load is a summary of the current invocation pressure, suited for scaling
+// decisions.
+type Load struct {
+	// Running is the number of invocations currently running.
+	Running int `json:"running"`
+	// Queued is the number of invocations waiting for a slot.
+	Queued int `json:"queued"`
+	// MaxConcurrent is the concurrency limit, 0 if there's none.
+	MaxConcurrent int `json:"maxConcurrentInvocations"`
+	// Utilization is the number of running and queued invocations relative to
+	// the concurrency limit, e.g. 1.5 when half of the limit is queued. It's 0
+	// if there's no limit.
+	Utilization float64 `json:"utilization"`
+	// P95LatencyMs is the 95th percentile duration, in milliseconds, of the
+	// invocations that finished within the LatencyWindow.
+	P95LatencyMs int64 `json:"p95LatencyMs"`
+	// Completed is the number of invocations the percentile is computed from.
+	Completed int `json:"completed"`
+}
+
+// Load returns the current load of the tracker. Exempt invocations are not
+// counted.
+func (t *Tracker) Load() Load {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l := Load{MaxConcurrent: max(t.maxConcurrent, 0)}
+	for _, inv := range t.inflight {
+		if inv.exempt {
+			continue
+		}
+		switch inv.Status {
+		case StatusQueued:
+			l.Queued++
+		case StatusRunning:
+			l.Running++
+		}
+	}
+	if l.MaxConcurrent > 0 {
+		l.Utilization = float64(l.Running+l.Queued) / float64(l.MaxConcurrent)
+	}
+
+	cutoff := time.Now().Add(-LatencyWindow)
+	durations := make([]time.Duration, 0, len(t.latencies))
+	for _, s := range t.latencies {
+		if s.finished.After(cutoff) {
+			durations = append(durations, s.duration)
+		}
+	}
+	l.Completed = len(durations)
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		l.P95LatencyMs = percentile(durations, 95).Milliseconds()
+	}
+	return l
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// Record is a finished invocation.
+type Record struct {
+	ID        string    `json:"id"`
+	Tool      string    `json:"tool"`
+	Caller    string    `json:"caller,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	// Queued is how long the invocation waited for a slot.
+	Queued   string `json:"queued"`
+	Duration string `json:"duration"`
+	// Error is the error the invocation failed with, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// History returns up to limit of the most recently finished invocations,
+// newest first. Exempt invocations are not recorded.
+func (t *Tracker) History(limit int) []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := min(max(limit, 0), len(t.history))
+	out := make([]Record, 0, n)
+	// the newest record is just before nextRecord in the ring buffer
+	for i := 1; i <= n; i++ {
+		out = append(out, t.history[(t.nextRecord-i+len(t.history))%len(t.history)])
+	}
+	return out
+}
+
+// Disabled describes a tool that can't be invoked until it's enabled again.
+type Disabled struct {
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// Disable rejects new invocations of the given tool with ErrToolDisabled.
+// Invocations that already began are not affected.
+func (t *Tracker) Disable(tool, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.disabled[tool] = Disabled{Reason: reason, Since: time.Now()}
+}
+
+// Enable allows invocations of a tool disabled with Disable again.
+func (t *Tracker) Enable(tool string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.disabled, tool)
+}
+
+// IsDisabled reports whether the given tool is disabled.
+func (t *Tracker) IsDisabled(tool string) (Disabled, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.disabled[tool]
+	return d, ok
+}
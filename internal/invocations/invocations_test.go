@@ -0,0 +1,320 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invocations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+)
+
+func waitForQueued(t *testing.T, tracker *invocations.Tracker, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if tracker.Snapshot("").Queued == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d queued invocations", want)
+}
+
+func TestTrackerQueue(t *testing.T) {
+	ctx := context.Background()
+	tracker := invocations.NewTracker(1)
+
+	_, done, err := tracker.Begin(ctx, "first", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	started := make(chan func())
+	go func() {
+		_, done, err := tracker.Begin(ctx, "second", "alice")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		started <- done
+	}()
+	waitForQueued(t, tracker, 1)
+
+	got := tracker.Snapshot("alice")
+	if got.Running != 1 || got.Queued != 1 || got.MaxConcurrent != 1 {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+	if len(got.Invocations) != 2 {
+		t.Fatalf("expected 2 invocations for caller, got %d", len(got.Invocations))
+	}
+	if got.Invocations[0].Tool != "first" || got.Invocations[0].Status != invocations.StatusRunning {
+		t.Errorf("unexpected first invocation: %+v", got.Invocations[0])
+	}
+	if got.Invocations[1].Tool != "second" || got.Invocations[1].QueuePosition != 1 {
+		t.Errorf("unexpected second invocation: %+v", got.Invocations[1])
+	}
+
+	// other callers only see aggregate counts
+	if other := tracker.Snapshot("bob"); len(other.Invocations) != 0 || other.Queued != 1 {
+		t.Errorf("unexpected snapshot for other caller: %+v", other)
+	}
+
+	// exempt invocations run immediately even when the server is at capacity
+	_, exemptDone, err := tracker.BeginExempt(ctx, "status", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s := tracker.Snapshot("alice"); len(s.Invocations) != 2 {
+		t.Errorf("exempt invocation should not be listed: %+v", s)
+	}
+	exemptDone()
+
+	done()
+	secondDone := <-started
+	secondDone()
+	if s := tracker.Snapshot("alice"); s.Running != 0 || s.Queued != 0 {
+		t.Errorf("expected empty queue, got %+v", s)
+	}
+}
+
+func TestTrackerCancelWhileQueued(t *testing.T) {
+	tracker := invocations.NewTracker(1)
+	_, done, err := tracker.Begin(context.Background(), "first", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := tracker.Begin(ctx, "second", ""); err == nil {
+		t.Fatalf("expected error when context is cancelled while queued")
+	}
+	if s := tracker.Snapshot(""); s.Queued != 0 {
+		t.Errorf("cancelled invocation should be removed from queue, got %+v", s)
+	}
+}
+
+func TestTrackerCallerFromContext(t *testing.T) {
+	tracker := invocations.NewTracker(0)
+	ctx, done, err := tracker.Begin(context.Background(), "tool", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer done()
+	inv, ok := invocations.FromContext(ctx)
+	if !ok {
+		t.Fatalf("expected invocation in context")
+	}
+	if inv.Caller != "alice" || inv.Tool != "tool" || inv.Status != invocations.StatusRunning {
+		t.Errorf("unexpected invocation: %+v", inv)
+	}
+}
+
+func TestTrackerLoad(t *testing.T) {
+	ctx := context.Background()
+	tracker := invocations.NewTracker(2)
+
+	if l := tracker.Load(); l != (invocations.Load{MaxConcurrent: 2}) {
+		t.Fatalf("unexpected load of an idle tracker: %+v", l)
+	}
+
+	_, done, err := tracker.Begin(ctx, "first", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, exemptDone, err := tracker.BeginExempt(ctx, "status", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	l := tracker.Load()
+	if l.Running != 1 || l.Queued != 0 || l.Utilization != 0.5 {
+		t.Errorf("unexpected load: %+v", l)
+	}
+	exemptDone()
+	time.Sleep(20 * time.Millisecond)
+	done()
+
+	l = tracker.Load()
+	if l.Running != 0 || l.Completed != 1 {
+		t.Errorf("unexpected load: %+v", l)
+	}
+	if l.P95LatencyMs < 20 {
+		t.Errorf("expected a p95 latency of at least 20ms, got %dms", l.P95LatencyMs)
+	}
+}
+
+func TestTrackerHistoryAndDisable(t *testing.T) {
+	tracker := invocations.NewTracker(0)
+	for i, tool := range []string{"first", "second", "third"} {
+		ctx, done, err := tracker.Begin(context.Background(), tool, "alice")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if i == 1 {
+			tracker.RecordError(ctx, errors.New("boom"))
+		}
+		done()
+	}
+	got := tracker.History(2)
+	if len(got) != 2 || got[0].Tool != "third" || got[1].Tool != "second" || got[1].Error != "boom" || got[0].Error != "" {
+		t.Fatalf("unexpected history: %+v", got)
+	}
+
+	tracker.Disable("first", "too slow")
+	if _, _, err := tracker.Begin(context.Background(), "first", ""); !errors.Is(err, invocations.ErrToolDisabled) {
+		t.Fatalf("expected ErrToolDisabled, got %v", err)
+	}
+	if d, ok := tracker.IsDisabled("first"); !ok || d.Reason != "too slow" {
+		t.Errorf("unexpected disabled state: %+v, %t", d, ok)
+	}
+	tracker.Enable("first")
+	_, done, err := tracker.Begin(context.Background(), "first", "")
+	if err != nil {
+		t.Fatalf("unexpected error after enabling: %s", err)
+	}
+	done()
+}
+
+func TestTrackerDrain(t *testing.T) {
+	ctx := context.Background()
+	tracker := invocations.NewTracker(1)
+
+	_, done, err := tracker.Begin(ctx, "first", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	queued := make(chan error)
+	go func() {
+		_, _, err := tracker.Begin(ctx, "second", "alice")
+		queued <- err
+	}()
+	waitForQueued(t, tracker, 1)
+
+	drained := make(chan error)
+	go func() { drained <- tracker.Drain(ctx) }()
+
+	// queued and new invocations are rejected, the running one is waited for
+	if err := <-queued; !errors.Is(err, invocations.ErrShuttingDown) {
+		t.Errorf("expected ErrShuttingDown for the queued invocation, got %v", err)
+	}
+	if _, _, err := tracker.Begin(ctx, "third", "alice"); !errors.Is(err, invocations.ErrShuttingDown) {
+		t.Errorf("expected ErrShuttingDown for a new invocation, got %v", err)
+	}
+	select {
+	case <-drained:
+		t.Fatal("Drain returned while an invocation is running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	done()
+	if err := <-drained; err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestTrackerDrainCancels(t *testing.T) {
+	tracker := invocations.NewTracker(0)
+	invCtx, done, err := tracker.Begin(context.Background(), "slow", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	go func() {
+		<-invCtx.Done()
+		done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := tracker.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	if cause := context.Cause(invCtx); !errors.Is(cause, invocations.ErrShuttingDown) {
+		t.Errorf("expected the invocation to be cancelled with ErrShuttingDown, got %v", cause)
+	}
+}
+
+func TestTrackerWait(t *testing.T) {
+	ctx := context.Background()
+	tracker := invocations.NewTracker(0)
+	_, done, err := tracker.Begin(ctx, "first", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	waited := make(chan error)
+	go func() { waited <- tracker.Wait(ctx) }()
+	select {
+	case <-waited:
+		t.Fatal("Wait returned while an invocation is running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	done()
+	if err := <-waited; err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if _, _, err := tracker.Begin(ctx, "second", "alice"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := tracker.Wait(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded while an invocation is running, got %v", err)
+	}
+}
+
+func TestTrackerStats(t *testing.T) {
+	tracker := invocations.NewTracker(0)
+	invoke := func(tool, caller string, err error) {
+		ctx, done, berr := tracker.Begin(context.Background(), tool, caller)
+		if berr != nil {
+			t.Fatalf("unexpected error: %s", berr)
+		}
+		tracker.RecordError(ctx, err)
+		done()
+	}
+	invoke("search", "alice", nil)
+	invoke("search", "bob", errors.New("boom"))
+	invoke("search", "", nil)
+	invoke("search", "alice", nil)
+	invoke("book", "alice", nil)
+	_, done, err := tracker.BeginExempt(context.Background(), "stats", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	done()
+
+	got := tracker.Stats(time.Hour)
+	if got.Window != "1h0m0s" {
+		t.Errorf("unexpected window %q", got.Window)
+	}
+	// exempt invocations aren't counted, the most invoked tools come first
+	if len(got.Tools) != 2 || got.Tools[0].Tool != "search" || got.Tools[1].Tool != "book" {
+		t.Fatalf("unexpected tools: %+v", got.Tools)
+	}
+	search := got.Tools[0]
+	if search.Invocations != 4 || search.Errors != 1 || search.ErrorRate != 0.25 || search.UniqueCallers != 2 {
+		t.Errorf("unexpected stats: %+v", search)
+	}
+	if search.LastInvokedAt == nil || time.Since(*search.LastInvokedAt) > time.Minute {
+		t.Errorf("unexpected last invocation time: %v", search.LastInvokedAt)
+	}
+
+	if got := tracker.Stats(time.Nanosecond); len(got.Tools) != 0 {
+		t.Errorf("expected no invocations in a window of 1ns, got %+v", got.Tools)
+	}
+}
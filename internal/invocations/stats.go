@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package invocations
+
+import (
+	"sort"
+	"time"
+)
+
+// maxUsageSamples is the number of finished invocations kept for computing
+// usage statistics.
+const maxUsageSamples = 100000
+
+type usageSample struct {
+	latencySample
+	tool   string
+	caller string
+	failed bool
+}
+
+// ToolStats is the usage of a single tool over a window of time.
+type ToolStats struct {
+	Tool        string `json:"tool"`
+	Invocations int    `json:"invocations"`
+	Errors      int    `json:"errors"`
+	// ErrorRate is the ratio of invocations that failed, between 0 and 1.
+	ErrorRate    float64 `json:"errorRate"`
+	P50LatencyMs int64   `json:"p50LatencyMs"`
+	P95LatencyMs int64   `json:"p95LatencyMs"`
+	// UniqueCallers is the number of distinct authenticated callers. Anonymous
+	// invocations aren't counted.
+	UniqueCallers int        `json:"uniqueCallers"`
+	LastInvokedAt *time.Time `json:"lastInvokedAt,omitempty"`
+}
+
+// Stats is the usage of every tool invoked over a window of time.
+type Stats struct {
+	Window string `json:"window"`
+	// Since is the start of the window. It's later than the window implies if
+	// older invocations were no longer kept.
+	Since time.Time   `json:"since"`
+	Tools []ToolStats `json:"tools"`
+}
+
+func (t *Tracker) recordUsage(s usageSample) {
+	if len(t.usage) < maxUsageSamples {
+		t.usage = append(t.usage, s)
+		return
+	}
+	t.usage[t.nextUsage] = s
+	t.nextUsage = (t.nextUsage + 1) % maxUsageSamples
+}
+
+// Stats returns the usage of the tools invoked in the given window, the most
+// invoked first. Exempt invocations are not counted.
+func (t *Tracker) Stats(window time.Duration) Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	since := now.Add(-window)
+	if len(t.usage) == maxUsageSamples {
+		// the oldest sample is about to be overwritten next
+		if oldest := t.usage[t.nextUsage].finished; oldest.After(since) {
+			since = oldest
+		}
+	}
+
+	type toolUsage struct {
+		stats     ToolStats
+		durations []time.Duration
+		callers   map[string]bool
+	}
+	byTool := make(map[string]*toolUsage)
+	for _, s := range t.usage {
+		if s.finished.Before(since) {
+			continue
+		}
+		u, ok := byTool[s.tool]
+		if !ok {
+			u = &toolUsage{stats: ToolStats{Tool: s.tool}, callers: make(map[string]bool)}
+			byTool[s.tool] = u
+		}
+		u.stats.Invocations++
+		if s.failed {
+			u.stats.Errors++
+		}
+		u.durations = append(u.durations, s.duration)
+		if s.caller != "" {
+			u.callers[s.caller] = true
+		}
+		if u.stats.LastInvokedAt == nil || s.finished.After(*u.stats.LastInvokedAt) {
+			finished := s.finished
+			u.stats.LastInvokedAt = &finished
+		}
+	}
+
+	out := Stats{Window: window.String(), Since: since, Tools: make([]ToolStats, 0, len(byTool))}
+	for _, u := range byTool {
+		sort.Slice(u.durations, func(i, j int) bool { return u.durations[i] < u.durations[j] })
+		u.stats.P50LatencyMs = percentile(u.durations, 50).Milliseconds()
+		u.stats.P95LatencyMs = percentile(u.durations, 95).Milliseconds()
+		u.stats.ErrorRate = float64(u.stats.Errors) / float64(u.stats.Invocations)
+		u.stats.UniqueCallers = len(u.callers)
+		out.Tools = append(out.Tools, u.stats)
+	}
+	sort.Slice(out.Tools, func(i, j int) bool {
+		a, b := out.Tools[i], out.Tools[j]
+		if a.Invocations != b.Invocations {
+			return a.Invocations > b.Invocations
+		}
+		return a.Tool < b.Tool
+	})
+	return out
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package llm generates text with a hosted language model, for tools that
+// call a model on the server.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+// KindVertexAI is the kind of Gemini models served by Vertex AI.
+const KindVertexAI string = "vertexai"
+
+const defaultVertexAILocation = "us-central1"
+
+// Config configures the language model a tool uses.
+type Config struct {
+	// Kind must be "vertexai".
+	Kind string `yaml:"kind" validate:"required"`
+	// Model is the name of the model, e.g. "gemini-2.5-flash".
+	Model string `yaml:"model" validate:"required"`
+	// Project is the Google Cloud project the model is called in.
+	Project string `yaml:"project" validate:"required"`
+	// Location is the region of the model. Defaults to us-central1.
+	Location string `yaml:"location"`
+	// Temperature controls the randomness of the responses. Defaults to 0.
+	Temperature float64 `yaml:"temperature"`
+}
+
+// Request is a single-turn request to a model.
+type Request struct {
+	// System is the system instruction.
+	System string
+	Prompt string
+	// ResponseSchema, if set, constrains the response to JSON matching this
+	// OpenAPI schema.
+	ResponseSchema map[string]any
+	// MaxTokens, if set, limits the length of the response.
+	MaxTokens int
+}
+
+// Model generates a response to a request.
+type Model interface {
+	Generate(ctx context.Context, req Request) (string, error)
+}
+
+type clientModelKey struct{}
+
+// WithClientModel returns a context whose invocations can generate responses
+// with the model of the connected client, e.g. through MCP sampling.
+func WithClientModel(ctx context.Context, m Model) context.Context {
+	return context.WithValue(ctx, clientModelKey{}, m)
+}
+
+// ClientModelFromContext returns the model of the client the invocation of
+// ctx comes from, if it offers one.
+func ClientModelFromContext(ctx context.Context) (Model, bool) {
+	m, ok := ctx.Value(clientModelKey{}).(Model)
+	return m, ok
+}
+
+// Initialize returns the configured Model, which is called with Application
+// Default Credentials.
+func (c Config) Initialize(ctx context.Context) (Model, error) {
+	if c.Kind != KindVertexAI {
+		return nil, fmt.Errorf("unknown model kind %q, must be %q", c.Kind, KindVertexAI)
+	}
+	location := c.Location
+	if location == "" {
+		location = defaultVertexAILocation
+	}
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		return nil, fmt.Errorf("unable to find default credentials: %w", err)
+	}
+	url := fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:generateContent", location, c.Project, location, c.Model)
+	return vertexAIModel{client: client, url: url, temperature: c.Temperature}, nil
+}
+
+type vertexAIModel struct {
+	client      *http.Client
+	url         string
+	temperature float64
+}
+
+func (m vertexAIModel) Generate(ctx context.Context, req Request) (string, error) {
+	generationConfig := map[string]any{"temperature": m.temperature}
+	if req.MaxTokens > 0 {
+		generationConfig["maxOutputTokens"] = req.MaxTokens
+	}
+	if req.ResponseSchema != nil {
+		generationConfig["responseMimeType"] = "application/json"
+		generationConfig["responseSchema"] = req.ResponseSchema
+	}
+	body := map[string]any{
+		"contents":         []map[string]any{{"role": "user", "parts": []map[string]any{{"text": req.Prompt}}}},
+		"generationConfig": generationConfig,
+	}
+	if req.System != "" {
+		body["systemInstruction"] = map[string]any{"parts": []map[string]any{{"text": req.System}}}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(b))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("unable to call model: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read model response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("model returned status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var out struct {
+		Candidates []struct {
+			FinishReason string `json:"finishReason"`
+			Content      struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return "", fmt.Errorf("unable to parse model response: %w", err)
+	}
+	if len(out.Candidates) == 0 {
+		return "", fmt.Errorf("model returned no response")
+	}
+	c := out.Candidates[0]
+	var text strings.Builder
+	for _, p := range c.Content.Parts {
+		text.WriteString(p.Text)
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("model returned an empty response, finish reason %q", c.FinishReason)
+	}
+	return text.String(), nil
+}
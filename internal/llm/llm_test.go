@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVertexAIGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unable to decode request: %s", err)
+		}
+		want := map[string]any{
+			"contents":          []any{map[string]any{"role": "user", "parts": []any{map[string]any{"text": "question"}}}},
+			"systemInstruction": map[string]any{"parts": []any{map[string]any{"text": "be brief"}}},
+			"generationConfig": map[string]any{
+				"temperature":      float64(0),
+				"responseMimeType": "application/json",
+				"responseSchema":   map[string]any{"type": "OBJECT"},
+			},
+		}
+		if diff := cmp.Diff(want, req); diff != "" {
+			t.Errorf("unexpected request: diff %v", diff)
+		}
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"{\"a\":"},{"text":"1}"}]}}]}`))
+	}))
+	defer srv.Close()
+
+	m := vertexAIModel{client: srv.Client(), url: srv.URL}
+	got, err := m.Generate(context.Background(), Request{System: "be brief", Prompt: "question", ResponseSchema: map[string]any{"type": "OBJECT"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != `{"a":1}` {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestVertexAIGenerateEmpty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"candidates":[{"finishReason":"SAFETY","content":{}}]}`))
+	}))
+	defer srv.Close()
+
+	m := vertexAIModel{client: srv.Client(), url: srv.URL}
+	if _, err := m.Generate(context.Background(), Request{Prompt: "question"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestInitializeUnknownKind(t *testing.T) {
+	if _, err := (Config{Kind: "openai", Model: "m", Project: "p"}).Initialize(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+}
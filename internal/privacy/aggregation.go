@@ -0,0 +1,447 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package privacy restricts the queries run on sensitive sources to
+// aggregates over groups of a minimum size, so that no single result
+// describes an individual row. Without noise, comparing the results of
+// several queries can still reveal one.
+package privacy
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultAggregates are the aggregate functions queries may use, unless a
+// source allows others. Aggregates such as min, max or array_agg return the
+// values of individual rows, so they aren't allowed by default.
+var DefaultAggregates = []string{"count", "sum", "avg"}
+
+// knownAggregates are the aggregate functions of PostgreSQL. Calls of the ones
+// that aren't allowed are rejected.
+var knownAggregates = map[string]bool{
+	"array_agg": true, "avg": true, "bit_and": true, "bit_or": true, "bit_xor": true,
+	"bool_and": true, "bool_or": true, "count": true, "corr": true, "covar_pop": true,
+	"covar_samp": true, "every": true, "json_agg": true, "json_object_agg": true,
+	"jsonb_agg": true, "jsonb_object_agg": true, "max": true, "min": true, "mode": true,
+	"percentile_cont": true, "percentile_disc": true, "range_agg": true,
+	"range_intersect_agg": true, "regr_avgx": true, "regr_avgy": true, "regr_count": true,
+	"regr_intercept": true, "regr_r2": true, "regr_slope": true, "regr_sxx": true,
+	"regr_sxy": true, "regr_syy": true, "stddev": true, "stddev_pop": true,
+	"stddev_samp": true, "string_agg": true, "sum": true, "var_pop": true,
+	"var_samp": true, "variance": true, "xmlagg": true,
+}
+
+// rejectedKeywords are keywords that would let a query read rows other than
+// through its top-level aggregates, or write data.
+var rejectedKeywords = map[string]string{
+	"with":      "common table expressions",
+	"union":     "set operations",
+	"intersect": "set operations",
+	"except":    "set operations",
+	"over":      "window functions",
+	"window":    "window functions",
+	"into":      "SELECT INTO",
+}
+
+// conditionalKeywords are keywords of expressions that compare or select
+// rows. Inside the arguments of an aggregate they could make it depend on a
+// single row, e.g. sum(CASE WHEN id = 42 THEN salary ELSE 0 END).
+var conditionalKeywords = map[string]bool{
+	"case": true, "when": true, "then": true, "else": true, "end": true,
+	"and": true, "or": true, "not": true, "is": true, "isnull": true,
+	"notnull": true, "in": true, "between": true, "like": true, "ilike": true,
+	"similar": true, "any": true, "some": true, "exists": true,
+}
+
+// rejectedFunctions are functions that run queries or read data of their own.
+var rejectedFunctions = map[string]bool{
+	"cursor_to_xml": true, "dblink": true, "query_to_xml": true,
+	"query_to_xml_and_xmlschema": true, "table_to_xml": true,
+	"table_to_xml_and_xmlschema": true, "schema_to_xml": true,
+	"database_to_xml": true, "lo_get": true, "pg_read_file": true,
+	"pg_read_binary_file": true,
+}
+
+// AggregationConfig restricts the queries of a source to aggregates over
+// groups of at least MinGroupSize rows.
+type AggregationConfig struct {
+	// MinGroupSize is the fewest rows a returned group may aggregate.
+	MinGroupSize int `yaml:"minGroupSize"`
+	// Aggregates are the aggregate functions queries may use. Defaults to
+	// DefaultAggregates.
+	Aggregates []string `yaml:"aggregates"`
+	// NoiseScale, if set, is the scale of the Laplace noise added to the
+	// values of aggregates. A scale of sensitivity/ε makes a single
+	// aggregate ε-differentially private, e.g. 1/ε for a count.
+	NoiseScale float64 `yaml:"noiseScale"`
+}
+
+// Restricted is implemented by sources that can be restricted to aggregate
+// queries.
+type Restricted interface {
+	AggregationOnly() *AggregationConfig
+}
+
+// FromSource returns the aggregation restriction of a source, if it has one.
+func FromSource(s any) *AggregationConfig {
+	if r, ok := s.(Restricted); ok {
+		return r.AggregationOnly()
+	}
+	return nil
+}
+
+// Validate checks the configuration of a source's restriction.
+func (c *AggregationConfig) Validate() error {
+	if c.MinGroupSize < 2 {
+		return fmt.Errorf("aggregationOnly requires a minGroupSize of at least 2, got %d", c.MinGroupSize)
+	}
+	if c.NoiseScale < 0 {
+		return fmt.Errorf("aggregationOnly noiseScale must not be negative, got %g", c.NoiseScale)
+	}
+	for _, a := range c.Aggregates {
+		if !knownAggregates[strings.ToLower(a)] {
+			return fmt.Errorf("unknown aggregate function %q", a)
+		}
+	}
+	return nil
+}
+
+// AllowedAggregates returns the aggregate functions queries may use.
+func (c *AggregationConfig) AllowedAggregates() []string {
+	if len(c.Aggregates) == 0 {
+		return DefaultAggregates
+	}
+	return c.Aggregates
+}
+
+func (c *AggregationConfig) allowed(fn string) bool {
+	return slices.ContainsFunc(c.AllowedAggregates(), func(a string) bool { return strings.EqualFold(a, fn) })
+}
+
+// Query is a statement rewritten to only return groups of at least the
+// minimum group size.
+type Query struct {
+	Statement string
+	// aggregates are the indexes of the columns computed by aggregates.
+	aggregates map[int]bool
+	noiseScale float64
+}
+
+// Rewrite checks that statement is a single SELECT whose only access to rows
+// is through the allowed aggregates, and adds a HAVING clause dropping the
+// groups of fewer rows than the minimum group size. Subqueries, common table
+// expressions, set operations and window functions are rejected, since they
+// could read rows outside of the top-level aggregates. Unless noise is added,
+// aggregates may only be applied to columns: expressions and FILTER clauses
+// could select a single row of a group to aggregate.
+func (c *AggregationConfig) Rewrite(statement string) (Query, error) {
+	toks := tokenize(statement)
+	for len(toks) > 0 && toks[len(toks)-1].text == ";" {
+		statement = statement[:toks[len(toks)-1].start]
+		toks = toks[:len(toks)-1]
+	}
+	if len(toks) == 0 || toks[0].word != "select" {
+		return Query{}, fmt.Errorf("only SELECT statements are allowed")
+	}
+
+	var from, having, end = -1, -1, len(statement)
+	depth := 0
+	for i, t := range toks {
+		switch t.text {
+		case "(":
+			depth++
+			continue
+		case ")":
+			depth--
+			continue
+		case ";":
+			return Query{}, fmt.Errorf("only a single statement is allowed")
+		}
+		if t.word == "" {
+			continue
+		}
+		if t.word == "select" && i > 0 {
+			return Query{}, fmt.Errorf("subqueries are not allowed")
+		}
+		if what, ok := rejectedKeywords[t.word]; ok {
+			return Query{}, fmt.Errorf("%s are not allowed", what)
+		}
+		if i+1 < len(toks) && toks[i+1].text == "(" {
+			if knownAggregates[t.word] && !c.allowed(t.word) {
+				return Query{}, fmt.Errorf("aggregate function %s is not allowed", t.word)
+			}
+			if rejectedFunctions[t.word] {
+				return Query{}, fmt.Errorf("function %s is not allowed", t.word)
+			}
+			if c.NoiseScale == 0 && t.word == "filter" {
+				return Query{}, fmt.Errorf("FILTER clauses are not allowed unless noiseScale is set")
+			}
+			if c.NoiseScale == 0 && knownAggregates[t.word] {
+				if err := checkAggregateArgs(toks, i+1); err != nil {
+					return Query{}, err
+				}
+			}
+		}
+		if depth > 0 {
+			continue
+		}
+		switch t.word {
+		case "from":
+			if from < 0 {
+				from = i
+			}
+		case "having":
+			having = i
+		case "order", "limit", "offset", "fetch", "for":
+			if end == len(statement) {
+				end = t.start
+			}
+		}
+	}
+
+	// the columns of the select list computed by aggregates
+	listEnd := len(toks)
+	if from >= 0 {
+		listEnd = from
+	}
+	aggregates := make(map[int]bool)
+	column, depth := 0, 0
+	for i := 1; i < listEnd && toks[i].start < end; i++ {
+		t := toks[i]
+		switch {
+		case t.text == "(":
+			depth++
+		case t.text == ")":
+			depth--
+		case t.text == "," && depth == 0:
+			column++
+		case t.word != "" && knownAggregates[t.word] && i+1 < len(toks) && toks[i+1].text == "(":
+			aggregates[column] = true
+		}
+	}
+
+	threshold := fmt.Sprintf("count(*) >= %d", c.MinGroupSize)
+	head, tail := strings.TrimRight(statement[:end], " \t\r\n"), statement[end:]
+	if having >= 0 {
+		cond := strings.TrimSpace(statement[toks[having].end:len(head)])
+		head = statement[:toks[having].end] + " (" + cond + ") AND " + threshold
+	} else {
+		head += " HAVING " + threshold
+	}
+	if tail != "" {
+		head += " "
+	}
+	return Query{Statement: head + tail, aggregates: aggregates, noiseScale: c.NoiseScale}, nil
+}
+
+// checkAggregateArgs checks that the arguments of the aggregate call whose
+// parenthesis is toks[open] only refer to columns and literals. Operators,
+// function calls and conditional keywords are rejected, since they could
+// give every row but one no weight in the aggregate.
+func checkAggregateArgs(toks []token, open int) error {
+	fn := toks[open-1].word
+	depth := 0
+	for i := open; i < len(toks); i++ {
+		t := toks[i]
+		var ok bool
+		switch {
+		case t.text == "(":
+			depth++
+			ok = true
+		case t.text == ")":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+			ok = true
+		case t.word != "":
+			// a column, or a keyword such as DISTINCT, but not a function call
+			call := i+1 < len(toks) && toks[i+1].text == "(" && t.word != "distinct"
+			ok = !conditionalKeywords[t.word] && !call
+		case t.text == "*":
+			// count(*) or t.*
+			ok = toks[i-1].text == "." || (toks[i-1].text == "(" && i+1 < len(toks) && toks[i+1].text == ")")
+		case t.text == "," || t.text == "." || t.text == ":":
+			// argument lists, qualified columns and casts
+			ok = true
+		default:
+			// quoted identifiers, strings and numbers are single tokens, while
+			// operators are single characters
+			ok = len(t.text) > 1 || (t.text[0] >= '0' && t.text[0] <= '9')
+		}
+		if !ok {
+			return fmt.Errorf("the arguments of aggregate function %s must be columns unless noiseScale is set, got %q", fn, t.text)
+		}
+	}
+	return nil
+}
+
+// Noise returns the value v of column i with Laplace noise added, if the
+// column is computed by an aggregate and the source adds noise. Integers stay
+// integers.
+func (q Query) Noise(i int, v any) any {
+	if q.noiseScale == 0 || !q.aggregates[i] {
+		return v
+	}
+	switch n := v.(type) {
+	case int64:
+		return n + int64(math.Round(laplace(q.noiseScale)))
+	case int32:
+		return int64(n) + int64(math.Round(laplace(q.noiseScale)))
+	case int:
+		return int64(n) + int64(math.Round(laplace(q.noiseScale)))
+	case float64:
+		return n + laplace(q.noiseScale)
+	case float32:
+		return float64(n) + laplace(q.noiseScale)
+	case driver.Valuer:
+		// e.g. numeric values, which drivers return as decimal strings
+		dv, err := n.Value()
+		if err != nil {
+			return v
+		}
+		s, ok := dv.(string)
+		if !ok {
+			return v
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return v
+		}
+		return f + laplace(q.noiseScale)
+	}
+	return v
+}
+
+// laplace samples the Laplace distribution centered on 0 with scale b.
+func laplace(b float64) float64 {
+	u := rand.Float64() - 0.5
+	return -b * math.Copysign(1, u) * math.Log(1-2*math.Abs(u))
+}
+
+// token is a lexical token of a statement. word is set, in lower case, for
+// unquoted identifiers and keywords.
+type token struct {
+	text       string
+	word       string
+	start, end int
+}
+
+// tokenize splits a statement into tokens, skipping whitespace and comments.
+// Quoted strings and identifiers are single tokens.
+func tokenize(s string) []token {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case strings.HasPrefix(s[i:], "--"):
+			i = skipPast(s, i, "\n")
+			continue
+		case strings.HasPrefix(s[i:], "/*"):
+			i = skipPast(s, i+2, "*/")
+			continue
+		case (c == 'e' || c == 'E') && i+1 < len(s) && s[i+1] == '\'':
+			i = skipEscaped(s, i+1)
+		case c == '\'' || c == '"':
+			i = skipQuoted(s, i, c)
+		case c == '$' && dollarTag(s, i) != "":
+			tag := dollarTag(s, i)
+			i = skipPast(s, i+len(tag), tag)
+		case isWordChar(c) && !(c >= '0' && c <= '9'):
+			for i < len(s) && (isWordChar(s[i]) || s[i] == '$') {
+				i++
+			}
+			toks = append(toks, token{text: s[start:i], word: strings.ToLower(s[start:i]), start: start, end: i})
+			continue
+		case isWordChar(c):
+			for i < len(s) && (isWordChar(s[i]) || s[i] == '.') {
+				i++
+			}
+		default:
+			i++
+		}
+		toks = append(toks, token{text: s[start:i], start: start, end: i})
+	}
+	return toks
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+// skipQuoted returns the index after the quoted string or identifier starting
+// at i. A doubled quote character escapes it.
+func skipQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != quote {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(s)
+}
+
+// skipEscaped returns the index after the escape string whose quote starts at
+// i, in which backslashes escape characters.
+func skipEscaped(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '\'':
+			if j+1 < len(s) && s[j+1] == '\'' {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// skipPast returns the index after the first occurrence of end at or after i,
+// or the length of s if there is none.
+func skipPast(s string, i int, end string) int {
+	if j := strings.Index(s[i:], end); j >= 0 {
+		return i + j + len(end)
+	}
+	return len(s)
+}
+
+// dollarTag returns the tag, e.g. "$body$", of the dollar-quoted string
+// starting at i, or "" if there is none.
+func dollarTag(s string, i int) string {
+	j := i + 1
+	for j < len(s) && (s[j] == '_' || (s[j] >= 'a' && s[j] <= 'z') || (s[j] >= 'A' && s[j] <= 'Z') || (j > i+1 && s[j] >= '0' && s[j] <= '9')) {
+		j++
+	}
+	if j < len(s) && s[j] == '$' {
+		return s[i : j+1]
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privacy_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/privacy"
+)
+
+func TestRewrite(t *testing.T) {
+	cfg := &privacy.AggregationConfig{MinGroupSize: 10}
+	tcs := []struct {
+		desc string
+		in   string
+		want string
+	}{
+		{
+			desc: "group by",
+			in:   "SELECT region, count(*) FROM orders GROUP BY region",
+			want: "SELECT region, count(*) FROM orders GROUP BY region HAVING count(*) >= 10",
+		},
+		{
+			desc: "no group by",
+			in:   "SELECT avg(amount) FROM orders WHERE region = $1;",
+			want: "SELECT avg(amount) FROM orders WHERE region = $1 HAVING count(*) >= 10",
+		},
+		{
+			desc: "existing having",
+			in:   "SELECT region, sum(amount) FROM orders GROUP BY region HAVING sum(amount) > 100 ORDER BY 2 DESC LIMIT 5",
+			want: "SELECT region, sum(amount) FROM orders GROUP BY region HAVING (sum(amount) > 100) AND count(*) >= 10 ORDER BY 2 DESC LIMIT 5",
+		},
+		{
+			desc: "keywords in strings and comments",
+			in:   "SELECT count(*) -- order by\nFROM orders WHERE note <> 'select min(x) union' AND tag = E'it\\'s' /* limit */",
+			want: "SELECT count(*) -- order by\nFROM orders WHERE note <> 'select min(x) union' AND tag = E'it\\'s' /* limit */ HAVING count(*) >= 10",
+		},
+		{
+			desc: "nested parentheses",
+			in:   "SELECT extract(year FROM created), count(DISTINCT (customer)) FROM orders GROUP BY 1 ORDER BY 1",
+			want: "SELECT extract(year FROM created), count(DISTINCT (customer)) FROM orders GROUP BY 1 HAVING count(*) >= 10 ORDER BY 1",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := cfg.Rewrite(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.Statement != tc.want {
+				t.Fatalf("incorrect statement: got %q, want %q", got.Statement, tc.want)
+			}
+		})
+	}
+}
+
+func TestRewriteRejects(t *testing.T) {
+	cfg := &privacy.AggregationConfig{MinGroupSize: 10}
+	tcs := []struct {
+		desc string
+		in   string
+		want string
+	}{
+		{desc: "not a select", in: "DELETE FROM orders", want: "only SELECT"},
+		{desc: "subquery", in: "SELECT count(*) FROM (SELECT * FROM orders) o", want: "subqueries"},
+		{desc: "cte", in: "SELECT count(*) FROM orders WITH x", want: "common table expressions"},
+		{desc: "union", in: "SELECT count(*) FROM a UNION ALL SELECT count(*) FROM b", want: "set operations"},
+		{desc: "window", in: "SELECT count(*) OVER () FROM orders", want: "window functions"},
+		{desc: "min", in: "SELECT min(amount) FROM orders", want: "aggregate function min"},
+		{desc: "multiple statements", in: "SELECT count(*) FROM a; DROP TABLE a", want: "single statement"},
+		{desc: "query function", in: "SELECT count(*), query_to_xml('x', true, true, '') FROM a", want: "function query_to_xml"},
+		{desc: "case in aggregate", in: "SELECT sum(CASE WHEN id = 42 THEN salary ELSE 0 END) FROM staff", want: "aggregate function sum must be columns"},
+		{desc: "filter clause", in: "SELECT sum(salary) FILTER (WHERE id = 42) FROM staff", want: "FILTER clauses"},
+		{desc: "comparison in aggregate", in: "SELECT sum(salary * (id = 42)::int) FROM staff", want: "aggregate function sum must be columns"},
+		{desc: "function in aggregate", in: "SELECT avg(salary * sign(abs(id - 42))) FROM staff", want: "aggregate function avg must be columns"},
+		{desc: "conditional function in aggregate", in: "SELECT count(nullif(id, 42)) FROM staff", want: "aggregate function count must be columns"},
+		{desc: "aggregate in having", in: "SELECT count(*) FROM staff HAVING sum(CASE WHEN id = 42 THEN 1 END) > 0", want: "aggregate function sum must be columns"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := cfg.Rewrite(tc.in)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestRewriteAggregateArguments(t *testing.T) {
+	plain := &privacy.AggregationConfig{MinGroupSize: 10}
+	for _, in := range []string{
+		"SELECT count(*), count(DISTINCT o.customer), sum(o.amount::numeric) FROM orders o",
+		`SELECT avg("Amount") FROM orders`,
+	} {
+		if _, err := plain.Rewrite(in); err != nil {
+			t.Errorf("Rewrite(%q) unexpected error: %s", in, err)
+		}
+	}
+
+	// with noise, aggregates may be applied to expressions
+	noisy := &privacy.AggregationConfig{MinGroupSize: 10, NoiseScale: 1}
+	for _, in := range []string{
+		"SELECT sum(CASE WHEN paid THEN amount ELSE 0 END) FROM orders",
+		"SELECT count(*) FILTER (WHERE paid) FROM orders",
+	} {
+		if _, err := noisy.Rewrite(in); err != nil {
+			t.Errorf("Rewrite(%q) unexpected error: %s", in, err)
+		}
+	}
+}
+
+func TestRewriteAllowedAggregates(t *testing.T) {
+	cfg := &privacy.AggregationConfig{MinGroupSize: 5, Aggregates: []string{"count", "MAX"}}
+	if _, err := cfg.Rewrite("SELECT max(amount) FROM orders"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := cfg.Rewrite("SELECT sum(amount) FROM orders"); err == nil {
+		t.Fatalf("expected sum to be rejected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  privacy.AggregationConfig
+		ok   bool
+	}{
+		{desc: "valid", cfg: privacy.AggregationConfig{MinGroupSize: 10, NoiseScale: 1}, ok: true},
+		{desc: "group size", cfg: privacy.AggregationConfig{MinGroupSize: 1}},
+		{desc: "negative noise", cfg: privacy.AggregationConfig{MinGroupSize: 10, NoiseScale: -1}},
+		{desc: "unknown aggregate", cfg: privacy.AggregationConfig{MinGroupSize: 10, Aggregates: []string{"median"}}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := tc.cfg.Validate(); (err == nil) != tc.ok {
+				t.Fatalf("unexpected result: %v", err)
+			}
+		})
+	}
+}
+
+func TestNoise(t *testing.T) {
+	cfg := &privacy.AggregationConfig{MinGroupSize: 10, NoiseScale: 2}
+	q, err := cfg.Rewrite("SELECT region, count(*), avg(amount) FROM orders GROUP BY region")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := q.Noise(0, "emea"); got != "emea" {
+		t.Fatalf("grouping column was changed: %v", got)
+	}
+	var sum float64
+	const n = 2000
+	for range n {
+		v, ok := q.Noise(1, int64(1000)).(int64)
+		if !ok {
+			t.Fatalf("count is no longer an integer")
+		}
+		sum += float64(v)
+	}
+	if mean := sum / n; math.Abs(mean-1000) > 1 {
+		t.Fatalf("noise is biased: mean %g", mean)
+	}
+	if _, ok := q.Noise(2, 3.5).(float64); !ok {
+		t.Fatalf("average is no longer a float")
+	}
+
+	plain, _ := (&privacy.AggregationConfig{MinGroupSize: 10}).Rewrite("SELECT count(*) FROM orders")
+	if got := plain.Noise(0, int64(7)); got != int64(7) {
+		t.Fatalf("noise added without a scale: %v", got)
+	}
+}
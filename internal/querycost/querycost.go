@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querycost rejects the queries agents write on a source when the
+// planner estimates they cost more than the source allows, before they run.
+package querycost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Limits are the largest estimates of the queries a source runs for agents.
+// Zero leaves an estimate unlimited.
+type Limits struct {
+	// MaxCost is the largest total cost estimated by the planner, in its own
+	// units.
+	MaxCost float64 `yaml:"maxCost"`
+	// MaxRows is the most rows the planner may estimate a query returns.
+	MaxRows float64 `yaml:"maxRows"`
+}
+
+// Limited is implemented by sources that limit the cost of queries.
+type Limited interface {
+	CostLimits() *Limits
+}
+
+// FromSource returns the cost limits of a source, if it has them.
+func FromSource(s any) *Limits {
+	if l, ok := s.(Limited); ok {
+		return l.CostLimits()
+	}
+	return nil
+}
+
+// Validate checks the configuration of a source's limits.
+func (l *Limits) Validate() error {
+	if l.MaxCost < 0 || l.MaxRows < 0 {
+		return fmt.Errorf("costLimits must not be negative")
+	}
+	if l.MaxCost == 0 && l.MaxRows == 0 {
+		return fmt.Errorf("costLimits requires maxCost, maxRows or both")
+	}
+	return nil
+}
+
+// Estimate is the planner's estimate of a query.
+type Estimate struct {
+	Cost float64 `json:"cost"`
+	Rows float64 `json:"rows"`
+}
+
+// ExceededError is returned for queries whose estimate exceeds the limits.
+type ExceededError struct {
+	Estimate Estimate
+	Limits   Limits
+}
+
+func (e *ExceededError) Error() string {
+	var over []string
+	if e.Limits.MaxCost > 0 && e.Estimate.Cost > e.Limits.MaxCost {
+		over = append(over, fmt.Sprintf("a cost of %.0f", e.Limits.MaxCost))
+	}
+	if e.Limits.MaxRows > 0 && e.Estimate.Rows > e.Limits.MaxRows {
+		over = append(over, fmt.Sprintf("%.0f rows", e.Limits.MaxRows))
+	}
+	return fmt.Sprintf("the query is estimated to cost %.0f and return %.0f rows, over the limit of %s; narrow it, e.g. with more selective filters, aggregates or a LIMIT",
+		e.Estimate.Cost, e.Estimate.Rows, strings.Join(over, " and "))
+}
+
+// Check returns an ExceededError if e exceeds the limits.
+func (l *Limits) Check(e Estimate) error {
+	if (l.MaxCost > 0 && e.Cost > l.MaxCost) || (l.MaxRows > 0 && e.Rows > l.MaxRows) {
+		return &ExceededError{Estimate: e, Limits: *l}
+	}
+	return nil
+}
+
+// PostgresEstimate returns the estimate of the root of a plan returned by
+// EXPLAIN (FORMAT JSON).
+func PostgresEstimate(plan []byte) (Estimate, error) {
+	var explain []struct {
+		Plan struct {
+			TotalCost float64 `json:"Total Cost"`
+			PlanRows  float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explain); err != nil {
+		return Estimate{}, fmt.Errorf("unable to parse query plan: %w", err)
+	}
+	if len(explain) == 0 {
+		return Estimate{}, fmt.Errorf("query plan is empty")
+	}
+	return Estimate{Cost: explain[0].Plan.TotalCost, Rows: explain[0].Plan.PlanRows}, nil
+}
+
+// Beginner begins transactions, e.g. a *pgxpool.Pool.
+type Beginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// CheckPostgres plans sql, without running it, and returns an ExceededError if
+// its estimate exceeds the limits. The statement is planned in a read-only
+// transaction that is rolled back, as a single statement. Statements that
+// can't be planned, e.g. DDL, are rejected.
+func (l *Limits) CheckPostgres(ctx context.Context, db Beginner, sql string, args ...any) error {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+
+	var plan []byte
+	args = append([]any{pgx.QueryExecModeExec}, args...)
+	if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+sql, args...).Scan(&plan); err != nil {
+		return fmt.Errorf("unable to estimate the cost of the query: %w", err)
+	}
+	e, err := PostgresEstimate(plan)
+	if err != nil {
+		return err
+	}
+	return l.Check(e)
+}
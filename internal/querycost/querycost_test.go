@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycost_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/querycost"
+)
+
+func TestPostgresEstimate(t *testing.T) {
+	plan := `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 18334.5, "Plan Rows": 1000000, "Plans": []}}]`
+	got, err := querycost.PostgresEstimate([]byte(plan))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := (querycost.Estimate{Cost: 18334.5, Rows: 1000000}); got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if _, err := querycost.PostgresEstimate([]byte(`[]`)); err == nil {
+		t.Fatalf("expected error for an empty plan")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tcs := []struct {
+		desc     string
+		limits   querycost.Limits
+		estimate querycost.Estimate
+		want     string
+	}{
+		{
+			desc:     "under limits",
+			limits:   querycost.Limits{MaxCost: 1000, MaxRows: 100},
+			estimate: querycost.Estimate{Cost: 999, Rows: 100},
+		},
+		{
+			desc:     "over cost",
+			limits:   querycost.Limits{MaxCost: 1000},
+			estimate: querycost.Estimate{Cost: 18334.5, Rows: 10},
+			want:     "the query is estimated to cost 18334 and return 10 rows, over the limit of a cost of 1000; narrow it, e.g. with more selective filters, aggregates or a LIMIT",
+		},
+		{
+			desc:     "over both",
+			limits:   querycost.Limits{MaxCost: 1000, MaxRows: 100},
+			estimate: querycost.Estimate{Cost: 5000, Rows: 20000},
+			want:     "the query is estimated to cost 5000 and return 20000 rows, over the limit of a cost of 1000 and 100 rows; narrow it, e.g. with more selective filters, aggregates or a LIMIT",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.limits.Check(tc.estimate)
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			var exceeded *querycost.ExceededError
+			if !errors.As(err, &exceeded) {
+				t.Fatalf("expected an ExceededError, got %v", err)
+			}
+			if err.Error() != tc.want {
+				t.Fatalf("got %q, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&querycost.Limits{MaxRows: 10}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := (&querycost.Limits{}).Validate(); err == nil {
+		t.Fatalf("expected error without limits")
+	}
+	if err := (&querycost.Limits{MaxCost: -1}).Validate(); err == nil {
+		t.Fatalf("expected error for negative limits")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querylog logs the statements sources run, and the slow ones apart,
+// so that DBAs can see what agents actually run.
+package querylog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// SlowQueryCountName is the metric counting the statements slower than the
+// threshold of their source.
+const SlowQueryCountName = "toolbox.source.query.slow.count"
+
+// pingStatement is the statement of pool health checks, which aren't logged.
+const pingStatement = "-- ping"
+
+// Logger logs the statements of a source.
+type Logger struct {
+	source string
+	// all logs every statement at INFO.
+	all bool
+	// slow, if set, logs the statements running longer at WARN.
+	slow      time.Duration
+	slowCount metric.Int64Counter
+}
+
+// New returns the logger of a source, which logs every statement if all is
+// set, and the statements running longer than slowQueryThreshold, e.g.
+// "500ms", if it's set. It returns nil if neither is set.
+func New(source string, all bool, slowQueryThreshold string) (*Logger, error) {
+	l := &Logger{source: source, all: all}
+	if slowQueryThreshold != "" {
+		d, err := time.ParseDuration(slowQueryThreshold)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slowQueryThreshold: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("slowQueryThreshold must be positive, got %s", d)
+		}
+		l.slow = d
+		l.slowCount, err = otel.Meter(telemetry.MetricName).Int64Counter(
+			SlowQueryCountName,
+			metric.WithDescription("Number of statements slower than the threshold of their source."),
+			metric.WithUnit("{query}"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create %s metric: %w", SlowQueryCountName, err)
+		}
+	}
+	if !l.all && l.slow == 0 {
+		return nil, nil
+	}
+	return l, nil
+}
+
+// Query is a statement run by a source.
+type Query struct {
+	SQL  string
+	Args []any
+	// Rows is the number of rows returned or affected.
+	Rows     int64
+	Duration time.Duration
+	Err      error
+}
+
+// Log logs q, if it's logged.
+func (l *Logger) Log(ctx context.Context, q Query) {
+	slow := l.slow > 0 && q.Duration >= l.slow
+	if !slow && !l.all {
+		return
+	}
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return
+	}
+	if slow {
+		l.slowCount.Add(ctx, 1, metric.WithAttributes(attribute.String("toolbox.source.name", l.source)))
+		logger.WarnContext(ctx, "slow "+l.message(ctx, q))
+		return
+	}
+	logger.InfoContext(ctx, l.message(ctx, q))
+}
+
+func (l *Logger) message(ctx context.Context, q Query) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "query on source %q took %s", l.source, q.Duration)
+	if q.Err != nil {
+		fmt.Fprintf(&b, " and failed: %s", q.Err)
+	} else {
+		fmt.Fprintf(&b, " for %d rows", q.Rows)
+	}
+	if inv, ok := invocations.FromContext(ctx); ok {
+		fmt.Fprintf(&b, ", tool %q", inv.Tool)
+		if inv.Caller != "" {
+			fmt.Fprintf(&b, ", caller %q", inv.Caller)
+		}
+	}
+	fmt.Fprintf(&b, ": %s", q.SQL)
+	if len(q.Args) > 0 {
+		fmt.Fprintf(&b, " params %s", strings.Join(Fingerprints(q.Args), ","))
+	}
+	return b.String()
+}
+
+// Fingerprints returns a short hash of each bound parameter, which tells
+// calls with the same values apart from others without logging the values.
+func Fingerprints(args []any) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == nil {
+			out[i] = "null"
+			continue
+		}
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%T:%v", a, a)))
+		out[i] = hex.EncodeToString(sum[:4])
+	}
+	return out
+}
+
+type startKey struct{}
+
+type start struct {
+	sql  string
+	args []any
+	at   time.Time
+}
+
+var _ pgx.QueryTracer = &Logger{}
+
+// TraceQueryStart records the start of a statement of a pgx connection.
+func (l *Logger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if data.SQL == pingStatement {
+		return ctx
+	}
+	return context.WithValue(ctx, startKey{}, start{sql: data.SQL, args: data.Args, at: time.Now()})
+}
+
+// TraceQueryEnd logs a statement of a pgx connection, once its result is
+// read.
+func (l *Logger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	s, ok := ctx.Value(startKey{}).(start)
+	if !ok {
+		return
+	}
+	l.Log(ctx, Query{SQL: s.sql, Args: s.args, Rows: data.CommandTag.RowsAffected(), Duration: time.Since(s.at), Err: data.Err})
+}
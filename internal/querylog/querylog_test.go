@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/querylog"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func newContext(t *testing.T) (context.Context, *bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	logger, err := log.NewStdLogger(&out, &errOut, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	return util.WithLogger(context.Background(), logger), &out, &errOut
+}
+
+func TestNew(t *testing.T) {
+	l, err := querylog.New("my-pg-source", false, "")
+	if err != nil || l != nil {
+		t.Fatalf("expected no logger, got %v, %v", l, err)
+	}
+	if _, err := querylog.New("my-pg-source", false, "fast"); err == nil {
+		t.Fatalf("expected error for an invalid threshold")
+	}
+	if _, err := querylog.New("my-pg-source", false, "-1s"); err == nil {
+		t.Fatalf("expected error for a negative threshold")
+	}
+}
+
+func TestLog(t *testing.T) {
+	l, err := querylog.New("my-pg-source", true, "1s")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx, out, errOut := newContext(t)
+	ctx, done, err := invocations.NewTracker(0).Begin(ctx, "search_orders", "alice")
+	if err != nil {
+		t.Fatalf("unable to begin invocation: %s", err)
+	}
+	defer done()
+
+	l.Log(ctx, querylog.Query{SQL: "SELECT * FROM orders WHERE id = $1", Args: []any{int64(42)}, Rows: 1, Duration: 20 * time.Millisecond})
+	got := out.String()
+	for _, want := range []string{`query on source \"my-pg-source\" took 20ms for 1 rows, tool \"search_orders\", caller \"alice\": SELECT * FROM orders WHERE id = $1 params `, querylog.Fingerprints([]any{int64(42)})[0]} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("log %q doesn't contain %q", got, want)
+		}
+	}
+
+	l.Log(ctx, querylog.Query{SQL: "SELECT count(*) FROM orders", Rows: 1, Duration: 2 * time.Second})
+	if got := errOut.String(); !strings.Contains(got, "WARN") || !strings.Contains(got, "slow query on source") {
+		t.Fatalf("expected a slow query warning, got %q", got)
+	}
+}
+
+func TestLogSlowOnly(t *testing.T) {
+	l, err := querylog.New("my-pg-source", false, "100ms")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx, out, errOut := newContext(t)
+
+	ctx = l.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	l.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+	if out.Len() != 0 || errOut.Len() != 0 {
+		t.Fatalf("fast query was logged: %q %q", out.String(), errOut.String())
+	}
+}
+
+func TestFingerprints(t *testing.T) {
+	a := querylog.Fingerprints([]any{"alice@example.com", nil, int64(1)})
+	b := querylog.Fingerprints([]any{"alice@example.com", nil, "1"})
+	if a[0] != b[0] || a[1] != "null" || a[2] == b[2] {
+		t.Fatalf("unexpected fingerprints: %v, %v", a, b)
+	}
+	if len(a[0]) != 8 {
+		t.Fatalf("unexpected fingerprint length: %q", a[0])
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// defaultHistoryLimit is the number of finished invocations listed by
+// /admin/invocations unless a limit is given.
+const defaultHistoryLimit = 50
+
+// adminRouter creates a router that represents the routes under /admin. Every
+// route requires the admin token as a bearer token.
+func adminRouter(s *Server) (chi.Router, error) {
+	r := chi.NewRouter()
+
+	r.Use(middleware.StripSlashes)
+	r.Use(render.SetContentType(render.ContentTypeJSON))
+	r.Use(func(next http.Handler) http.Handler { return adminAuth(s, next) })
+
+	r.Get("/tools", func(w http.ResponseWriter, r *http.Request) { adminToolsHandler(s, w, r) })
+	r.Route("/tools/{toolName}", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { adminToolHandler(s, w, r) })
+		r.Post("/disable", func(w http.ResponseWriter, r *http.Request) { adminDisableHandler(s, w, r) })
+		r.Post("/enable", func(w http.ResponseWriter, r *http.Request) { adminEnableHandler(s, w, r) })
+	})
+	r.Get("/sources", func(w http.ResponseWriter, r *http.Request) { adminSourcesHandler(s, w, r) })
+	r.Get("/invocations", func(w http.ResponseWriter, r *http.Request) { adminInvocationsHandler(s, w, r) })
+	r.Get("/journal", func(w http.ResponseWriter, r *http.Request) { adminJournalHandler(s, w, r) })
+	r.Post("/journal/{invocationId}/resolve", func(w http.ResponseWriter, r *http.Request) { adminResolveHandler(s, w, r) })
+	r.Get("/approvals", func(w http.ResponseWriter, r *http.Request) { adminApprovalsHandler(s, w, r) })
+	r.Post("/approvals/{jobId}/approve", func(w http.ResponseWriter, r *http.Request) { adminApproveHandler(s, w, r) })
+	r.Post("/approvals/{jobId}/reject", func(w http.ResponseWriter, r *http.Request) { adminRejectHandler(s, w, r) })
+
+	return r, nil
+}
+
+// adminAuth rejects requests that don't carry the admin token.
+func adminAuth(s *Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="toolbox-admin"`)
+			_ = render.Render(w, r, newErrResponse(fmt.Errorf("a valid admin token is required"), http.StatusUnauthorized))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminTool is a loaded tool as listed by the admin API.
+type adminTool struct {
+	Name        string                `json:"name"`
+	Toolsets    []string              `json:"toolsets"`
+	Disabled    *invocations.Disabled `json:"disabled,omitempty"`
+	Manifest    tools.Manifest        `json:"manifest"`
+	McpManifest tools.McpManifest     `json:"mcpManifest"`
+}
+
+func (s *Server) adminTool(name string, tool tools.Tool, toolsets map[string]tools.Toolset) adminTool {
+	t := adminTool{
+		Name:        name,
+		Toolsets:    make([]string, 0),
+		Manifest:    tool.Manifest(),
+		McpManifest: tool.McpManifest(),
+	}
+	for tsName, ts := range toolsets {
+		if _, ok := ts.Manifest.ToolsManifest[name]; ok && tsName != "" {
+			t.Toolsets = append(t.Toolsets, tsName)
+		}
+	}
+	slices.Sort(t.Toolsets)
+	if d, ok := s.invocations.IsDisabled(name); ok {
+		t.Disabled = &d
+	}
+	return t
+}
+
+// adminToolsHandler lists every loaded tool with its manifests.
+func adminToolsHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	toolsMap := s.ResourceMgr.GetToolsMap()
+	toolsets := s.ResourceMgr.GetToolsetsMap()
+	names := make([]string, 0, len(toolsMap))
+	for name := range toolsMap {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	out := make([]adminTool, 0, len(names))
+	for _, name := range names {
+		out = append(out, s.adminTool(name, toolsMap[name], toolsets))
+	}
+	render.JSON(w, r, map[string]any{"tools": out})
+}
+
+// adminToolHandler returns a single loaded tool.
+func adminToolHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	toolName := chi.URLParam(r, "toolName")
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		_ = render.Render(w, r, newErrResponse(fmt.Errorf("tool with name %q does not exist", toolName), http.StatusNotFound))
+		return
+	}
+	render.JSON(w, r, s.adminTool(toolName, tool, s.ResourceMgr.GetToolsetsMap()))
+}
+
+// adminDisableHandler disables a tool until it's enabled again. Disabled tools
+// stay listed, but their invocations fail with a TOOL_DISABLED error. Disabling
+// outlasts reloads of the tools file, but not restarts.
+func adminDisableHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	toolName := chi.URLParam(r, "toolName")
+	if _, ok := s.ResourceMgr.GetTool(toolName); !ok {
+		_ = render.Render(w, r, newErrResponse(fmt.Errorf("tool with name %q does not exist", toolName), http.StatusNotFound))
+		return
+	}
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := util.DecodeJSON(r.Body, &body); err != nil {
+			_ = render.Render(w, r, newErrResponse(fmt.Errorf("request body was invalid JSON: %w", err), http.StatusBadRequest))
+			return
+		}
+	}
+	s.invocations.Disable(toolName, body.Reason)
+	s.logger.WarnContext(r.Context(), fmt.Sprintf("tool %q was disabled through the admin API", toolName))
+	adminToolHandler(s, w, r)
+}
+
+// adminEnableHandler enables a disabled tool.
+func adminEnableHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	toolName := chi.URLParam(r, "toolName")
+	if _, ok := s.invocations.IsDisabled(toolName); ok {
+		s.invocations.Enable(toolName)
+		s.logger.InfoContext(r.Context(), fmt.Sprintf("tool %q was enabled through the admin API", toolName))
+	}
+	if _, ok := s.ResourceMgr.GetTool(toolName); !ok {
+		// a tool removed while it was disabled can still be enabled
+		render.NoContent(w, r)
+		return
+	}
+	adminToolHandler(s, w, r)
+}
+
+// adminSource is a loaded source as listed by the admin API.
+type adminSource struct {
+	Name string     `json:"name"`
+	Kind string     `json:"kind"`
+	Pool *poolStats `json:"pool,omitempty"`
+}
+
+// poolStats are the statistics of a source's connection pool.
+type poolStats struct {
+	// Open is the number of open connections, both in use and idle.
+	Open  int `json:"open"`
+	InUse int `json:"inUse"`
+	Idle  int `json:"idle"`
+	// MaxOpen is the maximum number of open connections, 0 if unlimited.
+	MaxOpen int `json:"maxOpen"`
+	// WaitCount is the number of times a connection had to be waited for.
+	WaitCount int64 `json:"waitCount"`
+	// WaitDuration is the total time spent waiting for connections.
+	WaitDuration string `json:"waitDuration"`
+}
+
+// sourcePoolStats returns the statistics of the connection pool of a source,
+// or nil if it doesn't have one.
+func sourcePoolStats(src sources.Source) *poolStats {
+	var db *sql.DB
+	switch s := src.(type) {
+	case interface{ PostgresPool() *pgxpool.Pool }:
+		stat := s.PostgresPool().Stat()
+		return &poolStats{
+			Open:         int(stat.TotalConns()),
+			InUse:        int(stat.AcquiredConns()),
+			Idle:         int(stat.IdleConns()),
+			MaxOpen:      int(stat.MaxConns()),
+			WaitCount:    stat.EmptyAcquireCount(),
+			WaitDuration: stat.AcquireDuration().String(),
+		}
+	case interface{ MySQLPool() *sql.DB }:
+		db = s.MySQLPool()
+	case interface{ MSSQLDB() *sql.DB }:
+		db = s.MSSQLDB()
+	case interface{ SQLiteDB() *sql.DB }:
+		db = s.SQLiteDB()
+	case interface{ DuckDb() *sql.DB }:
+		db = s.DuckDb()
+	}
+	if db == nil {
+		return nil
+	}
+	stat := db.Stats()
+	return &poolStats{
+		Open:         stat.OpenConnections,
+		InUse:        stat.InUse,
+		Idle:         stat.Idle,
+		MaxOpen:      stat.MaxOpenConnections,
+		WaitCount:    stat.WaitCount,
+		WaitDuration: stat.WaitDuration.String(),
+	}
+}
+
+// adminSourcesHandler lists every loaded source with its pool statistics.
+func adminSourcesHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	sourcesMap := s.ResourceMgr.GetSourcesMap()
+	names := make([]string, 0, len(sourcesMap))
+	for name := range sourcesMap {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	out := make([]adminSource, 0, len(names))
+	for _, name := range names {
+		src := sourcesMap[name]
+		out = append(out, adminSource{Name: name, Kind: src.SourceKind(), Pool: sourcePoolStats(src)})
+	}
+	render.JSON(w, r, map[string]any{"sources": out})
+}
+
+// adminInvocationsHandler lists the in-flight invocations of every caller and
+// the most recently finished invocations, newest first.
+func adminInvocationsHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	limit := defaultHistoryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			_ = render.Render(w, r, newErrResponse(fmt.Errorf("invalid limit %q", v), http.StatusBadRequest))
+			return
+		}
+		limit = n
+	}
+	render.JSON(w, r, map[string]any{
+		"inflight": s.invocations.SnapshotAll(),
+		"history":  s.invocations.History(limit),
+	})
+}
+
+// adminJournal is an open invocation journal as listed by the admin API.
+type adminJournal struct {
+	Name    string               `json:"name"`
+	Pending []tools.JournalEntry `json:"pending"`
+}
+
+// adminJournalHandler lists the journaled invocations that haven't finished.
+// After a restart, these are the invocations whose writes may or may not have
+// been committed.
+func adminJournalHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	out := make([]adminJournal, 0)
+	for _, j := range tools.Journals() {
+		out = append(out, adminJournal{Name: j.Name(), Pending: j.Pending()})
+	}
+	render.JSON(w, r, map[string]any{"journals": out})
+}
+
+// adminResolveHandler records the outcome of a journaled invocation that
+// didn't finish, once an operator has reconciled it with the database.
+func adminResolveHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "invocationId")
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := util.DecodeJSON(r.Body, &body); err != nil {
+		_ = render.Render(w, r, newErrResponse(fmt.Errorf("request body was invalid JSON: %w", err), http.StatusBadRequest))
+		return
+	}
+	if body.Status != tools.JournalCommitted && body.Status != tools.JournalFailed {
+		_ = render.Render(w, r, newErrResponse(fmt.Errorf("invalid status %q, must be %q or %q", body.Status, tools.JournalCommitted, tools.JournalFailed), http.StatusBadRequest))
+		return
+	}
+	for _, j := range tools.Journals() {
+		if !slices.ContainsFunc(j.Pending(), func(e tools.JournalEntry) bool { return e.ID == id }) {
+			continue
+		}
+		e, err := j.Resolve(id, body.Status)
+		if err != nil {
+			_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+			return
+		}
+		s.logger.InfoContext(r.Context(), fmt.Sprintf("invocation %q of tool %q was resolved as %s through the admin API", id, e.Tool, e.Status))
+		render.JSON(w, r, e)
+		return
+	}
+	_ = render.Render(w, r, newErrResponse(fmt.Errorf("no unfinished invocation %q is journaled", id), http.StatusNotFound))
+}
@@ -0,0 +1,303 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestAdminEndpoints(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "admin", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	admin := map[string]string{"Authorization": "Bearer " + fakeAdminToken}
+	invoke := func() int {
+		resp, _, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/api/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		return resp.StatusCode
+	}
+
+	for _, header := range []map[string]string{nil, {"Authorization": "Bearer wrong"}} {
+		resp, _, err := runRequest(ts, http.MethodGet, "/admin/tools", nil, header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected status %d without a valid token, got %d", http.StatusUnauthorized, resp.StatusCode)
+		}
+	}
+
+	resp, body, err := runRequest(ts, http.MethodGet, "/admin/tools", nil, admin)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+	}
+	var listed struct {
+		Tools []adminTool `json:"tools"`
+	}
+	if err := json.Unmarshal(body, &listed); err != nil {
+		t.Fatalf("unable to parse tools: %s", err)
+	}
+	if len(listed.Tools) != 2 || listed.Tools[0].Name != tool1.Name || listed.Tools[0].McpManifest.Name != tool1.Name {
+		t.Fatalf("unexpected tools: %s", body)
+	}
+	if got := listed.Tools[0].Toolsets; len(got) != 1 || got[0] != "tool1_only" {
+		t.Errorf("unexpected toolsets of %q: %v", tool1.Name, got)
+	}
+
+	// disabled tools can't be invoked until they're enabled again
+	resp, body, err = runRequest(ts, http.MethodPost, fmt.Sprintf("/admin/tools/%s/disable", tool1.Name), bytes.NewBuffer([]byte(`{"reason": "slow queries"}`)), admin)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var disabled adminTool
+	if err := json.Unmarshal(body, &disabled); err != nil || disabled.Disabled == nil || disabled.Disabled.Reason != "slow queries" {
+		t.Fatalf("unexpected response to disabling a tool (status %d): %s", resp.StatusCode, body)
+	}
+	if status := invoke(); status != http.StatusBadRequest {
+		t.Errorf("expected invoking a disabled tool to fail with %d, got %d", http.StatusBadRequest, status)
+	}
+	if _, _, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/admin/tools/%s/enable", tool1.Name), nil, admin); err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if status := invoke(); status != http.StatusOK {
+		t.Errorf("expected invoking an enabled tool to succeed, got %d", status)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodGet, "/admin/invocations?limit=5", nil, admin)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var invs struct {
+		History []struct {
+			Tool string `json:"tool"`
+		} `json:"history"`
+	}
+	if err := json.Unmarshal(body, &invs); err != nil {
+		t.Fatalf("unable to parse invocations (status %d): %s", resp.StatusCode, err)
+	}
+	if len(invs.History) != 1 || invs.History[0].Tool != tool1.Name {
+		t.Errorf("unexpected invocation history: %s", body)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodGet, "/admin/sources", nil, admin)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != "{\"sources\":[]}\n" {
+		t.Errorf("unexpected sources (status %d): %s", resp.StatusCode, body)
+	}
+
+	if resp, _, err := runRequest(ts, http.MethodGet, "/admin/tools/unknown", nil, admin); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected an unknown tool to be not found")
+	}
+
+	resp, body, err = runRequest(ts, http.MethodGet, "/admin/journal", nil, admin)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response listing journals: %s", body)
+	}
+	resp, _, err = runRequest(ts, http.MethodPost, "/admin/journal/jrn-unknown/resolve", bytes.NewBuffer([]byte(`{"status": "maybe"}`)), admin)
+	if err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected an invalid status to be rejected")
+	}
+	resp, _, err = runRequest(ts, http.MethodPost, "/admin/journal/jrn-unknown/resolve", bytes.NewBuffer([]byte(`{"status": "failed"}`)), admin)
+	if err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected an unknown invocation to be not found")
+	}
+}
+
+func TestAdminApprovals(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	gated, err := tools.ApprovalToolConfig{ToolConfig: mockToolConfig{tool: MockTool{Name: "delete_order"}}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	toolsMap["delete_order"] = gated
+	r, shutdown := setUpServer(t, "admin", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	admin := map[string]string{"Authorization": "Bearer " + fakeAdminToken}
+	request := func() tools.JobStatus {
+		resp, body, err := runRequest(ts, http.MethodPost, "/api/tool/delete_order/invoke", bytes.NewBufferString(`{}`), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var invokeResp resultResponse
+		if err := json.Unmarshal(body, &invokeResp); err != nil {
+			t.Fatalf("unable to parse response (status %d): %s", resp.StatusCode, err)
+		}
+		var job tools.JobStatus
+		if err := json.Unmarshal([]byte(invokeResp.Result), &job); err != nil {
+			t.Fatalf("unable to parse job: %s", err)
+		}
+		if job.Status != tools.JobPendingApproval {
+			t.Fatalf("expected a job pending approval, got %s", invokeResp.Result)
+		}
+		return job
+	}
+
+	approved := request()
+	rejected := request()
+	resp, body, err := runRequest(ts, http.MethodGet, "/admin/approvals", nil, admin)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var listed struct {
+		Pending []tools.PendingApproval `json:"pending"`
+	}
+	if err := json.Unmarshal(body, &listed); err != nil {
+		t.Fatalf("unable to parse approvals (status %d): %s", resp.StatusCode, err)
+	}
+	if len(listed.Pending) != 2 || listed.Pending[0].JobID != approved.JobID || listed.Pending[0].Tool != "delete_order" {
+		t.Fatalf("unexpected pending approvals: %s", body)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodPost, "/admin/approvals/"+approved.JobID+"/approve", nil, admin)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response approving a job (status %d): %s", resp.StatusCode, body)
+	}
+	var res tools.JobResult
+	for deadline := time.Now().Add(5 * time.Second); res.Status != tools.JobSucceeded; {
+		if time.Now().After(deadline) {
+			t.Fatalf("approved job didn't succeed, last result: %#v", res)
+		}
+		_, body, err = runRequest(ts, http.MethodGet, "/api/job/"+approved.JobID+"/result", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if err := json.Unmarshal(body, &res); err != nil {
+			t.Fatalf("unable to parse result: %s", err)
+		}
+	}
+	if !slices.Equal(res.Rows, []any{"delete_order"}) {
+		t.Fatalf("unexpected result: %s", body)
+	}
+
+	resp, body, err = runRequest(ts, http.MethodPost, "/admin/approvals/"+rejected.JobID+"/reject", bytes.NewBufferString(`{"reason": "wrong order"}`), admin)
+	if err != nil || resp.StatusCode != http.StatusOK || !strings.Contains(string(body), tools.ErrorCodeApprovalRejected) || !strings.Contains(string(body), "wrong order") {
+		t.Fatalf("unexpected response rejecting a job (status %d): %s", resp.StatusCode, body)
+	}
+
+	// decisions are only taken once
+	resp, _, err = runRequest(ts, http.MethodPost, "/admin/approvals/"+approved.JobID+"/reject", nil, admin)
+	if err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a decided job to be not found, got %d", resp.StatusCode)
+	}
+
+	// webhook callbacks need the token of the job
+	pending := request()
+	for _, tc := range []struct {
+		body       string
+		wantStatus int
+	}{
+		{body: `{"token": "wrong"}`, wantStatus: http.StatusBadRequest},
+		{body: `{"token": "wrong", "approved": true}`, wantStatus: http.StatusUnauthorized},
+	} {
+		resp, body, err = runRequest(ts, http.MethodPost, "/api/job/"+pending.JobID+"/approval", bytes.NewBufferString(tc.body), nil)
+		if err != nil || resp.StatusCode != tc.wantStatus {
+			t.Errorf("unexpected response to callback %s: %d: %s", tc.body, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestApprovalWebhook(t *testing.T) {
+	received := make(chan tools.ApprovalRequest, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req tools.ApprovalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unable to parse approval request: %s", err)
+		}
+		received <- req
+	}))
+	defer hook.Close()
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	s := &Server{logger: testLogger}
+	want := tools.ApprovalRequest{
+		PendingApproval: tools.PendingApproval{JobID: "job-1", Tool: "delete_order", Caller: "alice", Params: map[string]any{"id": float64(42)}},
+		Token:           "secret",
+	}
+	s.notifyApproval(context.Background(), hook.URL, want)
+	select {
+	case got := <-received:
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect approval request (-want +got):\n%s", diff)
+		}
+	default:
+		t.Fatalf("the webhook wasn't notified")
+	}
+}
+
+func TestCheckApprovals(t *testing.T) {
+	toolsMap, _ := setUpResources(t, []MockTool{tool1, tool2})
+	// the approval is found under the wrappers applied around it
+	gated, err := tools.QueryTagsToolConfig{
+		ToolConfig: tools.ApprovalToolConfig{ToolConfig: mockToolConfig{tool: MockTool{Name: "delete_order"}}},
+		Tags:       map[string]string{"team": "ops"},
+	}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	withApproval := maps.Clone(toolsMap)
+	withApproval["delete_order"] = gated
+
+	tcs := []struct {
+		desc     string
+		server   *Server
+		toolsMap map[string]tools.Tool
+		wantErr  bool
+	}{
+		{desc: "no approvals", server: &Server{}, toolsMap: toolsMap},
+		{desc: "no way to decide", server: &Server{}, toolsMap: withApproval, wantErr: true},
+		{desc: "admin API", server: &Server{adminToken: fakeAdminToken}, toolsMap: withApproval},
+		{desc: "webhook", server: &Server{approvalWebhook: "http://127.0.0.1:5001/approvals"}, toolsMap: withApproval},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.server.CheckApprovals(tc.toolsMap)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: got %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultAnonymousRequestsPerMinute = 60
+	defaultAnonymousBurst             = 10
+	// anonymousClientIdleTimeout is how long the rate limiter of a client
+	// that stopped sending requests is kept.
+	anonymousClientIdleTimeout = 10 * time.Minute
+)
+
+// AnonymousAccessConfig configures the anonymous access tier. While it is
+// enabled, callers without a verified auth token may only invoke the tools of
+// the tier, at a limited rate, and every other tool requires a token from one
+// of the configured auth services.
+type AnonymousAccessConfig struct {
+	// Tools are the tools anonymous callers may invoke. They should be
+	// read-only, and must not require authentication.
+	Tools []string `yaml:"tools"`
+	// RateLimit limits how often each anonymous client may invoke the tools.
+	RateLimit AnonymousRateLimit `yaml:"rateLimit"`
+	// TrustedProxies are the addresses or CIDR ranges of the proxies in front
+	// of Toolbox. Requests they forward are rate limited by the client
+	// address in their X-Forwarded-For header rather than by the proxy's.
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+// AnonymousRateLimit is a per-client token bucket.
+type AnonymousRateLimit struct {
+	// RequestsPerMinute is the sustained rate. Defaults to 60.
+	RequestsPerMinute int `yaml:"requestsPerMinute"`
+	// Burst is the number of requests allowed at once. Defaults to 10.
+	Burst int `yaml:"burst"`
+}
+
+// anonymousTier enforces an AnonymousAccessConfig. A nil anonymousTier means
+// the tier is disabled and anonymous callers are treated as before.
+type anonymousTier struct {
+	tools             []string
+	requestsPerMinute int
+	burst             int
+	trustedProxies    []netip.Prefix
+	now               func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*anonymousClient
+	lastSweep time.Time
+}
+
+type anonymousClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newAnonymousTier validates cfg against the tools it refers to. It returns a
+// nil tier if cfg is nil.
+func newAnonymousTier(cfg *AnonymousAccessConfig, toolsMap map[string]tools.Tool) (*anonymousTier, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.Tools) == 0 {
+		return nil, fmt.Errorf("anonymousAccess must list at least one tool")
+	}
+	for _, name := range cfg.Tools {
+		tool, ok := toolsMap[name]
+		if !ok {
+			return nil, fmt.Errorf("anonymousAccess refers to tool %q, which does not exist", name)
+		}
+		if !tool.Authorized([]string{}) {
+			return nil, fmt.Errorf("tool %q requires authentication and can't be invoked anonymously", name)
+		}
+		for _, p := range tool.Manifest().Parameters {
+			if len(p.AuthServices) > 0 {
+				return nil, fmt.Errorf("tool %q has authenticated parameter %q and can't be invoked anonymously", name, p.Name)
+			}
+		}
+	}
+	rl := cfg.RateLimit
+	if rl.RequestsPerMinute < 0 || rl.Burst < 0 {
+		return nil, fmt.Errorf("anonymousAccess rateLimit must not be negative")
+	}
+	if rl.RequestsPerMinute == 0 {
+		rl.RequestsPerMinute = defaultAnonymousRequestsPerMinute
+	}
+	if rl.Burst == 0 {
+		rl.Burst = defaultAnonymousBurst
+	}
+	trustedProxies := make([]netip.Prefix, 0, len(cfg.TrustedProxies))
+	for _, p := range cfg.TrustedProxies {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(p)
+			if addrErr != nil {
+				return nil, fmt.Errorf("anonymousAccess trustedProxies entry %q is neither an address nor a CIDR range", p)
+			}
+			prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		}
+		trustedProxies = append(trustedProxies, prefix.Masked())
+	}
+	return &anonymousTier{
+		tools:             slices.Clone(cfg.Tools),
+		requestsPerMinute: rl.RequestsPerMinute,
+		burst:             rl.Burst,
+		trustedProxies:    trustedProxies,
+		now:               time.Now,
+		clients:           make(map[string]*anonymousClient),
+	}, nil
+}
+
+// admit checks that an anonymous client may invoke the named tool.
+func (a *anonymousTier) admit(toolName, client string) error {
+	if a == nil {
+		return nil
+	}
+	if !slices.Contains(a.tools, toolName) {
+		err := fmt.Errorf("tool %q requires authentication: anonymous callers may only invoke the tools of the anonymous access tier", toolName)
+		return tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized, "", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if now.Sub(a.lastSweep) > anonymousClientIdleTimeout {
+		for k, c := range a.clients {
+			if now.Sub(c.lastSeen) > anonymousClientIdleTimeout {
+				delete(a.clients, k)
+			}
+		}
+		a.lastSweep = now
+	}
+	c, ok := a.clients[client]
+	if !ok {
+		c = &anonymousClient{limiter: rate.NewLimiter(rate.Limit(float64(a.requestsPerMinute)/60), a.burst)}
+		a.clients[client] = c
+	}
+	c.lastSeen = now
+	if !c.limiter.AllowN(now, 1) {
+		err := fmt.Errorf("anonymous rate limit of %d requests per minute exceeded", a.requestsPerMinute)
+		te := tools.NewToolError(tools.ErrorCategoryRateLimit, tools.ErrorCodeRateLimited, "", err)
+		te.Retryable = true
+		return te
+	}
+	return nil
+}
+
+// filterToolset returns the tools of toolset that are in the tier.
+func (a *anonymousTier) filterToolset(toolset tools.Toolset) tools.Toolset {
+	if a == nil {
+		return toolset
+	}
+	filtered := tools.Toolset{
+		Name: toolset.Name,
+		Manifest: tools.ToolsetManifest{
+			ServerVersion: toolset.Manifest.ServerVersion,
+			ToolsManifest: make(map[string]tools.Manifest),
+		},
+		McpManifest: make([]tools.McpManifest, 0),
+	}
+	for name, m := range toolset.Manifest.ToolsManifest {
+		if slices.Contains(a.tools, name) {
+			filtered.Manifest.ToolsManifest[name] = m
+		}
+	}
+	for _, m := range toolset.McpManifest {
+		if slices.Contains(a.tools, m.Name) {
+			filtered.McpManifest = append(filtered.McpManifest, m)
+		}
+	}
+	return filtered
+}
+
+// clientHost returns the host part of a remote address, so all connections
+// from the same client share a rate limit.
+func clientHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// client returns the address an anonymous request is rate limited by: the
+// host of remoteAddr, or for requests forwarded by trusted proxies, the last
+// address of their X-Forwarded-For header that isn't a trusted proxy. Each
+// proxy appends the address it received the request from, so the addresses
+// before it may have been made up by the client.
+func (a *anonymousTier) client(remoteAddr string, header http.Header) string {
+	host := clientHost(remoteAddr)
+	if a == nil || !a.trustedProxy(host) {
+		return host
+	}
+	var hops []string
+	for _, v := range header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	if len(hops) == 0 {
+		return host
+	}
+	for i := len(hops) - 1; i > 0; i-- {
+		if !a.trustedProxy(hops[i]) {
+			return hops[i]
+		}
+	}
+	return hops[0]
+}
+
+// trustedProxy reports whether addr is the address of a trusted proxy.
+func (a *anonymousTier) trustedProxy(addr string) bool {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, p := range a.trustedProxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type anonymousClientKey struct{}
+
+// withAnonymousClient marks ctx as a request of a remote MCP client, which has
+// no way of authenticating and is subject to the anonymous access tier.
+func withAnonymousClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, anonymousClientKey{}, client)
+}
+
+// anonymousClientFromContext returns the client set by withAnonymousClient.
+// MCP stdio sessions are local and aren't subject to the tier.
+func anonymousClientFromContext(ctx context.Context) (string, bool) {
+	client, ok := ctx.Value(anonymousClientKey{}).(string)
+	return client, ok
+}
+
+// mcpToolName returns the name of the tool called by an MCP tools/call request.
+func mcpToolName(body []byte) string {
+	var req struct {
+		Params struct {
+			Name string `json:"name"`
+		} `json:"params"`
+	}
+	_ = json.Unmarshal(body, &req)
+	return req.Params.Name
+}
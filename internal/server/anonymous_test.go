@@ -0,0 +1,261 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestNewAnonymousTier(t *testing.T) {
+	authParamTool := MockTool{
+		Name: "auth_param",
+		Params: tools.Parameters{
+			tools.NewStringParameterWithAuth("email", "user email", []tools.ParamAuthService{{Name: "my-google-auth", Field: "email"}}),
+		},
+	}
+	toolsMap, _ := setUpResources(t, []MockTool{tool1, tool2, authParamTool})
+
+	tcs := []struct {
+		desc    string
+		cfg     *AnonymousAccessConfig
+		wantErr string
+	}{
+		{
+			desc: "disabled",
+		},
+		{
+			desc: "valid",
+			cfg:  &AnonymousAccessConfig{Tools: []string{tool1.Name}, RateLimit: AnonymousRateLimit{RequestsPerMinute: 5}},
+		},
+		{
+			desc:    "no tools",
+			cfg:     &AnonymousAccessConfig{},
+			wantErr: "at least one tool",
+		},
+		{
+			desc:    "unknown tool",
+			cfg:     &AnonymousAccessConfig{Tools: []string{"missing"}},
+			wantErr: `tool "missing", which does not exist`,
+		},
+		{
+			desc:    "authenticated parameter",
+			cfg:     &AnonymousAccessConfig{Tools: []string{authParamTool.Name}},
+			wantErr: `authenticated parameter "email"`,
+		},
+		{
+			desc:    "invalid trusted proxy",
+			cfg:     &AnonymousAccessConfig{Tools: []string{tool1.Name}, TrustedProxies: []string{"10.0.0.0/33"}},
+			wantErr: `trustedProxies entry "10.0.0.0/33"`,
+		},
+		{
+			desc:    "negative rate limit",
+			cfg:     &AnonymousAccessConfig{Tools: []string{tool1.Name}, RateLimit: AnonymousRateLimit{Burst: -1}},
+			wantErr: "must not be negative",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tier, err := newAnonymousTier(tc.cfg, toolsMap)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (tc.cfg == nil) != (tier == nil) {
+				t.Fatalf("unexpected tier: %v", tier)
+			}
+		})
+	}
+}
+
+func TestAnonymousTierAdmit(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	tier, err := newAnonymousTier(&AnonymousAccessConfig{
+		Tools:     []string{tool1.Name},
+		RateLimit: AnonymousRateLimit{RequestsPerMinute: 60, Burst: 2},
+	}, toolsMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	now := time.Unix(1700000000, 0)
+	tier.now = func() time.Time { return now }
+
+	wantCode := func(err error, code string) {
+		t.Helper()
+		if code == "" {
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			return
+		}
+		var te *tools.ToolError
+		if !errors.As(err, &te) || te.Code != code {
+			t.Fatalf("unexpected error: got %v, want code %s", err, code)
+		}
+	}
+
+	wantCode(tier.admit(tool2.Name, "10.0.0.1"), tools.ErrorCodeUnauthorized)
+	wantCode(tier.admit(tool1.Name, "10.0.0.1"), "")
+	wantCode(tier.admit(tool1.Name, "10.0.0.1"), "")
+	wantCode(tier.admit(tool1.Name, "10.0.0.1"), tools.ErrorCodeRateLimited)
+	// clients are limited separately
+	wantCode(tier.admit(tool1.Name, "10.0.0.2"), "")
+	// one request per second is refilled
+	now = now.Add(time.Second)
+	wantCode(tier.admit(tool1.Name, "10.0.0.1"), "")
+
+	// the tier is disabled without a config
+	var disabled *anonymousTier
+	wantCode(disabled.admit(tool2.Name, "10.0.0.1"), "")
+
+	filtered := tier.filterToolset(toolsets[""])
+	if len(filtered.McpManifest) != 1 || filtered.McpManifest[0].Name != tool1.Name {
+		t.Fatalf("unexpected mcp manifest: %v", filtered.McpManifest)
+	}
+	if _, ok := filtered.Manifest.ToolsManifest[tool2.Name]; ok {
+		t.Fatalf("unexpected tool %q in manifest", tool2.Name)
+	}
+}
+
+func TestAnonymousTierClient(t *testing.T) {
+	toolsMap, _ := setUpResources(t, []MockTool{tool1, tool2})
+	tier, err := newAnonymousTier(&AnonymousAccessConfig{
+		Tools:          []string{tool1.Name},
+		TrustedProxies: []string{"10.0.0.0/8", "35.191.0.1"},
+	}, toolsMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	untrusting, err := newAnonymousTier(&AnonymousAccessConfig{Tools: []string{tool1.Name}}, toolsMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc         string
+		tier         *anonymousTier
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{
+			desc:       "direct",
+			tier:       tier,
+			remoteAddr: "203.0.113.7:52100",
+			want:       "203.0.113.7",
+		},
+		{
+			desc:         "untrusted peer",
+			tier:         tier,
+			remoteAddr:   "203.0.113.7:52100",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "203.0.113.7",
+		},
+		{
+			desc:         "no trusted proxies",
+			tier:         untrusting,
+			remoteAddr:   "10.0.0.5:52100",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "10.0.0.5",
+		},
+		{
+			desc:         "disabled",
+			remoteAddr:   "10.0.0.5:52100",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "10.0.0.5",
+		},
+		{
+			desc:         "trusted proxy",
+			tier:         tier,
+			remoteAddr:   "10.0.0.5:52100",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "198.51.100.1",
+		},
+		{
+			desc:         "chain of proxies",
+			tier:         tier,
+			remoteAddr:   "10.0.0.5:52100",
+			forwardedFor: []string{"198.51.100.1, 35.191.0.1", "10.1.2.3"},
+			want:         "198.51.100.1",
+		},
+		{
+			desc:         "spoofed addresses",
+			tier:         tier,
+			remoteAddr:   "[::ffff:10.0.0.5]:52100",
+			forwardedFor: []string{"10.9.9.9, 192.0.2.66, 198.51.100.1, 35.191.0.1"},
+			want:         "198.51.100.1",
+		},
+		{
+			desc:       "trusted proxy without header",
+			tier:       tier,
+			remoteAddr: "10.0.0.5:52100",
+			want:       "10.0.0.5",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			header := http.Header{}
+			for _, v := range tc.forwardedFor {
+				header.Add("X-Forwarded-For", v)
+			}
+			if got := tc.tier.client(tc.remoteAddr, header); got != tc.want {
+				t.Fatalf("unexpected client: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetAnonymousAccess(t *testing.T) {
+	toolsMap, _ := setUpResources(t, []MockTool{tool1, tool2})
+	s := &Server{}
+
+	if err := s.SetAnonymousAccess(&AnonymousAccessConfig{Tools: []string{tool1.Name}}, toolsMap); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := s.anonymousAccess().admit(tool2.Name, "127.0.0.1"); err == nil {
+		t.Fatalf("expected %q to require authentication", tool2.Name)
+	}
+
+	// an invalid config leaves the current tier in place
+	if err := s.SetAnonymousAccess(&AnonymousAccessConfig{Tools: []string{"missing"}}, toolsMap); err == nil {
+		t.Fatalf("expected an error for an unknown tool")
+	}
+	if err := s.anonymousAccess().admit(tool1.Name, "127.0.0.1"); err != nil {
+		t.Fatalf("unexpected error for a tool of the current tier: %s", err)
+	}
+	if err := s.SetAnonymousAccess(&AnonymousAccessConfig{Tools: []string{tool2.Name}}, toolsMap); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := s.anonymousAccess().admit(tool2.Name, "127.0.0.1"); err != nil {
+		t.Fatalf("unexpected error for a tool of the reloaded tier: %s", err)
+	}
+
+	if err := s.SetAnonymousAccess(nil, toolsMap); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s.anonymousAccess() != nil {
+		t.Fatalf("expected anonymous access to be disabled")
+	}
+}
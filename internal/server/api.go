@@ -15,13 +15,17 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
@@ -37,12 +41,24 @@ func apiRouter(s *Server) (chi.Router, error) {
 	r.Use(middleware.StripSlashes)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
+	r.Get("/capabilities", func(w http.ResponseWriter, r *http.Request) { capabilitiesHandler(s, w, r) })
+	r.Get("/load", func(w http.ResponseWriter, r *http.Request) { loadHandler(s, w, r) })
+	r.Get("/stats", func(w http.ResponseWriter, r *http.Request) { statsHandler(s, w, r) })
+	r.Get("/manifest", func(w http.ResponseWriter, r *http.Request) { manifestHandler(s, w, r) })
+	r.Get("/manifest/{toolsetName}", func(w http.ResponseWriter, r *http.Request) { manifestHandler(s, w, r) })
 	r.Get("/toolset", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
 	r.Get("/toolset/{toolsetName}", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
 
 	r.Route("/tool/{toolName}", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
 		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+		r.Post("/preview", func(w http.ResponseWriter, r *http.Request) { toolPreviewHandler(s, w, r) })
+	})
+	r.Route("/job/{jobId}", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { jobStatusHandler(s, w, r) })
+		r.Get("/result", func(w http.ResponseWriter, r *http.Request) { jobResultHandler(s, w, r) })
+		r.Post("/cancel", func(w http.ResponseWriter, r *http.Request) { jobCancelHandler(s, w, r) })
+		r.Post("/approval", func(w http.ResponseWriter, r *http.Request) { approvalCallbackHandler(s, w, r) })
 	})
 
 	return r, nil
@@ -82,6 +98,10 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
+	if toolset.ManifestJSON != nil {
+		renderRawJSON(w, toolset.ManifestJSON)
+		return
+	}
 	render.JSON(w, r, toolset.Manifest)
 }
 
@@ -118,7 +138,13 @@ func toolGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
-	// TODO: this can be optimized later with some caching
+	// use the manifest precomputed with the default toolset, if available
+	if toolset, ok := s.ResourceMgr.GetToolset(""); ok {
+		if b, ok := toolset.ToolManifestsJSON[toolName]; ok {
+			renderRawJSON(w, b)
+			return
+		}
+	}
 	m := tools.ToolsetManifest{
 		ServerVersion: s.version,
 		ToolsManifest: map[string]tools.Manifest{
@@ -134,6 +160,8 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/invoke")
 	r = r.WithContext(ctx)
 	ctx = util.WithLogger(r.Context(), s.logger)
+	ctx = util.WithInvocationTracker(ctx, s.invocations)
+	ctx = tools.WithJobStore(ctx, s.jobs)
 
 	toolName := chi.URLParam(r, "toolName")
 	s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
@@ -161,50 +189,42 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		_ = render.Render(w, r, newToolErrResponse(tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeToolNotFound, "", err)))
 		return
 	}
 
 	// Tool authentication
 	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	claimsFromAuth := make(map[string]map[string]any)
-	for _, aS := range s.ResourceMgr.GetAuthServiceMap() {
-		claims, err := aS.GetClaimsFromHeader(ctx, r.Header)
-		if err != nil {
-			s.logger.DebugContext(ctx, err.Error())
-			continue
-		}
-		if claims == nil {
-			// authService not present in header
-			continue
-		}
-		claimsFromAuth[aS.GetName()] = claims
-	}
+	claimsFromAuth, delegations := s.claimsFromHeader(ctx, r.Header)
 
 	// Tool authorization check
-	verifiedAuthServices := make([]string, len(claimsFromAuth))
-	i := 0
-	for k := range claimsFromAuth {
-		verifiedAuthServices[i] = k
-		i++
-	}
+	verifiedAuthServices := verifiedAuthServiceNames(claimsFromAuth)
 
 	// Check if any of the specified auth services is verified
 	isAuthorized := tool.Authorized(verifiedAuthServices)
 	if !isAuthorized {
 		err = fmt.Errorf("tool invocation not authorized. Please make sure your specify correct auth headers")
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		_ = render.Render(w, r, newToolErrResponse(tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized, "", err)))
 		return
 	}
+	if len(claimsFromAuth) == 0 {
+		anonymous := s.anonymousAccess()
+		if err = anonymous.admit(toolName, anonymous.client(r.RemoteAddr, r.Header)); err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newToolErrResponse(tools.ClassifyError(err, tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized)))
+			return
+		}
+	}
 	s.logger.DebugContext(ctx, "tool invocation authorized")
+	s.auditDelegations(ctx, toolName, delegations)
 
 	var data map[string]any
 	if err = util.DecodeJSON(r.Body, &data); err != nil {
 		render.Status(r, http.StatusBadRequest)
 		err = fmt.Errorf("request body was invalid JSON: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		_ = render.Render(w, r, newToolErrResponse(tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeInvalidRequest, "", err)))
 		return
 	}
 
@@ -212,35 +232,187 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		_ = render.Render(w, r, newToolErrResponse(tools.ClassifyError(err, tools.ErrorCategoryValidation, tools.ErrorCodeInvalidParameters)))
 		return
 	}
 	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
+	ctx, done, err := s.invocations.BeginTool(ctx, toolName, tool, callerFromClaims(claimsFromAuth, delegations))
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newToolErrResponse(tools.ClassifyError(err, tools.ErrorCategoryInternal, tools.ErrorCodeInternal)))
+		return
+	}
+	// the slot is released even if the tool panics
+	defer done()
+	if st, ok := tools.AsStreamingTool(tool); ok && acceptsEventStream(r) {
+		_ = s.streamResult(ctx, w, r, st, params)
+		return
+	}
 	res, err := tool.Invoke(ctx, params)
+	s.invocations.RecordError(ctx, err)
 	if err != nil {
 		err = fmt.Errorf("error while invoking tool: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		_ = render.Render(w, r, newToolErrResponse(tools.ClassifyError(err, tools.ErrorCategoryQuery, tools.ErrorCodeQueryFailed)))
 		return
 	}
 
+	// clients that can decode columnar results ask for them with a header
+	encoding := ""
+	if r.Header.Get(resultEncodingHeader) == tools.ResultEncodingColumnar {
+		if c, ok := tools.EncodeColumnar(res); ok {
+			res, encoding = c, tools.ResultEncodingColumnar
+			w.Header().Set(resultEncodingHeader, encoding)
+		}
+	}
+
 	resMarshal, err := json.Marshal(res)
 	if err != nil {
 		err = fmt.Errorf("unable to marshal result: %w", err)
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		_ = render.Render(w, r, newToolErrResponse(tools.NewToolError(tools.ErrorCategoryInternal, tools.ErrorCodeInternal, "", err)))
+		return
+	}
+
+	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal), Encoding: encoding})
+}
+
+// toolPreviewHandler handles the API request to render the statement an
+// invocation of a Tool would run, without running it. The request is
+// authorized and its parameters parsed as for an invocation.
+func toolPreviewHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/preview")
+	r = r.WithContext(ctx)
+	ctx = util.WithLogger(r.Context(), s.logger)
+	defer span.End()
+
+	toolName := chi.URLParam(r, "toolName")
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		err := fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newToolErrResponse(tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeToolNotFound, "", err)))
+		return
+	}
+
+	claimsFromAuth, _ := s.claimsFromHeader(ctx, r.Header)
+	if !tool.Authorized(verifiedAuthServiceNames(claimsFromAuth)) {
+		err := fmt.Errorf("tool preview not authorized. Please make sure your specify correct auth headers")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newToolErrResponse(tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized, "", err)))
+		return
+	}
+
+	var data map[string]any
+	if err := util.DecodeJSON(r.Body, &data); err != nil {
+		err = fmt.Errorf("request body was invalid JSON: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newToolErrResponse(tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeInvalidRequest, "", err)))
+		return
+	}
+	params, err := tool.ParseParams(data, claimsFromAuth)
+	if err != nil {
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newToolErrResponse(tools.ClassifyError(err, tools.ErrorCategoryValidation, tools.ErrorCodeInvalidParameters)))
 		return
 	}
 
-	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal)})
+	preview, err := tools.PreviewTool(ctx, tool, params)
+	if err != nil {
+		err = fmt.Errorf("unable to preview tool %q: %w", toolName, err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newToolErrResponse(tools.ClassifyError(err, tools.ErrorCategoryValidation, tools.ErrorCodeInvalidRequest)))
+		return
+	}
+	render.JSON(w, r, preview)
+}
+
+// claimsFromHeader returns the claims of every auth service with a valid token
+// in the header, keyed by the name of the auth service. End-user assertions
+// passed by allowed delegates replace the claims of their auth service and
+// are returned as delegations.
+func (s *Server) claimsFromHeader(ctx context.Context, h http.Header) (map[string]map[string]any, []delegation) {
+	authServices := s.ResourceMgr.GetAuthServiceMap()
+	claimsFromAuth := make(map[string]map[string]any)
+	for _, aS := range authServices {
+		start := time.Now()
+		claims, err := aS.GetClaimsFromHeader(ctx, h)
+		if err != nil || claims != nil {
+			status := "success"
+			if err != nil {
+				status = "failure"
+			}
+			attrs := metric.WithAttributes(
+				attribute.String("toolbox.auth.name", aS.GetName()),
+				attribute.String("toolbox.auth.kind", aS.AuthServiceKind()),
+				attribute.String("toolbox.operation.status", status),
+			)
+			s.instrumentation.AuthVerify.Add(ctx, 1, attrs)
+			s.instrumentation.AuthVerifyDuration.Record(ctx, float64(time.Since(start).Microseconds())/1000, attrs)
+		}
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			continue
+		}
+		if claims == nil {
+			// authService not present in header
+			continue
+		}
+		claimsFromAuth[aS.GetName()] = claims
+	}
+	delegations := s.verifyDelegations(ctx, h, authServices, claimsFromAuth)
+	return claimsFromAuth, delegations
+}
+
+// verifiedAuthServiceNames returns the names of the auth services in claimsFromAuth.
+func verifiedAuthServiceNames(claimsFromAuth map[string]map[string]any) []string {
+	verifiedAuthServices := make([]string, 0, len(claimsFromAuth))
+	for k := range claimsFromAuth {
+		verifiedAuthServices = append(verifiedAuthServices, k)
+	}
+	return verifiedAuthServices
+}
+
+// callerFromClaims identifies the caller of an invocation from its verified
+// auth claims. It returns an empty string for unauthenticated callers.
+// An end user that a delegate acts on behalf of is the caller.
+func callerFromClaims(claimsFromAuth map[string]map[string]any, delegations []delegation) string {
+	if len(delegations) > 0 {
+		return delegations[0].AuthService + ":" + delegations[0].User
+	}
+	names := make([]string, 0, len(claimsFromAuth))
+	for name := range claimsFromAuth {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if id := auth.Identity(claimsFromAuth[name]); id != "" {
+			return name + ":" + id
+		}
+	}
+	return ""
+}
+
+// renderRawJSON writes an already serialized JSON payload.
+func renderRawJSON(w http.ResponseWriter, b []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
 }
 
 var _ render.Renderer = &resultResponse{} // Renderer interface for managing response payloads.
 
+// resultEncodingHeader is the header clients send to ask for an encoding of the
+// result, and that the server sends back if the result was encoded.
+const resultEncodingHeader = "Toolbox-Result-Encoding"
+
 // resultResponse is the response sent back when the tool was invocated successfully.
 type resultResponse struct {
-	Result string `json:"result"` // result of tool invocation
+	Result   string `json:"result"`             // result of tool invocation
+	Encoding string `json:"encoding,omitempty"` // encoding of the result, if any
 }
 
 // Render renders a single payload and respond to the client request.
@@ -262,13 +434,48 @@ func newErrResponse(err error, code int) *errResponse {
 	}
 }
 
+// newToolErrResponse is newErrResponse for classified tool errors, with the
+// status code derived from the error's category.
+func newToolErrResponse(te *tools.ToolError) *errResponse {
+	e := newErrResponse(te, httpStatusFromToolError(te))
+	e.Details = te
+	return e
+}
+
+// httpStatusFromToolError maps the category of a tool error to a status code.
+func httpStatusFromToolError(te *tools.ToolError) int {
+	switch te.Category {
+	case tools.ErrorCategoryValidation:
+		if te.Code == tools.ErrorCodeToolNotFound || te.Code == tools.ErrorCodeJobNotFound {
+			return http.StatusNotFound
+		}
+		return http.StatusBadRequest
+	case tools.ErrorCategoryAuth:
+		if te.Code == tools.ErrorCodePolicyDenied || te.Code == tools.ErrorCodeApprovalRejected {
+			return http.StatusForbidden
+		}
+		return http.StatusUnauthorized
+	case tools.ErrorCategoryRateLimit:
+		return http.StatusTooManyRequests
+	case tools.ErrorCategoryTimeout:
+		return http.StatusGatewayTimeout
+	case tools.ErrorCategorySourceUnavailable:
+		return http.StatusServiceUnavailable
+	case tools.ErrorCategoryQuery:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // errResponse is the response sent back when an error has been encountered.
 type errResponse struct {
 	Err            error `json:"-"` // low-level runtime error
 	HTTPStatusCode int   `json:"-"` // http response status code
 
-	StatusText string `json:"status"`          // user-level status message
-	ErrorText  string `json:"error,omitempty"` // application-level error message, for debugging
+	StatusText string           `json:"status"`            // user-level status message
+	ErrorText  string           `json:"error,omitempty"`   // application-level error message, for debugging
+	Details    *tools.ToolError `json:"details,omitempty"` // classified error, for tool invocations
 }
 
 func (e *errResponse) Render(w http.ResponseWriter, r *http.Request) error {
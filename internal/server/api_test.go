@@ -16,13 +16,21 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -281,3 +289,520 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestToolInvokeEndpointErrors(t *testing.T) {
+	failTool := MockTool{Name: "fail", InvokeErr: fmt.Errorf("unable to execute query: %w", syscall.ECONNRESET)}
+	mockTools := []MockTool{tool2, failTool}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name        string
+		toolName    string
+		requestBody string
+		wantStatus  int
+		want        tools.ToolError
+	}{
+		{
+			name:        "invalid tool",
+			toolName:    "some_imaginary_tool",
+			requestBody: `{}`,
+			wantStatus:  http.StatusNotFound,
+			want: tools.ToolError{
+				Category: tools.ErrorCategoryValidation,
+				Code:     tools.ErrorCodeToolNotFound,
+				Detail:   "the tool does not exist",
+			},
+		},
+		{
+			name:        "invalid params",
+			toolName:    tool2.Name,
+			requestBody: `{"param1": 1}`,
+			wantStatus:  http.StatusBadRequest,
+			want: tools.ToolError{
+				Category: tools.ErrorCategoryValidation,
+				Code:     tools.ErrorCodeInvalidParameters,
+				Detail:   "the parameters of the invocation are invalid",
+			},
+		},
+		{
+			name:        "source unavailable",
+			toolName:    failTool.Name,
+			requestBody: `{}`,
+			wantStatus:  http.StatusServiceUnavailable,
+			want: tools.ToolError{
+				Category:  tools.ErrorCategorySourceUnavailable,
+				Code:      tools.ErrorCodeSourceUnavailable,
+				Detail:    "the source is unavailable",
+				Retryable: true,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), bytes.NewBufferString(tc.requestBody), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d, %s", resp.StatusCode, tc.wantStatus, string(body))
+			}
+			var got struct {
+				Details tools.ToolError `json:"details"`
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Details, cmpopts.IgnoreFields(tools.ToolError{}, "Err")); diff != "" {
+				t.Fatalf("unexpected error details (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// rowsMockTool is a MockTool whose result is a list of rows.
+type rowsMockTool struct {
+	MockTool
+	rows []any
+}
+
+func (t rowsMockTool) Invoke(context.Context, tools.ParamValues) (any, error) {
+	return t.rows, nil
+}
+
+func TestToolInvokeColumnarEncoding(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	rows := []any{
+		map[string]any{"id": 1, "region": "EU"},
+		map[string]any{"id": 2, "region": "EU"},
+		map[string]any{"id": 3, "region": "EU"},
+	}
+	toolsMap["rows"] = rowsMockTool{MockTool: MockTool{Name: "rows"}, rows: rows}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	columnar := map[string]string{"Toolbox-Result-Encoding": "columnar"}
+	tcs := []struct {
+		desc         string
+		tool         string
+		header       map[string]string
+		wantEncoding string
+		want         string
+	}{
+		{
+			desc:         "rows are encoded when asked",
+			tool:         "rows",
+			header:       columnar,
+			wantEncoding: "columnar",
+			want:         `{"encoding":"columnar","rowCount":3,"columns":[{"name":"id","values":[1,2,3]},{"name":"region","dictionary":["EU"],"indexes":[0,0,0]}]}`,
+		},
+		{
+			desc: "rows are not encoded by default",
+			tool: "rows",
+			want: `[{"id":1,"region":"EU"},{"id":2,"region":"EU"},{"id":3,"region":"EU"}]`,
+		},
+		{
+			desc:   "results that aren't rows are not encoded",
+			tool:   tool1.Name,
+			header: columnar,
+			want:   `["no_params"]`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.tool), bytes.NewBufferString(`{}`), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got resultResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response (status %d): %s", resp.StatusCode, body)
+			}
+			if got.Encoding != tc.wantEncoding || resp.Header.Get("Toolbox-Result-Encoding") != tc.wantEncoding {
+				t.Errorf("unexpected encoding: want %q, got %q", tc.wantEncoding, got.Encoding)
+			}
+			if got.Result != tc.want {
+				t.Errorf("unexpected result: want %s, got %s", tc.want, got.Result)
+			}
+		})
+	}
+}
+
+// previewMockTool is a MockTool that previews a templated statement.
+type previewMockTool struct {
+	MockTool
+	templateParams tools.Parameters
+	statement      string
+}
+
+func (t previewMockTool) ParseParams(data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(append(slices.Clone(t.templateParams), t.Params...), data, claimsMap)
+}
+
+func (t previewMockTool) Preview(_ context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	return tools.PreviewStatement(t.templateParams, t.Params, t.statement, params)
+}
+
+func TestToolPreview(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	toolsMap["preview"] = previewMockTool{
+		MockTool:       MockTool{Name: "preview", Params: tools.Parameters{tools.NewStringParameter("city", "some description")}},
+		templateParams: tools.Parameters{tools.NewStringParameter("table", "some description")},
+		statement:      "SELECT * FROM {{.table}} WHERE city = $1",
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	tcs := []struct {
+		desc       string
+		tool       string
+		body       string
+		wantStatus int
+		want       string
+	}{
+		{
+			desc:       "statement is rendered with placeholders",
+			tool:       "preview",
+			body:       `{"table": "hotels", "city": "Basel"}`,
+			wantStatus: http.StatusOK,
+			want:       `{"statement":"SELECT * FROM hotels WHERE city = $1","parameters":[{"name":"city","value":"Basel"}]}`,
+		},
+		{
+			desc:       "invalid parameters",
+			tool:       "preview",
+			body:       `{"table": "hotels"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc:       "tool without a statement",
+			tool:       tool1.Name,
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc:       "unknown tool",
+			tool:       "unknown",
+			body:       `{}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/preview", tc.tool), bytes.NewBufferString(tc.body), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status: want %d, got %d: %s", tc.wantStatus, resp.StatusCode, body)
+			}
+			if tc.want != "" && strings.TrimSpace(string(body)) != tc.want {
+				t.Fatalf("unexpected preview: want %s, got %s", tc.want, body)
+			}
+		})
+	}
+}
+
+func TestCapabilitiesEndpoint(t *testing.T) {
+	getCapabilities := func(t *testing.T, toolsMap map[string]tools.Tool, toolsets map[string]tools.Toolset) Capabilities {
+		t.Helper()
+		r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+		defer shutdown()
+		ts := runServer(r, false)
+		defer ts.Close()
+
+		resp, body, err := runRequest(ts, http.MethodGet, "/capabilities", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Logf("response body: %s", body)
+			t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var c Capabilities
+		if err := json.Unmarshal(body, &c); err != nil {
+			t.Fatalf("unable to parse Capabilities: %s", err)
+		}
+		return c
+	}
+
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	c := getCapabilities(t, toolsMap, toolsets)
+	if c.ServerVersion != fakeVersionString {
+		t.Fatalf("unexpected ServerVersion: want %q, got %q", fakeVersionString, c.ServerVersion)
+	}
+	if len(c.McpProtocolVersions) == 0 {
+		t.Fatalf("expected supported MCP protocol versions")
+	}
+	if !c.Features["dynamicReload"] {
+		t.Errorf("expected dynamicReload feature to be enabled")
+	}
+	for _, f := range []string{"invocationQueue", "asyncJobs", "approvals", "resultStreaming", "grpc"} {
+		if c.Features[f] {
+			t.Errorf("expected %s feature to be disabled", f)
+		}
+	}
+	wantLimits := Limits{
+		MaxManifestPageSize: maxManifestPageSize,
+		StreamStallTimeout:  "0s",
+		DrainTimeout:        "0s",
+		JobRetention:        tools.DefaultJobRetention.String(),
+		ApprovalTimeout:     tools.DefaultApprovalTimeout.String(),
+	}
+	if diff := cmp.Diff(wantLimits, c.Limits); diff != "" {
+		t.Errorf("incorrect limits (-want +got):\n%s", diff)
+	}
+
+	// features used by the configured tools are reported
+	async, err := tools.AsyncToolConfig{ToolConfig: mockToolConfig{tool: tool1}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	gated, err := tools.ApprovalToolConfig{ToolConfig: mockToolConfig{tool: tool2}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	c = getCapabilities(t, map[string]tools.Tool{tool1.Name: async, tool2.Name: gated}, toolsets)
+	for _, f := range []string{"asyncJobs", "approvals"} {
+		if !c.Features[f] {
+			t.Errorf("expected %s feature to be enabled", f)
+		}
+	}
+}
+
+func TestLoadEndpoint(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	if _, _, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil); err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	resp, body, err := runRequest(ts, http.MethodGet, "/load", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Logf("response body: %s", body)
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse load: %s", err)
+	}
+	for _, key := range []string{"running", "queued", "maxConcurrentInvocations", "utilization", "p95LatencyMs", "completed"} {
+		if _, ok := got[key].(float64); !ok {
+			t.Errorf("expected numeric %q in load, got %v", key, got)
+		}
+	}
+	if got["completed"] != float64(1) {
+		t.Errorf("expected 1 completed invocation, got %v", got["completed"])
+	}
+}
+
+func TestStatsEndpoint(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tool1.Name), bytes.NewBuffer([]byte(`{}`)), nil); err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+	}
+	resp, body, err := runRequest(ts, http.MethodGet, "/stats?window=24h", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Logf("response body: %s", body)
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	var got invocations.Stats
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse stats: %s", err)
+	}
+	if got.Window != "24h0m0s" || len(got.Tools) != 2 {
+		t.Fatalf("unexpected stats: %s", body)
+	}
+	// tools that weren't invoked are listed last
+	if got.Tools[0].Tool != tool1.Name || got.Tools[0].Invocations != 2 {
+		t.Errorf("unexpected stats of invoked tool: %+v", got.Tools[0])
+	}
+	if got.Tools[1].Tool != tool2.Name || got.Tools[1].Invocations != 0 || got.Tools[1].LastInvokedAt != nil {
+		t.Errorf("unexpected stats of unused tool: %+v", got.Tools[1])
+	}
+
+	resp, _, err = runRequest(ts, http.MethodGet, "/stats?window=soon", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected status code for an invalid window: want %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestManifestEndpoint(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// page through the default toolset one tool at a time
+	var got []string
+	path := "/manifest?pageSize=1&fields=description"
+	for range mockTools {
+		resp, body, err := runRequest(ts, http.MethodGet, path, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+		}
+		var m BulkManifest
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("unable to parse BulkManifest: %s", err)
+		}
+		if len(m.Tools) != 1 {
+			t.Fatalf("unexpected number of tools in page: want 1, got %d", len(m.Tools))
+		}
+		for name, fields := range m.Tools {
+			got = append(got, name)
+			if _, ok := fields["parameters"]; ok {
+				t.Errorf("unexpected field %q in manifest of %q", "parameters", name)
+			}
+		}
+		if m.NextPageToken == "" {
+			break
+		}
+		path = "/manifest?pageSize=1&fields=description&pageToken=" + m.NextPageToken
+	}
+	want := []string{tool3.Name, tool1.Name, tool2.Name}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected tools: want %q, got %q", want, got)
+	}
+
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+	}{
+		{name: "invalid toolset", path: "/manifest/some_imaginary_toolset", statusCode: http.StatusNotFound},
+		{name: "invalid field", path: "/manifest?fields=foo", statusCode: http.StatusBadRequest},
+		{name: "invalid page size", path: "/manifest?pageSize=0", statusCode: http.StatusBadRequest},
+		{name: "invalid page token", path: "/manifest?pageToken=foo", statusCode: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodGet, tc.path, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.statusCode {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.statusCode, resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+// mockToolConfig initializes to a prebuilt tool, so wrappers can be tested
+// against a MockTool.
+type mockToolConfig struct {
+	tool tools.Tool
+}
+
+func (c mockToolConfig) ToolConfigKind() string { return "mock" }
+func (c mockToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return c.tool, nil
+}
+
+func TestAsyncJobs(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	async, err := tools.AsyncToolConfig{ToolConfig: mockToolConfig{tool: MockTool{Name: "async"}}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	toolsMap["async"] = async
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/async/invoke", bytes.NewBufferString(`{}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status: %d: %s", resp.StatusCode, body)
+	}
+	var invokeResp resultResponse
+	if err := json.Unmarshal(body, &invokeResp); err != nil {
+		t.Fatalf("unable to parse response: %s", err)
+	}
+	var job tools.JobStatus
+	if err := json.Unmarshal([]byte(invokeResp.Result), &job); err != nil {
+		t.Fatalf("unable to parse job: %s", err)
+	}
+	if job.Status != tools.JobRunning || job.Tool != "async" {
+		t.Fatalf("expected a running job, got %s", invokeResp.Result)
+	}
+
+	var res tools.JobResult
+	for deadline := time.Now().Add(5 * time.Second); res.Status != tools.JobSucceeded; {
+		if time.Now().After(deadline) {
+			t.Fatalf("job didn't succeed, last result: %#v", res)
+		}
+		resp, body, err = runRequest(ts, http.MethodGet, "/job/"+job.JobID+"/result", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: %d: %s", resp.StatusCode, body)
+		}
+		if err := json.Unmarshal(body, &res); err != nil {
+			t.Fatalf("unable to parse result: %s", err)
+		}
+	}
+	if !slices.Equal(res.Rows, []any{"async"}) || res.NextOffset != 1 {
+		t.Fatalf("unexpected result: %s", body)
+	}
+
+	for _, path := range []string{"/job/" + job.JobID, "/job/" + job.JobID + "/cancel"} {
+		method := http.MethodGet
+		if strings.HasSuffix(path, "/cancel") {
+			method = http.MethodPost
+		}
+		resp, body, err = runRequest(ts, method, path, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"status":"succeeded"`) {
+			t.Fatalf("unexpected response for %s: %d: %s", path, resp.StatusCode, body)
+		}
+	}
+
+	// unknown jobs aren't found
+	resp, body, err = runRequest(ts, http.MethodGet, "/job/job-unknown", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusNotFound || !strings.Contains(string(body), tools.ErrorCodeJobNotFound) {
+		t.Fatalf("unexpected response for an unknown job: %d: %s", resp.StatusCode, body)
+	}
+}
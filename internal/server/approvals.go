@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// approvalWebhookTimeout is how long the approval webhook may take to accept
+// a notification.
+const approvalWebhookTimeout = 30 * time.Second
+
+// CheckApprovals returns an error if a tool of toolsMap requires approval,
+// but the server has neither the admin API nor an approval webhook to decide
+// it. Invocations of the tool would otherwise wait for the approval timeout.
+func (s *Server) CheckApprovals(toolsMap map[string]tools.Tool) error {
+	if s.adminToken != "" || s.approvalWebhook != "" {
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(toolsMap)) {
+		if tools.RequiresApproval(toolsMap[name]) {
+			return fmt.Errorf("tool %q requires approval, which can't be decided without --admin-token or --approval-webhook", name)
+		}
+	}
+	return nil
+}
+
+// notifyApproval POSTs an approval request to the approval webhook. Failed
+// notifications are logged; the invocation still waits for a decision through
+// the admin API.
+func (s *Server) notifyApproval(ctx context.Context, url string, req tools.ApprovalRequest) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), approvalWebhookTimeout)
+	defer cancel()
+	err := func() error {
+		body, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, respBody)
+		}
+		return nil
+	}()
+	if err != nil {
+		s.logger.ErrorContext(ctx, fmt.Sprintf("unable to notify the approval webhook of job %q: %s", req.JobID, err))
+	}
+}
+
+// adminApprovalsHandler lists the invocations waiting for approval, oldest
+// first.
+func adminApprovalsHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]any{"pending": s.jobs.PendingApprovals()})
+}
+
+// adminApproveHandler approves an invocation, which then runs as a job of its
+// caller.
+func adminApproveHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "jobId")
+	status, err := s.jobs.Approve(id)
+	if err != nil {
+		renderJobErr(s, w, r, err)
+		return
+	}
+	s.logger.InfoContext(r.Context(), fmt.Sprintf("job %q of tool %q was approved through the admin API", id, status.Tool))
+	render.JSON(w, r, status)
+}
+
+// adminRejectHandler rejects an invocation. Accepts an optional reason that is
+// returned to the caller.
+func adminRejectHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := util.DecodeJSON(r.Body, &body); err != nil {
+			_ = render.Render(w, r, newErrResponse(fmt.Errorf("request body was invalid JSON: %w", err), http.StatusBadRequest))
+			return
+		}
+	}
+	id := chi.URLParam(r, "jobId")
+	status, err := s.jobs.Reject(id, body.Reason)
+	if err != nil {
+		renderJobErr(s, w, r, err)
+		return
+	}
+	s.logger.InfoContext(r.Context(), fmt.Sprintf("job %q of tool %q was rejected through the admin API", id, status.Tool))
+	render.JSON(w, r, status)
+}
+
+// approvalCallbackHandler approves or rejects an invocation on behalf of the
+// approval webhook, authenticated by the token it was notified with rather
+// than the admin token.
+func approvalCallbackHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token    string `json:"token"`
+		Approved *bool  `json:"approved"`
+		Reason   string `json:"reason"`
+	}
+	if err := util.DecodeJSON(r.Body, &body); err != nil {
+		_ = render.Render(w, r, newErrResponse(fmt.Errorf("request body was invalid JSON: %w", err), http.StatusBadRequest))
+		return
+	}
+	if body.Approved == nil {
+		_ = render.Render(w, r, newErrResponse(fmt.Errorf("missing 'approved' field"), http.StatusBadRequest))
+		return
+	}
+	id := chi.URLParam(r, "jobId")
+	status, err := s.jobs.Decide(id, body.Token, *body.Approved, body.Reason)
+	if err != nil {
+		renderJobErr(s, w, r, err)
+		return
+	}
+	s.logger.InfoContext(r.Context(), fmt.Sprintf("job %q of tool %q was %s through the approval webhook", id, status.Tool, status.Status))
+	render.JSON(w, r, status)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// Capabilities describes what a running Toolbox server supports, so that
+// clients can adapt their behavior without prior knowledge of its config.
+type Capabilities struct {
+	ServerVersion       string          `json:"serverVersion"`
+	McpProtocolVersions []string        `json:"mcpProtocolVersions"`
+	SourceKinds         []string        `json:"sourceKinds"`
+	Features            map[string]bool `json:"features"`
+	Limits              Limits          `json:"limits"`
+}
+
+// Limits are the server-wide limits applied to tool invocations. Durations
+// are formatted as Go durations, e.g. "30s", and "0s" means no limit.
+type Limits struct {
+	// MaxConcurrentInvocations is the number of invocations that may run at
+	// once before additional invocations are queued. 0 means no limit.
+	MaxConcurrentInvocations int `json:"maxConcurrentInvocations"`
+	// MaxManifestPageSize is the largest number of tools returned per page of
+	// a toolset manifest.
+	MaxManifestPageSize int `json:"maxManifestPageSize"`
+	// MaxGrpcMessageBytes is the size of the largest gRPC request accepted.
+	// 0 when gRPC is disabled.
+	MaxGrpcMessageBytes int `json:"maxGrpcMessageBytes"`
+	// StreamStallTimeout is how long a client may stop reading a streamed
+	// result before its invocation is cancelled.
+	StreamStallTimeout string `json:"streamStallTimeout"`
+	// DrainTimeout is how long in-flight invocations may run once the server
+	// is shutting down.
+	DrainTimeout string `json:"drainTimeout"`
+	// JobRetention is how long a finished async job can still be polled.
+	JobRetention string `json:"jobRetention"`
+	// ApprovalTimeout is how long an invocation waits for approval before
+	// it's rejected.
+	ApprovalTimeout string `json:"approvalTimeout"`
+	// AnonymousRequestsPerMinute is the rate at which each anonymous client
+	// may invoke tools. 0 when anonymous access is disabled.
+	AnonymousRequestsPerMinute int `json:"anonymousRequestsPerMinute,omitempty"`
+}
+
+// capabilities builds the Capabilities of the server from its current resources.
+func (s *Server) capabilities() Capabilities {
+	kinds := make([]string, 0)
+	for _, src := range s.ResourceMgr.GetSourcesMap() {
+		if k := src.SourceKind(); !slices.Contains(kinds, k) {
+			kinds = append(kinds, k)
+		}
+	}
+	slices.Sort(kinds)
+
+	// features that depend on the configured tools are only reported if at
+	// least one tool uses them
+	var asyncJobs, approvals, streaming bool
+	for _, t := range s.ResourceMgr.GetToolsMap() {
+		async, approval := tools.RunsAsJob(t)
+		asyncJobs = asyncJobs || async
+		approvals = approvals || approval
+		if _, ok := tools.AsStreamingTool(t); ok {
+			streaming = true
+		}
+	}
+
+	maxConcurrent := s.invocations.MaxConcurrent()
+	if maxConcurrent < 0 {
+		maxConcurrent = 0
+	}
+	limits := Limits{
+		MaxConcurrentInvocations: maxConcurrent,
+		MaxManifestPageSize:      maxManifestPageSize,
+		StreamStallTimeout:       s.streamStallTimeout.String(),
+		DrainTimeout:             s.drainTimeout.String(),
+		JobRetention:             s.jobs.Retention().String(),
+		ApprovalTimeout:          s.jobs.ApprovalTimeout().String(),
+	}
+	if s.grpcSrv != nil {
+		limits.MaxGrpcMessageBytes = maxGrpcMessageSize
+	}
+	anonymous := s.anonymousAccess()
+	if anonymous != nil {
+		limits.AnonymousRequestsPerMinute = anonymous.requestsPerMinute
+	}
+	return Capabilities{
+		ServerVersion:       s.version,
+		McpProtocolVersions: mcp.SUPPORTED_PROTOCOL_VERSIONS,
+		SourceKinds:         kinds,
+		Features: map[string]bool{
+			"mcpSse":             true,
+			"mcpStreamableHttp":  true,
+			"dynamicReload":      !s.disableReload,
+			"grpc":               s.grpcSrv != nil,
+			"anonymousAccess":    anonymous != nil,
+			"invocationQueue":    maxConcurrent > 0,
+			"manifestPagination": true,
+			"manifestDelta":      true,
+			"adminApi":           s.adminToken != "",
+			"resultStreaming":    streaming,
+			"columnarEncoding":   true,
+			"statementPreview":   true,
+			"asyncJobs":          asyncJobs,
+			"approvals":          approvals,
+		},
+		Limits: limits,
+	}
+}
+
+// capabilitiesHandler handles requests for the server's capabilities.
+func capabilitiesHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/capabilities/get")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	render.JSON(w, r, s.capabilities())
+}
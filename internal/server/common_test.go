@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -32,6 +33,8 @@ import (
 // fakeVersionString is used as a temporary version string in tests
 const fakeVersionString = "0.0.0"
 
+const fakeAdminToken = "admin-secret"
+
 var _ tools.Tool = &MockTool{}
 
 // MockTool is used to mock tools in tests
@@ -39,10 +42,14 @@ type MockTool struct {
 	Name        string
 	Description string
 	Params      []tools.Parameter
+	InvokeErr   error
 	manifest    tools.Manifest
 }
 
 func (t MockTool) Invoke(context.Context, tools.ParamValues) (any, error) {
+	if t.InvokeErr != nil {
+		return nil, t.InvokeErr
+	}
 	mock := []any{t.Name}
 	return mock, nil
 }
@@ -134,7 +141,7 @@ func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool,
 }
 
 // setUpServer create a new server with tools and toolsets that are given
-func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
+func setUpServer(t *testing.T, router string, toolsMap map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
@@ -154,13 +161,15 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 
 	sseManager := newSseManager(ctx)
 
-	resourceManager := NewResourceManager(nil, nil, tools, toolsets)
+	resourceManager := NewResourceManager(nil, nil, toolsMap, toolsets)
 
 	server := Server{
 		version:         fakeVersionString,
 		logger:          testLogger,
 		instrumentation: instrumentation,
 		sseManager:      sseManager,
+		invocations:     invocations.NewTracker(0),
+		jobs:            tools.NewJobStore(0),
 		ResourceMgr:     resourceManager,
 	}
 
@@ -176,6 +185,20 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		if err != nil {
 			t.Fatalf("unable to initialize mcp router: %s", err)
 		}
+	case "admin":
+		// the admin API is tested along with the API it controls
+		server.adminToken = fakeAdminToken
+		apiR, err := apiRouter(&server)
+		if err != nil {
+			t.Fatalf("unable to initialize api router: %s", err)
+		}
+		adminR, err := adminRouter(&server)
+		if err != nil {
+			t.Fatalf("unable to initialize admin router: %s", err)
+		}
+		r = chi.NewRouter()
+		r.Mount("/api", apiR)
+		r.Mount("/admin", adminR)
 	default:
 		t.Fatalf("unknown router")
 	}
@@ -16,7 +16,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
@@ -53,8 +56,37 @@ type ServerConfig struct {
 	TelemetryServiceName string
 	// Stdio indicates if Toolbox is listening via MCP stdio.
 	Stdio bool
+	// StdioToolset is the name of the toolset served via MCP stdio. Empty
+	// serves all tools.
+	StdioToolset string
 	// DisableReload indicates if the user has disabled dynamic reloading for Toolbox.
 	DisableReload bool
+	// GrpcPort is the port the gRPC server will listen on. 0 disables gRPC.
+	GrpcPort int
+	// MaxConcurrentInvocations limits how many tool invocations run at once.
+	// Additional invocations are queued. 0 means no limit.
+	MaxConcurrentInvocations int
+	// AnonymousAccess configures the anonymous access tier. nil disables it.
+	AnonymousAccess *AnonymousAccessConfig
+	// AdminToken is the bearer token required by the admin API under /admin.
+	// Empty disables the admin API.
+	AdminToken string
+	// PolicyConfigs defines the policies tools can enforce.
+	PolicyConfigs PolicyConfigs
+	// ScheduleConfigs defines the tools run on a schedule.
+	ScheduleConfigs ScheduleConfigs
+	// StreamStallTimeout is how long a client may stop reading a streamed
+	// result before its invocation is cancelled. 0 disables the timeout.
+	StreamStallTimeout time.Duration
+	// DrainTimeout is how long in-flight invocations may run once the server
+	// is shutting down, before they are cancelled.
+	DrainTimeout time.Duration
+	// ApprovalWebhook is the URL notified of invocations waiting for
+	// approval. Empty disables notifications.
+	ApprovalWebhook string
+	// ApprovalTimeout is how long invocations wait for approval before they
+	// are rejected.
+	ApprovalTimeout time.Duration
 }
 
 type logFormat string
@@ -178,20 +210,37 @@ func (c *AuthServiceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(i
 			return fmt.Errorf("missing 'kind' field for %q", name)
 		}
 
+		// `onBehalfOf` applies to every kind of auth service
+		rawOnBehalfOf, hasOnBehalfOf := v["onBehalfOf"]
+		delete(v, "onBehalfOf")
+
 		dec, err := util.NewStrictDecoder(v)
 		if err != nil {
 			return fmt.Errorf("error creating decoder: %w", err)
 		}
+		var authCfg auth.AuthServiceConfig
 		switch kind {
 		case google.AuthServiceKind:
 			actual := google.Config{Name: name}
 			if err := dec.DecodeContext(ctx, &actual); err != nil {
 				return fmt.Errorf("unable to parse as %q: %w", kind, err)
 			}
-			(*c)[name] = actual
+			authCfg = actual
 		default:
 			return fmt.Errorf("%q is not a valid kind of auth source", kind)
 		}
+		if hasOnBehalfOf {
+			oboDec, err := util.NewStrictDecoder(rawOnBehalfOf)
+			if err != nil {
+				return fmt.Errorf("error creating decoder: %w", err)
+			}
+			var obo auth.OnBehalfOfConfig
+			if err := oboDec.DecodeContext(ctx, &obo); err != nil {
+				return fmt.Errorf("unable to parse onBehalfOf of %q: %w", name, err)
+			}
+			authCfg = auth.DelegatingAuthServiceConfig{AuthServiceConfig: authCfg, OnBehalfOf: obo}
+		}
+		(*c)[name] = authCfg
 	}
 	return nil
 }
@@ -230,20 +279,228 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 			return fmt.Errorf("invalid 'kind' field for tool %q (must be a string)", name)
 		}
 
-		yamlDecoder, err := util.NewStrictDecoder(v)
-		if err != nil {
-			return fmt.Errorf("error creating YAML decoder for tool %q: %w", name, err)
+		// `retry`, `binary`, `export`, `journal`, `responseBudget`,
+		// `queryTags`, `dictionary`, `async`, `requiresApproval` and
+		// `policies` apply to every kind of tool, so they are decoded here
+		// rather than by the tool itself, unless its kind declares them
+		wrapperFields := make(map[string]any)
+		for _, key := range toolWrapperKeys {
+			if val, ok := v[key]; ok {
+				wrapperFields[key] = val
+				delete(v, key)
+			}
 		}
-
-		toolCfg, err := tools.DecodeConfig(ctx, kindStr, name, yamlDecoder)
+		toolCfg, err := decodeToolConfig(ctx, kindStr, name, v)
 		if err != nil {
 			return err
 		}
+		if declared := declaredYAMLKeys(toolCfg, wrapperFields); len(declared) > 0 {
+			for _, key := range declared {
+				v[key] = wrapperFields[key]
+				delete(wrapperFields, key)
+			}
+			toolCfg, err = decodeToolConfig(ctx, kindStr, name, v)
+			if err != nil {
+				return err
+			}
+		}
+		rawRetry, hasRetry := wrapperFields["retry"]
+		rawBinary, hasBinary := wrapperFields["binary"]
+		rawExport, hasExport := wrapperFields["export"]
+		rawJournal, hasJournal := wrapperFields["journal"]
+		rawBudget, hasBudget := wrapperFields["responseBudget"]
+		rawQueryTags, hasQueryTags := wrapperFields["queryTags"]
+		rawDictionary, hasDictionary := wrapperFields["dictionary"]
+		rawAsync, hasAsync := wrapperFields["async"]
+		rawApproval, hasApproval := wrapperFields["requiresApproval"]
+		rawPolicies, hasPolicies := wrapperFields["policies"]
+		rawCfg := toolCfg
+		if hasBinary {
+			binaryDecoder, err := util.NewStrictDecoder(rawBinary)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for binary of tool %q: %w", name, err)
+			}
+			var binary tools.BinaryHandling
+			if err := binaryDecoder.DecodeContext(ctx, &binary); err != nil {
+				return fmt.Errorf("unable to parse binary of tool %q: %w", name, err)
+			}
+			toolCfg = tools.BinaryToolConfig{ToolConfig: toolCfg, Binary: binary}
+		}
+		if hasExport {
+			exportDecoder, err := util.NewStrictDecoder(rawExport)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for export of tool %q: %w", name, err)
+			}
+			var export tools.ExportConfig
+			if err := exportDecoder.DecodeContext(ctx, &export); err != nil {
+				return fmt.Errorf("unable to parse export of tool %q: %w", name, err)
+			}
+			toolCfg = tools.ExportToolConfig{ToolConfig: toolCfg, Export: export}
+		}
+		if hasRetry {
+			retryDecoder, err := util.NewStrictDecoder(rawRetry)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for retry of tool %q: %w", name, err)
+			}
+			var retry tools.RetryPolicy
+			if err := retryDecoder.DecodeContext(ctx, &retry); err != nil {
+				return fmt.Errorf("unable to parse retry of tool %q: %w", name, err)
+			}
+			toolCfg = tools.RetryToolConfig{ToolConfig: toolCfg, Retry: retry}
+		}
+		if hasJournal {
+			journalDecoder, err := util.NewStrictDecoder(rawJournal)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for journal of tool %q: %w", name, err)
+			}
+			var journal tools.JournalConfig
+			if err := journalDecoder.DecodeContext(ctx, &journal); err != nil {
+				return fmt.Errorf("unable to parse journal of tool %q: %w", name, err)
+			}
+			toolCfg = tools.JournalToolConfig{ToolConfig: toolCfg, Journal: journal}
+		}
+		if hasBudget {
+			budgetDecoder, err := util.NewStrictDecoder(rawBudget)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for responseBudget of tool %q: %w", name, err)
+			}
+			var budget tools.ResponseBudget
+			if err := budgetDecoder.DecodeContext(ctx, &budget); err != nil {
+				return fmt.Errorf("unable to parse responseBudget of tool %q: %w", name, err)
+			}
+			toolCfg = tools.BudgetToolConfig{ToolConfig: toolCfg, Budget: budget}
+		}
+		if hasQueryTags {
+			queryTagsDecoder, err := util.NewStrictDecoder(rawQueryTags)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for queryTags of tool %q: %w", name, err)
+			}
+			var tags map[string]string
+			if err := queryTagsDecoder.DecodeContext(ctx, &tags); err != nil {
+				return fmt.Errorf("unable to parse queryTags of tool %q: %w", name, err)
+			}
+			toolCfg = tools.QueryTagsToolConfig{ToolConfig: toolCfg, Tags: tags}
+		}
+		if hasDictionary {
+			dictionaryDecoder, err := util.NewStrictDecoder(rawDictionary)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for dictionary of tool %q: %w", name, err)
+			}
+			var tables []string
+			if err := dictionaryDecoder.DecodeContext(ctx, &tables); err != nil {
+				return fmt.Errorf("unable to parse dictionary of tool %q: %w", name, err)
+			}
+			source, _ := v["source"].(string)
+			toolCfg = tools.DictionaryToolConfig{ToolConfig: toolCfg, Source: source, Tables: tables}
+		}
+		if hasAsync {
+			async, ok := rawAsync.(bool)
+			if !ok {
+				return fmt.Errorf("invalid 'async' field for tool %q (must be a boolean)", name)
+			}
+			if async {
+				toolCfg = tools.AsyncToolConfig{ToolConfig: toolCfg}
+			}
+		}
+		if hasApproval {
+			requiresApproval, ok := rawApproval.(bool)
+			if !ok {
+				return fmt.Errorf("invalid 'requiresApproval' field for tool %q (must be a boolean)", name)
+			}
+			if requiresApproval {
+				toolCfg = tools.ApprovalToolConfig{ToolConfig: toolCfg}
+			}
+		}
+		// policies wrap every other wrapper, so they are checked before any
+		// of them run
+		if hasPolicies {
+			policiesDecoder, err := util.NewStrictDecoder(rawPolicies)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for policies of tool %q: %w", name, err)
+			}
+			var names []string
+			if err := policiesDecoder.DecodeContext(ctx, &names); err != nil {
+				return fmt.Errorf("unable to parse policies of tool %q: %w", name, err)
+			}
+			pcfg := tools.PolicyToolConfig{ToolConfig: toolCfg, Names: names}
+			if p, ok := rawCfg.(tools.PolicyPredicateSupport); ok {
+				pcfg.PredicatesSupported = p.SupportsPolicyPredicates()
+				pcfg.Statement = p.PolicyStatement()
+			}
+			toolCfg = pcfg
+		}
 		(*c)[name] = toolCfg
 	}
 	return nil
 }
 
+// toolWrapperKeys are the fields of tool configs that wrap the tool of any
+// kind rather than being decoded by it.
+var toolWrapperKeys = []string{
+	"retry", "binary", "export", "journal", "responseBudget",
+	"queryTags", "dictionary", "async", "requiresApproval", "policies",
+}
+
+// decodeToolConfig decodes the fields v of the tool name of the given kind.
+func decodeToolConfig(ctx context.Context, kind, name string, v map[string]any) (tools.ToolConfig, error) {
+	yamlDecoder, err := util.NewStrictDecoder(v)
+	if err != nil {
+		return nil, fmt.Errorf("error creating YAML decoder for tool %q: %w", name, err)
+	}
+	return tools.DecodeConfig(ctx, kind, name, yamlDecoder)
+}
+
+// declaredYAMLKeys returns the keys of fields that the config struct of cfg
+// declares itself, sorted.
+func declaredYAMLKeys(cfg tools.ToolConfig, fields map[string]any) []string {
+	t := reflect.TypeOf(cfg)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	keys := make(map[string]bool)
+	collectYAMLKeys(t, keys)
+	var declared []string
+	for key := range fields {
+		if keys[key] {
+			declared = append(declared, key)
+		}
+	}
+	sort.Strings(declared)
+	return declared
+}
+
+// collectYAMLKeys adds the YAML keys of the fields of struct type t to keys,
+// including the ones of inlined structs.
+func collectYAMLKeys(t reflect.Type, keys map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && (strings.Contains(opts, "inline") || (f.Anonymous && name == "")) {
+			collectYAMLKeys(ft, keys)
+			continue
+		}
+		if name != "" {
+			keys[name] = true
+		}
+	}
+}
+
+// PolicyConfigs is the named policies tools can reference.
+type PolicyConfigs map[string]tools.PolicyConfig
+
+// ScheduleConfigs is the named schedules of tools run by the server.
+type ScheduleConfigs map[string]tools.ScheduleConfig
+
 // ToolConfigs is a type used to allow unmarshal of the toolset configs
 type ToolsetConfigs map[string]tools.ToolsetConfig
 
@@ -253,13 +510,41 @@ var _ yaml.InterfaceUnmarshalerContext = &ToolsetConfigs{}
 func (c *ToolsetConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	*c = make(ToolsetConfigs)
 
-	var raw map[string][]string
+	var raw map[string]toolsetYAML
 	if err := unmarshal(&raw); err != nil {
 		return err
 	}
 
-	for name, toolList := range raw {
-		(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: toolList}
+	for name, t := range raw {
+		(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: t.Tools, Server: t.Server}
+	}
+	return nil
+}
+
+// toolsetYAML is a toolset, written either as a list of tool names or as a
+// mapping that also describes the MCP server serving the toolset:
+//
+//	my-toolset:
+//	  tools: [tool_a, tool_b]
+//	  server:
+//	    name: analytics
+//	    instructions: Answers questions about sales.
+type toolsetYAML struct {
+	Tools  []string
+	Server tools.ServerInfo
+}
+
+func (t *toolsetYAML) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	if err := unmarshal(&t.Tools); err == nil {
+		return nil
+	}
+	var long struct {
+		Tools  []string         `yaml:"tools" validate:"required"`
+		Server tools.ServerInfo `yaml:"server"`
+	}
+	if err := unmarshal(&long); err != nil {
+		return err
 	}
+	t.Tools, t.Server = long.Tools, long.Server
 	return nil
 }
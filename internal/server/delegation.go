@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// delegation is an end-user assertion that was passed by an allowed delegate
+// and verified.
+type delegation struct {
+	// AuthService verified the end-user assertion.
+	AuthService string
+	User        string
+	// Delegate is the auth service that verified the delegate.
+	Delegate   string
+	DelegateID string
+}
+
+// verifyDelegations verifies the end-user assertions in the header and
+// replaces the claims of their auth services in claimsFromAuth. Assertions are
+// only accepted from callers verified as an allowed delegate by a token sent
+// directly, never by another assertion.
+func (s *Server) verifyDelegations(ctx context.Context, h http.Header, authServices map[string]auth.AuthService, claimsFromAuth map[string]map[string]any) []delegation {
+	direct := maps.Clone(claimsFromAuth)
+	var delegations []delegation
+	for name, aS := range authServices {
+		d, ok := aS.(auth.DelegatingAuthService)
+		if !ok {
+			continue
+		}
+		assertion := h.Get(auth.OnBehalfOfHeader(name))
+		if assertion == "" {
+			continue
+		}
+		delegate, ok := d.OnBehalfOf.AllowedDelegate(direct)
+		if !ok {
+			s.logger.WarnContext(ctx, fmt.Sprintf("rejected on-behalf-of assertion for auth service %q: the caller is not an allowed delegate", name))
+			continue
+		}
+		claims, err := d.GetClaimsFromAssertion(ctx, assertion)
+		if err != nil || claims == nil {
+			s.logger.WarnContext(ctx, fmt.Sprintf("rejected on-behalf-of assertion for auth service %q from delegate %s:%s: %v", name, delegate, auth.Identity(direct[delegate]), err))
+			continue
+		}
+		claimsFromAuth[name] = claims
+		delegations = append(delegations, delegation{
+			AuthService: name,
+			User:        auth.Identity(claims),
+			Delegate:    delegate,
+			DelegateID:  auth.Identity(direct[delegate]),
+		})
+	}
+	return delegations
+}
+
+// auditDelegations records that a tool is invoked on behalf of end users.
+func (s *Server) auditDelegations(ctx context.Context, toolName string, delegations []delegation) {
+	span := trace.SpanFromContext(ctx)
+	for _, d := range delegations {
+		s.logger.InfoContext(ctx, fmt.Sprintf("tool %q invoked by delegate %s:%s on behalf of %s:%s", toolName, d.Delegate, d.DelegateID, d.AuthService, d.User))
+		span.SetAttributes(
+			attribute.String("toolbox.on_behalf_of.auth_service", d.AuthService),
+			attribute.String("toolbox.on_behalf_of.user", d.User),
+			attribute.String("toolbox.on_behalf_of.delegate", d.Delegate+":"+d.DelegateID),
+		)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+)
+
+// fakeAuthService accepts the tokens in its map and returns their claims.
+type fakeAuthService struct {
+	name   string
+	tokens map[string]map[string]any
+}
+
+func (a fakeAuthService) AuthServiceKind() string { return "fake" }
+func (a fakeAuthService) GetName() string         { return a.name }
+func (a fakeAuthService) GetClaimsFromHeader(_ context.Context, h http.Header) (map[string]any, error) {
+	token := h.Get(a.name + "_token")
+	if token == "" {
+		return nil, nil
+	}
+	claims, ok := a.tokens[token]
+	if !ok {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
+func TestClaimsFromHeaderOnBehalfOf(t *testing.T) {
+	backend := fakeAuthService{name: "backend", tokens: map[string]map[string]any{
+		"backend-token": {"sub": "svc@example.com"},
+		"other-token":   {"sub": "intruder@example.com"},
+	}}
+	users := auth.DelegatingAuthService{
+		AuthService: fakeAuthService{name: "users", tokens: map[string]map[string]any{
+			"alice-token": {"sub": "alice"},
+		}},
+		OnBehalfOf: auth.OnBehalfOfConfig{Delegates: []auth.Delegate{{AuthService: "backend", Subjects: []string{"svc@example.com"}}}},
+	}
+
+	logger, err := log.NewStdLogger(io.Discard, io.Discard, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	s := &Server{
+		logger:          logger,
+		instrumentation: instrumentation,
+		ResourceMgr:     NewResourceManager(nil, map[string]auth.AuthService{"backend": backend, "users": users}, nil, nil),
+	}
+
+	tcs := []struct {
+		desc            string
+		header          map[string]string
+		wantClaims      map[string]map[string]any
+		wantDelegations []delegation
+		wantCaller      string
+	}{
+		{
+			desc:       "direct token",
+			header:     map[string]string{"users_token": "alice-token"},
+			wantClaims: map[string]map[string]any{"users": {"sub": "alice"}},
+			wantCaller: "users:alice",
+		},
+		{
+			desc:   "allowed delegate",
+			header: map[string]string{"backend_token": "backend-token", "users_on_behalf_of": "alice-token"},
+			wantClaims: map[string]map[string]any{
+				"backend": {"sub": "svc@example.com"},
+				"users":   {"sub": "alice"},
+			},
+			wantDelegations: []delegation{{AuthService: "users", User: "alice", Delegate: "backend", DelegateID: "svc@example.com"}},
+			wantCaller:      "users:alice",
+		},
+		{
+			desc:       "delegate not allowed",
+			header:     map[string]string{"backend_token": "other-token", "users_on_behalf_of": "alice-token"},
+			wantClaims: map[string]map[string]any{"backend": {"sub": "intruder@example.com"}},
+			wantCaller: "backend:intruder@example.com",
+		},
+		{
+			desc:       "assertion without delegate",
+			header:     map[string]string{"users_on_behalf_of": "alice-token"},
+			wantClaims: map[string]map[string]any{},
+		},
+		{
+			desc:       "invalid assertion",
+			header:     map[string]string{"backend_token": "backend-token", "users_on_behalf_of": "forged"},
+			wantClaims: map[string]map[string]any{"backend": {"sub": "svc@example.com"}},
+			wantCaller: "backend:svc@example.com",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			h := make(http.Header)
+			for k, v := range tc.header {
+				h.Set(k, v)
+			}
+			claims, delegations := s.claimsFromHeader(context.Background(), h)
+			if diff := cmp.Diff(tc.wantClaims, claims); diff != "" {
+				t.Fatalf("unexpected claims (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDelegations, delegations); diff != "" {
+				t.Fatalf("unexpected delegations (-want +got):\n%s", diff)
+			}
+			if got := callerFromClaims(claims, delegations); got != tc.wantCaller {
+				t.Fatalf("unexpected caller: got %q, want %q", got, tc.wantCaller)
+			}
+		})
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/server/grpc/toolboxpb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+//go:generate protoc --proto_path=../../proto --go_out=../.. --go_opt=module=github.com/googleapis/genai-toolbox --go-grpc_out=../.. --go-grpc_opt=module=github.com/googleapis/genai-toolbox toolbox/v1/toolbox.proto
+
+// grpcService implements the toolbox.v1.Toolbox gRPC service.
+type grpcService struct {
+	toolboxpb.UnimplementedToolboxServer
+	s *Server
+}
+
+// maxGrpcMessageSize is the size of the largest gRPC request the server
+// accepts.
+const maxGrpcMessageSize = 4 << 20
+
+// newGrpcServer returns a gRPC server with the Toolbox service registered.
+func newGrpcServer(s *Server) *grpc.Server {
+	g := grpc.NewServer(grpc.MaxRecvMsgSize(maxGrpcMessageSize))
+	toolboxpb.RegisterToolboxServer(g, &grpcService{s: s})
+	return g
+}
+
+// anonymousCaller returns the anonymous access tier if it is enabled and the
+// caller sent no valid auth token, nil otherwise.
+func (g *grpcService) anonymousCaller(ctx context.Context) *anonymousTier {
+	anonymous := g.s.anonymousAccess()
+	if anonymous == nil {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if claimsFromAuth, _ := g.s.claimsFromHeader(ctx, metadataToHeader(md)); len(claimsFromAuth) > 0 {
+		return nil
+	}
+	return anonymous
+}
+
+// ListToolsets lists all toolsets and their tools. Anonymous callers only see
+// the tools of the anonymous access tier.
+func (g *grpcService) ListToolsets(ctx context.Context, _ *toolboxpb.ListToolsetsRequest) (*toolboxpb.ListToolsetsResponse, error) {
+	anonymous := g.anonymousCaller(ctx)
+	toolsets := g.s.ResourceMgr.GetToolsetsMap()
+	names := make([]string, 0, len(toolsets))
+	for name := range toolsets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	resp := &toolboxpb.ListToolsetsResponse{ServerVersion: g.s.version}
+	for _, name := range names {
+		m := anonymous.filterToolset(toolsets[name]).Manifest.ToolsManifest
+		toolNames := make([]string, 0, len(m))
+		for toolName := range m {
+			toolNames = append(toolNames, toolName)
+		}
+		slices.Sort(toolNames)
+
+		ts := &toolboxpb.Toolset{Name: name}
+		for _, toolName := range toolNames {
+			ts.Tools = append(ts.Tools, toolToProto(toolName, m[toolName]))
+		}
+		resp.Toolsets = append(resp.Toolsets, ts)
+	}
+	return resp, nil
+}
+
+// GetTool returns the manifest of a single tool. Anonymous callers only get
+// the tools of the anonymous access tier.
+func (g *grpcService) GetTool(ctx context.Context, req *toolboxpb.GetToolRequest) (*toolboxpb.Tool, error) {
+	tool, ok := g.s.ResourceMgr.GetTool(req.GetName())
+	if ok {
+		if anonymous := g.anonymousCaller(ctx); anonymous != nil {
+			ok = slices.Contains(anonymous.tools, req.GetName())
+		}
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "invalid tool name: tool with name %q does not exist", req.GetName())
+	}
+	return toolToProto(req.GetName(), tool.Manifest()), nil
+}
+
+// InvokeTool invokes a tool and streams its result.
+func (g *grpcService) InvokeTool(req *toolboxpb.InvokeToolRequest, stream grpc.ServerStreamingServer[toolboxpb.InvokeToolResponse]) error {
+	s := g.s
+	ctx, span := s.instrumentation.Tracer.Start(stream.Context(), "toolbox/server/grpc/tool/invoke")
+	ctx = util.WithLogger(ctx, s.logger)
+	ctx = util.WithInvocationTracker(ctx, s.invocations)
+	ctx = tools.WithJobStore(ctx, s.jobs)
+
+	toolName := req.GetName()
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		span.End()
+
+		opStatus := "success"
+		if err != nil {
+			opStatus = "error"
+		}
+		s.instrumentation.ToolInvoke.Add(
+			ctx,
+			1,
+			metric.WithAttributes(attribute.String("toolbox.name", toolName)),
+			metric.WithAttributes(attribute.String("toolbox.operation.status", opStatus)),
+		)
+	}()
+
+	err = g.invokeTool(ctx, req, stream)
+	return err
+}
+
+func (g *grpcService) invokeTool(ctx context.Context, req *toolboxpb.InvokeToolRequest, stream grpc.ServerStreamingServer[toolboxpb.InvokeToolResponse]) error {
+	s := g.s
+	toolName := req.GetName()
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		err := fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+		return grpcStatusFromToolError(tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeToolNotFound, "", err))
+	}
+
+	// Tool authentication and authorization, using the same headers as the
+	// HTTP API sent as metadata.
+	md, _ := metadata.FromIncomingContext(ctx)
+	claimsFromAuth, delegations := s.claimsFromHeader(ctx, metadataToHeader(md))
+	if !tool.Authorized(verifiedAuthServiceNames(claimsFromAuth)) {
+		err := fmt.Errorf("tool invocation not authorized. Please make sure your specify correct auth headers")
+		return grpcStatusFromToolError(tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized, "", err))
+	}
+	if len(claimsFromAuth) == 0 {
+		anonymous := s.anonymousAccess()
+		var client string
+		if p, ok := peer.FromContext(ctx); ok {
+			client = anonymous.client(p.Addr.String(), metadataToHeader(md))
+		}
+		if err := anonymous.admit(toolName, client); err != nil {
+			return grpcStatusFromToolError(tools.ClassifyError(err, tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized))
+		}
+	}
+	s.auditDelegations(ctx, toolName, delegations)
+
+	// Round trip the params through JSON so numbers are parsed the same way as
+	// in the HTTP API.
+	data := make(map[string]any)
+	if req.GetParams() != nil {
+		b, err := protojson.Marshal(req.GetParams())
+		if err == nil {
+			err = util.DecodeJSON(bytes.NewReader(b), &data)
+		}
+		if err != nil {
+			err = fmt.Errorf("invalid params: %w", err)
+			return grpcStatusFromToolError(tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeInvalidRequest, "", err))
+		}
+	}
+	params, err := tool.ParseParams(data, claimsFromAuth)
+	if err != nil {
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		return grpcStatusFromToolError(tools.ClassifyError(err, tools.ErrorCategoryValidation, tools.ErrorCodeInvalidParameters))
+	}
+
+	ctx, done, err := s.invocations.BeginTool(ctx, toolName, tool, callerFromClaims(claimsFromAuth, delegations))
+	if err != nil {
+		return grpcStatusFromToolError(tools.ClassifyError(err, tools.ErrorCategoryInternal, tools.ErrorCodeInternal))
+	}
+	// the slot is released even if the tool panics
+	defer done()
+	if st, ok := tools.AsStreamingTool(tool); ok {
+		// rows are sent as they're fetched, each within the stall timeout
+		err = g.streamRows(ctx, stream, st, params)
+		s.invocations.RecordError(ctx, err)
+		if err != nil {
+			err = fmt.Errorf("error while invoking tool: %w", err)
+			return grpcStatusFromToolError(tools.ClassifyError(err, tools.ErrorCategoryQuery, tools.ErrorCodeQueryFailed))
+		}
+		return nil
+	}
+	res, err := tool.Invoke(ctx, params)
+	s.invocations.RecordError(ctx, err)
+	if err != nil {
+		err = fmt.Errorf("error while invoking tool: %w", err)
+		return grpcStatusFromToolError(tools.ClassifyError(err, tools.ErrorCategoryQuery, tools.ErrorCodeQueryFailed))
+	}
+
+	values, err := resultToValues(res)
+	if err != nil {
+		err = fmt.Errorf("unable to marshal result: %w", err)
+		return grpcStatusFromToolError(tools.NewToolError(tools.ErrorCategoryInternal, tools.ErrorCodeInternal, "", err))
+	}
+	for _, v := range values {
+		if err := stream.Send(&toolboxpb.InvokeToolResponse{Result: v}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamRows streams the rows of a tool. The tool runs in its own goroutine
+// and hands each row over to this one, which is the only one to send on the
+// stream, so nothing is sent once the handler returns. If the client stops
+// reading and a row isn't handed over within the stall timeout, the tool is
+// cancelled, which stops its query; the stream ends once the blocked send
+// returns, when the client reads again or disconnects.
+func (g *grpcService) streamRows(ctx context.Context, stream grpc.ServerStreamingServer[toolboxpb.InvokeToolResponse], st tools.StreamingTool, params tools.ParamValues) error {
+	stall := g.s.streamStallTimeout
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	rows := make(chan *structpb.Value)
+	result := make(chan error, 1)
+	go func() {
+		result <- st.Stream(ctx, params, func(row any) error {
+			v, err := rowToValue(row)
+			if err != nil {
+				return fmt.Errorf("unable to marshal result: %w", err)
+			}
+			var timeout <-chan time.Time
+			if stall > 0 {
+				timer := time.NewTimer(stall)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			select {
+			case rows <- v:
+				return nil
+			case <-timeout:
+				err := fmt.Errorf("%w for %s", tools.ErrStreamStalled, stall)
+				cancel(err)
+				return err
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		})
+	}()
+
+	for {
+		select {
+		case v := <-rows:
+			if err := stream.Send(&toolboxpb.InvokeToolResponse{Result: v}); err != nil {
+				cancel(err)
+				<-result
+				return err
+			}
+		case err := <-result:
+			return err
+		}
+	}
+}
+
+// rowToValue converts a row of a tool result into a protobuf value.
+func rowToValue(row any) (*structpb.Value, error) {
+	b, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+	return structpb.NewValue(normalized)
+}
+
+// resultToValues converts a tool result into protobuf values. A list result is
+// split into one value per row.
+func resultToValues(res any) ([]*structpb.Value, error) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+	rows, ok := normalized.([]any)
+	if !ok {
+		rows = []any{normalized}
+	}
+	values := make([]*structpb.Value, 0, len(rows))
+	for _, row := range rows {
+		v, err := structpb.NewValue(row)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert row: %w", err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// grpcStatusFromToolError converts a classified tool error to a gRPC status.
+// The classification is attached as an ErrorInfo detail.
+func grpcStatusFromToolError(te *tools.ToolError) error {
+	var code codes.Code
+	switch te.Category {
+	case tools.ErrorCategoryValidation:
+		code = codes.InvalidArgument
+		if te.Code == tools.ErrorCodeToolNotFound {
+			code = codes.NotFound
+		}
+	case tools.ErrorCategoryAuth:
+		code = codes.Unauthenticated
+		if te.Code == tools.ErrorCodePolicyDenied {
+			code = codes.PermissionDenied
+		}
+	case tools.ErrorCategoryRateLimit:
+		code = codes.ResourceExhausted
+	case tools.ErrorCategoryTimeout:
+		code = codes.DeadlineExceeded
+		if te.Code == tools.ErrorCodeCancelled {
+			code = codes.Canceled
+		}
+	case tools.ErrorCategorySourceUnavailable:
+		code = codes.Unavailable
+	case tools.ErrorCategoryQuery:
+		code = codes.Unknown
+		if te.Retryable {
+			code = codes.Aborted
+		}
+	default:
+		code = codes.Internal
+	}
+	st := status.New(code, te.Error())
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: te.Code,
+		Domain: "toolbox",
+		Metadata: map[string]string{
+			"category":  string(te.Category),
+			"detail":    te.Detail,
+			"retryable": strconv.FormatBool(te.Retryable),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// metadataToHeader converts gRPC metadata into an HTTP header.
+func metadataToHeader(md metadata.MD) http.Header {
+	h := make(http.Header, len(md))
+	for k, vs := range md {
+		for _, v := range vs {
+			h.Add(k, v)
+		}
+	}
+	return h
+}
+
+func toolToProto(name string, m tools.Manifest) *toolboxpb.Tool {
+	t := &toolboxpb.Tool{
+		Name:         name,
+		Description:  m.Description,
+		AuthRequired: m.AuthRequired,
+	}
+	for _, p := range m.Parameters {
+		t.Parameters = append(t.Parameters, parameterToProto(&p))
+	}
+	return t
+}
+
+func parameterToProto(p *tools.ParameterManifest) *toolboxpb.Parameter {
+	if p == nil {
+		return nil
+	}
+	return &toolboxpb.Parameter{
+		Name:         p.Name,
+		Type:         p.Type,
+		Required:     p.Required,
+		Description:  p.Description,
+		AuthServices: p.AuthServices,
+		Items:        parameterToProto(p.Items),
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/server/grpc/toolboxpb"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func setUpGrpcClient(t *testing.T, mockTools []MockTool) (toolboxpb.ToolboxClient, func()) {
+	toolsMap, toolsets := setUpResources(t, mockTools)
+
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		invocations:     invocations.NewTracker(0),
+		ResourceMgr:     NewResourceManager(nil, nil, toolsMap, toolsets),
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	g := newGrpcServer(s)
+	go func() { _ = g.Serve(lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+	return toolboxpb.NewToolboxClient(conn), func() {
+		conn.Close()
+		g.Stop()
+	}
+}
+
+func TestGrpcListToolsetsAndGetTool(t *testing.T) {
+	client, shutdown := setUpGrpcClient(t, []MockTool{tool1, tool2})
+	defer shutdown()
+	ctx := context.Background()
+
+	resp, err := client.ListToolsets(ctx, &toolboxpb.ListToolsetsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp.GetServerVersion() != fakeVersionString {
+		t.Errorf("unexpected server version: got %q", resp.GetServerVersion())
+	}
+	if len(resp.GetToolsets()) != 3 || resp.GetToolsets()[0].GetName() != "" || len(resp.GetToolsets()[0].GetTools()) != 2 {
+		t.Fatalf("unexpected toolsets: %v", resp.GetToolsets())
+	}
+
+	tool, err := client.GetTool(ctx, &toolboxpb.GetToolRequest{Name: tool2.Name})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(tool.GetParameters()) != 2 || tool.GetParameters()[0].GetType() != "integer" {
+		t.Errorf("unexpected parameters: %v", tool.GetParameters())
+	}
+
+	_, err = client.GetTool(ctx, &toolboxpb.GetToolRequest{Name: "some_imaginary_tool"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("unexpected error code: want %s, got %s", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestGrpcInvokeTool(t *testing.T) {
+	client, shutdown := setUpGrpcClient(t, []MockTool{tool1, tool2})
+	defer shutdown()
+	ctx := context.Background()
+
+	params, err := structpb.NewStruct(map[string]any{"param1": 1, "param2": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	stream, err := client.InvokeTool(ctx, &toolboxpb.InvokeToolRequest{Name: tool2.Name, Params: params})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var rows []any
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		rows = append(rows, resp.GetResult().AsInterface())
+	}
+	if len(rows) != 1 || rows[0] != tool2.Name {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+
+	// invalid params are rejected
+	bad, _ := structpb.NewStruct(map[string]any{"param1": "one"})
+	stream, err = client.InvokeTool(ctx, &toolboxpb.InvokeToolRequest{Name: tool2.Name, Params: bad})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unexpected error code: want %s, got %s", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+// blockedStream is an InvokeTool stream whose client stops reading after the
+// first response.
+type blockedStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	sent    int
+	release chan struct{}
+}
+
+func (b *blockedStream) Context() context.Context { return b.ctx }
+
+func (b *blockedStream) Send(*toolboxpb.InvokeToolResponse) error {
+	b.sent++
+	if b.sent > 1 {
+		<-b.release
+	}
+	return nil
+}
+
+func TestGrpcStreamStallTimeout(t *testing.T) {
+	g := &grpcService{s: &Server{streamStallTimeout: 100 * time.Millisecond}}
+	stream := &blockedStream{ctx: context.Background(), release: make(chan struct{})}
+
+	cancelled := make(chan error, 1)
+	tool := streamToolFunc(func(ctx context.Context, send func(row any) error) error {
+		for {
+			if err := send(map[string]any{"id": 1}); err != nil {
+				cancelled <- context.Cause(ctx)
+				return err
+			}
+		}
+	})
+	errs := make(chan error, 1)
+	go func() { errs <- g.streamRows(context.Background(), stream, tool, nil) }()
+
+	// the tool is cancelled while the send is still blocked
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, tools.ErrStreamStalled) {
+			t.Fatalf("expected the tool to be cancelled as stalled, got %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatalf("tool was not cancelled")
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("returned before the blocked send: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(stream.release)
+	if err := <-errs; !errors.Is(err, tools.ErrStreamStalled) {
+		t.Fatalf("expected the stream to stall, got %v", err)
+	}
+	if stream.sent != 2 {
+		t.Errorf("unexpected number of sends: %d", stream.sent)
+	}
+}
+
+func TestGrpcAnonymousAccess(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	users := fakeAuthService{name: "users", tokens: map[string]map[string]any{"alice-token": {"sub": "alice"}}}
+	logger, err := log.NewStdLogger(io.Discard, io.Discard, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          logger,
+		instrumentation: instrumentation,
+		ResourceMgr:     NewResourceManager(nil, map[string]auth.AuthService{"users": users}, toolsMap, toolsets),
+	}
+	if err := s.SetAnonymousAccess(&AnonymousAccessConfig{Tools: []string{tool1.Name}}, toolsMap); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	g := &grpcService{s: s}
+
+	tcs := []struct {
+		desc      string
+		ctx       context.Context
+		wantTools int
+	}{
+		{desc: "anonymous", ctx: context.Background(), wantTools: 1},
+		{desc: "invalid token", ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("users_token", "bad-token")), wantTools: 1},
+		{desc: "authenticated", ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("users_token", "alice-token")), wantTools: 2},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, err := g.ListToolsets(tc.ctx, &toolboxpb.ListToolsetsRequest{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := len(resp.GetToolsets()[0].GetTools()); got != tc.wantTools {
+				t.Fatalf("unexpected number of tools: got %d, want %d", got, tc.wantTools)
+			}
+			if _, err := g.GetTool(tc.ctx, &toolboxpb.GetToolRequest{Name: tool1.Name}); err != nil {
+				t.Fatalf("unexpected error getting a tool of the tier: %s", err)
+			}
+			_, err = g.GetTool(tc.ctx, &toolboxpb.GetToolRequest{Name: tool2.Name})
+			wantCode := codes.OK
+			if tc.wantTools == 1 {
+				wantCode = codes.NotFound
+			}
+			if status.Code(err) != wantCode {
+				t.Fatalf("unexpected error code: want %s, got %s", wantCode, status.Code(err))
+			}
+		})
+	}
+}
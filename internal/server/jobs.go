@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// jobCaller returns the caller a job request is made by, so callers can only
+// see and cancel their own jobs.
+func (s *Server) jobCaller(r *http.Request) string {
+	claimsFromAuth, delegations := s.claimsFromHeader(r.Context(), r.Header)
+	return callerFromClaims(claimsFromAuth, delegations)
+}
+
+// jobStatusHandler handles the API request for the status of an async job.
+func jobStatusHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	status, err := s.jobs.Status(chi.URLParam(r, "jobId"), s.jobCaller(r))
+	if err != nil {
+		renderJobErr(s, w, r, err)
+		return
+	}
+	render.JSON(w, r, status)
+}
+
+// jobResultHandler handles the API request for the rows an async job has
+// fetched since the `offset` query parameter, so running jobs can be polled
+// for partial results.
+func jobResultHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			renderJobErr(s, w, r, tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeInvalidRequest, "the offset must be an integer", fmt.Errorf("invalid offset %q", v)))
+			return
+		}
+		offset = n
+	}
+	res, err := s.jobs.Result(chi.URLParam(r, "jobId"), s.jobCaller(r), offset)
+	if err != nil {
+		renderJobErr(s, w, r, err)
+		return
+	}
+	render.JSON(w, r, res)
+}
+
+// jobCancelHandler handles the API request to cancel a running async job.
+func jobCancelHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	status, err := s.jobs.Cancel(chi.URLParam(r, "jobId"), s.jobCaller(r))
+	if err != nil {
+		renderJobErr(s, w, r, err)
+		return
+	}
+	render.JSON(w, r, status)
+}
+
+func renderJobErr(s *Server, w http.ResponseWriter, r *http.Request, err error) {
+	s.logger.DebugContext(r.Context(), err.Error())
+	_ = render.Render(w, r, newToolErrResponse(tools.ClassifyError(err, tools.ErrorCategoryValidation, tools.ErrorCodeInvalidRequest)))
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// loadHandler handles requests for the current invocation load of the server.
+// The response is a flat JSON object of numbers, so that autoscalers such as
+// the KEDA metrics-api scaler can read a single value from it.
+func loadHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/load/get")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	render.JSON(w, r, s.invocations.Load())
+}
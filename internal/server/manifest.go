@@ -0,0 +1,243 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// defaultManifestPageSize is the number of tools returned per page when
+	// the client does not specify a page size.
+	defaultManifestPageSize = 100
+	// maxManifestPageSize is the largest page size a client may request.
+	maxManifestPageSize = 1000
+)
+
+// manifestFields are the tool manifest fields that may be selected with the
+// `fields` query parameter.
+var manifestFields = []string{"description", "parameters", "authRequired"}
+
+// BulkManifest is a single page of a toolset manifest.
+type BulkManifest struct {
+	ServerVersion string `json:"serverVersion"`
+	// Revision is an opaque token identifying the state of the manifest. It
+	// can be passed back as `since` to only fetch tools changed after it.
+	Revision string `json:"revision"`
+	// Reset is true when the `since` token could not be honored, e.g. because
+	// the server restarted, and the full manifest is returned instead.
+	Reset bool `json:"reset,omitempty"`
+	// Tools maps tool names to their (optionally field-filtered) manifests.
+	Tools map[string]map[string]any `json:"tools"`
+	// Removed lists tools removed since the `since` revision. It is only set
+	// on the first page.
+	Removed []string `json:"removed,omitempty"`
+	// NextPageToken is set if there are more tools to fetch.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// manifestHandler handles requests for a paginated, optionally delta-encoded,
+// manifest of a toolset.
+func manifestHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/manifest/get")
+	r = r.WithContext(ctx)
+
+	toolsetName := chi.URLParam(r, "toolsetName")
+	span.SetAttributes(attribute.String("toolset_name", toolsetName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	state, ok := s.ResourceMgr.getManifestState(toolsetName)
+	if !ok {
+		err = fmt.Errorf("toolset %q does not exist", toolsetName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+
+	query := r.URL.Query()
+	fields, err := parseManifestFields(query.Get("fields"))
+	if err != nil {
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	pageSize, err := parseManifestPageSize(query.Get("pageSize"))
+	if err != nil {
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	m := BulkManifest{
+		ServerVersion: s.version,
+		Revision:      formatRevision(state.epoch, state.revision),
+		Tools:         make(map[string]map[string]any),
+	}
+
+	// Only tools changed after the `since` revision are returned. A revision
+	// from another epoch results in a full manifest.
+	var since uint64
+	if v := query.Get("since"); v != "" {
+		epoch, rev, parseErr := parseRevision(v)
+		if parseErr != nil || epoch != state.epoch || rev > state.revision {
+			m.Reset = true
+		} else {
+			since = rev
+		}
+	}
+
+	names := make([]string, 0, len(state.toolset.Manifest.ToolsManifest))
+	for name := range state.toolset.Manifest.ToolsManifest {
+		if state.toolRevisions[name] > since {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	offset := 0
+	if v := query.Get("pageToken"); v != "" {
+		var epoch int64
+		var rev uint64
+		epoch, rev, offset, err = parsePageToken(v)
+		if err != nil {
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+		if epoch != state.epoch || rev != state.revision {
+			err = fmt.Errorf("manifest changed since the page token was issued, restart from the first page")
+			_ = render.Render(w, r, newErrResponse(err, http.StatusGone))
+			return
+		}
+	}
+
+	if offset == 0 && since > 0 {
+		for name, rev := range state.removedTools {
+			if rev > since {
+				m.Removed = append(m.Removed, name)
+			}
+		}
+		slices.Sort(m.Removed)
+	}
+
+	end := min(offset+pageSize, len(names))
+	for _, name := range names[min(offset, len(names)):end] {
+		m.Tools[name] = selectManifestFields(state.toolset.Manifest.ToolsManifest[name], fields)
+	}
+	if end < len(names) {
+		m.NextPageToken = formatPageToken(state.epoch, state.revision, end)
+	}
+
+	render.JSON(w, r, m)
+}
+
+func parseManifestFields(v string) ([]string, error) {
+	if v == "" {
+		return manifestFields, nil
+	}
+	fields := strings.Split(v, ",")
+	for i, f := range fields {
+		fields[i] = strings.TrimSpace(f)
+		if !slices.Contains(manifestFields, fields[i]) {
+			return nil, fmt.Errorf("invalid field %q: must be one of %q", fields[i], manifestFields)
+		}
+	}
+	return fields, nil
+}
+
+func parseManifestPageSize(v string) (int, error) {
+	if v == "" {
+		return defaultManifestPageSize, nil
+	}
+	size, err := strconv.Atoi(v)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid page size %q: must be a positive integer", v)
+	}
+	return min(size, maxManifestPageSize), nil
+}
+
+func selectManifestFields(m tools.Manifest, fields []string) map[string]any {
+	selected := make(map[string]any, len(fields))
+	for _, f := range fields {
+		switch f {
+		case "description":
+			selected[f] = m.Description
+		case "parameters":
+			selected[f] = m.Parameters
+		case "authRequired":
+			selected[f] = m.AuthRequired
+		}
+	}
+	return selected
+}
+
+func formatRevision(epoch int64, rev uint64) string {
+	return fmt.Sprintf("%d.%d", epoch, rev)
+}
+
+func parseRevision(v string) (int64, uint64, error) {
+	epochStr, revStr, ok := strings.Cut(v, ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid revision %q", v)
+	}
+	epoch, err := strconv.ParseInt(epochStr, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid revision %q", v)
+	}
+	rev, err := strconv.ParseUint(revStr, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid revision %q", v)
+	}
+	return epoch, rev, nil
+}
+
+func formatPageToken(epoch int64, rev uint64, offset int) string {
+	raw := fmt.Sprintf("%s.%d", formatRevision(epoch, rev), offset)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parsePageToken(v string) (int64, uint64, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid page token %q", v)
+	}
+	i := strings.LastIndex(string(raw), ".")
+	if i < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid page token %q", v)
+	}
+	epoch, rev, err := parseRevision(string(raw[:i]))
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid page token %q", v)
+	}
+	offset, err := strconv.Atoi(string(raw[i+1:]))
+	if err != nil || offset < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid page token %q", v)
+	}
+	return epoch, rev, offset, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/telemetry"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestManifestRevisions(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r := NewResourceManager(nil, nil, toolsMap, toolsets)
+
+	before, ok := r.getManifestState("")
+	if !ok {
+		t.Fatalf("default toolset not found")
+	}
+
+	// reload with tool1 unchanged, tool2 removed, and tool3 added
+	newToolsMap, newToolsets := setUpResources(t, []MockTool{tool1, tool3})
+	r.SetResources(nil, nil, newToolsMap, newToolsets)
+
+	after, ok := r.getManifestState("")
+	if !ok {
+		t.Fatalf("default toolset not found")
+	}
+	if after.epoch != before.epoch {
+		t.Fatalf("epoch changed on reload")
+	}
+	if after.revision != before.revision+1 {
+		t.Fatalf("unexpected revision: want %d, got %d", before.revision+1, after.revision)
+	}
+	if got := after.toolRevisions[tool1.Name]; got != before.revision {
+		t.Errorf("unchanged tool revision was bumped: want %d, got %d", before.revision, got)
+	}
+	if got := after.toolRevisions[tool3.Name]; got != after.revision {
+		t.Errorf("unexpected revision for added tool: want %d, got %d", after.revision, got)
+	}
+	if got := after.removedTools[tool2.Name]; got != after.revision {
+		t.Errorf("unexpected revision for removed tool: want %d, got %d", after.revision, got)
+	}
+
+	// reload with tool1's manifest changed
+	changedTool1 := tool1
+	changedTool1.Description = "changed description"
+	r.SetResources(nil, nil, map[string]tools.Tool{tool1.Name: changedTool1}, newToolsets)
+	final, _ := r.getManifestState("")
+	if got := final.toolRevisions[tool1.Name]; got != final.revision {
+		t.Errorf("changed tool revision was not bumped: want %d, got %d", final.revision, got)
+	}
+}
+
+func TestManifestRevisionsToolsetMembership(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	r := NewResourceManager(nil, nil, toolsMap, toolsets)
+	before, _ := r.getManifestState("tool1_only")
+
+	// reload with both tools in tool1_only, without changing any tool
+	newToolsets := maps.Clone(toolsets)
+	newToolsets["tool1_only"] = initToolset(t, "tool1_only", toolsMap, tool1.Name, tool2.Name)
+	r.SetResources(nil, nil, toolsMap, newToolsets)
+	added, _ := r.getManifestState("tool1_only")
+	if got := added.toolRevisions[tool1.Name]; got != before.revision {
+		t.Errorf("unchanged member revision was bumped: want %d, got %d", before.revision, got)
+	}
+	if got := added.toolRevisions[tool2.Name]; got != added.revision {
+		t.Errorf("unexpected revision for added member: want %d, got %d", added.revision, got)
+	}
+	if got, _ := r.getManifestState(""); got.toolRevisions[tool2.Name] != before.revision {
+		t.Errorf("revision was bumped in a toolset the tool was already a member of: want %d, got %d", before.revision, got.toolRevisions[tool2.Name])
+	}
+
+	// reload with tool2 removed from tool1_only again, though it still exists
+	r.SetResources(nil, nil, toolsMap, toolsets)
+	removed, _ := r.getManifestState("tool1_only")
+	if got := removed.removedTools[tool2.Name]; got != removed.revision {
+		t.Errorf("unexpected revision for removed member: want %d, got %d", removed.revision, got)
+	}
+	if got, _ := r.getManifestState(""); got.removedTools[tool2.Name] != 0 {
+		t.Errorf("tool was removed from a toolset it's still a member of")
+	}
+}
+
+func TestManifestEndpointDelta(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	s := &Server{
+		version:         fakeVersionString,
+		instrumentation: instrumentation,
+		ResourceMgr:     NewResourceManager(nil, nil, toolsMap, toolsets),
+	}
+	getManifest := func(t *testing.T, since string) BulkManifest {
+		t.Helper()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("toolsetName", "tool1_only")
+		req := httptest.NewRequest(http.MethodGet, "/manifest/tool1_only?since="+since, nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		manifestHandler(s, w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: want %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+		}
+		var m BulkManifest
+		if err := json.Unmarshal(w.Body.Bytes(), &m); err != nil {
+			t.Fatalf("unable to parse BulkManifest: %s", err)
+		}
+		return m
+	}
+	toolNames := func(m BulkManifest) []string {
+		names := slices.Collect(maps.Keys(m.Tools))
+		slices.Sort(names)
+		return names
+	}
+
+	initial := getManifest(t, "")
+
+	// tool2 joins the toolset
+	joined := maps.Clone(toolsets)
+	joined["tool1_only"] = initToolset(t, "tool1_only", toolsMap, tool1.Name, tool2.Name)
+	s.ResourceMgr.SetResources(nil, nil, toolsMap, joined)
+	added := getManifest(t, initial.Revision)
+	if diff := cmp.Diff([]string{tool2.Name}, toolNames(added)); diff != "" {
+		t.Fatalf("incorrect tools in delta (-want +got):\n%s", diff)
+	}
+	if len(added.Removed) != 0 {
+		t.Fatalf("unexpected removed tools: %q", added.Removed)
+	}
+
+	// tool2 leaves the toolset, but is still served
+	s.ResourceMgr.SetResources(nil, nil, toolsMap, toolsets)
+	removed := getManifest(t, added.Revision)
+	if len(removed.Tools) != 0 {
+		t.Fatalf("unexpected tools in delta: %q", toolNames(removed))
+	}
+	if diff := cmp.Diff([]string{tool2.Name}, removed.Removed); diff != "" {
+		t.Fatalf("incorrect removed tools (-want +got):\n%s", diff)
+	}
+}
+
+// initToolset initializes a toolset of the given tools.
+func initToolset(t *testing.T, name string, toolsMap map[string]tools.Tool, toolNames ...string) tools.Toolset {
+	t.Helper()
+	ts, err := tools.ToolsetConfig{Name: name, ToolNames: toolNames}.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset %q: %s", name, err)
+	}
+	return ts
+}
@@ -34,6 +34,8 @@ import (
 	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	v20241105 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20241105"
 	v20250326 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250326"
+	v20250618 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250618"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -46,6 +48,8 @@ type sseSession struct {
 	done       chan struct{}
 	eventQueue chan string
 	lastActive time.Time
+	// sampling sends sampling requests as events of the session.
+	sampling *samplingClient
 }
 
 // sseManager manages and control access to sse sessions
@@ -110,26 +114,36 @@ func (m *sseManager) cleanupRoutine(ctx context.Context) {
 
 type stdioSession struct {
 	protocol string
+	// toolset is the name of the toolset served, all tools if empty.
+	toolset  string
 	server   *Server
 	reader   *bufio.Reader
 	writer   io.Writer
+	writeMu  sync.Mutex
+	sampling *samplingClient
 }
 
 func NewStdioSession(s *Server, stdin io.Reader, stdout io.Writer) *stdioSession {
 	stdioSession := &stdioSession{
-		server: s,
-		reader: bufio.NewReader(stdin),
-		writer: stdout,
+		toolset: s.stdioToolset,
+		server:  s,
+		reader:  bufio.NewReader(stdin),
+		writer:  stdout,
 	}
+	stdioSession.sampling = newSamplingClient(stdioSession.write)
 	return stdioSession
 }
 
 func (s *stdioSession) Start(ctx context.Context) error {
+	ctx = util.WithInvocationTracker(ctx, s.server.invocations)
+	ctx = tools.WithJobStore(ctx, s.server.jobs)
 	return s.readInputStream(ctx)
 }
 
 // readInputStream reads requests/notifications from MCP clients through stdin
 func (s *stdioSession) readInputStream(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -141,22 +155,48 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 			}
 			return err
 		}
-		v, res, err := processMcpMessage(ctx, []byte(line), s.server, s.protocol, "")
+		if s.sampling.deliver([]byte(line)) {
+			continue
+		}
+		s.sampling.observeInitialize([]byte(line))
+		if s.sampling.enabled.Load() {
+			// the tools called can wait for responses to sampling requests,
+			// which are read by this loop, so they run concurrently
+			wg.Add(1)
+			go func(protocol string) {
+				defer wg.Done()
+				if _, err := s.process(ctx, line, protocol); err != nil {
+					s.server.logger.ErrorContext(ctx, err.Error())
+				}
+			}(s.protocol)
+			continue
+		}
+		v, err := s.process(ctx, line, s.protocol)
 		if err != nil {
-			// errors during the processing of message will generate a valid MCP Error response.
-			// server can continue to run.
-			s.server.logger.ErrorContext(ctx, err.Error())
+			return err
 		}
 		if v != "" {
 			s.protocol = v
 		}
-		// no responses for notifications
-		if res != nil {
-			if err = s.write(ctx, res); err != nil {
-				return err
-			}
+	}
+}
+
+// process handles a request or notification, and writes its response. It only
+// returns errors writing the response.
+func (s *stdioSession) process(ctx context.Context, line, protocol string) (string, error) {
+	v, res, err := processMcpMessage(s.sampling.withModel(ctx), []byte(line), s.server, protocol, s.toolset)
+	if err != nil {
+		// errors during the processing of message will generate a valid MCP Error response.
+		// server can continue to run.
+		s.server.logger.ErrorContext(ctx, err.Error())
+	}
+	// no responses for notifications
+	if res != nil {
+		if err = s.write(ctx, res); err != nil {
+			return "", err
 		}
 	}
+	return v, nil
 }
 
 // readLine process each line within the input stream.
@@ -206,6 +246,8 @@ func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 func (s *stdioSession) write(ctx context.Context, response any) error {
 	res, _ := json.Marshal(response)
 
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 	_, err := fmt.Fprintf(s.writer, "%s\n", res)
 	return err
 }
@@ -280,6 +322,20 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		done:       make(chan struct{}),
 		eventQueue: make(chan string, 100),
 	}
+	session.sampling = newSamplingClient(func(ctx context.Context, msg any) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		select {
+		case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", data):
+			return nil
+		case <-session.done:
+			return fmt.Errorf("session is closed")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
 	s.sseManager.add(sessionId, session)
 	defer s.sseManager.remove(sessionId)
 
@@ -332,6 +388,16 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp")
 	r = r.WithContext(ctx)
 	ctx = util.WithLogger(r.Context(), s.logger)
+	ctx = util.WithInvocationTracker(ctx, s.invocations)
+	ctx = tools.WithJobStore(ctx, s.jobs)
+	ctx = withAnonymousClient(ctx, s.anonymousAccess().client(r.RemoteAddr, r.Header))
+	// invocations are tracked under the identity of any valid auth token
+	// sent. Tokens are only verified once a tool is called, not for every
+	// message of the session.
+	callerCtx := ctx
+	ctx = util.WithInvocationCaller(ctx, func() string {
+		return callerFromClaims(s.claimsFromHeader(callerCtx, r.Header))
+	})
 
 	var sessionId, protocolVersion string
 	var session *sseSession
@@ -401,6 +467,16 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if session != nil {
+		// clients of sse sessions post the responses to sampling requests
+		if session.sampling.deliver(body) {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		session.sampling.observeInitialize(body)
+		ctx = session.sampling.withModel(ctx)
+	}
+
 	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName)
 	// notifications will return empty string
 	if res == nil {
@@ -481,7 +557,10 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 
 	switch baseMessage.Method {
 	case mcputil.INITIALIZE:
-		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version)
+		// a toolset removed by a reload introduces itself as Toolbox, and
+		// fails the requests that follow
+		toolset, _ := s.ResourceMgr.GetToolset(toolsetName)
+		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version, toolset.Server)
 		if err != nil {
 			return "", res, err
 		}
@@ -492,7 +571,35 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 			err = fmt.Errorf("toolset does not exist")
 			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 		}
-		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.ResourceMgr.GetToolsMap(), body)
+		anonymous := s.anonymousAccess()
+		if client, ok := anonymousClientFromContext(ctx); ok && anonymous != nil {
+			// remote MCP clients can't authenticate, so they only see and
+			// invoke the tools of the anonymous access tier
+			toolset = anonymous.filterToolset(toolset)
+			if baseMessage.Method == v20250618.TOOLS_CALL {
+				if err = anonymous.admit(mcpToolName(body), client); err != nil {
+					te := tools.ClassifyError(err, tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized)
+					return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), te), err
+				}
+			}
+		}
+		toolsMap := s.ResourceMgr.GetToolsMap()
+		if toolsetName != "" {
+			toolsMap = toolsetTools(toolset, toolsMap)
+		}
+		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, toolsMap, body)
 		return "", res, err
 	}
 }
+
+// toolsetTools returns the tools of toolsMap in toolset, so that the clients
+// of a toolset can't call the tools outside of it.
+func toolsetTools(toolset tools.Toolset, toolsMap map[string]tools.Tool) map[string]tools.Tool {
+	out := make(map[string]tools.Tool, len(toolset.Manifest.ToolsManifest))
+	for name := range toolset.Manifest.ToolsManifest {
+		if t, ok := toolsMap[name]; ok {
+			out[name] = t
+		}
+	}
+	return out
+}
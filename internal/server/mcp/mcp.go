@@ -42,7 +42,9 @@ var SUPPORTED_PROTOCOL_VERSIONS = []string{
 // InitializeResponse runs capability negotiation and protocol version agreement.
 // This is the Initialization phase of the lifecycle for MCP client-server connections.
 // Always start with the latest protocol version supported.
-func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string) (any, string, error) {
+// serverInfo overrides the name and version the server introduces itself
+// with, and sets its instructions.
+func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string, serverInfo tools.ServerInfo) (any, string, error) {
 	var req mcputil.InitializeRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp initialize request: %w", err)
@@ -57,6 +59,14 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 		protocolVersion = LATEST_PROTOCOL_VERSION
 	}
 
+	name, version := mcputil.SERVER_NAME, toolboxVersion
+	if serverInfo.Name != "" {
+		name = serverInfo.Name
+	}
+	if serverInfo.Version != "" {
+		version = serverInfo.Version
+	}
+
 	toolsListChanged := false
 	result := mcputil.InitializeResult{
 		ProtocolVersion: protocolVersion,
@@ -67,10 +77,11 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 		},
 		ServerInfo: mcputil.Implementation{
 			BaseMetadata: mcputil.BaseMetadata{
-				Name: mcputil.SERVER_NAME,
+				Name: name,
 			},
-			Version: toolboxVersion,
+			Version: version,
 		},
+		Instructions: serverInfo.Instructions,
 	}
 	res := jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -0,0 +1,36 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "encoding/json"
+
+// RESULT_ENCODING_META_KEY is the `_meta` key of a tool call request that asks
+// for an encoding of the result, and of the result if it was encoded.
+const RESULT_ENCODING_META_KEY = "toolbox/resultEncoding"
+
+// RequestedResultEncoding returns the result encoding a tool call request
+// asks for in its `_meta`, or "" if it doesn't ask for one.
+func RequestedResultEncoding(body []byte) string {
+	var req struct {
+		Params struct {
+			Meta map[string]any `json:"_meta"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	encoding, _ := req.Params.Meta[RESULT_ENCODING_META_KEY].(string)
+	return encoding
+}
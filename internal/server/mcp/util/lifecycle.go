@@ -79,7 +79,7 @@ type ClientCapabilities struct {
 	// Present if the client supports listing roots.
 	Roots *ListChanged `json:"roots,omitempty"`
 	// Present if the client supports sampling from an LLM.
-	Sampling struct{} `json:"sampling,omitempty"`
+	Sampling *struct{} `json:"sampling,omitempty"`
 }
 
 // ServerCapabilities represents capabilities that a server may support. Known
@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+// SAMPLING_CREATE_MESSAGE is the method of the requests a server sends to ask
+// the client to sample its LLM. It's the same in every supported version.
+const SAMPLING_CREATE_MESSAGE = "sampling/createMessage"
+
+// TextContent is the text of a sampling message.
+type TextContent struct {
+	// Always "text".
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SamplingMessage is a message of a sampling request or result.
+type SamplingMessage struct {
+	// "user" or "assistant".
+	Role    string      `json:"role"`
+	Content TextContent `json:"content"`
+}
+
+// CreateMessageParams are the parameters of a sampling request.
+type CreateMessageParams struct {
+	Messages []SamplingMessage `json:"messages"`
+	// An optional system prompt the server wants to use for sampling. The
+	// client MAY modify or omit this prompt.
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+	// The maximum number of tokens to sample. The client MAY choose to
+	// sample fewer tokens than requested.
+	MaxTokens int `json:"maxTokens"`
+}
+
+// CreateMessageResult is the client's response to a sampling request.
+type CreateMessageResult struct {
+	SamplingMessage
+	// The name of the model that generated the message.
+	Model string `json:"model"`
+	// The reason why sampling stopped, if known.
+	StopReason string `json:"stopReason,omitempty"`
+}
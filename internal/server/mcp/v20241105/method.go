@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
@@ -45,8 +46,12 @@ func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	mcpManifest, err := toolset.SerializedMcpManifest()
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
 	result := ListToolsResult{
-		Tools: toolset.McpManifest,
+		Tools: mcpManifest,
 	}
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -56,7 +61,7 @@ func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte)
 }
 
 // toolsCallHandler generate a response for tools call.
-func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[string]tools.Tool, body []byte) (any, error) {
+func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolsMap map[string]tools.Tool, body []byte) (any, error) {
 	// retrieve logger from context
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -72,10 +77,11 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[strin
 	toolName := req.Params.Name
 	toolArgument := req.Params.Arguments
 	logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
-	tool, ok := tools[toolName]
+	tool, ok := toolsMap[toolName]
 	if !ok {
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
-		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+		te := tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeToolNotFound, "", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), te), err
 	}
 
 	// marshal arguments and decode it using decodeJSON instead to prevent loss between floats/int.
@@ -98,29 +104,64 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[strin
 	params, err := tool.ParseParams(data, claimsFromAuth)
 	if err != nil {
 		err = fmt.Errorf("provided parameters were invalid: %w", err)
-		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+		te := tools.ClassifyError(err, tools.ErrorCategoryValidation, tools.ErrorCodeInvalidParameters)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), te), err
 	}
 	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
 	if !tool.Authorized([]string{}) {
 		err = fmt.Errorf("unauthorized Tool call: `authRequired` is set for the target Tool")
-		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+		te := tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized, "", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), te), err
+	}
+
+	// track the invocation, queueing it if the server is at capacity.
+	// invocations are tracked under the caller identified by the transport.
+	if tracker, err := util.InvocationTrackerFromContext(ctx); err == nil {
+		var done func()
+		ctx, done, err = tracker.BeginTool(ctx, toolName, tool, util.InvocationCallerFromContext(ctx))
+		if err != nil {
+			te := tools.ClassifyError(err, tools.ErrorCategoryInternal, tools.ErrorCodeInternal)
+			return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), te), err
+		}
+		defer done()
 	}
 
 	// run tool invocation and generate response.
 	results, err := tool.Invoke(ctx, params)
 	if err != nil {
+		if tracker, terr := util.InvocationTrackerFromContext(ctx); terr == nil {
+			tracker.RecordError(ctx, err)
+		}
 		text := TextContent{
 			Type: "text",
 			Text: err.Error(),
 		}
+		te := tools.ClassifyError(err, tools.ErrorCategoryQuery, tools.ErrorCodeQueryFailed)
+		result := CallToolResult{Content: []TextContent{text}, IsError: true}
+		result.Meta = map[string]any{mcputil.TOOL_ERROR_META_KEY: te}
 		return jsonrpc.JSONRPCResponse{
 			Jsonrpc: jsonrpc.JSONRPC_VERSION,
 			Id:      id,
-			Result:  CallToolResult{Content: []TextContent{text}, IsError: true},
+			Result:  result,
 		}, nil
 	}
 
+	// clients that can decode columnar results ask for them in `_meta`
+	if mcputil.RequestedResultEncoding(body) == tools.ResultEncodingColumnar {
+		if c, ok := tools.EncodeColumnar(results); ok {
+			if cM, err := json.Marshal(c); err == nil {
+				result := CallToolResult{Content: []TextContent{{Type: "text", Text: string(cM)}}}
+				result.Meta = map[string]any{mcputil.RESULT_ENCODING_META_KEY: tools.ResultEncodingColumnar}
+				return jsonrpc.JSONRPCResponse{
+					Jsonrpc: jsonrpc.JSONRPC_VERSION,
+					Id:      id,
+					Result:  result,
+				}, nil
+			}
+		}
+	}
+
 	content := make([]TextContent, 0)
 
 	sliceRes, ok := results.([]any)
@@ -15,8 +15,9 @@
 package v20250618
 
 import (
+	"encoding/json"
+
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
-	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 // SERVER_NAME is the server name used in Implementation.
@@ -67,7 +68,8 @@ type ListToolsRequest struct {
 // The server's response to a tools/list request from the client.
 type ListToolsResult struct {
 	PaginatedResult
-	Tools []tools.McpManifest `json:"tools"`
+	// Tools is the serialized list of tools.McpManifest, precomputed per toolset.
+	Tools json.RawMessage `json:"tools"`
 }
 
 // Used by the client to invoke a tool provided by the server.
@@ -20,17 +20,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 const jsonrpcVersion = "2.0"
@@ -755,6 +760,7 @@ func TestStdioSession(t *testing.T) {
 		logger:          testLogger,
 		instrumentation: instrumentation,
 		sseManager:      sseManager,
+		invocations:     invocations.NewTracker(0),
 		ResourceMgr:     resourceManager,
 	}
 
@@ -792,3 +798,146 @@ func TestStdioSession(t *testing.T) {
 		t.Fatalf("unexpected read: got %s, want %s", read, want)
 	}
 }
+
+func TestStdioSessionToolset(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx := util.WithLogger(context.Background(), testLogger)
+
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	tc := tools.ToolsetConfig{
+		Name:      "analyst",
+		ToolNames: []string{tool1.Name},
+		Server:    tools.ServerInfo{Name: "analytics", Version: "2.1.0", Instructions: "Answers questions."},
+	}
+	if toolsets["analyst"], err = tc.Initialize(fakeVersionString, toolsMap); err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	server := &Server{
+		version:      fakeVersionString,
+		logger:       testLogger,
+		stdioToolset: "analyst",
+		ResourceMgr:  NewResourceManager(nil, nil, toolsMap, toolsets),
+	}
+	session := NewStdioSession(server, strings.NewReader(""), io.Discard)
+
+	send := func(req jsonrpc.JSONRPCRequest) map[string]any {
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("unable to marshal request: %s", err)
+		}
+		_, res, _ := processMcpMessage(ctx, body, server, protocolVersion20250618, session.toolset)
+		b, err := json.Marshal(res)
+		if err != nil {
+			t.Fatalf("unable to marshal response: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("unable to unmarshal response: %s", err)
+		}
+		return got
+	}
+
+	got := send(jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "init",
+		Request: jsonrpc.Request{Method: "initialize"},
+		Params:  map[string]any{"protocolVersion": protocolVersion20250618},
+	})
+	result, _ := got["result"].(map[string]any)
+	wantInfo := map[string]any{"name": "analytics", "version": "2.1.0"}
+	if !reflect.DeepEqual(result["serverInfo"], wantInfo) || result["instructions"] != "Answers questions." {
+		t.Fatalf("unexpected initialize result: %v", got)
+	}
+
+	// tools outside of the toolset can't be called
+	got = send(jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpcVersion,
+		Id:      "call",
+		Request: jsonrpc.Request{Method: "tools/call"},
+		Params:  map[string]any{"name": tool2.Name, "arguments": map[string]any{"param1": 1, "param2": 2}},
+	})
+	if _, ok := got["error"]; !ok {
+		t.Fatalf("expected an error calling a tool outside of the toolset, got %v", got)
+	}
+}
+
+// countingAuthService counts the headers it verifies.
+type countingAuthService struct {
+	fakeAuthService
+	verified *atomic.Int32
+}
+
+func (a countingAuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	a.verified.Add(1)
+	return a.fakeAuthService.GetClaimsFromHeader(ctx, h)
+}
+
+func TestMcpVerifiesTokensOnlyForToolCalls(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	verified := &atomic.Int32{}
+	authService := countingAuthService{
+		fakeAuthService: fakeAuthService{name: "users", tokens: map[string]map[string]any{"alice-token": {"sub": "alice"}}},
+		verified:        verified,
+	}
+
+	testLogger, err := log.NewStdLogger(io.Discard, io.Discard, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(fakeVersionString)
+	if err != nil {
+		t.Fatalf("unable to create custom metrics: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Server{
+		version:         fakeVersionString,
+		logger:          testLogger,
+		instrumentation: instrumentation,
+		sseManager:      newSseManager(ctx),
+		invocations:     invocations.NewTracker(0),
+		jobs:            tools.NewJobStore(0),
+		ResourceMgr:     NewResourceManager(nil, map[string]auth.AuthService{"users": authService}, toolsMap, toolsets),
+	}
+	r, err := mcpRouter(s)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	header := map[string]string{"users_token": "alice-token"}
+	tcs := []struct {
+		method       string
+		params       map[string]any
+		wantVerified int32
+	}{
+		{method: "initialize", params: map[string]any{"protocolVersion": protocolVersion20250326}},
+		{method: "notifications/initialized"},
+		{method: "tools/list"},
+		{method: "tools/call", params: map[string]any{"name": "no_params", "arguments": map[string]any{}}, wantVerified: 1},
+	}
+	for _, tc := range tcs {
+		body := map[string]any{"jsonrpc": jsonrpcVersion, "method": tc.method}
+		if !strings.HasPrefix(tc.method, "notifications/") {
+			body["id"] = tc.method
+		}
+		if tc.params != nil {
+			body["params"] = tc.params
+		}
+		reqMarshal, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body: %s", err)
+		}
+		if _, _, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header); err != nil {
+			t.Fatalf("unexpected error during %s request: %s", tc.method, err)
+		}
+		// the token is verified once per request, and only to call a tool
+		if got := verified.Swap(0); got != tc.wantVerified {
+			t.Errorf("%s: token verified %d times, want %d", tc.method, got, tc.wantVerified)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// closableSource records whether it was closed.
+type closableSource struct {
+	closed chan struct{}
+}
+
+func newClosableSource() *closableSource {
+	return &closableSource{closed: make(chan struct{})}
+}
+
+func (s *closableSource) SourceKind() string { return "closable" }
+
+func (s *closableSource) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func (s *closableSource) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestCloseReplacedSources(t *testing.T) {
+	logger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	reused, replaced, removed := newClosableSource(), newClosableSource(), newClosableSource()
+	prev := map[string]sources.Source{"reused": reused, "replaced": replaced, "removed": removed}
+	s := &Server{
+		logger:      logger,
+		invocations: invocations.NewTracker(0),
+		ResourceMgr: NewResourceManager(prev, nil, map[string]tools.Tool{}, map[string]tools.Toolset{}),
+	}
+
+	// an invocation started before the reload may still use the old sources
+	_, done, err := s.invocations.Begin(context.Background(), "my_tool", "")
+	if err != nil {
+		t.Fatalf("unable to begin invocation: %s", err)
+	}
+	replacement := newClosableSource()
+	s.ResourceMgr.SetResources(map[string]sources.Source{"reused": reused, "replaced": replacement}, nil, map[string]tools.Tool{}, map[string]tools.Toolset{})
+	closed := make(chan struct{})
+	go func() {
+		s.CloseReplacedSources(context.Background(), prev)
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("sources were closed while an invocation is in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	done()
+	<-closed
+	if !replaced.isClosed() || !removed.isClosed() {
+		t.Errorf("expected the replaced and removed sources to be closed")
+	}
+	if reused.isClosed() || replacement.isClosed() {
+		t.Errorf("closed a source that's still in use")
+	}
+}
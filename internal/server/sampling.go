@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
+)
+
+// defaultSamplingMaxTokens is the longest response sampled from clients,
+// unless the request sets another limit.
+const defaultSamplingMaxTokens = 1024
+
+// samplingClient generates responses with the LLM of an MCP client, by sending
+// it sampling requests over the session it's connected with.
+type samplingClient struct {
+	// send writes a message to the client.
+	send func(ctx context.Context, msg any) error
+	// enabled is set once the client declares it supports sampling.
+	enabled atomic.Bool
+	nextID  atomic.Int64
+
+	mu      sync.Mutex
+	pending map[string]chan samplingResponse
+}
+
+type samplingResponse struct {
+	Id     jsonrpc.RequestId            `json:"id"`
+	Result *mcputil.CreateMessageResult `json:"result"`
+	Error  *jsonrpc.Error               `json:"error"`
+}
+
+var _ llm.Model = &samplingClient{}
+
+func newSamplingClient(send func(ctx context.Context, msg any) error) *samplingClient {
+	return &samplingClient{send: send, pending: make(map[string]chan samplingResponse)}
+}
+
+// withModel returns ctx with the client's model, if the client supports
+// sampling.
+func (c *samplingClient) withModel(ctx context.Context) context.Context {
+	if !c.enabled.Load() {
+		return ctx
+	}
+	return llm.WithClientModel(ctx, c)
+}
+
+// Generate sends a sampling request to the client, and waits for its result.
+func (c *samplingClient) Generate(ctx context.Context, req llm.Request) (string, error) {
+	id := fmt.Sprintf("toolbox-sampling-%d", c.nextID.Add(1))
+	ch := make(chan samplingResponse, 1)
+	c.mu.Lock()
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	system := req.System
+	if req.ResponseSchema != nil {
+		// sampling can't constrain responses, so the schema is only asked for
+		schema, err := json.Marshal(req.ResponseSchema)
+		if err != nil {
+			return "", err
+		}
+		system += fmt.Sprintf("\nRespond with JSON matching this OpenAPI schema, and nothing else: %s", schema)
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = defaultSamplingMaxTokens
+	}
+	msg := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Request: jsonrpc.Request{Method: mcputil.SAMPLING_CREATE_MESSAGE},
+		Params: mcputil.CreateMessageParams{
+			Messages:     []mcputil.SamplingMessage{{Role: "user", Content: mcputil.TextContent{Type: "text", Text: req.Prompt}}},
+			SystemPrompt: system,
+			MaxTokens:    maxTokens,
+		},
+	}
+	if err := c.send(ctx, msg); err != nil {
+		return "", fmt.Errorf("unable to send sampling request: %w", err)
+	}
+
+	var resp samplingResponse
+	select {
+	case resp = <-ch:
+	case <-ctx.Done():
+		return "", fmt.Errorf("no response to sampling request: %w", ctx.Err())
+	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("client rejected sampling request: %s", resp.Error.Message)
+	}
+	if resp.Result == nil || resp.Result.Content.Type != "text" {
+		return "", fmt.Errorf("client returned no text for sampling request")
+	}
+	return resp.Result.Content.Text, nil
+}
+
+// deliver hands body to the sampling request it responds to, reporting
+// whether it was such a response.
+func (c *samplingClient) deliver(body []byte) bool {
+	var msg struct {
+		samplingResponse
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil || msg.Method != "" {
+		return false
+	}
+	id, ok := msg.Id.(string)
+	if !ok {
+		return false
+	}
+	c.mu.Lock()
+	ch, ok := c.pending[id]
+	c.mu.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case ch <- msg.samplingResponse:
+	default:
+		// a duplicate response
+	}
+	return true
+}
+
+// observeInitialize enables sampling if body is an initialize request of a
+// client that supports it.
+func (c *samplingClient) observeInitialize(body []byte) {
+	var req mcputil.InitializeRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Method != mcputil.INITIALIZE {
+		return
+	}
+	c.enabled.Store(req.Params.Capabilities.Sampling != nil)
+}
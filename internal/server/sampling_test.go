@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// samplingTool answers with the client's model.
+type samplingTool struct {
+	MockTool
+}
+
+func (t samplingTool) Invoke(ctx context.Context, _ tools.ParamValues) (any, error) {
+	model, ok := llm.ClientModelFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("client can't be sampled")
+	}
+	return model.Generate(ctx, llm.Request{System: "Be brief.", Prompt: "Summarize."})
+}
+
+func TestSamplingClient(t *testing.T) {
+	var c *samplingClient
+	c = newSamplingClient(func(ctx context.Context, msg any) error {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		var req map[string]any
+		if err := json.Unmarshal(b, &req); err != nil {
+			return err
+		}
+		params, _ := req["params"].(map[string]any)
+		if req["method"] != "sampling/createMessage" || params["systemPrompt"] != "Be brief." || params["maxTokens"] != float64(defaultSamplingMaxTokens) {
+			return fmt.Errorf("unexpected request: %s", b)
+		}
+		go c.deliver([]byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%q,"result":{"role":"assistant","content":{"type":"text","text":"done"},"model":"m"}}`, req["id"])))
+		return nil
+	})
+	got, err := c.Generate(context.Background(), llm.Request{System: "Be brief.", Prompt: "Summarize."})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != "done" {
+		t.Fatalf("unexpected response: %q", got)
+	}
+
+	// responses to unknown requests aren't delivered
+	if c.deliver([]byte(`{"jsonrpc":"2.0","id":"toolbox-sampling-1","result":{}}`)) {
+		t.Fatalf("delivered a response after its request was answered")
+	}
+	if c.deliver([]byte(`{"jsonrpc":"2.0","id":"toolbox-sampling-1","method":"tools/list"}`)) {
+		t.Fatalf("delivered a request")
+	}
+}
+
+func TestSamplingClientErrors(t *testing.T) {
+	var c *samplingClient
+	c = newSamplingClient(func(ctx context.Context, msg any) error {
+		b, _ := json.Marshal(msg)
+		var req struct {
+			Id string `json:"id"`
+		}
+		_ = json.Unmarshal(b, &req)
+		go c.deliver([]byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%q,"error":{"code":-1,"message":"User rejected sampling request"}}`, req.Id)))
+		return nil
+	})
+	if _, err := c.Generate(context.Background(), llm.Request{Prompt: "Summarize."}); err == nil || !strings.Contains(err.Error(), "User rejected") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// clients that don't respond time out with the invocation
+	c = newSamplingClient(func(ctx context.Context, msg any) error { return nil })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Generate(ctx, llm.Request{Prompt: "Summarize."}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestStdioSessionSampling(t *testing.T) {
+	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "warn")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	ctx, cancel := context.WithCancel(util.WithLogger(context.Background(), testLogger))
+	defer cancel()
+
+	tool := samplingTool{MockTool{Name: "summarize", Params: tools.Parameters{}}}
+	toolsMap := map[string]tools.Tool{tool.Name: tool}
+	toolset, err := tools.ToolsetConfig{ToolNames: []string{tool.Name}}.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	server := &Server{
+		version:     fakeVersionString,
+		logger:      testLogger,
+		invocations: invocations.NewTracker(0),
+		ResourceMgr: NewResourceManager(nil, nil, toolsMap, map[string]tools.Toolset{"": toolset}),
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	session := NewStdioSession(server, inR, outW)
+	done := make(chan error, 1)
+	go func() { done <- session.Start(ctx) }()
+
+	out := bufio.NewReader(outR)
+	send := func(msg string) {
+		if _, err := fmt.Fprintln(inW, msg); err != nil {
+			t.Fatalf("unable to write: %s", err)
+		}
+	}
+	read := func() map[string]any {
+		line, err := out.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("unable to unmarshal %q: %s", line, err)
+		}
+		return got
+	}
+
+	send(`{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"sampling":{}}}}`)
+	if got := read(); got["id"] != "init" {
+		t.Fatalf("unexpected initialize response: %v", got)
+	}
+	send(`{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"name":"summarize","arguments":{}}}`)
+
+	// the tool call waits for the client to answer the sampling request
+	req := read()
+	if req["method"] != "sampling/createMessage" {
+		t.Fatalf("expected a sampling request, got %v", req)
+	}
+	send(fmt.Sprintf(`{"jsonrpc":"2.0","id":%q,"result":{"role":"assistant","content":{"type":"text","text":"all good"},"model":"m"}}`, req["id"]))
+
+	got := read()
+	if got["id"] != "call" || !strings.Contains(fmt.Sprint(got["result"]), "all good") {
+		t.Fatalf("unexpected tool call response: %v", got)
+	}
+
+	_ = inW.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -18,8 +18,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
+	"reflect"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -28,6 +31,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog/v2"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
@@ -35,6 +39,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 )
 
 // Server contains info for running an instance of Toolbox. Should be instantiated with NewServer().
@@ -42,20 +47,54 @@ type Server struct {
 	version         string
 	srv             *http.Server
 	listener        net.Listener
+	grpcSrv         *grpc.Server
+	grpcAddr        string
+	grpcListener    net.Listener
 	root            chi.Router
 	logger          log.Logger
 	instrumentation *telemetry.Instrumentation
 	sseManager      *sseManager
-	ResourceMgr     *ResourceManager
+	invocations     *invocations.Tracker
+	jobs            *tools.JobStore
+	schedulesMu     sync.Mutex
+	scheduler       *tools.Scheduler
+	anonymousMu     sync.RWMutex
+	anonymous       *anonymousTier
+	disableReload   bool
+	stdioToolset    string
+	adminToken      string
+	approvalWebhook string
+	// streamStallTimeout is how long a client may stop reading a streamed
+	// result. 0 disables the timeout.
+	streamStallTimeout time.Duration
+	// drainTimeout is how long in-flight invocations may run once the server
+	// is shutting down.
+	drainTimeout time.Duration
+	ResourceMgr  *ResourceManager
 }
 
 // ResourceManager contains available resources for the server. Should be initialized with NewResourceManager().
 type ResourceManager struct {
-	mu           sync.RWMutex
-	sources      map[string]sources.Source
-	authServices map[string]auth.AuthService
-	tools        map[string]tools.Tool
-	toolsets     map[string]tools.Toolset
+	mu            sync.RWMutex
+	sourceConfigs SourceConfigs
+	sources       map[string]sources.Source
+	authServices  map[string]auth.AuthService
+	tools         map[string]tools.Tool
+	toolsets      map[string]tools.Toolset
+
+	// epoch identifies this ResourceManager so that revisions handed out by a
+	// previous server process are never mistaken for current ones.
+	// revision is incremented every time resources are replaced.
+	// toolRevisions records the revision at which each tool's manifest last
+	// changed, and removedTools the revision at which a tool was removed.
+	// joinedToolsets and leftToolsets record, per toolset, the revision at
+	// which a tool was added to or removed from the toolset.
+	epoch          int64
+	revision       uint64
+	toolRevisions  map[string]uint64
+	removedTools   map[string]uint64
+	joinedToolsets map[string]map[string]uint64
+	leftToolsets   map[string]map[string]uint64
 }
 
 func NewResourceManager(
@@ -69,6 +108,16 @@ func NewResourceManager(
 		authServices: authServicesMap,
 		tools:        toolsMap,
 		toolsets:     toolsetsMap,
+
+		epoch:          time.Now().UnixNano(),
+		revision:       1,
+		toolRevisions:  make(map[string]uint64, len(toolsMap)),
+		removedTools:   make(map[string]uint64),
+		joinedToolsets: make(map[string]map[string]uint64),
+		leftToolsets:   make(map[string]map[string]uint64),
+	}
+	for name := range toolsMap {
+		resourceMgr.toolRevisions[name] = resourceMgr.revision
 	}
 
 	return resourceMgr
@@ -105,18 +154,142 @@ func (r *ResourceManager) GetToolset(toolsetName string) (tools.Toolset, bool) {
 func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.updateRevisions(toolsMap, toolsetsMap)
 	r.sources = sourcesMap
 	r.authServices = authServicesMap
 	r.tools = toolsMap
 	r.toolsets = toolsetsMap
 }
 
+// updateRevisions bumps the resource revision and records which tools were
+// added, changed, or removed compared to the current tools, and which tools
+// were added to or removed from each toolset. Must be called with the lock
+// held.
+func (r *ResourceManager) updateRevisions(toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset) {
+	r.revision++
+	for name, t := range toolsMap {
+		old, ok := r.tools[name]
+		if !ok || old == nil || t == nil || !reflect.DeepEqual(old.Manifest(), t.Manifest()) {
+			r.toolRevisions[name] = r.revision
+		}
+		delete(r.removedTools, name)
+	}
+	for name := range r.tools {
+		if _, ok := toolsMap[name]; !ok {
+			delete(r.toolRevisions, name)
+			r.removedTools[name] = r.revision
+		}
+	}
+
+	joinedToolsets := make(map[string]map[string]uint64, len(toolsetsMap))
+	leftToolsets := make(map[string]map[string]uint64, len(toolsetsMap))
+	for tsName, ts := range toolsetsMap {
+		joined, left := make(map[string]uint64), make(map[string]uint64)
+		old, ok := r.toolsets[tsName]
+		if ok {
+			maps.Copy(joined, r.joinedToolsets[tsName])
+			maps.Copy(left, r.leftToolsets[tsName])
+		}
+		// every tool of a new toolset is new to it
+		for name := range ts.Manifest.ToolsManifest {
+			if _, wasMember := old.Manifest.ToolsManifest[name]; !ok || !wasMember {
+				joined[name] = r.revision
+				delete(left, name)
+			}
+		}
+		for name := range old.Manifest.ToolsManifest {
+			if _, isMember := ts.Manifest.ToolsManifest[name]; !isMember {
+				left[name] = r.revision
+				delete(joined, name)
+			}
+		}
+		joinedToolsets[tsName], leftToolsets[tsName] = joined, left
+	}
+	r.joinedToolsets, r.leftToolsets = joinedToolsets, leftToolsets
+}
+
+// manifestState is a consistent view of a toolset and the revisions of its
+// tools, used to serve paginated and delta manifests.
+type manifestState struct {
+	toolset  tools.Toolset
+	epoch    int64
+	revision uint64
+	// toolRevisions is the revision at which each tool of the toolset last
+	// changed or joined the toolset, and removedTools the revision at which
+	// a tool was removed from the server or left the toolset.
+	toolRevisions map[string]uint64
+	removedTools  map[string]uint64
+}
+
+func (r *ResourceManager) getManifestState(toolsetName string) (manifestState, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	toolset, ok := r.toolsets[toolsetName]
+	if !ok {
+		return manifestState{}, false
+	}
+	state := manifestState{
+		toolset:       toolset,
+		epoch:         r.epoch,
+		revision:      r.revision,
+		toolRevisions: make(map[string]uint64, len(toolset.Manifest.ToolsManifest)),
+		removedTools:  make(map[string]uint64, len(r.removedTools)),
+	}
+	joined := r.joinedToolsets[toolsetName]
+	for name := range toolset.Manifest.ToolsManifest {
+		state.toolRevisions[name] = max(r.toolRevisions[name], joined[name])
+	}
+	for name, rev := range r.removedTools {
+		state.removedTools[name] = rev
+	}
+	for name, rev := range r.leftToolsets[toolsetName] {
+		state.removedTools[name] = max(state.removedTools[name], rev)
+	}
+	return state, true
+}
+
 func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	return r.authServices
 }
 
+// SetSourceConfigs records the configs the current sources were initialized
+// from, so that unchanged sources can be reused on reload.
+func (r *ResourceManager) SetSourceConfigs(sourceConfigs SourceConfigs) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sourceConfigs = sourceConfigs
+}
+
+// reusableSource returns the current source with the given name if it was
+// initialized from a config identical to sc.
+func (r *ResourceManager) reusableSource(name string, sc sources.SourceConfig) (sources.Source, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prevCfg, ok := r.sourceConfigs[name]
+	if !ok || !reflect.DeepEqual(prevCfg, sc) {
+		return nil, false
+	}
+	s, ok := r.sources[name]
+	return s, ok && s != nil
+}
+
+func (r *ResourceManager) GetSourcesMap() map[string]sources.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sources
+}
+
+func (r *ResourceManager) GetToolsetsMap() map[string]tools.Toolset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.toolsets
+}
+
 func (r *ResourceManager) GetToolsMap() map[string]tools.Tool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -129,6 +302,19 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	map[string]tools.Tool,
 	map[string]tools.Toolset,
 	error,
+) {
+	return ReconcileConfigs(ctx, cfg, nil)
+}
+
+// ReconcileConfigs initializes the resources of the given config like
+// InitializeConfigs, but reuses sources of prev whose config is unchanged
+// instead of opening new connections for them. prev may be nil.
+func ReconcileConfigs(ctx context.Context, cfg ServerConfig, prev *ResourceManager) (
+	map[string]sources.Source,
+	map[string]auth.AuthService,
+	map[string]tools.Tool,
+	map[string]tools.Toolset,
+	error,
 ) {
 	ctx = util.WithUserAgent(ctx, cfg.Version)
 	instrumentation, err := util.InstrumentationFromContext(ctx)
@@ -143,7 +329,26 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 
 	// initialize and validate the sources from configs
 	sourcesMap := make(map[string]sources.Source)
+	// the sources opened here are closed again if the config fails to
+	// initialize
+	initialized := false
+	defer func() {
+		if !initialized {
+			var prevSources map[string]sources.Source
+			if prev != nil {
+				prevSources = prev.GetSourcesMap()
+			}
+			CloseNewSources(ctx, sourcesMap, prevSources)
+		}
+	}()
+	reused := 0
 	for name, sc := range cfg.SourceConfigs {
+		if s, ok := prev.reusableSource(name, sc); ok {
+			l.DebugContext(ctx, fmt.Sprintf("Reusing unchanged source %q.", name))
+			sourcesMap[name] = s
+			reused++
+			continue
+		}
 		s, err := func() (sources.Source, error) {
 			childCtx, span := instrumentation.Tracer.Start(
 				ctx,
@@ -163,7 +368,11 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 		}
 		sourcesMap[name] = s
 	}
-	l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources.", len(sourcesMap)))
+	if reused > 0 {
+		l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources (%d reused).", len(sourcesMap), reused))
+	} else {
+		l.InfoContext(ctx, fmt.Sprintf("Initialized %d sources.", len(sourcesMap)))
+	}
 
 	// initialize and validate the auth services from configs
 	authServicesMap := make(map[string]auth.AuthService)
@@ -187,8 +396,24 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 		}
 		authServicesMap[name] = a
 	}
+	for name, a := range authServicesMap {
+		d, ok := a.(auth.DelegatingAuthService)
+		if !ok {
+			continue
+		}
+		for _, delegate := range d.OnBehalfOf.Delegates {
+			if _, ok := authServicesMap[delegate.AuthService]; !ok || delegate.AuthService == name {
+				return nil, nil, nil, nil, fmt.Errorf("invalid delegate %q for auth service %q: must be another configured auth service", delegate.AuthService, name)
+			}
+		}
+	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d authServices.", len(authServicesMap)))
 
+	policies, err := tools.CompilePolicies(cfg.PolicyConfigs)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	// initialize and validate the tools from configs
 	toolsMap := make(map[string]tools.Tool)
 	for name, tc := range cfg.ToolConfigs {
@@ -200,6 +425,10 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 				trace.WithAttributes(attribute.String("tool_name", name)),
 			)
 			defer span.End()
+			tc, err := tools.BindPolicies(tc, policies)
+			if err != nil {
+				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+			}
 			t, err := tc.Initialize(sourcesMap)
 			if err != nil {
 				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
@@ -213,6 +442,10 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(toolsMap)))
 
+	if err := tools.ValidateSchedules(cfg.ScheduleConfigs, toolsMap); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	// create a default toolset that contains all tools
 	allToolNames := make([]string, 0, len(toolsMap))
 	for name := range toolsMap {
@@ -246,6 +479,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d toolsets.", len(toolsetsMap)))
 
+	initialized = true
 	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
 }
 
@@ -308,16 +542,44 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 
 	sseManager := newSseManager(ctx)
 
+	anonymous, err := newAnonymousTier(cfg.AnonymousAccess, toolsMap)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize anonymous access: %w", err)
+	}
+
+	if _, ok := toolsetsMap[cfg.StdioToolset]; !ok {
+		return nil, fmt.Errorf("toolset %q does not exist", cfg.StdioToolset)
+	}
+
 	resourceManager := NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	resourceManager.SetSourceConfigs(cfg.SourceConfigs)
 
 	s := &Server{
-		version:         cfg.Version,
-		srv:             srv,
-		root:            r,
-		logger:          l,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+		version:            cfg.Version,
+		srv:                srv,
+		root:               r,
+		logger:             l,
+		instrumentation:    instrumentation,
+		sseManager:         sseManager,
+		invocations:        invocations.NewTracker(cfg.MaxConcurrentInvocations),
+		jobs:               tools.NewJobStore(tools.DefaultJobRetention),
+		anonymous:          anonymous,
+		disableReload:      cfg.DisableReload,
+		stdioToolset:       cfg.StdioToolset,
+		adminToken:         cfg.AdminToken,
+		approvalWebhook:    cfg.ApprovalWebhook,
+		streamStallTimeout: cfg.StreamStallTimeout,
+		drainTimeout:       cfg.DrainTimeout,
+		ResourceMgr:        resourceManager,
+	}
+	if err := s.CheckApprovals(toolsMap); err != nil {
+		return nil, err
+	}
+	s.jobs.SetApprovalTimeout(cfg.ApprovalTimeout)
+	if cfg.ApprovalWebhook != "" {
+		s.jobs.OnApprovalRequested(func(ctx context.Context, req tools.ApprovalRequest) {
+			go s.notifyApproval(ctx, cfg.ApprovalWebhook, req)
+		})
 	}
 	// control plane
 	apiR, err := apiRouter(s)
@@ -330,14 +592,82 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		return nil, err
 	}
 	r.Mount("/mcp", mcpR)
+	if cfg.AdminToken != "" {
+		adminR, err := adminRouter(s)
+		if err != nil {
+			return nil, err
+		}
+		r.Mount("/admin", adminR)
+	}
+	if cfg.GrpcPort > 0 {
+		s.grpcSrv = newGrpcServer(s)
+		s.grpcAddr = net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.GrpcPort))
+	}
 	// default endpoint for validating server is running
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("🧰 Hello, World! 🧰"))
 	})
 
+	if err := s.SetSchedules(ctx, cfg.ScheduleConfigs); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
+// SetAnonymousAccess replaces the anonymous access tier of the server with
+// one configured by cfg, validated against toolsMap. A nil cfg disables
+// anonymous access.
+func (s *Server) SetAnonymousAccess(cfg *AnonymousAccessConfig, toolsMap map[string]tools.Tool) error {
+	anonymous, err := newAnonymousTier(cfg, toolsMap)
+	if err != nil {
+		return fmt.Errorf("unable to initialize anonymous access: %w", err)
+	}
+	s.anonymousMu.Lock()
+	defer s.anonymousMu.Unlock()
+	s.anonymous = anonymous
+	return nil
+}
+
+// anonymousAccess returns the anonymous access tier of the server, nil if
+// anonymous access is disabled.
+func (s *Server) anonymousAccess() *anonymousTier {
+	s.anonymousMu.RLock()
+	defer s.anonymousMu.RUnlock()
+	return s.anonymous
+}
+
+// SetSchedules replaces the schedules of the server with cfgs, which run the
+// server's current tools. Runs of the previous schedules that are still
+// going are left to finish.
+func (s *Server) SetSchedules(ctx context.Context, cfgs ScheduleConfigs) error {
+	var next *tools.Scheduler
+	if len(cfgs) > 0 {
+		var err error
+		// runs outlive the request that configured them, e.g. a reload, and
+		// may inspect the invocations, e.g. to export usage statistics
+		runCtx := util.WithInvocationTracker(context.WithoutCancel(ctx), s.invocations)
+		next, err = tools.NewScheduler(runCtx, cfgs, s.ResourceMgr.GetToolsMap(), s.ResourceMgr.GetTool, s.invocations)
+		if err != nil {
+			return fmt.Errorf("unable to initialize schedules: %w", err)
+		}
+	}
+
+	s.schedulesMu.Lock()
+	prev := s.scheduler
+	s.scheduler = next
+	s.schedulesMu.Unlock()
+
+	if prev != nil {
+		go func() { _ = prev.Stop(context.Background()) }()
+	}
+	if next != nil {
+		next.Start()
+		s.logger.InfoContext(ctx, fmt.Sprintf("Initialized %d schedules.", len(cfgs)))
+	}
+	return nil
+}
+
 // Listen starts a listener for the given Server instance.
 func (s *Server) Listen(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
@@ -352,11 +682,39 @@ func (s *Server) Listen(ctx context.Context) error {
 		return fmt.Errorf("failed to open listener for %q: %w", s.srv.Addr, err)
 	}
 	s.logger.DebugContext(ctx, fmt.Sprintf("server listening on %s", s.srv.Addr))
+	if s.grpcSrv != nil {
+		if s.grpcListener, err = lc.Listen(ctx, "tcp", s.grpcAddr); err != nil {
+			return fmt.Errorf("failed to open gRPC listener for %q: %w", s.grpcAddr, err)
+		}
+		s.logger.DebugContext(ctx, fmt.Sprintf("gRPC server listening on %s", s.grpcAddr))
+	}
 	return nil
 }
 
-// Serve starts an HTTP server for the given Server instance.
+// Serve starts an HTTP server, and a gRPC server if configured, for the
+// given Server instance.
 func (s *Server) Serve(ctx context.Context) error {
+	if s.grpcSrv != nil && s.grpcListener != nil {
+		grpcErr := make(chan error, 1)
+		go func() {
+			s.logger.DebugContext(ctx, "Starting a gRPC server.")
+			grpcErr <- s.grpcSrv.Serve(s.grpcListener)
+		}()
+		httpErr := make(chan error, 1)
+		go func() {
+			s.logger.DebugContext(ctx, "Starting a HTTP server.")
+			httpErr <- s.srv.Serve(s.listener)
+		}()
+		select {
+		case err := <-grpcErr:
+			if err != nil {
+				return fmt.Errorf("gRPC server stopped: %w", err)
+			}
+			return <-httpErr
+		case err := <-httpErr:
+			return err
+		}
+	}
 	s.logger.DebugContext(ctx, "Starting a HTTP server.")
 	return s.srv.Serve(s.listener)
 }
@@ -367,9 +725,158 @@ func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writ
 	return stdioServer.Start(ctx)
 }
 
-// Shutdown gracefully shuts down the server without interrupting any active
-// connections. It uses http.Server.Shutdown() and has the same functionality.
+// DefaultDrainTimeout is how long Shutdown waits for in-flight invocations by
+// default, before cancelling them.
+const DefaultDrainTimeout = 10 * time.Second
+
+// serverShutdownTimeout is how long Shutdown waits, once the invocations are
+// drained, for the HTTP and gRPC servers to finish their responses.
+const serverShutdownTimeout = 5 * time.Second
+
+// Shutdown gracefully shuts down the server. It stops accepting new
+// invocations and waits for the in-flight ones until ctx is done, then
+// cancels the remaining ones, shuts down the HTTP and gRPC servers like
+// http.Server.Shutdown() within serverShutdownTimeout, and closes the
+// sources. It returns the error of ctx if in-flight invocations had to be
+// cancelled.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "shutting down the server.")
-	return s.srv.Shutdown(ctx)
+	s.schedulesMu.Lock()
+	scheduler := s.scheduler
+	s.scheduler = nil
+	s.schedulesMu.Unlock()
+	if scheduler != nil {
+		// running schedules are in-flight invocations, drained below
+		go func() { _ = scheduler.Stop(ctx) }()
+	}
+
+	drainErr := s.invocations.Drain(ctx)
+	if drainErr != nil {
+		s.logger.WarnContext(ctx, "Cancelled the invocations still running at the end of the drain timeout.")
+	}
+
+	// the drain may have used up ctx, so the servers get a deadline of their
+	// own to send the responses of the cancelled invocations
+	srvCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), serverShutdownTimeout)
+	defer cancel()
+	var grpcStopped chan struct{}
+	if s.grpcSrv != nil {
+		grpcStopped = make(chan struct{})
+		go func() {
+			s.grpcSrv.GracefulStop()
+			close(grpcStopped)
+		}()
+	}
+	err := s.srv.Shutdown(srvCtx)
+	if grpcStopped != nil {
+		select {
+		case <-grpcStopped:
+		case <-srvCtx.Done():
+			s.grpcSrv.Stop()
+		}
+	}
+	s.closeSources(context.WithoutCancel(ctx))
+	if drainErr != nil {
+		return drainErr
+	}
+	return err
+}
+
+// CloseReplacedSources closes the sources of prev that are no longer used by
+// the server, once the invocations in flight when it's called have finished.
+// ctx bounds the wait; the sources are closed when it's done either way.
+func (s *Server) CloseReplacedSources(ctx context.Context, prev map[string]sources.Source) {
+	current := s.ResourceMgr.GetSourcesMap()
+	names := make([]string, 0, len(prev))
+	for name, src := range prev {
+		if cur, ok := current[name]; ok && cur == src {
+			continue
+		}
+		if _, ok := src.(sources.Closer); ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	// reloaded tools may still be running on the replaced sources
+	if err := s.invocations.Wait(ctx); err != nil {
+		s.logger.WarnContext(ctx, fmt.Sprintf("closing replaced sources while invocations may still use them: %s", err))
+	}
+	for _, name := range names {
+		if err := prev[name].(sources.Closer).Close(); err != nil {
+			s.logger.WarnContext(ctx, fmt.Sprintf("unable to close replaced source %q: %s", name, err))
+			continue
+		}
+		s.logger.DebugContext(ctx, fmt.Sprintf("closed replaced source %q", name))
+	}
+}
+
+// CloseNewSources closes the sources of sourcesMap that aren't sources of
+// prev, for reloads that fail once their sources were initialized.
+func CloseNewSources(ctx context.Context, sourcesMap, prev map[string]sources.Source) {
+	l, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+	names := make([]string, 0, len(sourcesMap))
+	for name, src := range sourcesMap {
+		if p, ok := prev[name]; ok && p == src {
+			continue
+		}
+		if _, ok := src.(sources.Closer); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := sourcesMap[name].(sources.Closer).Close(); err != nil {
+			l.WarnContext(ctx, fmt.Sprintf("unable to close source %q: %s", name, err))
+			continue
+		}
+		l.DebugContext(ctx, fmt.Sprintf("closed unused source %q", name))
+	}
+}
+
+// closeSources closes the sources of the server that hold connections, the
+// ones depending on other sources first.
+func (s *Server) closeSources(ctx context.Context) {
+	sourcesMap := s.ResourceMgr.GetSourcesMap()
+	dependents := make(map[string][]string)
+	names := make([]string, 0, len(sourcesMap))
+	for name, src := range sourcesMap {
+		names = append(names, name)
+		if d, ok := src.(sources.Dependent); ok {
+			for _, dep := range d.DependsOn() {
+				dependents[dep] = append(dependents[dep], name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	visited := make(map[string]bool, len(names))
+	var closeSource func(name string)
+	closeSource = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, d := range dependents[name] {
+			closeSource(d)
+		}
+		c, ok := sourcesMap[name].(sources.Closer)
+		if !ok {
+			return
+		}
+		if err := c.Close(); err != nil {
+			s.logger.WarnContext(ctx, fmt.Sprintf("unable to close source %q: %s", name, err))
+			return
+		}
+		s.logger.DebugContext(ctx, fmt.Sprintf("closed source %q", name))
+	}
+	for _, name := range names {
+		closeSource(name)
+	}
 }
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"testing"
 
+	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
@@ -33,6 +34,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestServe(t *testing.T) {
@@ -172,3 +174,253 @@ func TestUpdateServer(t *testing.T) {
 		t.Errorf("error updating server, toolset (-want +got):\n%s", diff)
 	}
 }
+
+// countingSourceConfig is a source config that counts how often it is initialized.
+type countingSourceConfig struct {
+	Name  string
+	Host  string
+	count *int
+}
+
+func (c countingSourceConfig) SourceConfigKind() string { return "counting" }
+
+func (c countingSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	*c.count++
+	return &countingSource{host: c.Host}, nil
+}
+
+type countingSource struct {
+	host string
+}
+
+func (s *countingSource) SourceKind() string { return "counting" }
+
+func TestReconcileConfigsReusesUnchangedSources(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	var countA, countB int
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		SourceConfigs: server.SourceConfigs{
+			"a": countingSourceConfig{Name: "a", Host: "host-a", count: &countA},
+			"b": countingSourceConfig{Name: "b", Host: "host-b", count: &countB},
+		},
+	}
+	srcs, authSvcs, toolsMap, toolsets, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mgr := server.NewResourceManager(srcs, authSvcs, toolsMap, toolsets)
+	mgr.SetSourceConfigs(cfg.SourceConfigs)
+
+	// reload with "a" unchanged and "b" pointing to a new host
+	reloaded := server.ServerConfig{
+		Version: "0.0.0",
+		SourceConfigs: server.SourceConfigs{
+			"a": countingSourceConfig{Name: "a", Host: "host-a", count: &countA},
+			"b": countingSourceConfig{Name: "b", Host: "host-b2", count: &countB},
+		},
+	}
+	newSrcs, _, _, _, err := server.ReconcileConfigs(ctx, reloaded, mgr)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if countA != 1 {
+		t.Errorf("unchanged source was re-initialized: initialized %d times", countA)
+	}
+	if newSrcs["a"] != srcs["a"] {
+		t.Errorf("unchanged source was not reused")
+	}
+	if countB != 2 {
+		t.Errorf("changed source was not re-initialized: initialized %d times", countB)
+	}
+	if got := newSrcs["b"].(*countingSource).host; got != "host-b2" {
+		t.Errorf("unexpected host for changed source: got %q", got)
+	}
+}
+
+func TestInitializeConfigsValidatesSchedules(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		ScheduleConfigs: server.ScheduleConfigs{
+			"nightly": tools.ScheduleConfig{Tool: "missing_tool", Cron: "@daily"},
+		},
+	}
+	_, _, _, _, err = server.InitializeConfigs(ctx, cfg)
+	if err == nil || !strings.Contains(err.Error(), `invalid schedule "nightly"`) {
+		t.Fatalf("expected an invalid schedule error, got %v", err)
+	}
+}
+
+// closingSource records the order in which sources are closed.
+type closingSource struct {
+	name      string
+	dependsOn []string
+	closed    *[]string
+}
+
+func (s *closingSource) SourceKind() string { return "closing" }
+
+func (s *closingSource) DependsOn() []string { return s.dependsOn }
+
+func (s *closingSource) Close() error {
+	*s.closed = append(*s.closed, s.name)
+	return nil
+}
+
+func TestShutdownClosesSources(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	cfg := server.ServerConfig{Version: "0.0.0", Address: "127.0.0.1", Port: 5000}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(cfg.Version)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+	s, err := server.NewServer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("error setting up server: %s", err)
+	}
+
+	var closed []string
+	s.ResourceMgr.SetResources(map[string]sources.Source{
+		"a-federated": &closingSource{name: "a-federated", dependsOn: []string{"b-cache", "c-pg"}, closed: &closed},
+		"b-cache":     &closingSource{name: "b-cache", dependsOn: []string{"c-pg"}, closed: &closed},
+		"c-pg":        &closingSource{name: "c-pg", closed: &closed},
+		"d-open":      &countingSource{},
+	}, nil, map[string]tools.Tool{}, map[string]tools.Toolset{})
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"a-federated", "b-cache", "c-pg"}
+	if diff := cmp.Diff(want, closed); diff != "" {
+		t.Errorf("unexpected close order (-want +got):\n%s", diff)
+	}
+}
+
+// closingSourceConfig is a source config initializing closingSources.
+type closingSourceConfig struct {
+	Name   string
+	Host   string
+	closed *[]string
+}
+
+func (c closingSourceConfig) SourceConfigKind() string { return "closing" }
+
+func (c closingSourceConfig) Initialize(context.Context, trace.Tracer) (sources.Source, error) {
+	return &closingSource{name: c.Name, closed: c.closed}, nil
+}
+
+func TestReconcileConfigsClosesNewSourcesOnFailure(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	var closed []string
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		SourceConfigs: server.SourceConfigs{
+			"a": closingSourceConfig{Name: "a", Host: "host-a", closed: &closed},
+		},
+	}
+	srcs, authSvcs, toolsMap, toolsets, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mgr := server.NewResourceManager(srcs, authSvcs, toolsMap, toolsets)
+	mgr.SetSourceConfigs(cfg.SourceConfigs)
+
+	// "a" is reused and "b" is opened before the invalid schedule fails the reload
+	reloaded := server.ServerConfig{
+		Version: "0.0.0",
+		SourceConfigs: server.SourceConfigs{
+			"a": closingSourceConfig{Name: "a", Host: "host-a", closed: &closed},
+			"b": closingSourceConfig{Name: "b", Host: "host-b", closed: &closed},
+		},
+		ScheduleConfigs: server.ScheduleConfigs{
+			"nightly": tools.ScheduleConfig{Tool: "missing_tool", Cron: "@daily"},
+		},
+	}
+	if _, _, _, _, err := server.ReconcileConfigs(ctx, reloaded, mgr); err == nil {
+		t.Fatalf("expected the reload to fail")
+	}
+	if diff := cmp.Diff([]string{"b"}, closed); diff != "" {
+		t.Errorf("unexpected closed sources (-want +got):\n%s", diff)
+	}
+}
+
+// retryingToolConfig is a tool kind with a retry field of its own.
+type retryingToolConfig struct {
+	Name         string   `yaml:"name"`
+	Kind         string   `yaml:"kind"`
+	Retries      int      `yaml:"retry"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+func (c retryingToolConfig) ToolConfigKind() string { return "retrying" }
+
+func (c retryingToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func TestToolConfigsKeepDeclaredWrapperKeys(t *testing.T) {
+	tools.Register("retrying", func(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+		actual := retryingToolConfig{Name: name}
+		if err := decoder.DecodeContext(ctx, &actual); err != nil {
+			return nil, err
+		}
+		return actual, nil
+	})
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	in := `
+retrying_tool:
+  kind: retrying
+  retry: 3
+  queryTags:
+    team: data
+`
+	var got server.ToolConfigs
+	if err := yaml.UnmarshalContext(ctx, []byte(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	// the tool decodes its own retry field, queryTags still wrap it
+	want := server.ToolConfigs{
+		"retrying_tool": tools.QueryTagsToolConfig{
+			ToolConfig: retryingToolConfig{Name: "retrying_tool", Kind: "retrying", Retries: 3, AuthRequired: []string{}},
+			Tags:       map[string]string{"team": "data"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect tool configs (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+)
+
+// DefaultStatsWindow is the window of the usage statistics returned by
+// /api/stats unless a window is given.
+const DefaultStatsWindow = time.Hour
+
+// statsHandler handles requests for the usage statistics of each tool over a
+// rolling window. Loaded tools that weren't invoked in the window are listed
+// last, so that unused tools stand out.
+func statsHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/stats/get")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	window := DefaultStatsWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			_ = render.Render(w, r, newErrResponse(fmt.Errorf("invalid window %q, must be a positive duration such as \"24h\"", v), http.StatusBadRequest))
+			return
+		}
+		window = d
+	}
+
+	stats := s.invocations.Stats(window)
+	invoked := make(map[string]bool, len(stats.Tools))
+	for _, t := range stats.Tools {
+		invoked[t.Tool] = true
+	}
+	var unused []invocations.ToolStats
+	for name := range s.ResourceMgr.GetToolsMap() {
+		if !invoked[name] {
+			unused = append(unused, invocations.ToolStats{Tool: name})
+		}
+	}
+	slices.SortFunc(unused, func(a, b invocations.ToolStats) int { return strings.Compare(a.Tool, b.Tool) })
+	stats.Tools = append(stats.Tools, unused...)
+	render.JSON(w, r, stats)
+}
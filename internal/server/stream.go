@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// DefaultStreamStallTimeout is how long a client may stop reading a streamed
+// result before the invocation is cancelled.
+const DefaultStreamStallTimeout = 30 * time.Second
+
+// acceptsEventStream reports whether the client asked for the result as a
+// stream of server-sent events.
+func acceptsEventStream(r *http.Request) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		if t, _, err := mime.ParseMediaType(strings.TrimSpace(v)); err == nil && t == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
+
+// eventStream writes server-sent events to a client. Every event is flushed
+// before the next row is fetched, so rows are never buffered beyond the
+// connection's send buffer: a slow client blocks the write, which pauses the
+// query. A write blocked for longer than the stall timeout fails.
+type eventStream struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	stall   time.Duration
+	started bool
+}
+
+func newEventStream(w http.ResponseWriter, stall time.Duration) *eventStream {
+	return &eventStream{w: w, rc: http.NewResponseController(w), stall: stall}
+}
+
+// send writes a single event.
+func (e *eventStream) send(event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s: %w", event, err)
+	}
+	if !e.started {
+		e.w.Header().Set("Content-Type", "text/event-stream")
+		e.w.Header().Set("Cache-Control", "no-cache")
+		e.w.WriteHeader(http.StatusOK)
+		e.started = true
+	}
+	if e.stall > 0 {
+		// servers that can't set write deadlines stream without a stall timeout
+		if err := e.rc.SetWriteDeadline(time.Now().Add(e.stall)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+		return e.writeErr(err)
+	}
+	if err := e.rc.Flush(); err != nil {
+		return e.writeErr(err)
+	}
+	return nil
+}
+
+func (e *eventStream) writeErr(err error) error {
+	if e.stall > 0 && errors.Is(err, os.ErrDeadlineExceeded) {
+		return fmt.Errorf("%w for %s: %w", tools.ErrStreamStalled, e.stall, err)
+	}
+	return err
+}
+
+// streamResult streams the rows of a tool's result as `row` events, followed
+// by a `done` event with the number of rows. Errors before the first row are
+// returned as a regular error response; later errors are sent as an `error`
+// event, unless the client stalled and can't be written to anymore.
+func (s *Server) streamResult(ctx context.Context, w http.ResponseWriter, r *http.Request, tool tools.StreamingTool, params tools.ParamValues) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream := newEventStream(w, s.streamStallTimeout)
+	rows := 0
+	err := tool.Stream(ctx, params, func(row any) error {
+		if err := stream.send("row", row); err != nil {
+			// stop the query rather than wait for it to notice
+			cancel()
+			return err
+		}
+		rows++
+		return nil
+	})
+	if err == nil {
+		err = stream.send("done", map[string]int{"rows": rows})
+	}
+	s.invocations.RecordError(ctx, err)
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("error while invoking tool: %w", err)
+	s.logger.DebugContext(ctx, err.Error())
+	te := tools.ClassifyError(err, tools.ErrorCategoryQuery, tools.ErrorCodeQueryFailed)
+	switch {
+	case !stream.started:
+		_ = render.Render(w, r, newToolErrResponse(te))
+	case te.Code != tools.ErrorCodeStreamStalled:
+		_ = stream.send("error", te)
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+var _ tools.StreamingTool = streamTool{}
+
+// streamTool is a MockTool that streams the rows returned by rows, and then
+// fails with err.
+type streamTool struct {
+	MockTool
+	rows []any
+	err  error
+}
+
+func (t streamTool) Stream(ctx context.Context, params tools.ParamValues, send func(row any) error) error {
+	for _, row := range t.rows {
+		if err := send(row); err != nil {
+			return err
+		}
+	}
+	return t.err
+}
+
+func TestToolInvokeStreaming(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	rows := []any{map[string]any{"id": 1}, map[string]any{"id": 2}, map[string]any{"id": 3}}
+	toolsMap["streamed"] = streamTool{MockTool: MockTool{Name: "streamed"}, rows: rows}
+	toolsMap["fails_late"] = streamTool{MockTool: MockTool{Name: "fails_late"}, rows: rows[:1], err: fmt.Errorf("connection lost")}
+	toolsMap["fails_early"] = streamTool{MockTool: MockTool{Name: "fails_early"}, err: fmt.Errorf("syntax error")}
+	wrapped, err := tools.RetryToolConfig{ToolConfig: mockToolConfig{tool: streamTool{MockTool: MockTool{Name: "wrapped"}, rows: rows}}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	toolsMap["wrapped"] = wrapped
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	stream := map[string]string{"Accept": "text/event-stream"}
+	tcs := []struct {
+		desc       string
+		tool       string
+		header     map[string]string
+		wantStatus int
+		want       []string
+	}{
+		{
+			desc:       "rows are streamed",
+			tool:       "streamed",
+			header:     stream,
+			wantStatus: http.StatusOK,
+			want: []string{
+				"event: row\ndata: {\"id\":1}\n\n",
+				"event: row\ndata: {\"id\":3}\n\n",
+				"event: done\ndata: {\"rows\":3}\n\n",
+			},
+		},
+		{
+			desc:       "rows of wrapped tools are streamed",
+			tool:       "wrapped",
+			header:     stream,
+			wantStatus: http.StatusOK,
+			want:       []string{"event: row\ndata: {\"id\":2}\n\n", "event: done\ndata: {\"rows\":3}\n\n"},
+		},
+		{
+			desc:       "rows are returned at once without the accept header",
+			tool:       "streamed",
+			wantStatus: http.StatusOK,
+			want:       []string{`"result":"[\"streamed\"]"`},
+		},
+		{
+			desc:       "errors after the first row are sent as events",
+			tool:       "fails_late",
+			header:     stream,
+			wantStatus: http.StatusOK,
+			want:       []string{"event: row\n", "event: error\ndata: {\"category\":\"query-error\",\"code\":\"QUERY_FAILED\""},
+		},
+		{
+			desc:       "errors before the first row are regular responses",
+			tool:       "fails_early",
+			header:     stream,
+			wantStatus: http.StatusBadRequest,
+			want:       []string{`"code":"QUERY_FAILED"`},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.tool), strings.NewReader(`{}`), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: want %d, got %d: %s", tc.wantStatus, resp.StatusCode, body)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("expected response to contain %q, got %s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamStallTimeout(t *testing.T) {
+	logger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
+	if err != nil {
+		t.Fatalf("unable to initialize logger: %s", err)
+	}
+	s := &Server{logger: logger, invocations: invocations.NewTracker(0), streamStallTimeout: 100 * time.Millisecond}
+
+	// the tool sends rows until the client stops reading them
+	row := map[string]any{"data": strings.Repeat("x", 64*1024)}
+	var toolCtx context.Context
+	tool := streamToolFunc(func(ctx context.Context, send func(row any) error) error {
+		toolCtx = ctx
+		for {
+			if err := send(row); err != nil {
+				return err
+			}
+		}
+	})
+	errs := make(chan error, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		errs <- s.streamResult(r.Context(), w, r, tool, nil)
+	}))
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	defer conn.Close()
+	if _, err := fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: toolbox\r\nAccept: text/event-stream\r\n\r\n"); err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, tools.ErrStreamStalled) {
+			t.Fatalf("expected the stream to stall, got %v", err)
+		}
+		if toolCtx.Err() == nil {
+			t.Errorf("expected the invocation to be cancelled")
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatalf("stream did not stall")
+	}
+}
+
+// streamToolFunc is a streaming tool implemented by a function.
+type streamToolFunc func(ctx context.Context, send func(row any) error) error
+
+func (f streamToolFunc) Stream(ctx context.Context, _ tools.ParamValues, send func(row any) error) error {
+	return f(ctx, send)
+}
+func (f streamToolFunc) Invoke(context.Context, tools.ParamValues) (any, error) { return nil, nil }
+func (f streamToolFunc) ParseParams(map[string]any, map[string]map[string]any) (tools.ParamValues, error) {
+	return nil, nil
+}
+func (f streamToolFunc) Manifest() tools.Manifest       { return tools.Manifest{} }
+func (f streamToolFunc) McpManifest() tools.McpManifest { return tools.McpManifest{} }
+func (f streamToolFunc) Authorized([]string) bool       { return true }
@@ -22,6 +22,10 @@ import (
 
 	"cloud.google.com/go/alloydbconn"
 	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
+	"github.com/googleapis/genai-toolbox/internal/privacy"
+	"github.com/googleapis/genai-toolbox/internal/querycost"
+	"github.com/googleapis/genai-toolbox/internal/querylog"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -58,6 +62,26 @@ type Config struct {
 	User     string         `yaml:"user"`
 	Password string         `yaml:"password"`
 	Database string         `yaml:"database" validate:"required"`
+	// ApplicationName is reported by the connections of the source, e.g. in
+	// pg_stat_activity.
+	ApplicationName string `yaml:"applicationName"`
+	// QueryTags are added to the queries run through the source, along with
+	// the tool and caller of each query.
+	QueryTags map[string]string `yaml:"queryTags"`
+	// Dictionary describes the tables and columns of the source, for tools
+	// to ground agents in.
+	Dictionary dictionary.Dictionary `yaml:"dictionary"`
+	// AggregationOnly, if set, restricts the queries of tools using the
+	// source to aggregates over groups of a minimum size.
+	AggregationOnly *privacy.AggregationConfig `yaml:"aggregationOnly"`
+	// CostLimits, if set, rejects the queries agents write whose planner
+	// estimates exceed them, without running them.
+	CostLimits *querycost.Limits `yaml:"costLimits"`
+	// LogQueries logs every statement the source runs.
+	LogQueries bool `yaml:"logQueries"`
+	// SlowQueryThreshold, e.g. "500ms", logs the statements running longer
+	// as warnings, and counts them.
+	SlowQueryThreshold string `yaml:"slowQueryThreshold"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -65,7 +89,23 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initAlloyDBPgConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Cluster, r.Instance, r.IPType.String(), r.User, r.Password, r.Database)
+	if r.AggregationOnly != nil {
+		if err := r.AggregationOnly.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if r.CostLimits != nil {
+		if err := r.CostLimits.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	queryLogger, err := querylog.New(r.Name, r.LogQueries, r.SlowQueryThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := initAlloyDBPgConnectionPool(ctx, tracer, r.Name, r.Project, r.Region, r.Cluster, r.Instance, r.IPType.String(), r.User, r.Password, r.Database, r.ApplicationName, queryLogger)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
@@ -76,9 +116,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 	}
 
 	s := &Source{
-		Name: r.Name,
-		Kind: SourceKind,
-		Pool: pool,
+		Name:        r.Name,
+		Kind:        SourceKind,
+		Pool:        pool,
+		Tags:        r.QueryTags,
+		Dictionary:  r.Dictionary,
+		Aggregation: r.AggregationOnly,
+		CostLimit:   r.CostLimits,
 	}
 	return s, nil
 }
@@ -86,9 +130,13 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 var _ sources.Source = &Source{}
 
 type Source struct {
-	Name string `yaml:"name"`
-	Kind string `yaml:"kind"`
-	Pool *pgxpool.Pool
+	Name        string `yaml:"name"`
+	Kind        string `yaml:"kind"`
+	Pool        *pgxpool.Pool
+	Tags        map[string]string
+	Dictionary  dictionary.Dictionary
+	Aggregation *privacy.AggregationConfig
+	CostLimit   *querycost.Limits
 }
 
 func (s *Source) SourceKind() string {
@@ -99,6 +147,29 @@ func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+// Close closes the connections of the source, once the ones in use are
+// released.
+func (s *Source) Close() error {
+	s.Pool.Close()
+	return nil
+}
+
+func (s *Source) QueryTags() map[string]string {
+	return s.Tags
+}
+
+func (s *Source) DataDictionary() dictionary.Dictionary {
+	return s.Dictionary
+}
+
+func (s *Source) AggregationOnly() *privacy.AggregationConfig {
+	return s.Aggregation
+}
+
+func (s *Source) CostLimits() *querycost.Limits {
+	return s.CostLimit
+}
+
 func getOpts(ipType, userAgent string, useIAM bool) ([]alloydbconn.Option, error) {
 	opts := []alloydbconn.Option{alloydbconn.WithUserAgent(userAgent)}
 	switch strings.ToLower(ipType) {
@@ -145,7 +216,7 @@ func getConnectionConfig(ctx context.Context, user, pass, dbname string) (string
 	return dsn, useIAM, nil
 }
 
-func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, cluster, instance, ipType, user, pass, dbname string) (*pgxpool.Pool, error) {
+func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, cluster, instance, ipType, user, pass, dbname, applicationName string, queryLogger *querylog.Logger) (*pgxpool.Pool, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -159,6 +230,9 @@ func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name,
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse connection uri: %w", err)
 	}
+	if applicationName != "" {
+		config.ConnConfig.RuntimeParams["application_name"] = applicationName
+	}
 	// Create a new dialer with options
 	userAgent, err := util.UserAgentFromContext(ctx)
 	if err != nil {
@@ -179,6 +253,10 @@ func initAlloyDBPgConnectionPool(ctx context.Context, tracer trace.Tracer, name,
 		return d.Dial(ctx, i)
 	}
 
+	if queryLogger != nil {
+		config.ConnConfig.Tracer = queryLogger
+	}
+
 	// Interact with the driver directly as you normally would
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -17,9 +17,11 @@ package bigquery
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
@@ -53,6 +55,12 @@ type Config struct {
 	Kind     string `yaml:"kind" validate:"required"`
 	Project  string `yaml:"project" validate:"required"`
 	Location string `yaml:"location"`
+	// QueryTags are added as labels to the query jobs run through the
+	// source, along with the tool and caller of each job.
+	QueryTags map[string]string `yaml:"queryTags"`
+	// Dictionary describes the tables and columns of the source, for tools
+	// to ground agents in.
+	Dictionary dictionary.Dictionary `yaml:"dictionary"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -73,6 +81,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		Client:      client,
 		RestService: restService,
 		Location:    r.Location,
+		Tags:        r.QueryTags,
+		Dictionary:  r.Dictionary,
 	}
 	return s, nil
 
@@ -87,6 +97,8 @@ type Source struct {
 	Client      *bigqueryapi.Client
 	RestService *bigqueryrestapi.Service
 	Location    string `yaml:"location"`
+	Tags        map[string]string
+	Dictionary  dictionary.Dictionary
 }
 
 func (s *Source) SourceKind() string {
@@ -94,6 +106,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Client.Close()
+}
+
 func (s *Source) BigQueryClient() *bigqueryapi.Client {
 	return s.Client
 }
@@ -102,6 +119,53 @@ func (s *Source) BigQueryRestService() *bigqueryrestapi.Service {
 	return s.RestService
 }
 
+func (s *Source) QueryTags() map[string]string {
+	return s.Tags
+}
+
+func (s *Source) DataDictionary() dictionary.Dictionary {
+	return s.Dictionary
+}
+
+// maxLabelLength is the maximum length of the keys and values of labels.
+const maxLabelLength = 63
+
+// JobLabels returns query tags as job labels. Labels may only hold lowercase
+// letters, digits, underscores and dashes, so other characters are replaced
+// with underscores, e.g. a caller "jane@example.com" is labeled
+// "jane_example_com". Keys must also start with a letter.
+func JobLabels(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(tags))
+	for k, v := range tags {
+		k = labelValue(k)
+		if k == "" || k[0] < 'a' || k[0] > 'z' {
+			k = labelValue("tag_" + k)
+		}
+		labels[k] = labelValue(v)
+	}
+	return labels
+}
+
+func labelValue(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '_'
+		}
+	}, s)
+	if len(s) > maxLabelLength {
+		s = s[:maxLabelLength]
+	}
+	return s
+}
+
 func initBigQueryConnection(
 	ctx context.Context,
 	tracer trace.Tracer,
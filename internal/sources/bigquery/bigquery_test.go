@@ -48,6 +48,25 @@ func TestParseFromYamlBigQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with query tags",
+			in: `
+			sources:
+				my-instance:
+					kind: bigquery
+					project: my-project
+					queryTags:
+						team: finance
+			`,
+			want: server.SourceConfigs{
+				"my-instance": bigquery.Config{
+					Name:      "my-instance",
+					Kind:      bigquery.SourceKind,
+					Project:   "my-project",
+					QueryTags: map[string]string{"team": "finance"},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -113,3 +132,22 @@ func TestFailParseFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestJobLabels(t *testing.T) {
+	got := bigquery.JobLabels(map[string]string{
+		"team":           "Finance",
+		"toolbox_caller": "jane.doe@example.com",
+		"1st":            "a very long value that is longer than the sixty-three characters labels may hold",
+	})
+	want := map[string]string{
+		"team":           "finance",
+		"toolbox_caller": "jane_doe_example_com",
+		"tag_1st":        "a_very_long_value_that_is_longer_than_the_sixty-three_character",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect labels (-want +got):\n%s", diff)
+	}
+	if got := bigquery.JobLabels(nil); got != nil {
+		t.Fatalf("expected no labels, got %v", got)
+	}
+}
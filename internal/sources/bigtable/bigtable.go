@@ -82,6 +82,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Client.Close()
+}
+
 func (s *Source) BigtableClient() *bigtable.Client {
 	return s.Client
 }
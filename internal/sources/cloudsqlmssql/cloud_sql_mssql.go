@@ -106,6 +106,11 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Db.Close()
+}
+
 func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipAddress, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -97,6 +97,11 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Pool.Close()
+}
+
 func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -85,6 +85,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		Kind:                 SourceKind,
 		QueryScanConsistency: r.QueryScanConsistency,
 		Scope:                scope,
+		cluster:              cluster,
 	}
 	return s, nil
 }
@@ -96,12 +97,18 @@ type Source struct {
 	Kind                 string `yaml:"kind"`
 	QueryScanConsistency uint   `yaml:"queryScanConsistency"`
 	Scope                *gocb.Scope
+	cluster              *gocb.Cluster
 }
 
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.cluster.Close(nil)
+}
+
 func (s *Source) CouchbaseScope() *gocb.Scope {
 	return s.Scope
 }
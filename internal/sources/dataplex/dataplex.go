@@ -90,6 +90,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Client.Close()
+}
+
 func (s *Source) ProjectID() string {
 	return s.Project
 }
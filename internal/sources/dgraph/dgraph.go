@@ -108,6 +108,12 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the idle connections of the source.
+func (s *Source) Close() error {
+	s.Client.httpClient.CloseIdleConnections()
+	return nil
+}
+
 func (s *Source) DgraphClient() *DgraphClient {
 	return s.Client
 }
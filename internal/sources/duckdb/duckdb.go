@@ -17,13 +17,16 @@ package duckdb
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
-	_ "github.com/marcboeker/go-duckdb/v2"
+	goduckdb "github.com/marcboeker/go-duckdb/v2"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -58,6 +61,11 @@ func (s *Source) DuckDb() *sql.DB {
 	return s.Db
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Db.Close()
+}
+
 // validate Source
 var _ sources.Source = &Source{}
 
@@ -66,6 +74,61 @@ type Config struct {
 	Kind          string            `yaml:"kind" validate:"required"`
 	DatabaseFile  string            `yaml:"dbFilePath,omitempty"`
 	Configuration map[string]string `yaml:"configuration,omitempty"`
+	Attach        []AttachConfig    `yaml:"attach,omitempty"`
+}
+
+// Types of databases that can be attached. DuckDB loads the extension of a
+// type the first time a database of that type is attached.
+var attachTypes = []string{"duckdb", "sqlite", "postgres", "mysql"}
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AttachConfig is a database attached to the source under an alias, so tools
+// can query its tables as alias.table and join them with other databases.
+type AttachConfig struct {
+	Alias string `yaml:"alias" validate:"required"`
+	// Path is the path of a DuckDB or SQLite file, or the connection string
+	// of a Postgres or MySQL database.
+	Path string `yaml:"path" validate:"required"`
+	// Type is one of "duckdb", "sqlite", "postgres" or "mysql". Defaults to
+	// "duckdb".
+	Type     string `yaml:"type,omitempty"`
+	ReadOnly bool   `yaml:"readOnly,omitempty"`
+}
+
+// statement returns the ATTACH statement of the database.
+func (a AttachConfig) statement() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ATTACH IF NOT EXISTS '%s' AS %s", strings.ReplaceAll(a.Path, "'", "''"), a.Alias)
+	var opts []string
+	if a.Type != "" && a.Type != "duckdb" {
+		opts = append(opts, "TYPE "+a.Type)
+	}
+	if a.ReadOnly {
+		opts = append(opts, "READ_ONLY")
+	}
+	if len(opts) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(opts, ", "))
+	}
+	return b.String()
+}
+
+func validateAttach(attach []AttachConfig) error {
+	seen := make(map[string]bool, len(attach))
+	for _, a := range attach {
+		if !aliasPattern.MatchString(a.Alias) {
+			return fmt.Errorf("invalid alias %q: must start with a letter or underscore and contain only letters, digits and underscores", a.Alias)
+		}
+		key := strings.ToLower(a.Alias)
+		if seen[key] {
+			return fmt.Errorf("alias %q is attached more than once", a.Alias)
+		}
+		seen[key] = true
+		if a.Type != "" && !slices.Contains(attachTypes, a.Type) {
+			return fmt.Errorf("invalid type %q for %q: must be one of %q", a.Type, a.Alias, attachTypes)
+		}
+	}
+	return nil
 }
 
 func (r Config) SourceConfigKind() string {
@@ -73,7 +136,10 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	db, err := initDuckDbConnection(ctx, tracer, r.Name, r.DatabaseFile, r.Configuration)
+	if err := validateAttach(r.Attach); err != nil {
+		return nil, fmt.Errorf("invalid attach: %w", err)
+	}
+	db, err := initDuckDbConnection(ctx, tracer, r.Name, r.DatabaseFile, r.Configuration, r.Attach)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create db connection: %w", err)
 	}
@@ -94,19 +160,27 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 // validate interface
 var _ sources.SourceConfig = Config{}
 
-func initDuckDbConnection(ctx context.Context, tracer trace.Tracer, name string, dbFilePath string, duckdbConfiguration map[string]string) (*sql.DB, error) {
+func initDuckDbConnection(ctx context.Context, tracer trace.Tracer, name string, dbFilePath string, duckdbConfiguration map[string]string, attach []AttachConfig) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
 
 	var configStr string = getDuckDbConfiguration(dbFilePath, duckdbConfiguration)
 
-	//Open database connection
-	db, err := sql.Open("duckdb", configStr)
+	// attach the databases on every new connection, so none of the pool's
+	// connections miss them
+	connector, err := goduckdb.NewConnector(configStr, func(execer driver.ExecerContext) error {
+		for _, a := range attach {
+			if _, err := execer.ExecContext(context.Background(), a.statement(), nil); err != nil {
+				return fmt.Errorf("unable to attach %q: %w", a.Alias, err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to open duckdb connection: %w", err)
 	}
-	return db, nil
+	return sql.OpenDB(connector), nil
 }
 
 func getDuckDbConfiguration(dbFilePath string, duckdbConfiguration map[string]string) string {
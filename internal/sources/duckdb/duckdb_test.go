@@ -15,6 +15,9 @@
 package duckdb_test
 
 import (
+	"context"
+	"database/sql"
+	"path/filepath"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
@@ -22,6 +25,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources/duckdb"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParserFromYamlDuckDb(t *testing.T) {
@@ -65,6 +69,31 @@ func TestParserFromYamlDuckDb(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with attached databases",
+			in: `
+			sources:
+				my-duckdb:
+					kind: duckdb
+					attach:
+						- alias: sales
+							path: /data/sales.duckdb
+							readOnly: true
+						- alias: crm
+							type: postgres
+							path: host=localhost dbname=crm
+			`,
+			want: server.SourceConfigs{
+				"my-duckdb": duckdb.Config{
+					Name: "my-duckdb",
+					Kind: duckdb.SourceKind,
+					Attach: []duckdb.AttachConfig{
+						{Alias: "sales", Path: "/data/sales.duckdb", ReadOnly: true},
+						{Alias: "crm", Type: "postgres", Path: "host=localhost dbname=crm"},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -82,3 +111,66 @@ func TestParserFromYamlDuckDb(t *testing.T) {
 		})
 	}
 }
+
+func TestAttachCrossDatabaseQuery(t *testing.T) {
+	dir := t.TempDir()
+	// a database file with one table, attached by the source below
+	ordersPath := filepath.Join(dir, "orders.duckdb")
+	db, err := sql.Open("duckdb", ordersPath)
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	if _, err := db.Exec("CREATE TABLE orders (customer_id INTEGER, total DOUBLE); INSERT INTO orders VALUES (1, 19.5), (1, 5.5), (2, 3)"); err != nil {
+		t.Fatalf("unable to create table: %s", err)
+	}
+	db.Close()
+
+	cfg := duckdb.Config{
+		Name:   "my-duckdb",
+		Kind:   duckdb.SourceKind,
+		Attach: []duckdb.AttachConfig{{Alias: "sales", Path: ordersPath, ReadOnly: true}},
+	}
+	src, err := cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	pool := src.(*duckdb.Source).DuckDb()
+	defer pool.Close()
+	// a second connection must see the attached database too
+	pool.SetMaxIdleConns(0)
+	if _, err := pool.Exec("CREATE TABLE customers AS SELECT * FROM (VALUES (1, 'alice'), (2, 'bob')) t(id, name)"); err != nil {
+		t.Fatalf("unable to create table: %s", err)
+	}
+
+	var name string
+	var total float64
+	err = pool.QueryRow("SELECT c.name, SUM(o.total) FROM customers c JOIN sales.orders o ON o.customer_id = c.id GROUP BY c.name ORDER BY 2 DESC LIMIT 1").Scan(&name, &total)
+	if err != nil {
+		t.Fatalf("unable to run cross-database query: %s", err)
+	}
+	if name != "alice" || total != 25 {
+		t.Errorf("unexpected result: %s %v", name, total)
+	}
+	if _, err := pool.Exec("INSERT INTO sales.orders VALUES (3, 1)"); err == nil {
+		t.Errorf("expected a read-only database to reject writes")
+	}
+}
+
+func TestAttachErrors(t *testing.T) {
+	tcs := []struct {
+		desc   string
+		attach []duckdb.AttachConfig
+	}{
+		{desc: "invalid alias", attach: []duckdb.AttachConfig{{Alias: "sales; DROP", Path: "a.duckdb"}}},
+		{desc: "duplicate alias", attach: []duckdb.AttachConfig{{Alias: "a", Path: "a.duckdb"}, {Alias: "A", Path: "b.duckdb"}}},
+		{desc: "invalid type", attach: []duckdb.AttachConfig{{Alias: "a", Path: "a.db", Type: "oracle"}}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := duckdb.Config{Name: "my-duckdb", Kind: duckdb.SourceKind, Attach: tc.attach}
+			if _, err := cfg.Initialize(context.Background(), noop.NewTracerProvider().Tracer("")); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "elasticsearch"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name      string   `yaml:"name" validate:"required"`
+	Kind      string   `yaml:"kind" validate:"required"`
+	Addresses []string `yaml:"addresses" validate:"required"`
+	Username  string   `yaml:"username"`
+	Password  string   `yaml:"password"`
+	ApiKey    string   `yaml:"apiKey"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	client, err := initElasticsearchClient(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+
+	if err := client.ping(ctx); err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name:   r.Name,
+		Kind:   SourceKind,
+		Client: client,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name   string  `yaml:"name"`
+	Kind   string  `yaml:"kind"`
+	Client *Client `yaml:"client"`
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// Close closes the idle connections of the source.
+func (s *Source) Close() error {
+	s.Client.httpClient.CloseIdleConnections()
+	return nil
+}
+
+func (s *Source) ElasticsearchClient() *Client {
+	return s.Client
+}
+
+// Client is a minimal HTTP client for the Elasticsearch REST API. It is also
+// compatible with OpenSearch, which exposes the same search API.
+type Client struct {
+	httpClient *http.Client
+	addresses  []string
+	next       atomic.Uint64
+	username   string
+	password   string
+	apiKey     string
+}
+
+func initElasticsearchClient(ctx context.Context, tracer trace.Tracer, r Config) (*Client, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	if len(r.Addresses) == 0 {
+		return nil, fmt.Errorf("at least one address must be provided")
+	}
+	addresses := make([]string, 0, len(r.Addresses))
+	for _, a := range r.Addresses {
+		if _, err := url.ParseRequestURI(a); err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", a, err)
+		}
+		addresses = append(addresses, strings.TrimSuffix(a, "/"))
+	}
+
+	return &Client{
+		httpClient: &http.Client{},
+		addresses:  addresses,
+		username:   r.Username,
+		password:   r.Password,
+		apiKey:     r.ApiKey,
+	}, nil
+}
+
+// Search runs a Query DSL search request against the given index (or
+// comma-separated list of indices) and returns the raw response body.
+func (c *Client) Search(ctx context.Context, index string, body []byte) ([]byte, error) {
+	return c.do(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_search", body)
+}
+
+func (c *Client) ping(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodGet, "/", nil)
+	return err
+}
+
+// do sends a request to the next address in round-robin order.
+func (c *Client) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	addr := c.addresses[c.next.Add(1)%uint64(len(c.addresses))]
+	req, err := http.NewRequestWithContext(ctx, method, addr+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building request for %q: %w", addr+path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case c.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.apiKey)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
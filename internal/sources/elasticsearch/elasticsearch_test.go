@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/elasticsearch"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlElasticsearch(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-es-instance:
+					kind: elasticsearch
+					addresses:
+						- http://localhost:9200
+			`,
+			want: server.SourceConfigs{
+				"my-es-instance": elasticsearch.Config{
+					Name:      "my-es-instance",
+					Kind:      elasticsearch.SourceKind,
+					Addresses: []string{"http://localhost:9200"},
+				},
+			},
+		},
+		{
+			desc: "with credentials",
+			in: `
+			sources:
+				my-es-instance:
+					kind: elasticsearch
+					addresses:
+						- https://node1:9200
+						- https://node2:9200
+					username: elastic
+					password: changeme
+					apiKey: abc123
+			`,
+			want: server.SourceConfigs{
+				"my-es-instance": elasticsearch.Config{
+					Name:      "my-es-instance",
+					Kind:      elasticsearch.SourceKind,
+					Addresses: []string{"https://node1:9200", "https://node2:9200"},
+					Username:  "elastic",
+					Password:  "changeme",
+					ApiKey:    "abc123",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Sources); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYaml(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing required field",
+			in: `
+			sources:
+				my-es-instance:
+					kind: elasticsearch
+			`,
+			err: "unable to parse source \"my-es-instance\" as \"elasticsearch\": Key: 'Config.Addresses' Error:Field validation for 'Addresses' failed on the 'required' tag",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			errStr := err.Error()
+			if errStr != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", errStr, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exasol
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/exasol/exasol-driver-go/pkg/dsn"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDSN(t *testing.T) {
+	fingerprint := strings.Repeat("9A:", 31) + "9A"
+	cfg := Config{
+		Host:                   "10.0.0.5",
+		Port:                   "8564",
+		User:                   "my_user",
+		Password:               "p;a=ss",
+		Schema:                 "RETAIL",
+		CertificateFingerprint: fingerprint,
+		QueryTimeout:           "1500ms",
+	}
+	got, err := cfg.dsn()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	parsed, err := dsn.ParseDSN(got)
+	if err != nil {
+		t.Fatalf("unable to parse %q: %s", got, err)
+	}
+	want := &dsn.DSNConfig{
+		Host:                      "10.0.0.5",
+		Port:                      8564,
+		User:                      "my_user",
+		Password:                  "p;a=ss",
+		Autocommit:                parsed.Autocommit,
+		Encryption:                parsed.Encryption,
+		Compression:               parsed.Compression,
+		ValidateServerCertificate: parsed.ValidateServerCertificate,
+		CertificateFingerprint:    strings.ReplaceAll(fingerprint, ":", ""),
+		ClientName:                "genai-toolbox",
+		Params:                    parsed.Params,
+		FetchSize:                 parsed.FetchSize,
+		QueryTimeout:              2,
+		Schema:                    "RETAIL",
+	}
+	if diff := cmp.Diff(want, parsed); diff != "" {
+		t.Errorf("incorrect dsn: diff %v", diff)
+	}
+	if !*parsed.Autocommit || !*parsed.Encryption {
+		t.Errorf("expected autocommit and encryption, got %q", got)
+	}
+}
+
+func TestDSNInvalid(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  Config
+	}{
+		{desc: "port", cfg: Config{Host: "h", Port: "x"}},
+		{desc: "fingerprint", cfg: Config{Host: "h", Port: "8563", CertificateFingerprint: "9A:0B:1C"}},
+		{desc: "query timeout", cfg: Config{Host: "h", Port: "8563", QueryTimeout: "soon"}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := tc.cfg.dsn(); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exasol
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/exasol/exasol-driver-go"
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "exasol"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, Port: "8563"}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Host     string `yaml:"host" validate:"required"`
+	Port     string `yaml:"port" validate:"required"`
+	User     string `yaml:"user" validate:"required"`
+	Password string `yaml:"password" validate:"required"`
+	Schema   string `yaml:"schema"`
+	// CertificateFingerprint is the SHA-256 fingerprint of the server's
+	// certificate. When set, the certificate is pinned instead of verified
+	// against the system's root certificates, for self-signed certificates.
+	CertificateFingerprint string `yaml:"certificateFingerprint"`
+	QueryTimeout           string `yaml:"queryTimeout"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	db, err := initExasolConnection(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create db connection: %w", err)
+	}
+
+	// Verify db connection
+	err = db.PingContext(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name: r.Name,
+		Kind: SourceKind,
+		Db:   db,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+	Db   *sql.DB
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Db.Close()
+}
+
+func (s *Source) ExasolDB() *sql.DB {
+	return s.Db
+}
+
+func initExasolConnection(ctx context.Context, tracer trace.Tracer, r Config) (*sql.DB, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	dsn, err := r.dsn()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("exasol", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open: %w", err)
+	}
+	return db, nil
+}
+
+// dsn returns the data source name the driver connects with.
+func (r Config) dsn() (string, error) {
+	port, err := strconv.Atoi(r.Port)
+	if err != nil {
+		return "", fmt.Errorf("invalid port %q: %w", r.Port, err)
+	}
+	cfg := exasol.NewConfig(r.User, r.Password).
+		Host(r.Host).
+		Port(port).
+		Autocommit(true).
+		ClientName("genai-toolbox")
+	if r.Schema != "" {
+		cfg = cfg.Schema(r.Schema)
+	}
+	if r.QueryTimeout != "" {
+		timeout, err := time.ParseDuration(r.QueryTimeout)
+		if err != nil {
+			return "", fmt.Errorf("invalid queryTimeout %q: %w", r.QueryTimeout, err)
+		}
+		// the server takes the timeout in whole seconds
+		cfg = cfg.QueryTimeout(int(math.Ceil(timeout.Seconds())))
+	}
+	if r.CertificateFingerprint != "" {
+		// the driver compares the fingerprint in hex without colons, and
+		// skips the verification of the chain when it's set
+		fingerprint := strings.ReplaceAll(r.CertificateFingerprint, ":", "")
+		if b, err := hex.DecodeString(fingerprint); err != nil || len(b) != sha256.Size {
+			return "", fmt.Errorf("invalid certificateFingerprint %q: must be a hex-encoded SHA-256 digest", r.CertificateFingerprint)
+		}
+		cfg = cfg.CertificateFingerprint(fingerprint)
+	}
+	return cfg.String(), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exasol_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/exasol"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlExasol(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-exasol-instance:
+					kind: exasol
+					host: exasol.example.com
+					user: my_user
+					password: my_pass
+			`,
+			want: server.SourceConfigs{
+				"my-exasol-instance": exasol.Config{
+					Name:     "my-exasol-instance",
+					Kind:     exasol.SourceKind,
+					Host:     "exasol.example.com",
+					Port:     "8563",
+					User:     "my_user",
+					Password: "my_pass",
+				},
+			},
+		},
+		{
+			desc: "pinned certificate",
+			in: `
+			sources:
+				my-exasol-instance:
+					kind: exasol
+					host: 10.0.0.5
+					port: 8564
+					user: my_user
+					password: my_pass
+					schema: RETAIL
+					certificateFingerprint: 9A:0B:1C
+					queryTimeout: 30s
+			`,
+			want: server.SourceConfigs{
+				"my-exasol-instance": exasol.Config{
+					Name:                   "my-exasol-instance",
+					Kind:                   exasol.SourceKind,
+					Host:                   "10.0.0.5",
+					Port:                   "8564",
+					User:                   "my_user",
+					Password:               "my_pass",
+					Schema:                 "RETAIL",
+					CertificateFingerprint: "9A:0B:1C",
+					QueryTimeout:           "30s",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlExasol(t *testing.T) {
+	in := `
+	sources:
+		my-exasol-instance:
+			kind: exasol
+			host: exasol.example.com
+			user: my_user
+	`
+	got := struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+	}{}
+	if err := yaml.Unmarshal(testutils.FormatYaml(in), &got); err == nil {
+		t.Fatalf("expect parsing to fail")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firebolt
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	fireboltgosdk "github.com/firebolt-db/firebolt-go-sdk"
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "firebolt"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Kind string `yaml:"kind" validate:"required"`
+	// ClientID and ClientSecret are the credentials of a service account.
+	ClientID     string `yaml:"clientId" validate:"required"`
+	ClientSecret string `yaml:"clientSecret" validate:"required"`
+	Account      string `yaml:"account" validate:"required"`
+	Database     string `yaml:"database" validate:"required"`
+	Engine       string `yaml:"engine" validate:"required"`
+	QueryTimeout string `yaml:"queryTimeout"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	var timeout time.Duration
+	if r.QueryTimeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(r.QueryTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid queryTimeout %q: %w", r.QueryTimeout, err)
+		}
+	}
+
+	db, err := initFireboltConnection(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create db connection: %w", err)
+	}
+
+	s := &Source{
+		Name:         r.Name,
+		Kind:         SourceKind,
+		Db:           db,
+		QueryTimeout: timeout,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+	Db   *sql.DB
+	// QueryTimeout bounds the time tools wait for a query, if set.
+	QueryTimeout time.Duration
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Db.Close()
+}
+
+func (s *Source) FireboltDB() *sql.DB {
+	return s.Db
+}
+
+func (s *Source) FireboltQueryTimeout() time.Duration {
+	return s.QueryTimeout
+}
+
+func initFireboltConnection(ctx context.Context, tracer trace.Tracer, r Config) (*sql.DB, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	// the driver doesn't unescape the DSN, so values are written as they are
+	dsn := fmt.Sprintf("firebolt:///%s?account_name=%s&client_id=%s&client_secret=%s&engine=%s",
+		r.Database, r.Account, r.ClientID, r.ClientSecret, r.Engine)
+
+	// the connector authenticates and resolves the URL of the engine. A
+	// driver per source keeps sources from sharing the connection settings
+	// the registered driver caches.
+	connector, err := (&fireboltgosdk.FireboltDriver{}).OpenConnector(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to engine %q of database %q: %w", r.Engine, r.Database, err)
+	}
+	return sql.OpenDB(connector), nil
+}
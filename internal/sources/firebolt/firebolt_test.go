@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firebolt_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/firebolt"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlFirebolt(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-firebolt-instance:
+					kind: firebolt
+					clientId: my_client_id
+					clientSecret: my_client_secret
+					account: my_account
+					database: my_db
+					engine: my_engine
+			`,
+			want: server.SourceConfigs{
+				"my-firebolt-instance": firebolt.Config{
+					Name:         "my-firebolt-instance",
+					Kind:         firebolt.SourceKind,
+					ClientID:     "my_client_id",
+					ClientSecret: "my_client_secret",
+					Account:      "my_account",
+					Database:     "my_db",
+					Engine:       "my_engine",
+				},
+			},
+		},
+		{
+			desc: "query timeout",
+			in: `
+			sources:
+				my-firebolt-instance:
+					kind: firebolt
+					clientId: my_client_id
+					clientSecret: my_client_secret
+					account: my_account
+					database: my_db
+					engine: my_engine
+					queryTimeout: 1m
+			`,
+			want: server.SourceConfigs{
+				"my-firebolt-instance": firebolt.Config{
+					Name:         "my-firebolt-instance",
+					Kind:         firebolt.SourceKind,
+					ClientID:     "my_client_id",
+					ClientSecret: "my_client_secret",
+					Account:      "my_account",
+					Database:     "my_db",
+					Engine:       "my_engine",
+					QueryTimeout: "1m",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlFirebolt(t *testing.T) {
+	in := `
+	sources:
+		my-firebolt-instance:
+			kind: firebolt
+			clientId: my_client_id
+			clientSecret: my_client_secret
+			account: my_account
+			database: my_db
+	`
+	got := struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+	}{}
+	if err := yaml.Unmarshal(testutils.FormatYaml(in), &got); err == nil {
+		t.Fatalf("expect parsing to fail")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package greenplum
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "greenplum"
+
+// minMajorVersion is the oldest Greenplum release the source connects to.
+// Greenplum 5 is based on PostgreSQL 8.3, which pgx doesn't support.
+const minMajorVersion = 6
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, Port: "5432"} // Default Port
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Host     string `yaml:"host" validate:"required"`
+	Port     string `yaml:"port" validate:"required"`
+	User     string `yaml:"user" validate:"required"`
+	Password string `yaml:"password" validate:"required"`
+	Database string `yaml:"database" validate:"required"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	pool, err := initGreenplumConnectionPool(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create pool: %w", err)
+	}
+
+	var version string
+	if err := pool.QueryRow(ctx, "SELECT version()").Scan(&version); err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+	major, err := ParseMajorVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	if major < minMajorVersion {
+		return nil, fmt.Errorf("greenplum %d is not supported, the oldest supported version is %d", major, minMajorVersion)
+	}
+
+	s := &Source{
+		Name:         r.Name,
+		Kind:         SourceKind,
+		Pool:         pool,
+		MajorVersion: major,
+	}
+	return s, nil
+}
+
+var versionRe = regexp.MustCompile(`Greenplum Database (\d+)\.`)
+
+// ParseMajorVersion returns the major Greenplum version in the output of
+// `SELECT version()`, e.g. "PostgreSQL 12.12 (Greenplum Database 7.1.0 ...)".
+func ParseMajorVersion(version string) (int, error) {
+	m := versionRe.FindStringSubmatch(version)
+	if m == nil {
+		return 0, fmt.Errorf("server is not a Greenplum database: %q", version)
+	}
+	return strconv.Atoi(m[1])
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name         string `yaml:"name"`
+	Kind         string `yaml:"kind"`
+	Pool         *pgxpool.Pool
+	MajorVersion int
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+func (s *Source) PostgresPool() *pgxpool.Pool {
+	return s.Pool
+}
+
+// Close closes the connections of the source, once the ones in use are
+// released.
+func (s *Source) Close() error {
+	s.Pool.Close()
+	return nil
+}
+
+// GreenplumMajorVersion returns the major version of the server, which tools
+// use to pick catalog queries: Greenplum 7 replaced the legacy partitioning
+// catalog and storage column of Greenplum 6.
+func (s *Source) GreenplumMajorVersion() int {
+	return s.MajorVersion
+}
+
+func initGreenplumConnectionPool(ctx context.Context, tracer trace.Tracer, r Config) (*pgxpool.Pool, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	u := &url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(r.User, r.Password),
+		Host:   fmt.Sprintf("%s:%s", r.Host, r.Port),
+		Path:   r.Database,
+	}
+	pool, err := pgxpool.New(ctx, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	return pool, nil
+}
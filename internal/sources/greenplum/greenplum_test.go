@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package greenplum_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/greenplum"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlGreenplum(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-gp-instance:
+					kind: greenplum
+					host: my-host
+					database: my_db
+					user: my_user
+					password: my_pass
+			`,
+			want: server.SourceConfigs{
+				"my-gp-instance": greenplum.Config{
+					Name:     "my-gp-instance",
+					Kind:     greenplum.SourceKind,
+					Host:     "my-host",
+					Port:     "5432",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestParseMajorVersion(t *testing.T) {
+	tcs := []struct {
+		in   string
+		want int
+	}{
+		{
+			in:   "PostgreSQL 9.4.26 (Greenplum Database 6.26.4 build commit:abc) on x86_64-unknown-linux-gnu, compiled by gcc",
+			want: 6,
+		},
+		{
+			in:   "PostgreSQL 12.12 (Greenplum Database 7.1.0 build commit:def) on x86_64-pc-linux-gnu, compiled by gcc",
+			want: 7,
+		},
+	}
+	for _, tc := range tcs {
+		got, err := greenplum.ParseMajorVersion(tc.in)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != tc.want {
+			t.Fatalf("got %d, want %d", got, tc.want)
+		}
+	}
+	if _, err := greenplum.ParseMajorVersion("PostgreSQL 16.2 on x86_64-pc-linux-gnu"); err == nil {
+		t.Fatalf("expected error for a PostgreSQL server")
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beltran/gohive"
+)
+
+const (
+	// maxIdleTime is how long an idle session is kept, shorter than the idle
+	// timeouts of load balancers and servers.
+	maxIdleTime = 5 * time.Minute
+	// connectTimeout bounds connecting to the server when the context has
+	// no deadline.
+	connectTimeout = 30 * time.Second
+)
+
+// Client runs statements on HiveServer2, keeping a few sessions open between
+// them. Each session runs one statement at a time.
+type Client struct {
+	engine       string
+	host         string
+	port         int
+	auth         string
+	database     string
+	queryTimeout time.Duration
+	// config holds the settings every session is opened with
+	config gohive.ConnectConfiguration
+
+	// mu guards idle, and closed, which keeps sessions from being put back
+	// once the client is closed
+	mu     sync.Mutex
+	idle   []*session
+	closed bool
+}
+
+// Engine returns the engine serving HiveServer2, EngineHive or EngineImpala.
+func (c *Client) Engine() string {
+	return c.engine
+}
+
+// Query runs statement and returns its rows as maps from column names to
+// values. conf overrides configuration properties for the statement: Hive
+// configuration for Hive, and query options for Impala.
+func (c *Client) Query(ctx context.Context, statement string, conf map[string]string) ([]any, error) {
+	if c.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.queryTimeout)
+		defer cancel()
+	}
+	s, err := c.get(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	defer c.put(s)
+	return s.query(ctx, statement)
+}
+
+// get returns an idle session opened with conf, or opens one. gohive doesn't
+// send configuration with statements, so sessions are opened with the
+// overrides of the statements they run.
+func (c *Client) get(ctx context.Context, conf map[string]string) (*session, error) {
+	key := confKey(conf)
+	var expired []*session
+	c.mu.Lock()
+	var s *session
+	for i := len(c.idle) - 1; i >= 0; i-- {
+		idle := c.idle[i]
+		if time.Since(idle.lastUsed) >= maxIdleTime {
+			expired = append(expired, idle)
+			c.idle = slices.Delete(c.idle, i, i+1)
+			continue
+		}
+		if s == nil && idle.conf == key {
+			s = idle
+			c.idle = slices.Delete(c.idle, i, i+1)
+		}
+	}
+	c.mu.Unlock()
+	for _, e := range expired {
+		e.close()
+	}
+	if s != nil {
+		return s, nil
+	}
+	return c.open(ctx, conf)
+}
+
+func (c *Client) put(s *session) {
+	if s.broken {
+		s.close()
+		return
+	}
+	s.lastUsed = time.Now()
+	c.mu.Lock()
+	kept := !c.closed && len(c.idle) < maxIdleSessions
+	if kept {
+		c.idle = append(c.idle, s)
+	}
+	c.mu.Unlock()
+	if !kept {
+		s.close()
+	}
+}
+
+// Close closes the idle sessions, and sessions in use once they're put back.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	idle := c.idle
+	c.idle = nil
+	c.mu.Unlock()
+	for _, s := range idle {
+		s.close()
+	}
+	return nil
+}
+
+type session struct {
+	conn *gohive.Connection
+	// conf is the key of the overrides the session was opened with
+	conf     string
+	lastUsed time.Time
+	// broken is set once a request fails for another reason than the
+	// statement, after which the connection may be out of sync with the
+	// server.
+	broken bool
+}
+
+func (c *Client) open(ctx context.Context, conf map[string]string) (*session, error) {
+	cfg := c.config
+	cfg.HiveConfiguration = maps.Clone(c.config.HiveConfiguration)
+	if cfg.HiveConfiguration == nil {
+		cfg.HiveConfiguration = make(map[string]string, len(conf))
+	}
+	for k, v := range conf {
+		// Hive applies properties prefixed with set:hiveconf: like SET
+		// statements, Impala takes query options as they are
+		if c.engine == EngineHive {
+			k = "set:hiveconf:" + k
+		}
+		cfg.HiveConfiguration[k] = v
+	}
+	cfg.ConnectTimeout = connectTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		cfg.ConnectTimeout = time.Until(deadline)
+	}
+
+	// gohive panics on settings it doesn't know, which the config validates
+	conn, err := gohive.Connect(c.host, c.port, c.auth, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %s:%d: %w", c.host, c.port, err)
+	}
+	s := &session{conn: conn, conf: confKey(conf)}
+	if c.database != "" {
+		if _, err := s.query(ctx, "USE "+quoteIdentifier(c.database)); err != nil {
+			s.close()
+			return nil, fmt.Errorf("unable to use database %q: %w", c.database, err)
+		}
+	}
+	return s, nil
+}
+
+func (s *session) close() {
+	_ = s.conn.Close()
+}
+
+// query runs statement and fetches all of its rows. If ctx is done first,
+// the statement is cancelled.
+func (s *session) query(ctx context.Context, statement string) ([]any, error) {
+	cursor := s.conn.Cursor()
+	defer cursor.Close()
+
+	cursor.Exec(ctx, statement)
+	if err := s.check(ctx, cursor.Err); err != nil {
+		return nil, err
+	}
+	var out []any
+	for cursor.HasMore(ctx) {
+		if err := s.check(ctx, cursor.Err); err != nil {
+			return nil, err
+		}
+		row := cursor.RowMap(ctx)
+		if err := s.check(ctx, cursor.Err); err != nil {
+			return nil, err
+		}
+		out = append(out, convertRow(row))
+	}
+	if err := s.check(ctx, cursor.Err); err != nil {
+		return nil, fmt.Errorf("unable to fetch results: %w", err)
+	}
+	return out, nil
+}
+
+// check returns the error of a request, marking the session broken unless
+// the server rejected the statement.
+func (s *session) check(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var hiveErr gohive.HiveError
+	if errors.As(err, &hiveErr) && hiveErr.Message != "" {
+		return errors.New(hiveErr.Message)
+	}
+	s.broken = true
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// convertRow widens the integers of a row to int64. gohive returns strings
+// for decimals, dates, timestamps and complex types, and bytes for binary
+// values.
+func convertRow(row map[string]any) map[string]any {
+	for k, v := range row {
+		switch v := v.(type) {
+		case int8:
+			row[k] = int64(v)
+		case int16:
+			row[k] = int64(v)
+		case int32:
+			row[k] = int64(v)
+		}
+	}
+	return row
+}
+
+// confKey returns a key identifying the overrides of conf.
+func confKey(conf map[string]string) string {
+	keys := slices.Sorted(maps.Keys(conf))
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, conf[k])
+	}
+	return b.String()
+}
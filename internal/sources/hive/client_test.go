@@ -0,0 +1,210 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hive
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/beltran/gohive"
+	"github.com/beltran/gohive/hiveserver"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeServer is a HiveServer2 without SASL that answers every query with the
+// same two rows, and rejects statements starting with "FAIL".
+type fakeServer struct {
+	// TCLIService isn't set, the methods the client doesn't call panic
+	hiveserver.TCLIService
+
+	mu         sync.Mutex
+	sessions   []map[string]string
+	statements []string
+	// fetched is set for the operations whose rows were fetched
+	fetched map[string]bool
+}
+
+func newFakeServer(t *testing.T) (*fakeServer, string, int) {
+	f := &fakeServer{fetched: map[string]bool{}}
+	socket, err := thrift.NewTServerSocket("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to create socket: %s", err)
+	}
+	if err := socket.Listen(); err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	srv := thrift.NewTSimpleServer4(hiveserver.NewTCLIServiceProcessor(f), socket,
+		thrift.NewTBufferedTransportFactory(4096), thrift.NewTBinaryProtocolFactoryConf(nil))
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	host, port, _ := net.SplitHostPort(socket.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return f, host, p
+}
+
+func ok() *hiveserver.TStatus {
+	return &hiveserver.TStatus{StatusCode: hiveserver.TStatusCode_SUCCESS_STATUS}
+}
+
+func handle(id string) *hiveserver.THandleIdentifier {
+	return &hiveserver.THandleIdentifier{GUID: []byte(id), Secret: []byte("secret")}
+}
+
+func (f *fakeServer) OpenSession(_ context.Context, req *hiveserver.TOpenSessionReq) (*hiveserver.TOpenSessionResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions = append(f.sessions, req.Configuration)
+	return &hiveserver.TOpenSessionResp{
+		Status:                ok(),
+		ServerProtocolVersion: req.ClientProtocol,
+		SessionHandle:         &hiveserver.TSessionHandle{SessionId: handle(fmt.Sprint("session", len(f.sessions)))},
+	}, nil
+}
+
+func (f *fakeServer) CloseSession(context.Context, *hiveserver.TCloseSessionReq) (*hiveserver.TCloseSessionResp, error) {
+	return &hiveserver.TCloseSessionResp{Status: ok()}, nil
+}
+
+func (f *fakeServer) ExecuteStatement(_ context.Context, req *hiveserver.TExecuteStatementReq) (*hiveserver.TExecuteStatementResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = append(f.statements, req.Statement)
+	if strings.HasPrefix(req.Statement, "FAIL") {
+		msg := "ParseException line 1:0 cannot recognize input near 'FAIL'"
+		return &hiveserver.TExecuteStatementResp{Status: &hiveserver.TStatus{StatusCode: hiveserver.TStatusCode_ERROR_STATUS, ErrorMessage: &msg}}, nil
+	}
+	return &hiveserver.TExecuteStatementResp{
+		Status: ok(),
+		OperationHandle: &hiveserver.TOperationHandle{
+			OperationId:   handle(fmt.Sprint("op", len(f.statements))),
+			OperationType: hiveserver.TOperationType_EXECUTE_STATEMENT,
+			HasResultSet:  !strings.HasPrefix(req.Statement, "USE"),
+		},
+	}, nil
+}
+
+func (f *fakeServer) GetOperationStatus(context.Context, *hiveserver.TGetOperationStatusReq) (*hiveserver.TGetOperationStatusResp, error) {
+	state := hiveserver.TOperationState_FINISHED_STATE
+	return &hiveserver.TGetOperationStatusResp{Status: ok(), OperationState: &state}, nil
+}
+
+func (f *fakeServer) GetResultSetMetadata(context.Context, *hiveserver.TGetResultSetMetadataReq) (*hiveserver.TGetResultSetMetadataResp, error) {
+	column := func(name string, typ hiveserver.TTypeId, pos int32) *hiveserver.TColumnDesc {
+		return &hiveserver.TColumnDesc{
+			ColumnName: name,
+			TypeDesc:   &hiveserver.TTypeDesc{Types: []*hiveserver.TTypeEntry{{PrimitiveEntry: &hiveserver.TPrimitiveTypeEntry{Type: typ}}}},
+			Position:   pos,
+		}
+	}
+	return &hiveserver.TGetResultSetMetadataResp{
+		Status: ok(),
+		Schema: &hiveserver.TTableSchema{Columns: []*hiveserver.TColumnDesc{
+			column("id", hiveserver.TTypeId_INT_TYPE, 1),
+			column("name", hiveserver.TTypeId_STRING_TYPE, 2),
+		}},
+	}, nil
+}
+
+func (f *fakeServer) FetchResults(_ context.Context, req *hiveserver.TFetchResultsReq) (*hiveserver.TFetchResultsResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := string(req.OperationHandle.OperationId.GUID)
+	ids := &hiveserver.TI32Column{Values: []int32{}, Nulls: []byte{}}
+	names := &hiveserver.TStringColumn{Values: []string{}, Nulls: []byte{}}
+	if !f.fetched[id] {
+		f.fetched[id] = true
+		ids = &hiveserver.TI32Column{Values: []int32{1, 2}, Nulls: []byte{0}}
+		names = &hiveserver.TStringColumn{Values: []string{"a", ""}, Nulls: []byte{2}}
+	}
+	return &hiveserver.TFetchResultsResp{
+		Status: ok(),
+		Results: &hiveserver.TRowSet{
+			Rows:    []*hiveserver.TRow{},
+			Columns: []*hiveserver.TColumn{{I32Val: ids}, {StringVal: names}},
+		},
+	}, nil
+}
+
+func (f *fakeServer) CloseOperation(context.Context, *hiveserver.TCloseOperationReq) (*hiveserver.TCloseOperationResp, error) {
+	return &hiveserver.TCloseOperationResp{Status: ok()}, nil
+}
+
+func TestClientQuery(t *testing.T) {
+	f, host, port := newFakeServer(t)
+	cfg := gohive.NewConnectConfiguration()
+	cfg.Username = "alice"
+	cfg.PollIntervalInMillis = 1
+	cfg.HiveConfiguration = map[string]string{"set:hiveconf:hive.resultset.use.unique.column.names": "false"}
+	c := &Client{
+		engine:   EngineHive,
+		host:     host,
+		port:     port,
+		auth:     AuthNoSASL,
+		database: "sales",
+		config:   *cfg,
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	strict := map[string]string{"hive.strict.checks.no.partition.filter": "true"}
+	for _, conf := range []map[string]string{strict, strict, nil} {
+		got, err := c.Query(ctx, "SELECT id, name FROM orders", conf)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := []any{
+			map[string]any{"id": int64(1), "name": "a"},
+			map[string]any{"id": int64(2), "name": nil},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("incorrect rows: diff %v", diff)
+		}
+	}
+	// the server rejecting a statement doesn't break the session
+	if _, err := c.Query(ctx, "FAIL", nil); err == nil || !strings.Contains(err.Error(), "cannot recognize input") {
+		t.Fatalf("expected the error of the server, got %v", err)
+	}
+	if _, err := c.Query(ctx, "SELECT 1", nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// a session is opened for each configuration, and reused
+	wantSessions := []map[string]string{
+		{
+			"set:hiveconf:hive.resultset.use.unique.column.names": "false",
+			"set:hiveconf:hive.strict.checks.no.partition.filter": "true",
+		},
+		{"set:hiveconf:hive.resultset.use.unique.column.names": "false"},
+	}
+	if diff := cmp.Diff(wantSessions, f.sessions); diff != "" {
+		t.Errorf("incorrect sessions: diff %v", diff)
+	}
+	wantStatements := []string{
+		"USE `sales`", "SELECT id, name FROM orders", "SELECT id, name FROM orders",
+		"USE `sales`", "SELECT id, name FROM orders", "FAIL", "SELECT 1",
+	}
+	if diff := cmp.Diff(wantStatements, f.statements); diff != "" {
+		t.Errorf("incorrect statements: diff %v", diff)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hive
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beltran/gohive"
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "hive"
+
+// Engines serving HiveServer2.
+const (
+	EngineHive   string = "hive"
+	EngineImpala string = "impala"
+)
+
+// Authentication mechanisms. NONE and LDAP authenticate with SASL PLAIN, or
+// HTTP basic authentication, KERBEROS with GSSAPI, or SPNEGO over HTTP, and
+// NOSASL sends requests without a SASL handshake.
+const (
+	AuthNone     string = "NONE"
+	AuthLDAP     string = "LDAP"
+	AuthKerberos string = "KERBEROS"
+	AuthNoSASL   string = "NOSASL"
+)
+
+// Transport modes of HiveServer2, hive.server2.transport.mode.
+const (
+	TransportBinary string = "binary"
+	TransportHTTP   string = "http"
+)
+
+// maxIdleSessions is the number of sessions kept open between queries.
+const maxIdleSessions = 4
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, Engine: EngineHive}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Kind string `yaml:"kind" validate:"required"`
+	Host string `yaml:"host" validate:"required"`
+	// Port defaults to 10000 for Hive and 21050 for Impala.
+	Port   string `yaml:"port"`
+	Engine string `yaml:"engine" validate:"oneof=hive impala"`
+	// Auth defaults to NONE for Hive and NOSASL for Impala.
+	Auth     string `yaml:"auth" validate:"omitempty,oneof=NONE LDAP KERBEROS NOSASL"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	// KerberosService is the service name of the server's principal. Defaults
+	// to the engine.
+	KerberosService string `yaml:"kerberosService"`
+	// TransportMode defaults to binary.
+	TransportMode string `yaml:"transportMode" validate:"omitempty,oneof=binary http"`
+	// HTTPPath is the path of the endpoint in the http transport mode.
+	// Defaults to cliservice.
+	HTTPPath     string `yaml:"httpPath"`
+	Database     string `yaml:"database"`
+	UseTLS       bool   `yaml:"useTLS"`
+	QueryTimeout string `yaml:"queryTimeout"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	client, err := initHiveClient(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+
+	s := &Source{
+		Name:   r.Name,
+		Kind:   SourceKind,
+		Client: client,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name   string `yaml:"name"`
+	Kind   string `yaml:"kind"`
+	Client *Client
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// Close closes the sessions of the source. Sessions in use are closed
+// when their queries end.
+func (s *Source) Close() error {
+	return s.Client.Close()
+}
+
+func (s *Source) HiveClient() *Client {
+	return s.Client
+}
+
+func initHiveClient(ctx context.Context, tracer trace.Tracer, r Config) (*Client, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	port, auth := r.Port, r.Auth
+	if port == "" {
+		port = "10000"
+		if r.Engine == EngineImpala {
+			port = "21050"
+		}
+	}
+	if auth == "" {
+		auth = AuthNone
+		if r.Engine == EngineImpala {
+			auth = AuthNoSASL
+		}
+	}
+	if auth == AuthLDAP && (r.User == "" || r.Password == "") {
+		return nil, fmt.Errorf("LDAP authentication requires a user and password")
+	}
+	if auth == AuthKerberos && !kerberosSupported {
+		return nil, fmt.Errorf("%s authentication requires Toolbox to be built with the kerberos build tag", AuthKerberos)
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", port)
+	}
+	var timeout time.Duration
+	if r.QueryTimeout != "" {
+		timeout, err = time.ParseDuration(r.QueryTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid queryTimeout %q: %w", r.QueryTimeout, err)
+		}
+	}
+
+	cfg := gohive.NewConnectConfiguration()
+	cfg.Username, cfg.Password = r.User, r.Password
+	// PLAIN requires a user, which HiveServer2 ignores without LDAP
+	if cfg.Username == "" && auth != AuthKerberos {
+		cfg.Username = "anonymous"
+	}
+	if auth == AuthKerberos {
+		cfg.Service = r.KerberosService
+		if cfg.Service == "" {
+			cfg.Service = r.Engine
+		}
+	}
+	if r.TransportMode == TransportHTTP {
+		cfg.TransportMode = TransportHTTP
+		if r.HTTPPath != "" {
+			cfg.HTTPPath = strings.TrimPrefix(r.HTTPPath, "/")
+		}
+		switch auth {
+		case AuthNoSASL:
+			return nil, fmt.Errorf("%s authentication isn't supported with the %s transport mode", AuthNoSASL, TransportHTTP)
+		case AuthLDAP:
+			// the user and password are sent with basic authentication
+			auth = AuthNone
+		}
+	}
+	if r.UseTLS {
+		cfg.TLSConfig = &tls.Config{ServerName: r.Host, MinVersion: tls.VersionTLS12}
+	}
+	if r.Engine == EngineHive {
+		// name result columns "col" rather than "table.col"
+		cfg.HiveConfiguration = map[string]string{"set:hiveconf:hive.resultset.use.unique.column.names": "false"}
+	}
+
+	c := &Client{
+		engine:       r.Engine,
+		host:         r.Host,
+		port:         portNum,
+		auth:         auth,
+		database:     r.Database,
+		queryTimeout: timeout,
+		config:       *cfg,
+	}
+
+	// open a first session to verify the connection and credentials
+	s, err := c.open(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.put(s)
+	return c, nil
+}
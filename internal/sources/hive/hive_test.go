@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hive_test
+
+import (
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/hive"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlHive(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-hive-instance:
+					kind: hive
+					host: hs2.example.com
+			`,
+			want: server.SourceConfigs{
+				"my-hive-instance": hive.Config{
+					Name:   "my-hive-instance",
+					Kind:   hive.SourceKind,
+					Host:   "hs2.example.com",
+					Engine: "hive",
+				},
+			},
+		},
+		{
+			desc: "impala with ldap",
+			in: `
+			sources:
+				my-impala-instance:
+					kind: hive
+					engine: impala
+					host: impala.example.com
+					port: 21051
+					auth: LDAP
+					user: my_user
+					password: my_pass
+					database: sales
+					useTLS: true
+					queryTimeout: 5m
+			`,
+			want: server.SourceConfigs{
+				"my-impala-instance": hive.Config{
+					Name:         "my-impala-instance",
+					Kind:         hive.SourceKind,
+					Host:         "impala.example.com",
+					Port:         "21051",
+					Engine:       "impala",
+					Auth:         "LDAP",
+					User:         "my_user",
+					Password:     "my_pass",
+					Database:     "sales",
+					UseTLS:       true,
+					QueryTimeout: "5m",
+				},
+			},
+		},
+		{
+			desc: "kerberos over http",
+			in: `
+			sources:
+				my-hive-instance:
+					kind: hive
+					host: hs2.example.com
+					port: 10001
+					auth: KERBEROS
+					kerberosService: hive
+					transportMode: http
+					httpPath: gateway/default/hive
+			`,
+			want: server.SourceConfigs{
+				"my-hive-instance": hive.Config{
+					Name:            "my-hive-instance",
+					Kind:            hive.SourceKind,
+					Host:            "hs2.example.com",
+					Port:            "10001",
+					Engine:          "hive",
+					Auth:            "KERBEROS",
+					KerberosService: "hive",
+					TransportMode:   "http",
+					HTTPPath:        "gateway/default/hive",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlHive(t *testing.T) {
+	in := `
+	sources:
+		my-hive-instance:
+			kind: hive
+			host: hs2.example.com
+			engine: presto
+	`
+	got := struct {
+		Sources server.SourceConfigs `yaml:"sources"`
+	}{}
+	if err := yaml.Unmarshal(testutils.FormatYaml(in), &got); err == nil {
+		t.Fatalf("expect parsing to fail")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		statement string
+		args      []any
+		want      string
+	}{
+		{
+			desc:      "values",
+			statement: "SELECT * FROM t WHERE a = ? AND b = ? AND c > ? AND d = ? AND e IS ?",
+			args:      []any{"it's", 3, 1.5, true, nil},
+			want:      `SELECT * FROM t WHERE a = 'it\'s' AND b = 3 AND c > 1.5 AND d = TRUE AND e IS NULL`,
+		},
+		{
+			desc:      "quotes and comments",
+			statement: "SELECT '?', `a?`, \"\\\"?\" -- ?\nFROM t /* ? */ WHERE x = ?",
+			args:      []any{`a\b`},
+			want:      "SELECT '?', `a?`, \"\\\"?\" -- ?\nFROM t /* ? */ WHERE x = 'a\\\\b'",
+		},
+		{
+			desc:      "timestamp, decimal and array",
+			statement: "SELECT * FROM t WHERE ts > ? AND amount = ? AND dt IN (?)",
+			args:      []any{time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), hive.Decimal("12.50"), []any{"2025-01-01", "2025-01-02"}},
+			want:      "SELECT * FROM t WHERE ts > CAST('2025-01-02 03:04:05' AS TIMESTAMP) AND amount = 12.50 AND dt IN ('2025-01-01', '2025-01-02')",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := hive.Interpolate(tc.statement, tc.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInterpolateInvalid(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		statement string
+		args      []any
+	}{
+		{desc: "too few args", statement: "SELECT ? + ?", args: []any{1}},
+		{desc: "too many args", statement: "SELECT ?", args: []any{1, 2}},
+		{desc: "empty array", statement: "SELECT * FROM t WHERE a IN (?)", args: []any{[]any{}}},
+		{desc: "invalid decimal", statement: "SELECT ?", args: []any{hive.Decimal("1; DROP TABLE t")}},
+		{desc: "decimal infinity", statement: "SELECT ?", args: []any{hive.Decimal("Inf")}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := hive.Interpolate(tc.statement, tc.args); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
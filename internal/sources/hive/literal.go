@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hive
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decimal is a decimal number, written to statements unquoted so it compares
+// with DECIMAL columns, which Impala doesn't compare with strings.
+type Decimal string
+
+var decimalRe = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// Interpolate replaces each `?` placeholder of statement, outside of quotes
+// and comments, with the next of args as a SQL literal. HiveServer2 doesn't
+// bind parameters, so statements are sent with their values inlined.
+func Interpolate(statement string, args []any) (string, error) {
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// copy the quoted string or identifier, including escapes
+			j := i + 1
+			for j < len(statement) && statement[j] != c {
+				if statement[j] == '\\' && c != '`' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(statement))
+			b.WriteString(statement[i:j])
+			i = j - 1
+		case c == '-' && strings.HasPrefix(statement[i:], "--"):
+			j := strings.IndexByte(statement[i:], '\n')
+			if j < 0 {
+				j = len(statement) - i
+			}
+			b.WriteString(statement[i : i+j])
+			i += j - 1
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			j := strings.Index(statement[i+2:], "*/")
+			if j < 0 {
+				j = len(statement) - i
+			} else {
+				j += 4
+			}
+			b.WriteString(statement[i : i+j])
+			i += j - 1
+		case c == '?':
+			if next >= len(args) {
+				return "", fmt.Errorf("statement has more placeholders than the %d parameters", len(args))
+			}
+			lit, err := literal(args[next])
+			if err != nil {
+				return "", fmt.Errorf("parameter %d: %w", next+1, err)
+			}
+			b.WriteString(lit)
+			next++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if next != len(args) {
+		return "", fmt.Errorf("statement has %d placeholders but %d parameters", next, len(args))
+	}
+	return b.String(), nil
+}
+
+func literal(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return quoteString(v), nil
+	case Decimal:
+		if !decimalRe.MatchString(string(v)) {
+			return "", fmt.Errorf("%q is not a decimal number", v)
+		}
+		return string(v), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("%v is not a finite number", v)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Time:
+		return fmt.Sprintf("CAST('%s' AS TIMESTAMP)", v.Format("2006-01-02 15:04:05.999999999")), nil
+	case []any:
+		// written as a list of values, e.g. for `IN (?)`
+		if len(v) == 0 {
+			return "", fmt.Errorf("array must not be empty")
+		}
+		parts := make([]string, len(v))
+		for i, e := range v {
+			lit, err := literal(e)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = lit
+		}
+		return strings.Join(parts, ", "), nil
+	default:
+		return "", fmt.Errorf("unsupported parameter type %T", v)
+	}
+}
+
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
@@ -119,3 +119,9 @@ type Source struct {
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
+
+// Close closes the idle connections of the source.
+func (s *Source) Close() error {
+	s.Client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "kafka"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name          string   `yaml:"name" validate:"required"`
+	Kind          string   `yaml:"kind" validate:"required"`
+	Brokers       []string `yaml:"brokers" validate:"required"`
+	Username      string   `yaml:"username"`
+	Password      string   `yaml:"password"`
+	SaslMechanism string   `yaml:"saslMechanism"`
+	UseTLS        bool     `yaml:"useTLS"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	dialer, writer, err := initKafkaClients(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+
+	// verify that at least one broker is reachable
+	conn, err := dialer.DialContext(ctx, "tcp", r.Brokers[0])
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Brokers(); err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name:    r.Name,
+		Kind:    SourceKind,
+		Brokers: r.Brokers,
+		Dialer:  dialer,
+		Writer:  writer,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name    string   `yaml:"name"`
+	Kind    string   `yaml:"kind"`
+	Brokers []string `yaml:"brokers"`
+	Dialer  *kafka.Dialer
+	Writer  *kafka.Writer
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// Close flushes the messages the writer holds and closes its connections.
+func (s *Source) Close() error {
+	return s.Writer.Close()
+}
+
+// KafkaWriter returns a writer that publishes messages to any topic.
+func (s *Source) KafkaWriter() *kafka.Writer {
+	return s.Writer
+}
+
+// KafkaDialer returns a dialer configured with the source's credentials.
+func (s *Source) KafkaDialer() *kafka.Dialer {
+	return s.Dialer
+}
+
+// KafkaBrokers returns the bootstrap brokers of the cluster.
+func (s *Source) KafkaBrokers() []string {
+	return s.Brokers
+}
+
+func initKafkaClients(ctx context.Context, tracer trace.Tracer, r Config) (*kafka.Dialer, *kafka.Writer, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	if len(r.Brokers) == 0 {
+		return nil, nil, fmt.Errorf("at least one broker must be provided")
+	}
+
+	mechanism, err := saslMechanism(r.SaslMechanism, r.Username, r.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tlsConfig *tls.Config
+	if r.UseTLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	dialer := &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		SASLMechanism: mechanism,
+		TLS:           tlsConfig,
+	}
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(r.Brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		Transport: &kafka.Transport{
+			SASL: mechanism,
+			TLS:  tlsConfig,
+		},
+	}
+	return dialer, writer, nil
+}
+
+// saslMechanism returns the SASL mechanism for the given name. An empty name
+// defaults to PLAIN if a username is provided, and no authentication
+// otherwise.
+func saslMechanism(name, username, password string) (sasl.Mechanism, error) {
+	switch strings.ToLower(name) {
+	case "":
+		if username == "" {
+			return nil, nil
+		}
+		return plain.Mechanism{Username: username, Password: password}, nil
+	case "plain":
+		return plain.Mechanism{Username: username, Password: password}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, username, password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, username, password)
+	default:
+		return nil, fmt.Errorf(`invalid saslMechanism %q: must be one of "plain", "scram-sha-256", or "scram-sha-512"`, name)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlKafka(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-kafka-instance:
+					kind: kafka
+					brokers:
+						- localhost:9092
+			`,
+			want: server.SourceConfigs{
+				"my-kafka-instance": kafka.Config{
+					Name:    "my-kafka-instance",
+					Kind:    kafka.SourceKind,
+					Brokers: []string{"localhost:9092"},
+				},
+			},
+		},
+		{
+			desc: "with sasl",
+			in: `
+			sources:
+				my-kafka-instance:
+					kind: kafka
+					brokers:
+						- broker1:9093
+						- broker2:9093
+					username: user
+					password: pass
+					saslMechanism: scram-sha-512
+					useTLS: true
+			`,
+			want: server.SourceConfigs{
+				"my-kafka-instance": kafka.Config{
+					Name:          "my-kafka-instance",
+					Kind:          kafka.SourceKind,
+					Brokers:       []string{"broker1:9093", "broker2:9093"},
+					Username:      "user",
+					Password:      "pass",
+					SaslMechanism: "scram-sha-512",
+					UseTLS:        true,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Sources); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYaml(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing required field",
+			in: `
+			sources:
+				my-kafka-instance:
+					kind: kafka
+			`,
+			err: "unable to parse source \"my-kafka-instance\" as \"kafka\": Key: 'Config.Brokers' Error:Field validation for 'Brokers' failed on the 'required' tag",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			errStr := err.Error()
+			if errStr != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", errStr, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"time"
+	"unicode/utf8"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// Scope is the part of the directory tree below the base DN that a search
+// covers.
+type Scope int
+
+const (
+	ScopeBase Scope = 0
+	ScopeOne  Scope = 1
+	ScopeSub  Scope = 2
+)
+
+// ParseScope parses "base", "one", or "sub".
+func ParseScope(s string) (Scope, error) {
+	switch s {
+	case "base":
+		return ScopeBase, nil
+	case "one":
+		return ScopeOne, nil
+	case "", "sub":
+		return ScopeSub, nil
+	default:
+		return 0, fmt.Errorf(`invalid scope %q: must be one of "base", "one", or "sub"`, s)
+	}
+}
+
+// SearchRequest is a search of the directory.
+type SearchRequest struct {
+	BaseDN string
+	Scope  Scope
+	// Filter is a search filter in its string representation, e.g.
+	// "(&(objectClass=person)(uid=jdoe))".
+	Filter string
+	// Attributes to return. All user attributes are returned if empty.
+	Attributes []string
+	// SizeLimit is the maximum number of entries to return.
+	SizeLimit int
+}
+
+// Entry is an entry returned by a search.
+type Entry struct {
+	DN         string
+	Attributes []Attribute
+}
+
+// Attribute is an attribute of an entry and its values.
+type Attribute struct {
+	Name   string
+	Values [][]byte
+}
+
+// Row converts the entry into a row. Attributes with a single value are
+// returned as a string and others as a list. Values that aren't valid UTF-8,
+// such as objectGUID, are base64 encoded.
+func (e Entry) Row() map[string]any {
+	row := map[string]any{"dn": e.DN}
+	for _, a := range e.Attributes {
+		values := make([]any, 0, len(a.Values))
+		for _, v := range a.Values {
+			if utf8.Valid(v) {
+				values = append(values, string(v))
+			} else {
+				values = append(values, base64.StdEncoding.EncodeToString(v))
+			}
+		}
+		if len(values) == 1 {
+			row[a.Name] = values[0]
+		} else {
+			row[a.Name] = values
+		}
+	}
+	return row
+}
+
+// EscapeFilter escapes a value for use in a search filter, so that it only
+// ever matches literally (RFC 4515, section 3).
+func EscapeFilter(s string) string {
+	return goldap.EscapeFilter(s)
+}
+
+// EscapeDN escapes a value for use as an attribute value of a distinguished
+// name (RFC 4514, section 2.4).
+func EscapeDN(s string) string {
+	return goldap.EscapeDN(s)
+}
+
+// ValidateFilter reports whether filter is a valid search filter.
+func ValidateFilter(filter string) error {
+	_, err := goldap.CompileFilter(filter)
+	return err
+}
+
+// Client searches an LDAP directory. Every search opens its own connection
+// and binds with the client's credentials, so searches never share state.
+type Client struct {
+	addr      string
+	tlsConfig *tls.Config
+	startTLS  bool
+	bindDN    string
+	password  string
+	timeout   time.Duration
+}
+
+// dial connects and binds to the directory. Dialing and every request are
+// bounded by the client's timeout, or by ctx's deadline if it's sooner.
+func (c *Client) dial(ctx context.Context) (*goldap.Conn, error) {
+	timeout := c.timeout
+	if d, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(d))
+	}
+	url := "ldap://" + c.addr
+	opts := []goldap.DialOpt{goldap.DialWithDialer(&net.Dialer{Timeout: timeout})}
+	if c.tlsConfig != nil && !c.startTLS {
+		url = "ldaps://" + c.addr
+		opts = append(opts, goldap.DialWithTLSConfig(c.tlsConfig))
+	}
+	conn, err := goldap.DialURL(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+
+	if c.startTLS {
+		if err := conn.StartTLS(c.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("unable to start TLS: %w", err)
+		}
+	}
+	if c.bindDN != "" {
+		if err := conn.Bind(c.bindDN, c.password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("unable to bind as %q: %w", c.bindDN, err)
+		}
+	}
+	return conn, nil
+}
+
+// Ping connects and binds to the directory.
+func (c *Client) Ping(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Unbind()
+}
+
+// Search runs a search and returns the entries it found. If the size limit is
+// reached, the entries found so far are returned.
+func (c *Client) Search(ctx context.Context, req SearchRequest) ([]Entry, error) {
+	if err := ValidateFilter(req.Filter); err != nil {
+		return nil, err
+	}
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Unbind()
+	// stop waiting for the response if the request is canceled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	result, err := conn.Search(goldap.NewSearchRequest(
+		req.BaseDN,
+		int(req.Scope),
+		goldap.NeverDerefAliases,
+		req.SizeLimit,
+		int(c.timeout/time.Second),
+		false,
+		req.Filter,
+		req.Attributes,
+		nil,
+	))
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, err
+	}
+	// referrals to other servers aren't followed
+	entries := make([]Entry, 0, len(result.Entries))
+	for _, e := range result.Entries {
+		entry := Entry{DN: e.DN}
+		for _, a := range e.Attributes {
+			entry.Attributes = append(entry.Attributes, Attribute{Name: a.Name, Values: a.ByteValues})
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
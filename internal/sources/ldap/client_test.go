@@ -0,0 +1,240 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateFilter(t *testing.T) {
+	for _, f := range []string{"(cn=Babs Jensen)", "(&(a=b)(|(c=d)))", "(cn=J*o*n)", `(cn=a\2ab)`, "(memberOf:1.2.3:=x)"} {
+		if err := ValidateFilter(f); err != nil {
+			t.Errorf("ValidateFilter(%q) unexpected error: %s", f, err)
+		}
+	}
+	for _, f := range []string{"", "cn=a", "(cn=a", "(cn=a))", `(cn=a\2)`, "(&(a=b)c)"} {
+		if err := ValidateFilter(f); err == nil {
+			t.Errorf("ValidateFilter(%q) expected an error", f)
+		}
+	}
+}
+
+// fakeDirectory serves binds and searches over a single connection per
+// request, as the client makes them.
+type fakeDirectory struct {
+	ln       net.Listener
+	password string
+	entries  []Entry
+	// searches records the base DN, scope, size limit, filter, and attributes
+	// of each search
+	searches chan fakeSearch
+	// resultCode of the SearchResultDone response
+	resultCode int64
+}
+
+type fakeSearch struct {
+	baseDN     string
+	scope      int64
+	sizeLimit  int64
+	filter     string
+	attributes []string
+}
+
+func newFakeDirectory(t *testing.T) *fakeDirectory {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	d := &fakeDirectory{ln: ln, password: "secret", searches: make(chan fakeSearch, 10)}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go d.serve(c)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return d
+}
+
+func (d *fakeDirectory) serve(c net.Conn) {
+	defer c.Close()
+	respond := func(id int64, op *ber.Packet) {
+		msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+		msg.AppendChild(op)
+		_, _ = c.Write(msg.Bytes())
+	}
+	result := func(tag ber.Tag, code int64, msg string) *ber.Packet {
+		p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+		p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""))
+		p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+		p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, msg, ""))
+		return p
+	}
+	for {
+		msg, err := ber.ReadPacket(c)
+		if err != nil {
+			return
+		}
+		id := msg.Children[0].Value.(int64)
+		op := msg.Children[1]
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			if op.Children[2].Data.String() != d.password {
+				respond(id, result(goldap.ApplicationBindResponse, 49, "invalid credentials"))
+				continue
+			}
+			respond(id, result(goldap.ApplicationBindResponse, 0, ""))
+		case goldap.ApplicationSearchRequest:
+			s := fakeSearch{
+				baseDN:    op.Children[0].Value.(string),
+				scope:     op.Children[1].Value.(int64),
+				sizeLimit: op.Children[3].Value.(int64),
+			}
+			s.filter, _ = goldap.DecompileFilter(op.Children[6])
+			for _, a := range op.Children[7].Children {
+				s.attributes = append(s.attributes, a.Value.(string))
+			}
+			d.searches <- s
+			for _, e := range d.entries {
+				entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "")
+				entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, ""))
+				attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+				for _, a := range e.Attributes {
+					attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+					attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, a.Name, ""))
+					vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+					for _, v := range a.Values {
+						vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, string(v), ""))
+					}
+					attr.AppendChild(vals)
+					attrs.AppendChild(attr)
+				}
+				entry.AppendChild(attrs)
+				respond(id, entry)
+			}
+			respond(id, result(goldap.ApplicationSearchResultDone, d.resultCode, "done"))
+		case goldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	d := newFakeDirectory(t)
+	d.entries = []Entry{
+		{DN: "uid=jdoe,ou=people,dc=example,dc=com", Attributes: []Attribute{
+			{Name: "cn", Values: [][]byte{[]byte("John Doe")}},
+			{Name: "memberOf", Values: [][]byte{[]byte("cn=a"), []byte("cn=b")}},
+			{Name: "objectGUID", Values: [][]byte{{0xff, 0x00, 0x10}}},
+		}},
+	}
+	c := &Client{
+		addr:     d.ln.Addr().String(),
+		bindDN:   "cn=toolbox,dc=example,dc=com",
+		password: "secret",
+		timeout:  5 * time.Second,
+	}
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("unable to ping: %s", err)
+	}
+
+	req := SearchRequest{
+		BaseDN:     "ou=people,dc=example,dc=com",
+		Scope:      ScopeOne,
+		Filter:     "(&(objectClass=person)(uid=jdoe))",
+		Attributes: []string{"cn", "memberOf", "objectGUID"},
+		SizeLimit:  10,
+	}
+	entries, err := c.Search(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unable to search: %s", err)
+	}
+	s := <-d.searches
+	if s.baseDN != req.BaseDN || s.scope != 1 || s.sizeLimit != 10 || s.filter != req.Filter || !cmp.Equal(s.attributes, req.Attributes) {
+		t.Errorf("unexpected search request: %+v", s)
+	}
+	var rows []map[string]any
+	for _, e := range entries {
+		rows = append(rows, e.Row())
+	}
+	want := []map[string]any{{
+		"dn":         "uid=jdoe,ou=people,dc=example,dc=com",
+		"cn":         "John Doe",
+		"memberOf":   []any{"cn=a", "cn=b"},
+		"objectGUID": "/wAQ",
+	}}
+	if diff := cmp.Diff(want, rows); diff != "" {
+		t.Errorf("unexpected rows (-want +got):\n%s", diff)
+	}
+
+	// entries found before the size limit are returned
+	d.resultCode = goldap.LDAPResultSizeLimitExceeded
+	entries, err = c.Search(context.Background(), req)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("unexpected result when exceeding the size limit: %v, %v", entries, err)
+	}
+	<-d.searches
+
+	d.resultCode = 32 // noSuchObject
+	if _, err := c.Search(context.Background(), req); err == nil || !goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	<-d.searches
+
+	c.password = "wrong"
+	err = c.Ping(context.Background())
+	if !goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) || !strings.HasPrefix(err.Error(), `unable to bind as "cn=toolbox,dc=example,dc=com"`) {
+		t.Errorf("unexpected error for wrong password: %v", err)
+	}
+}
+
+func TestSearchCanceled(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	defer ln.Close()
+	// a server that accepts connections but never responds
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	c := &Client{addr: ln.Addr().String(), timeout: time.Minute}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err = c.Search(ctx, SearchRequest{Filter: "(objectClass=*)"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the search to be canceled, got %v", err)
+	}
+}
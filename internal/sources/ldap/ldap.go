@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "ldap"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name         string `yaml:"name" validate:"required"`
+	Kind         string `yaml:"kind" validate:"required"`
+	Host         string `yaml:"host" validate:"required"`
+	Port         string `yaml:"port"`
+	UseTLS       bool   `yaml:"useTLS"`
+	StartTLS     bool   `yaml:"startTLS"`
+	BindDN       string `yaml:"bindDn"`
+	BindPassword string `yaml:"bindPassword"`
+	BaseDN       string `yaml:"baseDn"`
+	Timeout      string `yaml:"timeout"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	client, err := initLDAPClient(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name:   r.Name,
+		Kind:   SourceKind,
+		BaseDN: r.BaseDN,
+		Client: client,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name   string `yaml:"name"`
+	Kind   string `yaml:"kind"`
+	BaseDN string `yaml:"baseDn"`
+	Client *Client
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// LDAPClient returns the client used to search the directory.
+func (s *Source) LDAPClient() *Client {
+	return s.Client
+}
+
+// LDAPBaseDN returns the default base DN of searches.
+func (s *Source) LDAPBaseDN() string {
+	return s.BaseDN
+}
+
+func initLDAPClient(ctx context.Context, tracer trace.Tracer, r Config) (*Client, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	if r.UseTLS && r.StartTLS {
+		return nil, fmt.Errorf("useTLS and startTLS must not both be set")
+	}
+	if r.BindPassword != "" && r.BindDN == "" {
+		return nil, fmt.Errorf("bindPassword requires a bindDn")
+	}
+	port := r.Port
+	if port == "" {
+		port = "389"
+		if r.UseTLS {
+			port = "636"
+		}
+	}
+	timeout := 10 * time.Second
+	if r.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(r.Timeout)
+		if err != nil || timeout < time.Second {
+			return nil, fmt.Errorf("invalid timeout %q: must be a duration of at least 1s", r.Timeout)
+		}
+	}
+	var tlsConfig *tls.Config
+	if r.UseTLS || r.StartTLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12, ServerName: r.Host}
+	}
+	return &Client{
+		addr:      net.JoinHostPort(r.Host, port),
+		tlsConfig: tlsConfig,
+		startTLS:  r.StartTLS,
+		bindDN:    r.BindDN,
+		password:  r.BindPassword,
+		timeout:   timeout,
+	}, nil
+}
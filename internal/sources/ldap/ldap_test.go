@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/ldap"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlLDAP(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-ldap-instance:
+					kind: ldap
+					host: ldap.example.com
+			`,
+			want: server.SourceConfigs{
+				"my-ldap-instance": ldap.Config{
+					Name: "my-ldap-instance",
+					Kind: ldap.SourceKind,
+					Host: "ldap.example.com",
+				},
+			},
+		},
+		{
+			desc: "all fields",
+			in: `
+			sources:
+				my-ldap-instance:
+					kind: ldap
+					host: dc1.corp.example.com
+					port: 3269
+					useTLS: true
+					bindDn: CN=svc-toolbox,OU=Service Accounts,DC=corp,DC=example,DC=com
+					bindPassword: secret
+					baseDn: DC=corp,DC=example,DC=com
+					timeout: 5s
+			`,
+			want: server.SourceConfigs{
+				"my-ldap-instance": ldap.Config{
+					Name:         "my-ldap-instance",
+					Kind:         ldap.SourceKind,
+					Host:         "dc1.corp.example.com",
+					Port:         "3269",
+					UseTLS:       true,
+					BindDN:       "CN=svc-toolbox,OU=Service Accounts,DC=corp,DC=example,DC=com",
+					BindPassword: "secret",
+					BaseDN:       "DC=corp,DC=example,DC=com",
+					Timeout:      "5s",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Sources); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYaml(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing required field",
+			in: `
+			sources:
+				my-ldap-instance:
+					kind: ldap
+					baseDn: dc=example,dc=com
+			`,
+			err: "unable to parse source \"my-ldap-instance\" as \"ldap\": Key: 'Config.Host' Error:Field validation for 'Host' failed on the 'required' tag",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			errStr := err.Error()
+			if errStr != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", errStr, tc.err)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got, want := ldap.EscapeFilter(`*)(uid=*))(|(uid=*`), `\2a\29\28uid=\2a\29\29\28|\28uid=\2a`; got != want {
+		t.Errorf("EscapeFilter: got %q, want %q", got, want)
+	}
+	if got, want := ldap.EscapeFilter("a\\b\x00"), `a\5cb\00`; got != want {
+		t.Errorf("EscapeFilter: got %q, want %q", got, want)
+	}
+	if got, want := ldap.EscapeDN(` #Smith, "Jr"+1 `), `\ #Smith\, \"Jr\"\+1\ `; got != want {
+		t.Errorf("EscapeDN: got %q, want %q", got, want)
+	}
+	if got, want := ldap.EscapeDN("#1"), `\#1`; got != want {
+		t.Errorf("EscapeDN: got %q, want %q", got, want)
+	}
+}
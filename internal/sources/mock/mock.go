@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mock is a source that doesn't connect to anything. Its tools answer
+// with canned or generated responses, so tool configurations and agent flows
+// can be tested without provisioning a database.
+package mock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "mock"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Kind string `yaml:"kind" validate:"required"`
+	// Seed makes generated rows differ between sources. The same seed and
+	// parameters always generate the same rows.
+	Seed int64 `yaml:"seed"`
+	// Latency delays every response, to simulate a real database.
+	Latency string `yaml:"latency"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	var latency time.Duration
+	if r.Latency != "" {
+		var err error
+		latency, err = time.ParseDuration(r.Latency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency %q: %w", r.Latency, err)
+		}
+		if latency < 0 {
+			return nil, fmt.Errorf("latency must not be negative")
+		}
+	}
+
+	s := &Source{
+		Name:    r.Name,
+		Kind:    SourceKind,
+		Seed:    r.Seed,
+		Latency: latency,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`
+	Seed    int64
+	Latency time.Duration
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// MockSeed returns the seed of the rows generated by tools of the source.
+func (s *Source) MockSeed() int64 {
+	return s.Seed
+}
+
+// Wait blocks for the configured latency, or until ctx is done.
+func (s *Source) Wait(ctx context.Context) error {
+	if s.Latency == 0 {
+		return nil
+	}
+	timer := time.NewTimer(s.Latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
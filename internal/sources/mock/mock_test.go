@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mock"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlMock(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-mock:
+					kind: mock
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-mock": mock.Config{
+					Name: "my-mock",
+					Kind: mock.SourceKind,
+				},
+			},
+		},
+		{
+			desc: "seed and latency",
+			in: `
+			sources:
+				my-mock:
+					kind: mock
+					seed: 42
+					latency: 50ms
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-mock": mock.Config{
+					Name:    "my-mock",
+					Kind:    mock.SourceKind,
+					Seed:    42,
+					Latency: "50ms",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	s, err := mock.Config{Name: "my-mock", Kind: mock.SourceKind, Latency: "20ms"}.Initialize(context.Background(), tracer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	src := s.(*mock.Source)
+	if src.Latency != 20*time.Millisecond {
+		t.Fatalf("unexpected latency: %s", src.Latency)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := src.Wait(ctx); err != context.Canceled {
+		t.Fatalf("expected the wait to be cancelled, got %v", err)
+	}
+
+	_, err = mock.Config{Name: "my-mock", Kind: mock.SourceKind, Latency: "soon"}.Initialize(context.Background(), tracer)
+	if err == nil || !strings.Contains(err.Error(), "invalid latency") {
+		t.Fatalf("expected an invalid latency error, got %v", err)
+	}
+}
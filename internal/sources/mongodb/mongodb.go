@@ -87,6 +87,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close disconnects the source, once the operations in progress end.
+func (s *Source) Close() error {
+	return s.Client.Disconnect(context.Background())
+}
+
 func (s *Source) MongoClient() *mongo.Client {
 	return s.Client
 }
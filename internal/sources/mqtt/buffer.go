@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a message received on a subscribed topic.
+type Message struct {
+	Topic    string
+	Payload  []byte
+	QoS      byte
+	Retained bool
+	Received time.Time
+}
+
+// Row converts the message into a row. Payloads that are valid JSON are
+// decoded, so structured readings are returned as objects instead of strings.
+func (m Message) Row() map[string]any {
+	var value any = string(m.Payload)
+	if json.Valid(m.Payload) {
+		var v any
+		if err := json.Unmarshal(m.Payload, &v); err == nil {
+			value = v
+		}
+	}
+	return map[string]any{
+		"topic":    m.Topic,
+		"value":    value,
+		"qos":      m.QoS,
+		"retained": m.Retained,
+		"received": m.Received.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// Buffer holds the most recent messages of each topic. It keeps at most
+// perTopic messages for at most maxTopics topics, and drops messages older
+// than the retention period.
+type Buffer struct {
+	mu        sync.RWMutex
+	topics    map[string][]Message
+	perTopic  int
+	maxTopics int
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewBuffer returns an empty Buffer with the given limits.
+func NewBuffer(perTopic, maxTopics int, retention time.Duration) *Buffer {
+	return &Buffer{
+		topics:    make(map[string][]Message),
+		perTopic:  perTopic,
+		maxTopics: maxTopics,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Add stores a message, evicting the oldest messages and topics that no longer
+// fit in the buffer.
+func (b *Buffer) Add(m Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	msgs, ok := b.topics[m.Topic]
+	if !ok && len(b.topics) >= b.maxTopics {
+		b.evictTopic()
+	}
+	msgs = append(b.expire(msgs), m)
+	if len(msgs) > b.perTopic {
+		msgs = append(msgs[:0], msgs[len(msgs)-b.perTopic:]...)
+	}
+	b.topics[m.Topic] = msgs
+}
+
+// expire drops the messages that are past the retention period.
+func (b *Buffer) expire(msgs []Message) []Message {
+	cutoff := b.now().Add(-b.retention)
+	i := 0
+	for i < len(msgs) && msgs[i].Received.Before(cutoff) {
+		i++
+	}
+	return msgs[i:]
+}
+
+// evictTopic makes room for a new topic by dropping the topic whose last
+// message is the oldest.
+func (b *Buffer) evictTopic() {
+	var oldest string
+	var oldestTime time.Time
+	for topic, msgs := range b.topics {
+		last := msgs[len(msgs)-1].Received
+		if oldest == "" || last.Before(oldestTime) {
+			oldest, oldestTime = topic, last
+		}
+	}
+	delete(b.topics, oldest)
+}
+
+// Latest returns the last message of each topic matching all of the filters,
+// sorted by topic.
+func (b *Buffer) Latest(filters ...string) []Message {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cutoff := b.now().Add(-b.retention)
+	var out []Message
+	for topic, msgs := range b.topics {
+		last := msgs[len(msgs)-1]
+		if !matchAll(filters, topic) || last.Received.Before(cutoff) {
+			continue
+		}
+		out = append(out, last)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Topic < out[j].Topic })
+	return out
+}
+
+// Recent returns the last limit messages received since the given time on
+// topics matching all of the filters, oldest first.
+func (b *Buffer) Recent(since time.Time, limit int, filters ...string) []Message {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if cutoff := b.now().Add(-b.retention); since.Before(cutoff) {
+		since = cutoff
+	}
+	var out []Message
+	for topic, msgs := range b.topics {
+		if !matchAll(filters, topic) {
+			continue
+		}
+		for _, m := range msgs {
+			if !m.Received.Before(since) {
+				out = append(out, m)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].Received.Equal(out[j].Received) {
+			return out[i].Received.Before(out[j].Received)
+		}
+		return out[i].Topic < out[j].Topic
+	})
+	if len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
+
+func matchAll(filters []string, topic string) bool {
+	for _, f := range filters {
+		if !MatchTopic(f, topic) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateTopicFilter reports whether filter is a valid MQTT topic filter. The
+// single-level wildcard "+" must occupy a whole level, and the multi-level
+// wildcard "#" must be the last level.
+func ValidateTopicFilter(filter string) error {
+	if filter == "" {
+		return fmt.Errorf("topic filter must not be empty")
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		switch {
+		case level == "#" && i != len(levels)-1:
+			return fmt.Errorf("invalid topic filter %q: '#' must be the last level", filter)
+		case level != "+" && level != "#" && strings.ContainsAny(level, "+#"):
+			return fmt.Errorf("invalid topic filter %q: wildcards must occupy a whole level", filter)
+		}
+	}
+	return nil
+}
+
+// MatchTopic reports whether topic matches the topic filter. As in MQTT,
+// wildcards at the first level don't match topics starting with "$".
+func MatchTopic(filter, topic string) bool {
+	if strings.HasPrefix(topic, "$") && (strings.HasPrefix(filter, "+") || strings.HasPrefix(filter, "#")) {
+		return false
+	}
+	fLevels := strings.Split(filter, "/")
+	tLevels := strings.Split(topic, "/")
+	for i, f := range fLevels {
+		if f == "#" {
+			return true
+		}
+		if i >= len(tLevels) {
+			return false
+		}
+		if f != "+" && f != tLevels[i] {
+			return false
+		}
+	}
+	return len(fLevels) == len(tLevels)
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/googleapis/genai-toolbox/internal/log"
+)
+
+const (
+	maxReconnectDelay = 30 * time.Second
+	// disconnectQuiesce is how long, in milliseconds, Close waits for
+	// in-flight work before sending DISCONNECT.
+	disconnectQuiesce = 250
+)
+
+// client maintains a subscription to the source's topics, storing every
+// message it receives in a Buffer. It reconnects and subscribes again whenever
+// the connection is lost, until it is closed.
+type client struct {
+	addr      string
+	tlsConfig *tls.Config
+	clientID  string
+	username  string
+	password  string
+	keepAlive time.Duration
+	topics    []string
+	qos       byte
+	buffer    *Buffer
+
+	conn paho.Client
+}
+
+// connect opens a session with the broker and subscribes to the topics. ctx
+// only bounds the first connection; the session lasts until close is called.
+func (c *client) connect(ctx context.Context, logger log.Logger, name string) error {
+	scheme := "tcp"
+	if c.tlsConfig != nil {
+		scheme = "tls"
+	}
+	// the session is clean, so the subscription is restored on every
+	// connection. The first outcome is reported to connect, later ones are
+	// logged.
+	subscribed := make(chan error, 1)
+	var first sync.Once
+	opts := paho.NewClientOptions().
+		AddBroker(scheme + "://" + c.addr).
+		SetClientID(c.clientID).
+		SetUsername(c.username).
+		SetPassword(c.password).
+		SetTLSConfig(c.tlsConfig).
+		SetCleanSession(true).
+		SetKeepAlive(c.keepAlive).
+		SetConnectTimeout(c.keepAlive).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(maxReconnectDelay).
+		// retained messages may arrive before the SUBACK
+		SetDefaultPublishHandler(c.handleMessage).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			logger.WarnContext(context.Background(), fmt.Sprintf("lost connection to MQTT broker of source %q: %s", name, err))
+		}).
+		SetOnConnectHandler(func(pc paho.Client) {
+			err := c.subscribe(pc)
+			reported := false
+			first.Do(func() {
+				subscribed <- err
+				reported = true
+			})
+			switch {
+			case reported:
+			case err != nil:
+				logger.WarnContext(context.Background(), fmt.Sprintf("unable to resubscribe to MQTT broker of source %q: %s", name, err))
+			default:
+				logger.InfoContext(context.Background(), fmt.Sprintf("reconnected to MQTT broker of source %q", name))
+			}
+		})
+	c.conn = paho.NewClient(opts)
+
+	if err := wait(ctx, c.conn.Connect()); err != nil {
+		c.close()
+		return err
+	}
+	select {
+	case err := <-subscribed:
+		if err != nil {
+			c.close()
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		c.close()
+		return ctx.Err()
+	}
+}
+
+// subscribe subscribes to the topics and fails if the broker rejects any of
+// them.
+func (c *client) subscribe(pc paho.Client) error {
+	filters := make(map[string]byte, len(c.topics))
+	for _, topic := range c.topics {
+		filters[topic] = c.qos
+	}
+	token := pc.SubscribeMultiple(filters, c.handleMessage)
+	if err := wait(context.Background(), token); err != nil {
+		return fmt.Errorf("unable to subscribe: %w", err)
+	}
+	for topic, code := range token.(*paho.SubscribeToken).Result() {
+		if code == 0x80 {
+			return fmt.Errorf("broker rejected subscription to %q", topic)
+		}
+	}
+	return nil
+}
+
+// handleMessage stores a received message. paho acknowledges QoS 1 messages
+// once it returns.
+func (c *client) handleMessage(_ paho.Client, m paho.Message) {
+	c.buffer.Add(Message{
+		Topic:    m.Topic(),
+		Payload:  m.Payload(),
+		QoS:      m.Qos(),
+		Retained: m.Retained(),
+		Received: c.buffer.now(),
+	})
+}
+
+// close sends DISCONNECT, if connected, and stops reconnecting.
+func (c *client) close() {
+	c.conn.Disconnect(disconnectQuiesce)
+}
+
+func wait(ctx context.Context, token paho.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
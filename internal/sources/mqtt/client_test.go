@@ -0,0 +1,321 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestMatchTopic(t *testing.T) {
+	tcs := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"devices/42/temp", "devices/42/temp", true},
+		{"devices/+/temp", "devices/42/temp", true},
+		{"devices/+/temp", "devices/42/humidity", false},
+		{"devices/+", "devices/42/temp", false},
+		{"devices/#", "devices/42/temp", true},
+		{"devices/#", "devices", true},
+		{"#", "devices/42", true},
+		{"#", "$SYS/uptime", false},
+		{"+/uptime", "$SYS/uptime", false},
+		{"$SYS/#", "$SYS/uptime", true},
+	}
+	for _, tc := range tcs {
+		if got := MatchTopic(tc.filter, tc.topic); got != tc.want {
+			t.Errorf("MatchTopic(%q, %q) = %t, want %t", tc.filter, tc.topic, got, tc.want)
+		}
+	}
+}
+
+func TestValidateTopicFilter(t *testing.T) {
+	for _, f := range []string{"a/b", "a/+/c", "a/#", "#", "+"} {
+		if err := ValidateTopicFilter(f); err != nil {
+			t.Errorf("ValidateTopicFilter(%q) unexpected error: %s", f, err)
+		}
+	}
+	for _, f := range []string{"", "a/#/c", "a/b+", "a#"} {
+		if err := ValidateTopicFilter(f); err == nil {
+			t.Errorf("ValidateTopicFilter(%q) expected an error", f)
+		}
+	}
+}
+
+func TestBufferRetention(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := NewBuffer(2, 2, time.Minute)
+	b.now = func() time.Time { return now }
+	add := func(topic, payload string, age time.Duration) {
+		b.Add(Message{Topic: topic, Payload: []byte(payload), Received: now.Add(-age)})
+	}
+	payloads := func(msgs []Message) []string {
+		var out []string
+		for _, m := range msgs {
+			out = append(out, m.Topic+"="+string(m.Payload))
+		}
+		return out
+	}
+
+	add("a", "1", 50*time.Second)
+	add("a", "2", 40*time.Second)
+	add("a", "3", 30*time.Second)
+	add("b", "1", 20*time.Second)
+	if diff := cmp.Diff([]string{"a=2", "a=3", "b=1"}, payloads(b.Recent(time.Time{}, 10, "#"))); diff != "" {
+		t.Errorf("messages per topic not bounded (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"a=3", "b=1"}, payloads(b.Recent(time.Time{}, 2, "#"))); diff != "" {
+		t.Errorf("limit not applied (-want +got):\n%s", diff)
+	}
+
+	// a third topic evicts "a", whose last message is the oldest
+	add("c", "1", 10*time.Second)
+	if diff := cmp.Diff([]string{"b=1", "c=1"}, payloads(b.Latest("#"))); diff != "" {
+		t.Errorf("topics not bounded (-want +got):\n%s", diff)
+	}
+
+	now = now.Add(45 * time.Second)
+	if diff := cmp.Diff([]string{"c=1"}, payloads(b.Latest("#"))); diff != "" {
+		t.Errorf("expired messages returned (-want +got):\n%s", diff)
+	}
+	if got := b.Recent(time.Time{}, 10, "b"); len(got) != 0 {
+		t.Errorf("expired messages returned: %v", payloads(got))
+	}
+}
+
+func TestMessageRow(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	got := Message{Topic: "devices/42/state", Payload: []byte(`{"on":true}`), QoS: 1, Retained: true, Received: ts}.Row()
+	want := map[string]any{
+		"topic":    "devices/42/state",
+		"value":    map[string]any{"on": true},
+		"qos":      byte(1),
+		"retained": true,
+		"received": "2025-01-02T03:04:05Z",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("incorrect row (-want +got):\n%s", diff)
+	}
+}
+
+// fakeBroker accepts one session at a time, checks its CONNECT and SUBSCRIBE,
+// and then publishes the given messages.
+type fakeBroker struct {
+	t           *testing.T
+	ln          net.Listener
+	sessions    chan net.Conn
+	pubacks     chan uint16
+	disconnects chan struct{}
+
+	writeMu sync.Mutex
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+	b := &fakeBroker{
+		t:           t,
+		ln:          ln,
+		sessions:    make(chan net.Conn, 1),
+		pubacks:     make(chan uint16, 10),
+		disconnects: make(chan struct{}, 1),
+	}
+	go b.serve()
+	t.Cleanup(func() { ln.Close() })
+	return b
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		p, err := packets.ReadPacket(conn)
+		connect, ok := p.(*packets.ConnectPacket)
+		if err != nil || !ok {
+			b.t.Errorf("expected CONNECT, got %v: %v", p, err)
+			conn.Close()
+			continue
+		}
+		if connect.ProtocolName != "MQTT" || connect.ProtocolVersion != 4 || !connect.CleanSession ||
+			connect.Username != "user" || string(connect.Password) != "pass" {
+			b.t.Errorf("unexpected CONNECT: %v", connect)
+		}
+		b.write(conn, packets.NewControlPacket(packets.Connack))
+
+		p, err = packets.ReadPacket(conn)
+		subscribe, ok := p.(*packets.SubscribePacket)
+		if err != nil || !ok {
+			b.t.Errorf("expected SUBSCRIBE, got %v: %v", p, err)
+			conn.Close()
+			continue
+		}
+		if diff := cmp.Diff([]string{"devices/#"}, subscribe.Topics); diff != "" || subscribe.Qoss[0] != 1 {
+			b.t.Errorf("unexpected subscription: %v", subscribe)
+		}
+		// send a retained message before the SUBACK, as some brokers do
+		b.publish(conn, "devices/1/state", `{"on":true}`, 1, true, 7)
+		suback := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+		suback.MessageID = subscribe.MessageID
+		suback.ReturnCodes = []byte{1}
+		b.write(conn, suback)
+
+		go func() {
+			for {
+				p, err := packets.ReadPacket(conn)
+				if err != nil {
+					return
+				}
+				switch p := p.(type) {
+				case *packets.PubackPacket:
+					b.pubacks <- p.MessageID
+				case *packets.PingreqPacket:
+					b.write(conn, packets.NewControlPacket(packets.Pingresp))
+				case *packets.DisconnectPacket:
+					b.disconnects <- struct{}{}
+				}
+			}
+		}()
+		b.sessions <- conn
+	}
+}
+
+func (b *fakeBroker) write(conn net.Conn, p packets.ControlPacket) {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	_ = p.Write(conn)
+}
+
+func (b *fakeBroker) publish(conn net.Conn, topic, payload string, qos byte, retain bool, id uint16) {
+	p := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	p.Qos = qos
+	p.Retain = retain
+	p.TopicName = topic
+	p.MessageID = id
+	p.Payload = []byte(payload)
+	b.write(conn, p)
+}
+
+func waitFor(t *testing.T, desc string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSourceSubscribes(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	broker := newFakeBroker(t)
+	cfg := Config{
+		Name:      "my-mqtt",
+		Kind:      SourceKind,
+		Broker:    broker.ln.Addr().String(),
+		Username:  "user",
+		Password:  "pass",
+		Topics:    []string{"devices/#"},
+		QoS:       1,
+		KeepAlive: "2s",
+	}
+	src, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	buf := src.(*Source).MQTTBuffer()
+
+	conn := <-broker.sessions
+	if id := <-broker.pubacks; id != 7 {
+		t.Errorf("unexpected PUBACK for packet %d", id)
+	}
+	broker.publish(conn, "devices/2/state", "off", 0, false, 0)
+	waitFor(t, "messages", func() bool { return len(buf.Latest("#")) == 2 })
+	latest := buf.Latest("devices/+/state")
+	if latest[0].Topic != "devices/1/state" || !latest[0].Retained || latest[0].QoS != 1 {
+		t.Errorf("unexpected retained message: %+v", latest[0])
+	}
+	if latest[1].Topic != "devices/2/state" || string(latest[1].Payload) != "off" {
+		t.Errorf("unexpected message: %+v", latest[1])
+	}
+
+	// the client reconnects when the broker drops the connection
+	conn.Close()
+	conn = <-broker.sessions
+	broker.publish(conn, "devices/3/state", "on", 0, false, 0)
+	waitFor(t, "messages after reconnecting", func() bool { return len(buf.Latest("devices/3/#")) == 1 })
+
+	// closing the source disconnects it for good
+	if err := src.(*Source).Close(); err != nil {
+		t.Fatalf("unable to close source: %s", err)
+	}
+	select {
+	case <-broker.disconnects:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for DISCONNECT")
+	}
+	select {
+	case <-broker.sessions:
+		t.Errorf("client reconnected after being closed")
+	case <-time.After(2 * time.Second):
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	tcs := []struct {
+		desc string
+		cfg  Config
+	}{
+		{desc: "invalid topic", cfg: Config{Broker: "b", Topics: []string{"a/#/b"}}},
+		{desc: "invalid qos", cfg: Config{Broker: "b", Topics: []string{"a"}, QoS: 2}},
+		{desc: "negative limit", cfg: Config{Broker: "b", Topics: []string{"a"}, MaxTopics: -1}},
+		{desc: "invalid retention", cfg: Config{Broker: "b", Topics: []string{"a"}, Retention: "forever"}},
+		{desc: "keep alive too short", cfg: Config{Broker: "b", Topics: []string{"a"}, KeepAlive: "10ms"}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := tc.cfg.newClient(); err == nil {
+				t.Fatalf("expected validation to fail")
+			}
+		})
+	}
+
+	c, err := Config{Broker: "broker.example.com", UseTLS: true, Topics: []string{"a"}}.newClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c.addr != "broker.example.com:8883" || c.tlsConfig.ServerName != "broker.example.com" {
+		t.Errorf("unexpected address %q with server name %q", c.addr, c.tlsConfig.ServerName)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "mqtt"
+
+const (
+	defaultMessagesPerTopic = 100
+	defaultMaxTopics        = 10000
+	defaultRetention        = time.Hour
+	defaultKeepAlive        = 60 * time.Second
+)
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name             string   `yaml:"name" validate:"required"`
+	Kind             string   `yaml:"kind" validate:"required"`
+	Broker           string   `yaml:"broker" validate:"required"`
+	ClientID         string   `yaml:"clientId"`
+	Username         string   `yaml:"username"`
+	Password         string   `yaml:"password"`
+	UseTLS           bool     `yaml:"useTLS"`
+	Topics           []string `yaml:"topics" validate:"required"`
+	QoS              int      `yaml:"qos"`
+	MessagesPerTopic int      `yaml:"messagesPerTopic"`
+	MaxTopics        int      `yaml:"maxTopics"`
+	Retention        string   `yaml:"retention"`
+	KeepAlive        string   `yaml:"keepAlive"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
+	}
+
+	c, err := r.newClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := initMQTTConnection(ctx, tracer, logger, r.Name, c); err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name:   r.Name,
+		Kind:   SourceKind,
+		Topics: r.Topics,
+		Buffer: c.buffer,
+		client: c,
+	}
+	return s, nil
+}
+
+// newClient validates the config and returns a client for it.
+func (r Config) newClient() (*client, error) {
+	for _, topic := range r.Topics {
+		if err := ValidateTopicFilter(topic); err != nil {
+			return nil, err
+		}
+	}
+	if r.QoS != 0 && r.QoS != 1 {
+		return nil, fmt.Errorf("invalid qos %d: must be 0 or 1", r.QoS)
+	}
+	perTopic := r.MessagesPerTopic
+	if perTopic == 0 {
+		perTopic = defaultMessagesPerTopic
+	}
+	maxTopics := r.MaxTopics
+	if maxTopics == 0 {
+		maxTopics = defaultMaxTopics
+	}
+	if perTopic < 0 || maxTopics < 0 {
+		return nil, fmt.Errorf("messagesPerTopic and maxTopics must not be negative")
+	}
+	retention, err := parseDuration("retention", r.Retention, defaultRetention)
+	if err != nil {
+		return nil, err
+	}
+	keepAlive, err := parseDuration("keepAlive", r.KeepAlive, defaultKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+	if keepAlive < time.Second || keepAlive > 65535*time.Second {
+		return nil, fmt.Errorf("invalid keepAlive %q: must be between 1s and 18h12m15s", r.KeepAlive)
+	}
+
+	addr := r.Broker
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		port := "1883"
+		if r.UseTLS {
+			port = "8883"
+		}
+		addr = net.JoinHostPort(addr, port)
+	}
+	var tlsConfig *tls.Config
+	if r.UseTLS {
+		host, _, _ := net.SplitHostPort(addr)
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12, ServerName: host}
+	}
+	clientID := r.ClientID
+	if clientID == "" {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		clientID = "toolbox-" + hex.EncodeToString(b)
+	}
+
+	return &client{
+		addr:      addr,
+		tlsConfig: tlsConfig,
+		clientID:  clientID,
+		username:  r.Username,
+		password:  r.Password,
+		keepAlive: keepAlive,
+		topics:    r.Topics,
+		qos:       byte(r.QoS),
+		buffer:    NewBuffer(perTopic, maxTopics, retention),
+	}, nil
+}
+
+func parseDuration(field, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration", field, value)
+	}
+	return d, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name   string   `yaml:"name"`
+	Kind   string   `yaml:"kind"`
+	Topics []string `yaml:"topics"`
+	Buffer *Buffer
+	client *client
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// MQTTBuffer returns the buffer of recent messages received on the source's
+// topics.
+func (s *Source) MQTTBuffer() *Buffer {
+	return s.Buffer
+}
+
+// Close disconnects from the broker. The buffer keeps the messages received
+// so far.
+func (s *Source) Close() error {
+	s.client.close()
+	return nil
+}
+
+func initMQTTConnection(ctx context.Context, tracer trace.Tracer, logger log.Logger, name string, c *client) error {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
+	defer span.End()
+
+	return c.connect(ctx, logger, name)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/mqtt"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMQTT(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-mqtt-instance:
+					kind: mqtt
+					broker: localhost:1883
+					topics:
+						- devices/+/state
+			`,
+			want: server.SourceConfigs{
+				"my-mqtt-instance": mqtt.Config{
+					Name:   "my-mqtt-instance",
+					Kind:   mqtt.SourceKind,
+					Broker: "localhost:1883",
+					Topics: []string{"devices/+/state"},
+				},
+			},
+		},
+		{
+			desc: "all fields",
+			in: `
+			sources:
+				my-mqtt-instance:
+					kind: mqtt
+					broker: broker.example.com
+					clientId: toolbox
+					username: user
+					password: pass
+					useTLS: true
+					topics:
+						- devices/#
+						- alerts
+					qos: 1
+					messagesPerTopic: 50
+					maxTopics: 500
+					retention: 30m
+					keepAlive: 30s
+			`,
+			want: server.SourceConfigs{
+				"my-mqtt-instance": mqtt.Config{
+					Name:             "my-mqtt-instance",
+					Kind:             mqtt.SourceKind,
+					Broker:           "broker.example.com",
+					ClientID:         "toolbox",
+					Username:         "user",
+					Password:         "pass",
+					UseTLS:           true,
+					Topics:           []string{"devices/#", "alerts"},
+					QoS:              1,
+					MessagesPerTopic: 50,
+					MaxTopics:        500,
+					Retention:        "30m",
+					KeepAlive:        "30s",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Sources); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYaml(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		err  string
+	}{
+		{
+			desc: "missing required field",
+			in: `
+			sources:
+				my-mqtt-instance:
+					kind: mqtt
+					broker: localhost:1883
+			`,
+			err: "unable to parse source \"my-mqtt-instance\" as \"mqtt\": Key: 'Config.Topics' Error:Field validation for 'Topics' failed on the 'required' tag",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+			errStr := err.Error()
+			if errStr != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", errStr, tc.err)
+			}
+		})
+	}
+}
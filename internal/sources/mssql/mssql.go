@@ -102,6 +102,11 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Db.Close()
+}
+
 func initMssqlConnection(
 	ctx context.Context,
 	tracer trace.Tracer,
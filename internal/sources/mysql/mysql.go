@@ -54,6 +54,9 @@ type Config struct {
 	Password     string `yaml:"password" validate:"required"`
 	Database     string `yaml:"database" validate:"required"`
 	QueryTimeout string `yaml:"queryTimeout"`
+	// MultiStatements allows a query to hold several statements separated by
+	// semicolons, each of which may return a result set.
+	MultiStatements bool `yaml:"multiStatements"`
 }
 
 func (r Config) SourceConfigKind() string {
@@ -61,7 +64,7 @@ func (r Config) SourceConfigKind() string {
 }
 
 func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
-	pool, err := initMySQLConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.QueryTimeout)
+	pool, err := initMySQLConnectionPool(ctx, tracer, r.Name, r.Host, r.Port, r.User, r.Password, r.Database, r.QueryTimeout, r.MultiStatements)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create pool: %w", err)
 	}
@@ -75,6 +78,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		Name: r.Name,
 		Kind: SourceKind,
 		Pool: pool,
+		// kept for tools that open connections of their own
+		Config: r,
 	}
 	return s, nil
 }
@@ -85,6 +90,8 @@ type Source struct {
 	Name string `yaml:"name"`
 	Kind string `yaml:"kind"`
 	Pool *sql.DB
+	// Config is the configuration the source connects with.
+	Config Config `yaml:"-"`
 }
 
 func (s *Source) SourceKind() string {
@@ -95,7 +102,12 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
-func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, queryTimeout string) (*sql.DB, error) {
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Pool.Close()
+}
+
+func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname, queryTimeout string, multiStatements bool) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
 	defer span.End()
@@ -111,6 +123,9 @@ func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, hos
 		}
 		dsn += "&readTimeout=" + timeout.String()
 	}
+	if multiStatements {
+		dsn += "&multiStatements=true"
+	}
 
 	// Interact with the driver directly as you normally would
 	pool, err := sql.Open("mysql", dsn)
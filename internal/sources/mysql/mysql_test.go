@@ -80,6 +80,32 @@ func TestParseFromYamlCloudSQLMySQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with multi statements",
+			in: `
+			sources:
+				my-mysql-instance:
+					kind: mysql
+					host: 0.0.0.0
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					multiStatements: true
+			`,
+			want: server.SourceConfigs{
+				"my-mysql-instance": mysql.Config{
+					Name:            "my-mysql-instance",
+					Kind:            mysql.SourceKind,
+					Host:            "0.0.0.0",
+					Port:            "my-port",
+					Database:        "my_db",
+					User:            "my_user",
+					Password:        "my_pass",
+					MultiStatements: true,
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -92,6 +92,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Driver.Close(context.Background())
+}
+
 func (s *Source) Neo4jDriver() neo4j.DriverWithContext {
 	return s.Driver
 }
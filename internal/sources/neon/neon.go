@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neon
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "neon"
+
+const poolerSuffix = "-pooler"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, Port: "5432"} // Default Port
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name     string `yaml:"name" validate:"required"`
+	Kind     string `yaml:"kind" validate:"required"`
+	Host     string `yaml:"host" validate:"required"`
+	Port     string `yaml:"port" validate:"required"`
+	User     string `yaml:"user" validate:"required"`
+	Password string `yaml:"password" validate:"required"`
+	Database string `yaml:"database" validate:"required"`
+	// Pooled connects through the endpoint's connection pooler.
+	Pooled bool `yaml:"pooled"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+// ConnString returns the connection string of the endpoint. Neon routes
+// connections by the endpoint ID, which is sent with SNI and, for clients
+// whose TLS library doesn't support SNI, in the `endpoint` option.
+func (r Config) ConnString() (string, error) {
+	label, domain, ok := strings.Cut(r.Host, ".")
+	if !ok || !strings.HasPrefix(label, "ep-") {
+		return "", fmt.Errorf("host %q is not a Neon endpoint host, e.g. \"ep-cool-darkness-123456.us-east-2.aws.neon.tech\"", r.Host)
+	}
+	endpointID := strings.TrimSuffix(label, poolerSuffix)
+	host := endpointID + "." + domain
+	q := url.Values{}
+	q.Set("sslmode", "require")
+	q.Set("options", "endpoint="+endpointID)
+	if r.Pooled {
+		host = endpointID + poolerSuffix + "." + domain
+		// the pooler runs PgBouncer in transaction mode, which doesn't keep the
+		// prepared statements of a client between transactions
+		q.Set("default_query_exec_mode", "simple_protocol")
+	}
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(r.User, r.Password),
+		Host:     fmt.Sprintf("%s:%s", host, r.Port),
+		Path:     r.Database,
+		RawQuery: q.Encode(),
+	}
+	return u.String(), nil
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	pool, err := initNeonConnectionPool(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create pool: %w", err)
+	}
+
+	err = pool.Ping(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name: r.Name,
+		Kind: SourceKind,
+		Pool: pool,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+	Pool *pgxpool.Pool
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+func (s *Source) PostgresPool() *pgxpool.Pool {
+	return s.Pool
+}
+
+// Close closes the connections of the source, once the ones in use are
+// released.
+func (s *Source) Close() error {
+	s.Pool.Close()
+	return nil
+}
+
+func initNeonConnectionPool(ctx context.Context, tracer trace.Tracer, r Config) (*pgxpool.Pool, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	connString, err := r.ConnString()
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	return pool, nil
+}
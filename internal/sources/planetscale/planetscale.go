@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planetscale
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "planetscale"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name         string `yaml:"name" validate:"required"`
+	Kind         string `yaml:"kind" validate:"required"`
+	Host         string `yaml:"host" validate:"required"`
+	User         string `yaml:"user" validate:"required"`
+	Password     string `yaml:"password" validate:"required"`
+	Database     string `yaml:"database" validate:"required"`
+	QueryTimeout string `yaml:"queryTimeout"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+// DSN returns the data source name of the database. PlanetScale only accepts
+// TLS connections, which are verified against the system's root certificates.
+func (r Config) DSN() (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = r.User
+	cfg.Passwd = r.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(r.Host, "3306")
+	cfg.DBName = r.Database
+	cfg.TLSConfig = "true"
+	cfg.ParseTime = true
+	// interpolating parameters saves the round trips of preparing every
+	// statement through the edge proxy
+	cfg.InterpolateParams = true
+	if r.QueryTimeout != "" {
+		timeout, err := time.ParseDuration(r.QueryTimeout)
+		if err != nil {
+			return "", fmt.Errorf("invalid queryTimeout %q: %w", r.QueryTimeout, err)
+		}
+		cfg.ReadTimeout = timeout
+	}
+	return cfg.FormatDSN(), nil
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	pool, err := initPlanetScaleConnectionPool(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create pool: %w", err)
+	}
+
+	err = pool.PingContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name: r.Name,
+		Kind: SourceKind,
+		Pool: pool,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+	Pool *sql.DB
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+func (s *Source) MySQLPool() *sql.DB {
+	return s.Pool
+}
+
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Pool.Close()
+}
+
+func initPlanetScaleConnectionPool(ctx context.Context, tracer trace.Tracer, r Config) (*sql.DB, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	dsn, err := r.DSN()
+	if err != nil {
+		return nil, err
+	}
+	pool, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open: %w", err)
+	}
+	return pool, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planetscale_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/planetscale"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlPlanetScale(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-planetscale-db:
+					kind: planetscale
+					host: aws.connect.psdb.cloud
+					database: my_db
+					user: my_user
+					password: my_pass
+					queryTimeout: 30s
+			`,
+			want: server.SourceConfigs{
+				"my-planetscale-db": planetscale.Config{
+					Name:         "my-planetscale-db",
+					Kind:         planetscale.SourceKind,
+					Host:         "aws.connect.psdb.cloud",
+					Database:     "my_db",
+					User:         "my_user",
+					Password:     "my_pass",
+					QueryTimeout: "30s",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestDSN(t *testing.T) {
+	cfg := planetscale.Config{Host: "aws.connect.psdb.cloud", User: "my_user", Password: "p@ss", Database: "my_db", QueryTimeout: "30s"}
+	got, err := cfg.DSN()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "my_user:p@ss@tcp(aws.connect.psdb.cloud:3306)/my_db?interpolateParams=true&parseTime=true&readTimeout=30s&tls=true"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	cfg.QueryTimeout = "soon"
+	if _, err := cfg.DSN(); err == nil {
+		t.Fatalf("expected error for invalid queryTimeout")
+	}
+}
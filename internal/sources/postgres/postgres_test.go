@@ -19,6 +19,9 @@ import (
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
+	"github.com/googleapis/genai-toolbox/internal/privacy"
+	"github.com/googleapis/genai-toolbox/internal/querycost"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
@@ -54,6 +57,165 @@ func TestParseFromYamlPostgres(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with application name and query tags",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					applicationName: genai-toolbox
+					queryTags:
+						team: finance
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:            "my-pg-instance",
+					Kind:            postgres.SourceKind,
+					Host:            "my-host",
+					Port:            "my-port",
+					Database:        "my_db",
+					User:            "my_user",
+					Password:        "my_pass",
+					ApplicationName: "genai-toolbox",
+					QueryTags:       map[string]string{"team": "finance"},
+				},
+			},
+		},
+		{
+			desc: "with dictionary",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					dictionary:
+						orders:
+							description: One row per order.
+							synonyms: [purchases]
+							columns:
+								total:
+									description: Order total including tax.
+									unit: USD
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:     "my-pg-instance",
+					Kind:     postgres.SourceKind,
+					Host:     "my-host",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					Dictionary: dictionary.Dictionary{
+						"orders": {
+							Description: "One row per order.",
+							Synonyms:    []string{"purchases"},
+							Columns: map[string]dictionary.Column{
+								"total": {Description: "Order total including tax.", Unit: "USD"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "with aggregationOnly",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					aggregationOnly:
+						minGroupSize: 20
+						aggregates: [count, sum]
+						noiseScale: 0.5
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:     "my-pg-instance",
+					Kind:     postgres.SourceKind,
+					Host:     "my-host",
+					Port:     "my-port",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					AggregationOnly: &privacy.AggregationConfig{
+						MinGroupSize: 20,
+						Aggregates:   []string{"count", "sum"},
+						NoiseScale:   0.5,
+					},
+				},
+			},
+		},
+		{
+			desc: "with costLimits",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					costLimits:
+						maxCost: 100000
+						maxRows: 5000
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:       "my-pg-instance",
+					Kind:       postgres.SourceKind,
+					Host:       "my-host",
+					Port:       "my-port",
+					Database:   "my_db",
+					User:       "my_user",
+					Password:   "my_pass",
+					CostLimits: &querycost.Limits{MaxCost: 100000, MaxRows: 5000},
+				},
+			},
+		},
+		{
+			desc: "with query logging",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: postgres
+					host: my-host
+					port: my-port
+					database: my_db
+					user: my_user
+					password: my_pass
+					logQueries: true
+					slowQueryThreshold: 500ms
+			`,
+			want: server.SourceConfigs{
+				"my-pg-instance": postgres.Config{
+					Name:               "my-pg-instance",
+					Kind:               postgres.SourceKind,
+					Host:               "my-host",
+					Port:               "my-port",
+					Database:           "my_db",
+					User:               "my_user",
+					Password:           "my_pass",
+					LogQueries:         true,
+					SlowQueryThreshold: "500ms",
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -61,6 +61,7 @@ func (r Config) SourceConfigKind() string {
 // RedisClient is an interface for `redis.Client` and `redis.ClusterClient
 type RedisClient interface {
 	Do(context.Context, ...any) *redis.Cmd
+	Close() error
 }
 
 var _ RedisClient = (*redis.Client)(nil)
@@ -147,6 +148,11 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	return s.Client.Close()
+}
+
 func (s *Source) RedisClient() RedisClient {
 	return s.Client
 }
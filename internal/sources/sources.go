@@ -64,6 +64,18 @@ type Source interface {
 	SourceKind() string
 }
 
+// Closer is implemented by sources holding connections that must be closed
+// when the server shuts down.
+type Closer interface {
+	Close() error
+}
+
+// Dependent is implemented by sources built on top of other sources. A source
+// is closed after the sources that depend on it.
+type Dependent interface {
+	DependsOn() []string
+}
+
 // InitConnectionSpan adds a span for database pool connection initialization
 func InitConnectionSpan(ctx context.Context, tracer trace.Tracer, sourceKind, sourceName string) (context.Context, trace.Span) {
 	ctx, span := tracer.Start(
@@ -85,6 +85,12 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the sessions of the source.
+func (s *Source) Close() error {
+	s.Client.Close()
+	return nil
+}
+
 func (s *Source) SpannerClient() *spanner.Client {
 	return s.Client
 }
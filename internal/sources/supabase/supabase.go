@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supabase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "supabase"
+
+// Connection pooler modes.
+const (
+	PoolerSession     string = "session"
+	PoolerTransaction string = "transaction"
+)
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, User: "postgres", Database: "postgres"} // Supabase defaults
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name       string `yaml:"name" validate:"required"`
+	Kind       string `yaml:"kind" validate:"required"`
+	ProjectRef string `yaml:"projectRef" validate:"required"`
+	User       string `yaml:"user" validate:"required"`
+	Password   string `yaml:"password" validate:"required"`
+	Database   string `yaml:"database" validate:"required"`
+	// Pooler connects through Supavisor in "session" or "transaction" mode
+	// instead of directly to the database.
+	Pooler string `yaml:"pooler" validate:"omitempty,oneof=session transaction"`
+	// Region is the region of the project, required by the pooler.
+	Region string `yaml:"region" validate:"required_with=Pooler"`
+	// PoolerHost overrides the host of the pooler, which is shown in the
+	// connection settings of the project.
+	PoolerHost string `yaml:"poolerHost"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+// ConnString returns the connection string of the project's database.
+func (r Config) ConnString() string {
+	host := fmt.Sprintf("db.%s.supabase.co:5432", r.ProjectRef)
+	user := r.User
+	q := url.Values{}
+	q.Set("sslmode", "require")
+	if r.Pooler != "" {
+		poolerHost := r.PoolerHost
+		if poolerHost == "" {
+			poolerHost = fmt.Sprintf("aws-0-%s.pooler.supabase.com", r.Region)
+		}
+		port := "5432"
+		if r.Pooler == PoolerTransaction {
+			port = "6543"
+			// transaction mode doesn't keep the prepared statements of a
+			// client between transactions
+			q.Set("default_query_exec_mode", "simple_protocol")
+		}
+		host = poolerHost + ":" + port
+		// the pooler identifies the project by the user name
+		user = fmt.Sprintf("%s.%s", r.User, r.ProjectRef)
+	}
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(user, r.Password),
+		Host:     host,
+		Path:     r.Database,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	pool, err := initSupabaseConnectionPool(ctx, tracer, r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create pool: %w", err)
+	}
+
+	err = pool.Ping(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect successfully: %w", err)
+	}
+
+	s := &Source{
+		Name: r.Name,
+		Kind: SourceKind,
+		Pool: pool,
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+	Pool *pgxpool.Pool
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+func (s *Source) PostgresPool() *pgxpool.Pool {
+	return s.Pool
+}
+
+// Close closes the connections of the source, once the ones in use are
+// released.
+func (s *Source) Close() error {
+	s.Pool.Close()
+	return nil
+}
+
+func initSupabaseConnectionPool(ctx context.Context, tracer trace.Tracer, r Config) (*pgxpool.Pool, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	pool, err := pgxpool.New(ctx, r.ConnString())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidb_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources/tidb"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlTiDB(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			sources:
+				my-tidb-instance:
+					kind: tidb
+					host: gateway01.us-west-2.prod.aws.tidbcloud.com
+					database: my_db
+					user: my_user
+					password: my_pass
+					useTLS: true
+			`,
+			want: server.SourceConfigs{
+				"my-tidb-instance": tidb.Config{
+					Name:     "my-tidb-instance",
+					Kind:     tidb.SourceKind,
+					Host:     "gateway01.us-west-2.prod.aws.tidbcloud.com",
+					Port:     "4000",
+					Database: "my_db",
+					User:     "my_user",
+					Password: "my_pass",
+					UseTLS:   true,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestDSN(t *testing.T) {
+	cfg := tidb.Config{Host: "gateway01.us-west-2.prod.aws.tidbcloud.com", Port: "4000", User: "my_user", Password: "my_pass", Database: "my_db", UseTLS: true}
+	got, err := cfg.DSN()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "my_user:my_pass@tcp(gateway01.us-west-2.prod.aws.tidbcloud.com:4000)/my_db?parseTime=true&tls=true"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -120,6 +120,12 @@ func (s *Source) SourceKind() string {
 	return SourceKind
 }
 
+// Close closes the connections of the source.
+func (s *Source) Close() error {
+	s.Client.Close()
+	return nil
+}
+
 func (s *Source) ValkeyClient() valkey.Client {
 	return s.Client
 }
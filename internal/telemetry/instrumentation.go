@@ -31,6 +31,9 @@ const (
 	toolInvokeCountName = "toolbox.server.tool.invoke.count"
 	mcpSseCountName     = "toolbox.server.mcp.sse.count"
 	mcpPostCountName    = "toolbox.server.mcp.post.count"
+
+	authVerifyCountName    = "toolbox.server.auth.verify.count"
+	authVerifyDurationName = "toolbox.server.auth.verify.duration"
 )
 
 // Instrumentation defines the telemetry instrumentation for toolbox
@@ -42,6 +45,9 @@ type Instrumentation struct {
 	ToolInvoke metric.Int64Counter
 	McpSse     metric.Int64Counter
 	McpPost    metric.Int64Counter
+
+	AuthVerify         metric.Int64Counter
+	AuthVerifyDuration metric.Float64Histogram
 }
 
 func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, error) {
@@ -96,6 +102,24 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", mcpPostCountName, err)
 	}
 
+	authVerify, err := meter.Int64Counter(
+		authVerifyCountName,
+		metric.WithDescription("Number of auth token verifications."),
+		metric.WithUnit("{verification}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", authVerifyCountName, err)
+	}
+
+	authVerifyDuration, err := meter.Float64Histogram(
+		authVerifyDurationName,
+		metric.WithDescription("Latency of auth token verifications."),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", authVerifyDurationName, err)
+	}
+
 	instrumentation := &Instrumentation{
 		Tracer:     tracer,
 		meter:      meter,
@@ -104,6 +128,9 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		ToolInvoke: toolInvoke,
 		McpSse:     mcpSse,
 		McpPost:    mcpPost,
+
+		AuthVerify:         authVerify,
+		AuthVerifyDuration: authVerifyDuration,
 	}
 	return instrumentation, nil
 }
@@ -0,0 +1,310 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// DefaultApprovalTimeout is how long an invocation waits for approval before
+// it's rejected.
+const DefaultApprovalTimeout = 24 * time.Hour
+
+var (
+	// ErrApprovalNotFound is returned for jobs that aren't waiting for
+	// approval.
+	ErrApprovalNotFound = errors.New("no pending approval")
+	// ErrApprovalRejected is the cause of the error of a rejected job.
+	ErrApprovalRejected = errors.New("the invocation was not approved")
+)
+
+// PendingApproval is an invocation waiting for approval, as shown to
+// approvers.
+type PendingApproval struct {
+	JobID  string         `json:"jobId"`
+	Tool   string         `json:"tool"`
+	Caller string         `json:"caller"`
+	Params map[string]any `json:"params"`
+	// Preview is the statement the invocation runs once approved, for tools
+	// that support previews.
+	Preview     *StatementPreview `json:"preview,omitempty"`
+	RequestedAt time.Time         `json:"requestedAt"`
+	ExpiresAt   time.Time         `json:"expiresAt"`
+}
+
+// ApprovalRequest notifies approvers of a pending approval.
+type ApprovalRequest struct {
+	PendingApproval
+	// Token authenticates a decision on the approval made without the admin
+	// token, such as a webhook callback.
+	Token string `json:"token"`
+}
+
+type approval struct {
+	PendingApproval
+	token string
+	ctx   context.Context
+	run   func(ctx context.Context, send func(row any) error) (any, error)
+}
+
+// SetApprovalTimeout sets how long jobs wait for approval before they're
+// rejected.
+func (s *JobStore) SetApprovalTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.approvalTimeout = timeout
+}
+
+// ApprovalTimeout returns how long jobs wait for approval before they're
+// rejected.
+func (s *JobStore) ApprovalTimeout() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.approvalTimeout
+}
+
+// OnApprovalRequested sets a function called whenever a job starts waiting for
+// approval. It must not block.
+func (s *JobStore) OnApprovalRequested(notify func(ctx context.Context, req ApprovalRequest)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notify = notify
+}
+
+// Hold returns the status of a job that only runs run once it's approved. Like
+// the jobs of Start, the job can only be seen by the given caller.
+func (s *JobStore) Hold(ctx context.Context, tool, caller string, params map[string]any, preview *StatementPreview, run func(ctx context.Context, send func(row any) error) (any, error)) (JobStatus, error) {
+	j, jobCtx, err := newJob(ctx, tool, JobPendingApproval)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	token, err := randomHex(32)
+	if err != nil {
+		return JobStatus{}, err
+	}
+
+	s.mu.Lock()
+	s.prune()
+	j.approval = &approval{
+		PendingApproval: PendingApproval{
+			JobID:       j.status.JobID,
+			Tool:        tool,
+			Caller:      caller,
+			Params:      params,
+			Preview:     preview,
+			RequestedAt: j.status.StartedAt,
+			ExpiresAt:   j.status.StartedAt.Add(s.approvalTimeout),
+		},
+		token: token,
+		ctx:   jobCtx,
+		run:   run,
+	}
+	s.jobs[jobKey(j.status.JobID, caller)] = j
+	status := j.status.snapshot()
+	req := ApprovalRequest{PendingApproval: j.approval.PendingApproval, Token: token}
+	notify := s.notify
+	s.mu.Unlock()
+
+	if notify != nil {
+		notify(ctx, req)
+	}
+	return status, nil
+}
+
+// PendingApprovals lists the jobs of every caller waiting for approval, oldest
+// first.
+func (s *JobStore) PendingApprovals() []PendingApproval {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]PendingApproval, 0)
+	for _, j := range s.jobs {
+		s.expire(j)
+		if j.approval != nil {
+			out = append(out, j.approval.PendingApproval)
+		}
+	}
+	slices.SortFunc(out, func(a, b PendingApproval) int {
+		return cmp.Or(a.RequestedAt.Compare(b.RequestedAt), cmp.Compare(a.JobID, b.JobID))
+	})
+	return out
+}
+
+// Approve runs a job waiting for approval.
+func (s *JobStore) Approve(id string) (JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.pending(id)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	s.approve(j)
+	return j.status.snapshot(), nil
+}
+
+// Reject rejects a job waiting for approval, so it never runs.
+func (s *JobStore) Reject(id, reason string) (JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.pending(id)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	s.reject(j, rejectionDetail(reason))
+	return j.status.snapshot(), nil
+}
+
+// Decide approves or rejects a job waiting for approval on behalf of the
+// holder of its token.
+func (s *JobStore) Decide(id, token string, approve bool, reason string) (JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.pending(id)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(j.approval.token)) != 1 {
+		return JobStatus{}, NewToolError(ErrorCategoryAuth, ErrorCodeUnauthorized, "invalid approval token", fmt.Errorf("invalid approval token for job %q", id))
+	}
+	if approve {
+		s.approve(j)
+	} else {
+		s.reject(j, rejectionDetail(reason))
+	}
+	return j.status.snapshot(), nil
+}
+
+func rejectionDetail(reason string) string {
+	if reason == "" {
+		return "the invocation was rejected"
+	}
+	return "the invocation was rejected: " + reason
+}
+
+// approve starts a job waiting for approval. s.mu must be held.
+func (s *JobStore) approve(j *job) {
+	a := j.approval
+	j.approval = nil
+	j.status.Status = JobRunning
+	j.status.StartedAt = time.Now()
+	s.launch(a.ctx, j, a.run)
+}
+
+// pending returns the job with the given ID if it's waiting for approval,
+// whatever its caller. s.mu must be held.
+func (s *JobStore) pending(id string) (*job, error) {
+	for _, j := range s.jobs {
+		if j.status.JobID != id {
+			continue
+		}
+		s.expire(j)
+		if j.approval == nil {
+			break
+		}
+		return j, nil
+	}
+	return nil, NewToolError(ErrorCategoryValidation, ErrorCodeJobNotFound, "", fmt.Errorf("%w for job %q", ErrApprovalNotFound, id))
+}
+
+// expire rejects a job that waited for approval for too long. s.mu must be
+// held.
+func (s *JobStore) expire(j *job) {
+	if j.approval != nil && time.Now().After(j.approval.ExpiresAt) {
+		s.reject(j, "the invocation was not approved in time")
+	}
+}
+
+// reject finishes a job waiting for approval as rejected. s.mu must be held.
+func (s *JobStore) reject(j *job, detail string) {
+	now := time.Now()
+	j.approval = nil
+	j.cancel()
+	j.status.Status = JobRejected
+	j.status.FinishedAt = &now
+	j.status.Error = NewToolError(ErrorCategoryAuth, ErrorCodeApprovalRejected, detail, ErrApprovalRejected)
+}
+
+// RequiresApproval reports whether the invocations of t are held until a
+// human approves them.
+func RequiresApproval(t Tool) bool {
+	a, ok := t.(interface{ RequiresApproval() bool })
+	return ok && a.RequiresApproval()
+}
+
+// ApprovalToolConfig wraps a ToolConfig so its invocations only run once a
+// human approves them.
+type ApprovalToolConfig struct {
+	ToolConfig
+}
+
+func (cfg ApprovalToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	// approved invocations already run as jobs
+	if a, ok := t.(asyncTool); ok {
+		t = a.Tool
+	}
+	return approvalTool{toolWrapper: toolWrapper{t}}, nil
+}
+
+// approvalTool holds a job for every invocation until it's approved, and
+// returns its status rather than running the tool.
+type approvalTool struct {
+	toolWrapper
+}
+
+func (t approvalTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	store, err := JobStoreFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("approvals are not available: %w", err)
+	}
+	name := t.Tool.McpManifest().Name
+	// approvers see the statement the invocation would run
+	var preview *StatementPreview
+	if p, err := PreviewTool(ctx, t.Tool, params); err == nil {
+		preview = &p
+	}
+	return store.Hold(ctx, name, JobCaller(ctx), params.AsMap(), preview, runJob(t.Tool, name, params))
+}
+
+// RequiresApproval is true, even when other wrappers are applied around the
+// tool.
+func (t approvalTool) RequiresApproval() bool {
+	return true
+}
+
+// CanStream is false since invocations return the status of their job. The
+// rows of the approved job are streamed to the job store instead.
+func (t approvalTool) CanStream() bool {
+	return false
+}
+
+// QueueExempt makes sure requesting approval never waits for a slot, since
+// the approved job itself does.
+func (t approvalTool) QueueExempt() bool {
+	return true
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// requestApproval invokes tool as a tool requiring approval on behalf of
+// caller, and returns the status of its job and the request sent to approvers.
+func requestApproval(t *testing.T, store *tools.JobStore, tool tools.Tool, caller string) (tools.JobStatus, tools.ApprovalRequest) {
+	t.Helper()
+	var req tools.ApprovalRequest
+	store.OnApprovalRequested(func(_ context.Context, r tools.ApprovalRequest) { req = r })
+	gated, err := tools.ApprovalToolConfig{ToolConfig: tools.AsyncToolConfig{ToolConfig: staticToolConfig{tool: tool}}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	ctx, done, err := invocations.NewTracker(0).BeginTool(tools.WithJobStore(context.Background(), store), "my_tool", gated, caller)
+	if err != nil {
+		t.Fatalf("unable to begin invocation: %s", err)
+	}
+	defer done()
+	res, err := gated.Invoke(ctx, tools.ParamValues{{Name: "id", Value: 42}})
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	status, ok := res.(tools.JobStatus)
+	if !ok || status.Status != tools.JobPendingApproval {
+		t.Fatalf("expected a job pending approval, got %#v", res)
+	}
+	return status, req
+}
+
+func TestApprovalToolApprove(t *testing.T) {
+	store := tools.NewJobStore(time.Minute)
+	job, req := requestApproval(t, store, resultTool{fakeTool: fakeTool{name: "my_tool"}, result: []any{"deleted"}}, "alice")
+
+	want := []tools.PendingApproval{{JobID: job.JobID, Tool: "my_tool", Caller: "alice", Params: map[string]any{"id": 42}}}
+	ignoreTimes := cmp.FilterPath(func(p cmp.Path) bool {
+		name := p.Last().String()
+		return name == ".RequestedAt" || name == ".ExpiresAt"
+	}, cmp.Ignore())
+	if diff := cmp.Diff(want, store.PendingApprovals(), ignoreTimes); diff != "" {
+		t.Fatalf("incorrect pending approvals (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want[0], req.PendingApproval, ignoreTimes); diff != "" || req.Token == "" {
+		t.Fatalf("incorrect approval request (-want +got):\n%s", diff)
+	}
+
+	// the job doesn't run until it's approved
+	time.Sleep(20 * time.Millisecond)
+	if status, err := store.Status(job.JobID, "alice"); err != nil || status.Status != tools.JobPendingApproval {
+		t.Fatalf("expected the job to still be pending, got %#v, %v", status, err)
+	}
+
+	if _, err := store.Approve(job.JobID); err != nil {
+		t.Fatalf("unable to approve job: %s", err)
+	}
+	waitForJob(t, store, job.JobID, "alice", func(s tools.JobStatus) bool { return s.Status == tools.JobSucceeded })
+	res, err := store.Result(job.JobID, "alice", 0)
+	if err != nil {
+		t.Fatalf("unable to get job result: %s", err)
+	}
+	if diff := cmp.Diff([]any{"deleted"}, res.Rows); diff != "" {
+		t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+	}
+	if got := store.PendingApprovals(); len(got) != 0 {
+		t.Fatalf("expected no pending approvals, got %#v", got)
+	}
+
+	// decisions are only taken once
+	if _, err := store.Reject(job.JobID, ""); !errors.Is(err, tools.ErrApprovalNotFound) {
+		t.Fatalf("expected ErrApprovalNotFound for an approved job, got %v", err)
+	}
+}
+
+func TestApprovalToolReject(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		decide     func(store *tools.JobStore, id, token string) (tools.JobStatus, error)
+		wantDetail string
+	}{
+		{
+			desc: "reject",
+			decide: func(store *tools.JobStore, id, _ string) (tools.JobStatus, error) {
+				return store.Reject(id, "wrong table")
+			},
+			wantDetail: "the invocation was rejected: wrong table",
+		},
+		{
+			desc: "reject with token",
+			decide: func(store *tools.JobStore, id, token string) (tools.JobStatus, error) {
+				return store.Decide(id, token, false, "")
+			},
+			wantDetail: "the invocation was rejected",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			store := tools.NewJobStore(time.Minute)
+			job, req := requestApproval(t, store, fakeTool{name: "my_tool"}, "alice")
+			status, err := tc.decide(store, job.JobID, req.Token)
+			if err != nil {
+				t.Fatalf("unable to reject job: %s", err)
+			}
+			if status.Status != tools.JobRejected || status.Error == nil || status.Error.Code != tools.ErrorCodeApprovalRejected || status.Error.Detail != tc.wantDetail {
+				t.Fatalf("unexpected status: %#v", status)
+			}
+		})
+	}
+}
+
+func TestApprovalToolDecideToken(t *testing.T) {
+	store := tools.NewJobStore(time.Minute)
+	job, req := requestApproval(t, store, fakeTool{name: "my_tool"}, "alice")
+
+	var te *tools.ToolError
+	if _, err := store.Decide(job.JobID, "wrong", true, ""); !errors.As(err, &te) || te.Code != tools.ErrorCodeUnauthorized {
+		t.Fatalf("expected an UNAUTHORIZED error for a wrong token, got %v", err)
+	}
+	if _, err := store.Decide(job.JobID, req.Token, true, ""); err != nil {
+		t.Fatalf("unable to approve job: %s", err)
+	}
+	waitForJob(t, store, job.JobID, "alice", func(s tools.JobStatus) bool { return s.Status == tools.JobSucceeded })
+}
+
+func TestApprovalToolCancelAndExpire(t *testing.T) {
+	store := tools.NewJobStore(time.Minute)
+	job, _ := requestApproval(t, store, fakeTool{name: "my_tool"}, "alice")
+	status, err := store.Cancel(job.JobID, "alice")
+	if err != nil {
+		t.Fatalf("unable to cancel job: %s", err)
+	}
+	if status.Status != tools.JobCancelled {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+	if _, err := store.Approve(job.JobID); !errors.Is(err, tools.ErrApprovalNotFound) {
+		t.Fatalf("expected ErrApprovalNotFound for a cancelled job, got %v", err)
+	}
+
+	store.SetApprovalTimeout(time.Millisecond)
+	job, _ = requestApproval(t, store, fakeTool{name: "my_tool"}, "alice")
+	time.Sleep(5 * time.Millisecond)
+	status, err = store.Status(job.JobID, "alice")
+	if err != nil {
+		t.Fatalf("unable to get job status: %s", err)
+	}
+	if status.Status != tools.JobRejected || status.Error.Detail != "the invocation was not approved in time" {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+}
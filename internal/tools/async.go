@@ -0,0 +1,391 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// States of async jobs.
+const (
+	JobRunning   string = "running"
+	JobSucceeded string = "succeeded"
+	JobFailed    string = "failed"
+	JobCancelled string = "cancelled"
+	// JobPendingApproval and JobRejected are only reached by the jobs of
+	// tools that require approval.
+	JobPendingApproval string = "pending-approval"
+	JobRejected        string = "rejected"
+)
+
+// DefaultJobRetention is how long a finished job can still be polled.
+const DefaultJobRetention = time.Hour
+
+// ErrJobNotFound is returned for unknown jobs, and for jobs of other callers.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobStatus describes an async job as reported to callers.
+type JobStatus struct {
+	JobID      string     `json:"jobId"`
+	Tool       string     `json:"tool"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Elapsed    string     `json:"elapsed"`
+	// RowCount is the number of rows fetched so far.
+	RowCount int        `json:"rowCount"`
+	Error    *ToolError `json:"error,omitempty"`
+}
+
+// JobResult is a page of the rows fetched by an async job.
+type JobResult struct {
+	JobStatus
+	// Rows are the rows fetched since the requested offset. Tools that
+	// stream their rows have them fetched while the job is running, other
+	// tools once it has succeeded.
+	Rows []any `json:"rows"`
+	// NextOffset is the offset to fetch the following rows from.
+	NextOffset int `json:"nextOffset"`
+	// Result is the result of a succeeded job that isn't a list of rows.
+	Result any `json:"result,omitempty"`
+}
+
+type job struct {
+	status JobStatus
+	rows   []any
+	result any
+	cancel context.CancelFunc
+	// cancelled is set once the caller asked for the job to be cancelled.
+	cancelled bool
+	// approval is set while the job is waiting for approval.
+	approval *approval
+}
+
+// JobStore keeps track of the async jobs of a server. Should be instantiated
+// with NewJobStore().
+type JobStore struct {
+	mu        sync.Mutex
+	jobs      map[string]*job
+	retention time.Duration
+	// approvalTimeout is how long a job waits for approval.
+	approvalTimeout time.Duration
+	notify          func(ctx context.Context, req ApprovalRequest)
+}
+
+// NewJobStore returns a JobStore that forgets finished jobs after retention.
+func NewJobStore(retention time.Duration) *JobStore {
+	if retention <= 0 {
+		retention = DefaultJobRetention
+	}
+	return &JobStore{jobs: make(map[string]*job), retention: retention, approvalTimeout: DefaultApprovalTimeout}
+}
+
+// Retention returns how long finished jobs can still be polled.
+func (s *JobStore) Retention() time.Duration {
+	return s.retention
+}
+
+// Start runs run in the background and returns the status of its job at once.
+// run outlives ctx, and keeps its values. Rows passed to send can be fetched
+// while the job is running. The job can only be seen by the given caller.
+func (s *JobStore) Start(ctx context.Context, tool, caller string, run func(ctx context.Context, send func(row any) error) (any, error)) (JobStatus, error) {
+	j, ctx, err := newJob(ctx, tool, JobRunning)
+	if err != nil {
+		return JobStatus{}, err
+	}
+
+	s.mu.Lock()
+	s.prune()
+	s.jobs[jobKey(j.status.JobID, caller)] = j
+	status := j.status.snapshot()
+	s.launch(ctx, j, run)
+	s.mu.Unlock()
+	return status, nil
+}
+
+// newJob returns a job in the given state, and the context it runs with.
+func newJob(ctx context.Context, tool, state string) (*job, context.Context, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	j := &job{
+		status: JobStatus{JobID: "job-" + id, Tool: tool, Status: state, StartedAt: time.Now()},
+		cancel: cancel,
+	}
+	return j, ctx, nil
+}
+
+// launch runs the job in the background.
+func (s *JobStore) launch(ctx context.Context, j *job, run func(ctx context.Context, send func(row any) error) (any, error)) {
+	go func() {
+		defer j.cancel()
+		res, err := run(ctx, func(row any) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			j.rows = append(j.rows, row)
+			j.status.RowCount = len(j.rows)
+			return nil
+		})
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.finish(j, res, err)
+	}()
+}
+
+// finish records the outcome of a job. s.mu must be held.
+func (s *JobStore) finish(j *job, res any, err error) {
+	now := time.Now()
+	j.status.FinishedAt = &now
+	switch {
+	case j.cancelled:
+		j.status.Status = JobCancelled
+		j.status.Error = NewToolError(ErrorCategoryTimeout, ErrorCodeCancelled, "the job was cancelled", context.Canceled)
+	case err != nil:
+		j.status.Status = JobFailed
+		j.status.Error = ClassifyError(err, ErrorCategoryQuery, ErrorCodeQueryFailed)
+	default:
+		j.status.Status = JobSucceeded
+		// a result that is a list of rows is paged like streamed rows
+		if rows, ok := res.([]any); ok && len(j.rows) == 0 {
+			j.rows = rows
+		} else if res != nil {
+			j.result = res
+		}
+		j.status.RowCount = len(j.rows)
+	}
+}
+
+// prune forgets the jobs that finished more than retention ago. s.mu must be
+// held.
+func (s *JobStore) prune() {
+	cutoff := time.Now().Add(-s.retention)
+	for k, j := range s.jobs {
+		if j.status.FinishedAt != nil && j.status.FinishedAt.Before(cutoff) {
+			delete(s.jobs, k)
+		}
+	}
+}
+
+// jobKey scopes job IDs to their caller, so that a caller can't tell whether
+// the job of another caller exists.
+func jobKey(id, caller string) string {
+	return caller + "\x00" + id
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *JobStore) get(id, caller string) (*job, error) {
+	j, ok := s.jobs[jobKey(id, caller)]
+	if !ok || (j.status.FinishedAt != nil && time.Since(*j.status.FinishedAt) > s.retention) {
+		return nil, NewToolError(ErrorCategoryValidation, ErrorCodeJobNotFound, "", fmt.Errorf("%w: %q", ErrJobNotFound, id))
+	}
+	s.expire(j)
+	return j, nil
+}
+
+// Status returns the status of a job of the caller.
+func (s *JobStore) Status(id, caller string) (JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.get(id, caller)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	return j.status.snapshot(), nil
+}
+
+// Result returns the rows of a job of the caller fetched since offset.
+func (s *JobStore) Result(id, caller string, offset int) (JobResult, error) {
+	if offset < 0 {
+		return JobResult{}, NewToolError(ErrorCategoryValidation, ErrorCodeInvalidRequest, "the offset must not be negative", fmt.Errorf("offset must not be negative, got %d", offset))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.get(id, caller)
+	if err != nil {
+		return JobResult{}, err
+	}
+	offset = min(offset, len(j.rows))
+	r := JobResult{
+		JobStatus:  j.status.snapshot(),
+		Rows:       append([]any{}, j.rows[offset:]...),
+		NextOffset: len(j.rows),
+	}
+	if j.status.Status == JobSucceeded {
+		r.Result = j.result
+	}
+	return r, nil
+}
+
+// Cancel cancels a running job of the caller. Cancelling a finished job has
+// no effect.
+func (s *JobStore) Cancel(id, caller string) (JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, err := s.get(id, caller)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	switch j.status.Status {
+	case JobRunning:
+		j.cancelled = true
+		j.cancel()
+	case JobPendingApproval:
+		// the job never started, so it's cancelled at once
+		j.cancelled = true
+		j.approval = nil
+		j.cancel()
+		s.finish(j, nil, nil)
+	}
+	return j.status.snapshot(), nil
+}
+
+// snapshot returns a copy of the status with the elapsed time filled in.
+func (st JobStatus) snapshot() JobStatus {
+	end := time.Now()
+	if st.FinishedAt != nil {
+		end = *st.FinishedAt
+	}
+	st.Elapsed = end.Sub(st.StartedAt).Round(time.Millisecond).String()
+	return st
+}
+
+type jobStoreKey struct{}
+
+// WithJobStore returns a context carrying the server's job store.
+func WithJobStore(ctx context.Context, store *JobStore) context.Context {
+	return context.WithValue(ctx, jobStoreKey{}, store)
+}
+
+// JobStoreFromContext returns the job store carried by ctx.
+func JobStoreFromContext(ctx context.Context) (*JobStore, error) {
+	if store, ok := ctx.Value(jobStoreKey{}).(*JobStore); ok && store != nil {
+		return store, nil
+	}
+	return nil, fmt.Errorf("unable to retrieve job store")
+}
+
+// JobCaller returns the caller that owns the jobs started or looked up with
+// ctx. Anonymous callers share an empty caller.
+func JobCaller(ctx context.Context) string {
+	if inv, ok := invocations.FromContext(ctx); ok {
+		return inv.Caller
+	}
+	return ""
+}
+
+// RunsAsJob reports whether the invocations of t run as async jobs, and
+// whether they wait for approval before they do.
+func RunsAsJob(t Tool) (async, approval bool) {
+	// policies are the only wrapper applied to async tools
+	if p, ok := t.(policyTool); ok {
+		t = p.Tool
+	}
+	switch t.(type) {
+	case asyncTool:
+		return true, false
+	case approvalTool:
+		return true, true
+	}
+	return false, false
+}
+
+// AsyncToolConfig wraps a ToolConfig so its invocations run as async jobs.
+type AsyncToolConfig struct {
+	ToolConfig
+}
+
+func (cfg AsyncToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return asyncTool{toolWrapper: toolWrapper{t}}, nil
+}
+
+// asyncTool starts a job for every invocation and returns its status rather
+// than waiting for the result.
+type asyncTool struct {
+	toolWrapper
+}
+
+func (t asyncTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	store, err := JobStoreFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("async jobs are not available: %w", err)
+	}
+	name := t.Tool.McpManifest().Name
+	return store.Start(ctx, name, JobCaller(ctx), runJob(t.Tool, name, params))
+}
+
+// CanStream is false since invocations return the status of their job. The
+// rows of the job are streamed to the job store instead.
+func (t asyncTool) CanStream() bool {
+	return false
+}
+
+// runJob returns the function a job of tool t runs.
+func runJob(t Tool, name string, params ParamValues) func(ctx context.Context, send func(row any) error) (any, error) {
+	return func(ctx context.Context, send func(row any) error) (any, error) {
+		// the job waits for a slot of its own, the invocation that started it
+		// only held one until it returned
+		if tracker, err := util.InvocationTrackerFromContext(ctx); err == nil {
+			var done func()
+			ctx, done, err = tracker.BeginTool(ctx, name, t, JobCaller(ctx))
+			if err != nil {
+				return nil, err
+			}
+			defer done()
+			res, err := streamJob(ctx, t, params, send)
+			tracker.RecordError(ctx, err)
+			return res, err
+		}
+		return streamJob(ctx, t, params, send)
+	}
+}
+
+// streamJob streams the rows of tools that can, so they can be fetched while
+// the job is running.
+func streamJob(ctx context.Context, t Tool, params ParamValues, send func(row any) error) (any, error) {
+	if st, ok := AsStreamingTool(t); ok {
+		return nil, st.Stream(ctx, params, send)
+	}
+	return t.Invoke(ctx, params)
+}
+
+// QueueExempt makes sure starting a job never waits for a slot, since the job
+// itself does.
+func (t asyncTool) QueueExempt() bool {
+	return true
+}
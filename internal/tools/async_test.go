@@ -0,0 +1,206 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// blockingStreamTool streams its rows, then blocks until release is closed.
+type blockingStreamTool struct {
+	fakeTool
+	rows    []any
+	release chan struct{}
+}
+
+func (t blockingStreamTool) Stream(ctx context.Context, _ tools.ParamValues, send func(row any) error) error {
+	for _, r := range t.rows {
+		if err := send(r); err != nil {
+			return err
+		}
+	}
+	select {
+	case <-t.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type resultTool struct {
+	fakeTool
+	result any
+}
+
+func (t resultTool) Invoke(context.Context, tools.ParamValues) (any, error) {
+	return t.result, nil
+}
+
+type staticToolConfig struct {
+	tool tools.Tool
+}
+
+func (c staticToolConfig) ToolConfigKind() string { return "static" }
+func (c staticToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return c.tool, nil
+}
+
+// startJob invokes tool as an async tool on behalf of caller.
+func startJob(t *testing.T, store *tools.JobStore, tool tools.Tool, caller string) tools.JobStatus {
+	t.Helper()
+	async, err := tools.AsyncToolConfig{ToolConfig: staticToolConfig{tool: tool}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	ctx, done, err := invocations.NewTracker(0).BeginTool(tools.WithJobStore(context.Background(), store), "my_tool", async, caller)
+	if err != nil {
+		t.Fatalf("unable to begin invocation: %s", err)
+	}
+	defer done()
+	res, err := async.Invoke(ctx, nil)
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	status, ok := res.(tools.JobStatus)
+	if !ok || status.Status != tools.JobRunning {
+		t.Fatalf("expected a running job, got %#v", res)
+	}
+	return status
+}
+
+// waitForJob polls a job until cond is true.
+func waitForJob(t *testing.T, store *tools.JobStore, id, caller string, cond func(tools.JobStatus) bool) tools.JobStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, err := store.Status(id, caller)
+		if err != nil {
+			t.Fatalf("unable to get job status: %s", err)
+		}
+		if cond(status) {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job didn't reach the expected state, last status: %#v", status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAsyncToolStreamsPartialResults(t *testing.T) {
+	store := tools.NewJobStore(time.Minute)
+	tool := blockingStreamTool{
+		fakeTool: fakeTool{name: "my_tool"},
+		rows:     []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+		release:  make(chan struct{}),
+	}
+	job := startJob(t, store, tool, "alice")
+
+	waitForJob(t, store, job.JobID, "alice", func(s tools.JobStatus) bool { return s.RowCount == 2 })
+	res, err := store.Result(job.JobID, "alice", 1)
+	if err != nil {
+		t.Fatalf("unable to get job result: %s", err)
+	}
+	if res.Status != tools.JobRunning || res.NextOffset != 2 {
+		t.Fatalf("unexpected partial result: %#v", res)
+	}
+	if diff := cmp.Diff([]any{map[string]any{"id": 2}}, res.Rows); diff != "" {
+		t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+	}
+
+	// jobs of other callers can't be seen
+	if _, err := store.Status(job.JobID, "bob"); !errors.Is(err, tools.ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound for another caller, got %v", err)
+	}
+
+	close(tool.release)
+	status := waitForJob(t, store, job.JobID, "alice", func(s tools.JobStatus) bool { return s.Status != tools.JobRunning })
+	if status.Status != tools.JobSucceeded || status.FinishedAt == nil || status.Error != nil {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+}
+
+func TestAsyncToolResults(t *testing.T) {
+	store := tools.NewJobStore(time.Minute)
+	tcs := []struct {
+		desc       string
+		result     any
+		wantRows   []any
+		wantResult any
+	}{
+		{
+			desc:     "rows are paged",
+			result:   []any{map[string]any{"id": 1}},
+			wantRows: []any{map[string]any{"id": 1}},
+		},
+		{
+			desc:       "other results are returned as is",
+			result:     map[string]any{"rowCount": 3},
+			wantRows:   []any{},
+			wantResult: map[string]any{"rowCount": 3},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			job := startJob(t, store, resultTool{fakeTool: fakeTool{name: "my_tool"}, result: tc.result}, "")
+			waitForJob(t, store, job.JobID, "", func(s tools.JobStatus) bool { return s.Status == tools.JobSucceeded })
+			res, err := store.Result(job.JobID, "", 0)
+			if err != nil {
+				t.Fatalf("unable to get job result: %s", err)
+			}
+			if diff := cmp.Diff(tc.wantRows, res.Rows); diff != "" {
+				t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantResult, res.Result); diff != "" {
+				t.Fatalf("incorrect result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAsyncToolCancel(t *testing.T) {
+	store := tools.NewJobStore(time.Minute)
+	tool := blockingStreamTool{fakeTool: fakeTool{name: "my_tool"}, release: make(chan struct{})}
+	job := startJob(t, store, tool, "alice")
+
+	if _, err := store.Cancel(job.JobID, "bob"); !errors.Is(err, tools.ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound for another caller, got %v", err)
+	}
+	if _, err := store.Cancel(job.JobID, "alice"); err != nil {
+		t.Fatalf("unable to cancel job: %s", err)
+	}
+	status := waitForJob(t, store, job.JobID, "alice", func(s tools.JobStatus) bool { return s.Status != tools.JobRunning })
+	if status.Status != tools.JobCancelled || status.Error == nil || status.Error.Code != tools.ErrorCodeCancelled {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+}
+
+func TestAsyncToolWithoutStore(t *testing.T) {
+	async, err := tools.AsyncToolConfig{ToolConfig: staticToolConfig{tool: fakeTool{name: "my_tool"}}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	if _, err := async.Invoke(context.Background(), nil); err == nil {
+		t.Fatalf("expected an error without a job store")
+	}
+}
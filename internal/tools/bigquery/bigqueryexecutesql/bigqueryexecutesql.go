@@ -96,6 +96,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Kind:         kind,
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
+		queryTags:    tools.SourceQueryTags(rawS),
 		Client:       s.BigQueryClient(),
 		RestService:  s.BigQueryRestService(),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
@@ -114,6 +115,7 @@ type Tool struct {
 	Parameters   tools.Parameters `yaml:"parameters"`
 	Client       *bigqueryapi.Client
 	RestService  *bigqueryrestapi.Service
+	queryTags    map[string]string
 	manifest     tools.Manifest
 	mcpManifest  tools.McpManifest
 }
@@ -134,6 +136,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 	// JobStatistics.QueryStatistics.StatementType
 	query := t.Client.Query(sql)
 	query.Location = t.Client.Location
+	query.Labels = bigqueryds.JobLabels(tools.QueryTags(ctx, t.queryTags))
 
 	// This block handles Data Manipulation Language (DML) and Data Definition Language (DDL) statements.
 	// These statements (e.g., INSERT, UPDATE, CREATE TABLE) do not return a row set.
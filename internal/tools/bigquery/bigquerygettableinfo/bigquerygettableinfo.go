@@ -20,6 +20,7 @@ import (
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	bigqueryds "github.com/googleapis/genai-toolbox/internal/sources/bigquery"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -99,6 +100,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
 		Client:       s.BigQueryClient(),
+		Dictionary:   dictionary.FromSource(rawS),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
 	}
@@ -116,6 +118,7 @@ type Tool struct {
 
 	Client      *bigqueryapi.Client
 	Statement   string
+	Dictionary  dictionary.Dictionary
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
@@ -145,9 +148,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 		return nil, fmt.Errorf("failed to get metadata for table %s.%s.%s: %w", projectId, datasetId, tableId, err)
 	}
 
+	if entry, ok := t.Dictionary.Table(datasetId + "." + tableId); ok {
+		annotateMetadata(metadata, entry)
+	}
 	return metadata, nil
 }
 
+// annotateMetadata merges the descriptions of a table and its top-level
+// columns with their entry in the data dictionary.
+func annotateMetadata(metadata *bigqueryapi.TableMetadata, entry dictionary.Table) {
+	metadata.Description = entry.Annotate(metadata.Description)
+	for _, field := range metadata.Schema {
+		if c, ok := entry.Column(field.Name); ok {
+			field.Description = c.Annotate(field.Description)
+		}
+	}
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.Parameters, data, claims)
 }
@@ -83,6 +83,11 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	statement, params, err := tools.BindNamedParams(cfg.Statement, tools.AtPlaceholders, cfg.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement for %q tool: %w", kind, err)
+	}
+
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
@@ -95,11 +100,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	t := Tool{
 		Name:               cfg.Name,
 		Kind:               kind,
-		Parameters:         cfg.Parameters,
+		Parameters:         params,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
-		Statement:          cfg.Statement,
+		Statement:          statement,
 		AuthRequired:       cfg.AuthRequired,
+		queryTags:          tools.SourceQueryTags(rawS),
 		Client:             s.BigQueryClient(),
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
@@ -109,6 +115,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.PreviewableTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -120,6 +127,7 @@ type Tool struct {
 
 	Client      *bigqueryapi.Client
 	Statement   string
+	queryTags   map[string]string
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
@@ -151,6 +159,13 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 			if err != nil {
 				return nil, fmt.Errorf("unable to convert parameter `%s` from []any to typed slice: %w", name, err)
 			}
+		case *tools.DateParameter, *tools.DecimalParameter:
+			// BigQuery binds DATE and NUMERIC from civil.Date and *big.Rat
+			var err error
+			value, err = tools.ConvertToNativeValue(p.GetType(), value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert parameter `%s`: %w", name, err)
+			}
 		}
 
 		if strings.Contains(t.Statement, "@"+name) {
@@ -168,6 +183,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 	query := t.Client.Query(newStatement)
 	query.Parameters = namedArgs
 	query.Location = t.Client.Location
+	query.Labels = bigqueryds.JobLabels(tools.QueryTags(ctx, t.queryTags))
 
 	it, err := query.Read(ctx)
 	if err != nil {
@@ -194,6 +210,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 	return out, nil
 }
 
+// Preview renders the statement the invocation would run.
+func (t Tool) Preview(ctx context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	return tools.PreviewStatement(t.TemplateParameters, t.Parameters, t.Statement, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
@@ -107,6 +107,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.PreviewableTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -214,6 +215,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 	return out, nil
 }
 
+// Preview renders the statement the invocation would run.
+func (t Tool) Preview(ctx context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	return tools.PreviewStatement(t.TemplateParameters, t.Parameters, t.Statement, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
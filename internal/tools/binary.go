@@ -0,0 +1,281 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"google.golang.org/api/googleapi"
+)
+
+// Kinds of artifact stores for binary values.
+const (
+	ArtifactStoreLocal string = "local"
+	ArtifactStoreGCS   string = "gcs"
+)
+
+const defaultMaxInlineBytes = 64 * 1024
+
+// BinaryHandling configures how binary values in a tool's results, such as
+// BLOB or BYTEA columns, are returned.
+type BinaryHandling struct {
+	// MaxInlineBytes is the size of the largest value returned inline as
+	// base64. Defaults to 64 KiB.
+	MaxInlineBytes int `yaml:"maxInlineBytes"`
+	// ArtifactStore stores larger values and returns a reference to them.
+	// Without one, larger values are returned without their data.
+	ArtifactStore *ArtifactStoreConfig `yaml:"artifactStore"`
+}
+
+// ArtifactStoreConfig configures where binary values too large to be inlined
+// are written.
+type ArtifactStoreConfig struct {
+	// Kind is either "local" or "gcs".
+	Kind string `yaml:"kind" validate:"required"`
+	// Path is the directory of a local store.
+	Path string `yaml:"path"`
+	// Bucket is the bucket of a gcs store.
+	Bucket string `yaml:"bucket"`
+	// Prefix is prepended to the names of the objects of a gcs store.
+	Prefix string `yaml:"prefix"`
+	// BaseURL, if set, is where clients can fetch the stored values. The URL
+	// of a value is the name of its file or object appended to BaseURL.
+	BaseURL string `yaml:"baseUrl"`
+}
+
+// BinaryValue replaces a binary value in a tool's result. Exactly one of
+// Base64 and URL is set, unless the value was omitted.
+type BinaryValue struct {
+	Base64   string `json:"base64,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	// Omitted reports that the value was too large to be inlined and no
+	// artifact store is configured.
+	Omitted bool `json:"omitted,omitempty"`
+}
+
+// artifactStore stores a binary value and returns the URL it can be fetched
+// from.
+type artifactStore interface {
+	put(ctx context.Context, name, mimeType string, data []byte) (string, error)
+}
+
+func (c ArtifactStoreConfig) initialize() (artifactStore, error) {
+	switch c.Kind {
+	case ArtifactStoreLocal:
+		if c.Path == "" {
+			return nil, fmt.Errorf("a local artifact store requires a path")
+		}
+		dir, err := filepath.Abs(c.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact store path %q: %w", c.Path, err)
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("unable to create artifact store directory: %w", err)
+		}
+		return localArtifactStore{dir: dir, baseURL: c.BaseURL}, nil
+	case ArtifactStoreGCS:
+		if c.Bucket == "" {
+			return nil, fmt.Errorf("a gcs artifact store requires a bucket")
+		}
+		client, err := storage.NewClient(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("unable to create storage client: %w", err)
+		}
+		return gcsArtifactStore{bucket: client.Bucket(c.Bucket), name: c.Bucket, prefix: c.Prefix, baseURL: c.BaseURL}, nil
+	default:
+		return nil, fmt.Errorf("unknown artifact store kind %q, must be %q or %q", c.Kind, ArtifactStoreLocal, ArtifactStoreGCS)
+	}
+}
+
+type localArtifactStore struct {
+	dir     string
+	baseURL string
+}
+
+func (s localArtifactStore) put(_ context.Context, name, _ string, data []byte) (string, error) {
+	path := filepath.Join(s.dir, name)
+	// names are content addressed, so an existing file already holds data
+	if _, err := os.Stat(path); err != nil {
+		f, err := os.CreateTemp(s.dir, ".artifact-*")
+		if err != nil {
+			return "", err
+		}
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = os.Rename(f.Name(), path)
+		}
+		if err != nil {
+			os.Remove(f.Name())
+			return "", err
+		}
+	}
+	if s.baseURL != "" {
+		return joinURL(s.baseURL, name), nil
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
+type gcsArtifactStore struct {
+	bucket  *storage.BucketHandle
+	name    string
+	prefix  string
+	baseURL string
+}
+
+func (s gcsArtifactStore) put(ctx context.Context, name, mimeType string, data []byte) (string, error) {
+	object := s.prefix + name
+	// names are content addressed, so an existing object already holds data
+	w := s.bucket.Object(object).If(storage.Conditions{DoesNotExist: true}).NewWriter(ctx)
+	w.ContentType = mimeType
+	_, err := w.Write(data)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	var gerr *googleapi.Error
+	if err != nil && !(errors.As(err, &gerr) && gerr.Code == http.StatusPreconditionFailed) {
+		return "", err
+	}
+	if s.baseURL != "" {
+		return joinURL(s.baseURL, object), nil
+	}
+	return fmt.Sprintf("gs://%s/%s", s.name, object), nil
+}
+
+func joinURL(base, name string) string {
+	return strings.TrimSuffix(base, "/") + "/" + name
+}
+
+// BinaryToolConfig wraps a ToolConfig so binary values in the results of the
+// tool it initializes are returned according to Binary.
+type BinaryToolConfig struct {
+	ToolConfig
+	Binary BinaryHandling
+}
+
+// validate interface
+var _ ToolConfig = BinaryToolConfig{}
+
+func (cfg BinaryToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	maxInline := cfg.Binary.MaxInlineBytes
+	if maxInline < 0 {
+		return nil, fmt.Errorf("invalid binary handling: maxInlineBytes must not be negative")
+	}
+	if maxInline == 0 {
+		maxInline = defaultMaxInlineBytes
+	}
+	var store artifactStore
+	if cfg.Binary.ArtifactStore != nil {
+		var err error
+		store, err = cfg.Binary.ArtifactStore.initialize()
+		if err != nil {
+			return nil, fmt.Errorf("invalid binary handling: %w", err)
+		}
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return binaryTool{toolWrapper: toolWrapper{t}, maxInline: maxInline, store: store}, nil
+}
+
+type binaryTool struct {
+	toolWrapper
+	maxInline int
+	store     artifactStore
+}
+
+func (t binaryTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return res, err
+	}
+	return t.encode(ctx, res)
+}
+
+// Stream forwards to the wrapped tool, encoding the binary values of each row
+// before it's sent.
+func (t binaryTool) Stream(ctx context.Context, params ParamValues, send func(row any) error) error {
+	return streamTool(ctx, t.Tool, params, func(row any) error {
+		row, err := t.encode(ctx, row)
+		if err != nil {
+			return err
+		}
+		return send(row)
+	})
+}
+
+// encode replaces the binary values in v, which are modified in place.
+func (t binaryTool) encode(ctx context.Context, v any) (any, error) {
+	var err error
+	switch v := v.(type) {
+	case []byte:
+		return t.encodeBytes(ctx, v)
+	case []any:
+		for i := range v {
+			if v[i], err = t.encode(ctx, v[i]); err != nil {
+				return nil, err
+			}
+		}
+	case []map[string]any:
+		for _, m := range v {
+			if _, err = t.encode(ctx, m); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		for k, e := range v {
+			if v[k], err = t.encode(ctx, e); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+func (t binaryTool) encodeBytes(ctx context.Context, data []byte) (BinaryValue, error) {
+	val := BinaryValue{Size: len(data), MimeType: http.DetectContentType(data)}
+	if len(data) <= t.maxInline {
+		val.Base64 = base64.StdEncoding.EncodeToString(data)
+		return val, nil
+	}
+	if t.store == nil {
+		val.Omitted = true
+		return val, nil
+	}
+	sum := sha256.Sum256(data)
+	u, err := t.store.put(ctx, hex.EncodeToString(sum[:]), val.MimeType, data)
+	if err != nil {
+		return val, fmt.Errorf("unable to store binary value: %w", err)
+	}
+	val.URL = u
+	return val, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+type rowsTool struct {
+	fakeTool
+	rows func() any
+}
+
+func (t rowsTool) Invoke(context.Context, tools.ParamValues) (any, error) {
+	return t.rows(), nil
+}
+
+type rowsToolConfig struct {
+	tool rowsTool
+}
+
+func (c rowsToolConfig) ToolConfigKind() string { return "rows" }
+func (c rowsToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return c.tool, nil
+}
+
+func TestBinaryToolConfig(t *testing.T) {
+	small := []byte("hello")
+	large := bytes.Repeat([]byte{0}, 32)
+	rows := func() any {
+		return []any{
+			map[string]any{"id": 1, "small": small, "large": large, "name": "a"},
+			[]any{small, nil},
+		}
+	}
+	dir := t.TempDir()
+
+	tcs := []struct {
+		desc   string
+		binary tools.BinaryHandling
+		want   func(largeURL string) any
+	}{
+		{
+			desc:   "large values are omitted without a store",
+			binary: tools.BinaryHandling{MaxInlineBytes: 16},
+			want: func(string) any {
+				return []any{
+					map[string]any{
+						"id":    1,
+						"small": tools.BinaryValue{Base64: "aGVsbG8=", Size: 5, MimeType: "text/plain; charset=utf-8"},
+						"large": tools.BinaryValue{Size: 32, MimeType: "application/octet-stream", Omitted: true},
+						"name":  "a",
+					},
+					[]any{tools.BinaryValue{Base64: "aGVsbG8=", Size: 5, MimeType: "text/plain; charset=utf-8"}, nil},
+				}
+			},
+		},
+		{
+			desc: "large values are written to the store",
+			binary: tools.BinaryHandling{
+				MaxInlineBytes: 16,
+				ArtifactStore:  &tools.ArtifactStoreConfig{Kind: tools.ArtifactStoreLocal, Path: dir},
+			},
+			want: func(largeURL string) any {
+				return []any{
+					map[string]any{
+						"id":    1,
+						"small": tools.BinaryValue{Base64: "aGVsbG8=", Size: 5, MimeType: "text/plain; charset=utf-8"},
+						"large": tools.BinaryValue{URL: largeURL, Size: 32, MimeType: "application/octet-stream"},
+						"name":  "a",
+					},
+					[]any{tools.BinaryValue{Base64: "aGVsbG8=", Size: 5, MimeType: "text/plain; charset=utf-8"}, nil},
+				}
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := tools.BinaryToolConfig{
+				ToolConfig: rowsToolConfig{tool: rowsTool{rows: rows}},
+				Binary:     tc.binary,
+			}
+			tool, err := cfg.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := tool.Invoke(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			largeURL := got.([]any)[0].(map[string]any)["large"].(tools.BinaryValue).URL
+			if diff := cmp.Diff(tc.want(largeURL), got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+			if largeURL == "" {
+				return
+			}
+			u, err := url.Parse(largeURL)
+			if err != nil || u.Scheme != "file" {
+				t.Fatalf("unexpected url %q", largeURL)
+			}
+			stored, err := os.ReadFile(u.Path)
+			if err != nil {
+				t.Fatalf("unable to read stored value: %s", err)
+			}
+			if !bytes.Equal(stored, large) {
+				t.Fatalf("stored value does not match")
+			}
+		})
+	}
+}
+
+func TestBinaryToolConfigInvalid(t *testing.T) {
+	tcs := []tools.BinaryHandling{
+		{MaxInlineBytes: -1},
+		{ArtifactStore: &tools.ArtifactStoreConfig{Kind: "s3"}},
+		{ArtifactStore: &tools.ArtifactStoreConfig{Kind: tools.ArtifactStoreLocal}},
+		{ArtifactStore: &tools.ArtifactStoreConfig{Kind: tools.ArtifactStoreGCS}},
+	}
+	for _, tc := range tcs {
+		cfg := tools.BinaryToolConfig{ToolConfig: rowsToolConfig{}, Binary: tc}
+		if _, err := cfg.Initialize(nil); err == nil {
+			t.Errorf("expected error for %+v", tc)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Reducers of results that exceed a response budget.
+const (
+	ReducerHead      string = "head"
+	ReducerSummarize string = "summarize"
+)
+
+// bytesPerToken is the rough number of bytes of JSON per model token, used to
+// turn a token budget into a byte budget.
+const bytesPerToken = 4
+
+// maxSummaryInputBytes caps how much of an oversized result is sent to the
+// model that summarizes it.
+const maxSummaryInputBytes = 512 * 1024
+
+// ResponseBudget configures the largest response a tool returns, and how
+// results that exceed it are reduced.
+type ResponseBudget struct {
+	// MaxBytes is the size of the largest JSON encoded result.
+	MaxBytes int `yaml:"maxBytes"`
+	// MaxTokens is the budget in model tokens, estimated at 4 bytes per token.
+	// If both are set, the smaller budget applies.
+	MaxTokens int `yaml:"maxTokens"`
+	// Reducer is either "head", the default, or "summarize".
+	Reducer string `yaml:"reducer"`
+	// Model is the language model of the "summarize" reducer.
+	Model *llm.Config `yaml:"model"`
+	// Instruction is added to the summarization prompt, e.g. to name the
+	// figures that matter most.
+	Instruction string `yaml:"instruction"`
+}
+
+// maxBytes returns the budget in bytes.
+func (b ResponseBudget) maxBytes() (int, error) {
+	if b.MaxBytes < 0 || b.MaxTokens < 0 {
+		return 0, fmt.Errorf("maxBytes and maxTokens must not be negative")
+	}
+	limit := b.MaxBytes
+	if tokenBytes := b.MaxTokens * bytesPerToken; tokenBytes > 0 && (limit == 0 || tokenBytes < limit) {
+		limit = tokenBytes
+	}
+	if limit == 0 {
+		return 0, fmt.Errorf("one of maxBytes or maxTokens is required")
+	}
+	return limit, nil
+}
+
+func (b ResponseBudget) reducer(ctx context.Context) (ResponseReducer, error) {
+	switch b.Reducer {
+	case "", ReducerHead:
+		if b.Model != nil {
+			return nil, fmt.Errorf("a model can only be used with the %q reducer", ReducerSummarize)
+		}
+		return HeadReducer{}, nil
+	case ReducerSummarize:
+		if b.Model == nil {
+			return nil, fmt.Errorf("the %q reducer requires a model", ReducerSummarize)
+		}
+		model, err := b.Model.Initialize(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize model: %w", err)
+		}
+		return NewSummarizeReducer(model, b.Instruction), nil
+	default:
+		return nil, fmt.Errorf("unknown reducer %q, must be %q or %q", b.Reducer, ReducerHead, ReducerSummarize)
+	}
+}
+
+// ReducedResponse replaces a result that exceeded a tool's response budget.
+type ReducedResponse struct {
+	// Reduced is always true, so agents can tell the result is incomplete.
+	Reduced bool   `json:"reduced"`
+	Reducer string `json:"reducer"`
+	// OriginalBytes is the size of the JSON encoded result.
+	OriginalBytes int `json:"originalBytes"`
+	// TotalRows is the number of rows of the result, if it's a list of rows.
+	TotalRows int `json:"totalRows,omitempty"`
+	// Rows are the leading rows of the result that fit the budget.
+	Rows []any `json:"rows,omitempty"`
+	// Stats summarizes each column of the rows of the result.
+	Stats map[string]ColumnStats `json:"stats,omitempty"`
+	// Summary is a summary of the result written by a model.
+	Summary string `json:"summary,omitempty"`
+	// Truncated is the beginning of the JSON encoding of a result that isn't
+	// a list of rows.
+	Truncated string `json:"truncated,omitempty"`
+}
+
+// ColumnStats are aggregate statistics of a column over all rows of a result.
+// Min, Max and Mean are only set for columns whose values are all numbers.
+type ColumnStats struct {
+	NonNull int      `json:"nonNull"`
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Mean    *float64 `json:"mean,omitempty"`
+}
+
+// ResponseReducer reduces a result whose JSON encoding is size bytes so that
+// it fits within maxBytes.
+type ResponseReducer interface {
+	Reduce(ctx context.Context, res any, size, maxBytes int) (ReducedResponse, error)
+}
+
+// HeadReducer keeps as many leading rows of a result as fit the budget, along
+// with statistics of every column. Results that aren't lists of rows are
+// truncated.
+type HeadReducer struct{}
+
+func (HeadReducer) Reduce(_ context.Context, res any, size, maxBytes int) (ReducedResponse, error) {
+	out := ReducedResponse{Reduced: true, Reducer: ReducerHead, OriginalBytes: size}
+	rows, ok := resultRows(res)
+	if !ok {
+		b, err := json.Marshal(res)
+		if err != nil {
+			return out, err
+		}
+		out.Truncated = string(b)
+		fitText(&out, &out.Truncated, maxBytes)
+		return out, nil
+	}
+	out.TotalRows = len(rows)
+	out.Stats = columnStats(rows)
+	n := fittingRows(out, rows, maxBytes)
+	if n == 0 && encodedSize(out) > maxBytes {
+		// the statistics alone exceed the budget
+		out.Stats = nil
+		n = fittingRows(out, rows, maxBytes)
+	}
+	out.Rows = rows[:n]
+	return out, nil
+}
+
+// fittingRows returns the largest number of leading rows that can be added to
+// out without exceeding maxBytes.
+func fittingRows(out ReducedResponse, rows []any, maxBytes int) int {
+	tooLarge := sort.Search(len(rows)+1, func(n int) bool {
+		out.Rows = rows[:n]
+		return encodedSize(out) > maxBytes
+	})
+	return max(tooLarge-1, 0)
+}
+
+// NewSummarizeReducer returns a ResponseReducer that asks model to summarize
+// results. instruction, if not empty, is added to the prompt.
+func NewSummarizeReducer(model llm.Model, instruction string) ResponseReducer {
+	return summarizeReducer{model: model, instruction: instruction}
+}
+
+type summarizeReducer struct {
+	model       llm.Model
+	instruction string
+}
+
+func (r summarizeReducer) Reduce(ctx context.Context, res any, size, maxBytes int) (ReducedResponse, error) {
+	out := ReducedResponse{Reduced: true, Reducer: ReducerSummarize, OriginalBytes: size}
+	if rows, ok := resultRows(res); ok {
+		out.TotalRows = len(rows)
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return out, err
+	}
+	prompt := string(b)
+	if len(prompt) > maxSummaryInputBytes {
+		prompt = truncateUTF8(prompt, maxSummaryInputBytes) + "\n(the result was truncated)"
+	}
+	system := fmt.Sprintf("You summarize the JSON result of a tool call for an AI agent whose context window can't hold the full result. "+
+		"Report the figures that matter exactly, mention notable outliers, and state how many rows there are. "+
+		"Answer in plain text of at most %d characters.", maxBytes/2)
+	if r.instruction != "" {
+		system += " " + r.instruction
+	}
+	summary, err := r.model.Generate(ctx, llm.Request{System: system, Prompt: prompt})
+	if err != nil {
+		return out, fmt.Errorf("unable to summarize result: %w", err)
+	}
+	out.Summary = summary
+	fitText(&out, &out.Summary, maxBytes)
+	return out, nil
+}
+
+// fitText shortens text, a field of out, until out fits within maxBytes.
+func fitText(out *ReducedResponse, text *string, maxBytes int) {
+	for over := encodedSize(out) - maxBytes; over > 0 && *text != ""; over = encodedSize(out) - maxBytes {
+		// escaping can make the encoding of text longer than text itself
+		*text = truncateUTF8(*text, len(*text)-over)
+	}
+}
+
+// resultRows returns the rows of a result that is a list.
+func resultRows(res any) ([]any, bool) {
+	switch res := res.(type) {
+	case []any:
+		return res, true
+	case []map[string]any:
+		rows := make([]any, len(res))
+		for i, r := range res {
+			rows[i] = r
+		}
+		return rows, true
+	}
+	return nil, false
+}
+
+func columnStats(rows []any) map[string]ColumnStats {
+	type acc struct {
+		nonNull, numeric int
+		min, max, sum    float64
+	}
+	accs := make(map[string]*acc)
+	for _, r := range rows {
+		row, ok := r.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for col, v := range row {
+			a, ok := accs[col]
+			if !ok {
+				a = &acc{}
+				accs[col] = a
+			}
+			if v == nil {
+				continue
+			}
+			a.nonNull++
+			f, ok := toFloat64(v)
+			if !ok {
+				continue
+			}
+			if a.numeric == 0 || f < a.min {
+				a.min = f
+			}
+			if a.numeric == 0 || f > a.max {
+				a.max = f
+			}
+			a.sum += f
+			a.numeric++
+		}
+	}
+	if len(accs) == 0 {
+		return nil
+	}
+	stats := make(map[string]ColumnStats, len(accs))
+	for col, a := range accs {
+		s := ColumnStats{NonNull: a.nonNull}
+		if a.numeric > 0 && a.numeric == a.nonNull {
+			mean := a.sum / float64(a.numeric)
+			s.Min, s.Max, s.Mean = &a.min, &a.max, &mean
+		}
+		stats[col] = s
+	}
+	return stats
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	if i, ok := toInt64(v); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
+
+func encodedSize(v any) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// truncateUTF8 returns the longest prefix of s of at most n bytes that
+// doesn't split a character.
+func truncateUTF8(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// BudgetToolConfig wraps a ToolConfig so results of the tool it initializes
+// that exceed Budget are reduced.
+type BudgetToolConfig struct {
+	ToolConfig
+	Budget ResponseBudget
+}
+
+// validate interface
+var _ ToolConfig = BudgetToolConfig{}
+
+func (cfg BudgetToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	maxBytes, err := cfg.Budget.maxBytes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid response budget: %w", err)
+	}
+	reducer, err := cfg.Budget.reducer(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("invalid response budget: %w", err)
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return budgetTool{toolWrapper: toolWrapper{t}, maxBytes: maxBytes, reducer: reducer}, nil
+}
+
+type budgetTool struct {
+	toolWrapper
+	maxBytes int
+	reducer  ResponseReducer
+}
+
+func (t budgetTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil {
+		return res, err
+	}
+	b, err := json.Marshal(res)
+	if err != nil || len(b) <= t.maxBytes {
+		// results that can't be encoded fail as usual when they're returned
+		return res, nil
+	}
+	reduced, err := t.reducer.Reduce(ctx, res, len(b), t.maxBytes)
+	if err != nil {
+		if _, ok := t.reducer.(HeadReducer); ok {
+			return nil, err
+		}
+		if logger, lerr := util.LoggerFromContext(ctx); lerr == nil {
+			logger.WarnContext(ctx, fmt.Sprintf("%s, keeping its leading rows instead", err))
+		}
+		return HeadReducer{}.Reduce(ctx, res, len(b), t.maxBytes)
+	}
+	return reduced, nil
+}
+
+// CanStream is false since reducing a result takes all of its rows, so the
+// results of tools with a budget are never streamed.
+func (t budgetTool) CanStream() bool {
+	return false
+}
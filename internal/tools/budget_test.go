@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func manyRows(n int) []any {
+	rows := make([]any, n)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i + 1, "name": fmt.Sprintf("row %d", i+1), "note": nil}
+	}
+	return rows
+}
+
+func TestBudgetToolConfig(t *testing.T) {
+	tcs := []struct {
+		desc     string
+		budget   tools.ResponseBudget
+		rows     any
+		wantRows int
+	}{
+		{desc: "within budget", budget: tools.ResponseBudget{MaxBytes: 10000}, rows: manyRows(3), wantRows: -1},
+		{desc: "rows are cut to the byte budget", budget: tools.ResponseBudget{MaxBytes: 600}, rows: manyRows(100), wantRows: 11},
+		{desc: "rows are cut to the token budget", budget: tools.ResponseBudget{MaxBytes: 10000, MaxTokens: 150}, rows: manyRows(100), wantRows: 11},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := tools.BudgetToolConfig{
+				ToolConfig: rowsToolConfig{tool: rowsTool{rows: func() any { return tc.rows }}},
+				Budget:     tc.budget,
+			}
+			tool, err := cfg.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := tool.Invoke(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantRows < 0 {
+				if diff := cmp.Diff(tc.rows, got); diff != "" {
+					t.Fatalf("expected the result to be unchanged: diff %v", diff)
+				}
+				return
+			}
+			reduced, ok := got.(tools.ReducedResponse)
+			if !ok {
+				t.Fatalf("expected a reduced response, got %T", got)
+			}
+			b, _ := json.Marshal(reduced)
+			if len(b) > 600 {
+				t.Errorf("reduced response of %d bytes exceeds the budget", len(b))
+			}
+			if !reduced.Reduced || reduced.Reducer != tools.ReducerHead || reduced.TotalRows != 100 {
+				t.Errorf("unexpected reduced response: %+v", reduced)
+			}
+			if len(reduced.Rows) != tc.wantRows {
+				t.Errorf("unexpected number of rows: want %d, got %d", tc.wantRows, len(reduced.Rows))
+			}
+			id := reduced.Stats["id"]
+			if id.NonNull != 100 || *id.Min != 1 || *id.Max != 100 || *id.Mean != 50.5 {
+				t.Errorf("unexpected stats of id: %+v", id)
+			}
+			if name := reduced.Stats["name"]; name.NonNull != 100 || name.Min != nil {
+				t.Errorf("unexpected stats of name: %+v", name)
+			}
+		})
+	}
+}
+
+func TestHeadReducerTruncatesOtherResults(t *testing.T) {
+	res := map[string]any{"text": strings.Repeat("é", 500)}
+	got, err := tools.HeadReducer{}.Reduce(context.Background(), res, 1010, 200)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.Truncated == "" || !strings.HasPrefix(`{"text":"`+strings.Repeat("é", 500), got.Truncated) {
+		t.Errorf("unexpected truncated result: %q", got.Truncated)
+	}
+	if b, _ := json.Marshal(got); len(b) > 200 {
+		t.Errorf("reduced response of %d bytes exceeds the budget", len(b))
+	}
+}
+
+type fakeModel struct {
+	reqs *[]llm.Request
+	err  error
+}
+
+func (m fakeModel) Generate(_ context.Context, req llm.Request) (string, error) {
+	*m.reqs = append(*m.reqs, req)
+	return "100 rows with ids 1 to 100", m.err
+}
+
+func TestSummarizeReducer(t *testing.T) {
+	var reqs []llm.Request
+	reducer := tools.NewSummarizeReducer(fakeModel{reqs: &reqs}, "Focus on the ids.")
+	got, err := reducer.Reduce(context.Background(), manyRows(100), 4000, 400)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.ReducedResponse{
+		Reduced:       true,
+		Reducer:       tools.ReducerSummarize,
+		OriginalBytes: 4000,
+		TotalRows:     100,
+		Summary:       "100 rows with ids 1 to 100",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected reduced response: diff %v", diff)
+	}
+	if len(reqs) != 1 || !strings.HasSuffix(reqs[0].System, "Focus on the ids.") || !strings.Contains(reqs[0].Prompt, `"row 100"`) {
+		t.Errorf("unexpected model request: %+v", reqs)
+	}
+}
+
+func TestBudgetToolConfigInvalid(t *testing.T) {
+	tcs := []tools.ResponseBudget{
+		{},
+		{MaxBytes: -1},
+		{MaxBytes: 100, Reducer: "tail"},
+		{MaxBytes: 100, Reducer: tools.ReducerSummarize},
+		{MaxBytes: 100, Model: &llm.Config{Kind: llm.KindVertexAI}},
+	}
+	for _, tc := range tcs {
+		cfg := tools.BudgetToolConfig{ToolConfig: rowsToolConfig{}, Budget: tc}
+		if _, err := cfg.Initialize(nil); err == nil {
+			t.Errorf("expected error for %+v", tc)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// ResultEncodingColumnar is the name of the columnar encoding of results,
+// which clients ask for to receive results that are lists of rows as columns.
+const ResultEncodingColumnar string = "columnar"
+
+// ColumnarResult is a list of rows encoded column by column. Column names are
+// sent once instead of once per row, and columns whose values repeat are
+// dictionary encoded, which shrinks wide, denormalized results considerably.
+type ColumnarResult struct {
+	Encoding string           `json:"encoding"`
+	RowCount int              `json:"rowCount"`
+	Columns  []ColumnarColumn `json:"columns"`
+}
+
+// ColumnarColumn holds the values of a column, either as Values, or as a
+// Dictionary of distinct values and the index in it of each row's value.
+// Rows without the column have a null value.
+type ColumnarColumn struct {
+	Name       string `json:"name"`
+	Values     []any  `json:"values,omitempty"`
+	Dictionary []any  `json:"dictionary,omitempty"`
+	Indexes    []int  `json:"indexes,omitempty"`
+}
+
+// EncodeColumnar encodes a result that is a list of rows. It reports false if
+// the result isn't a list of objects, and should be sent as is.
+func EncodeColumnar(res any) (ColumnarResult, bool) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return ColumnarResult{}, false
+	}
+	var rows []map[string]any
+	if err := util.DecodeJSON(bytes.NewReader(b), &rows); err != nil {
+		return ColumnarResult{}, false
+	}
+
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		if row == nil {
+			return ColumnarResult{}, false
+		}
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	out := ColumnarResult{Encoding: ResultEncodingColumnar, RowCount: len(rows), Columns: make([]ColumnarColumn, 0, len(names))}
+	for _, name := range names {
+		out.Columns = append(out.Columns, encodeColumn(name, rows))
+	}
+	return out, true
+}
+
+// encodeColumn dictionary encodes a column if its values repeat at least
+// twice on average.
+func encodeColumn(name string, rows []map[string]any) ColumnarColumn {
+	col := ColumnarColumn{Name: name}
+	dict := make(map[string]int)
+	indexes := make([]int, len(rows))
+	values := make([]any, len(rows))
+	for i, row := range rows {
+		v := row[name]
+		values[i] = v
+		key, err := json.Marshal(v)
+		if err != nil {
+			return ColumnarColumn{Name: name, Values: values}
+		}
+		idx, ok := dict[string(key)]
+		if !ok {
+			idx = len(col.Dictionary)
+			dict[string(key)] = idx
+			col.Dictionary = append(col.Dictionary, v)
+		}
+		indexes[i] = idx
+	}
+	if len(rows) < 2 || len(col.Dictionary)*2 > len(rows) {
+		return ColumnarColumn{Name: name, Values: values}
+	}
+	col.Indexes = indexes
+	return col
+}
+
+// Rows decodes the result back into a list of rows.
+func (c ColumnarResult) Rows() []map[string]any {
+	rows := make([]map[string]any, c.RowCount)
+	for i := range rows {
+		rows[i] = make(map[string]any, len(c.Columns))
+		for _, col := range c.Columns {
+			switch {
+			case col.Indexes != nil && i < len(col.Indexes) && col.Indexes[i] < len(col.Dictionary):
+				rows[i][col.Name] = col.Dictionary[col.Indexes[i]]
+			case i < len(col.Values):
+				rows[i][col.Name] = col.Values[i]
+			default:
+				rows[i][col.Name] = nil
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestEncodeColumnar(t *testing.T) {
+	rows := make([]any, 0)
+	for i := 0; i < 100; i++ {
+		rows = append(rows, map[string]any{"id": i, "region": []string{"EU", "US"}[i%2], "note": nil})
+	}
+	// a sparse row
+	rows = append(rows, map[string]any{"id": 100, "region": "EU", "extra": true})
+
+	got, ok := tools.EncodeColumnar(rows)
+	if !ok {
+		t.Fatalf("expected rows to be encoded")
+	}
+	if got.Encoding != tools.ResultEncodingColumnar || got.RowCount != 101 {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+	names := make([]string, 0)
+	for _, c := range got.Columns {
+		names = append(names, c.Name)
+	}
+	if diff := cmp.Diff([]string{"extra", "id", "note", "region"}, names); diff != "" {
+		t.Fatalf("unexpected columns: diff %v", diff)
+	}
+
+	region := got.Columns[3]
+	if diff := cmp.Diff([]any{"EU", "US"}, region.Dictionary); diff != "" || len(region.Indexes) != 101 || region.Values != nil {
+		t.Errorf("expected region to be dictionary encoded, got %+v", region)
+	}
+	if id := got.Columns[1]; id.Dictionary != nil || len(id.Values) != 101 {
+		t.Errorf("expected id to hold values, got %+v", id)
+	}
+
+	// the encoding round trips through JSON, with missing values as nulls
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("unable to marshal: %s", err)
+	}
+	var decoded tools.ColumnarResult
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	decodedRows := decoded.Rows()
+	if len(decodedRows) != 101 || decodedRows[3]["region"] != "US" || decodedRows[100]["extra"] != true || decodedRows[0]["extra"] != nil {
+		t.Errorf("unexpected decoded rows: %v", decodedRows)
+	}
+
+	plain, _ := json.Marshal(rows)
+	if len(b) >= len(plain) {
+		t.Errorf("expected the columnar encoding to be smaller: %d >= %d bytes", len(b), len(plain))
+	}
+}
+
+func TestEncodeColumnarNotRows(t *testing.T) {
+	for _, res := range []any{"text", []any{"a", "b"}, map[string]any{"a": 1}, []any{nil}} {
+		if _, ok := tools.EncodeColumnar(res); ok {
+			t.Errorf("expected %v not to be encoded", res)
+		}
+	}
+}
@@ -18,8 +18,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"regexp"
 	"text/template"
+	"time"
+
+	"cloud.google.com/go/civil"
 )
 
 var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]*$`)
@@ -72,10 +76,72 @@ func ConvertAnySliceToTyped(s []any, itemType string) (any, error) {
 			tempSlice[j] = b
 		}
 		typedSlice = tempSlice
+	case typeTimestamp:
+		tempSlice := make([]time.Time, len(s))
+		for j, item := range s {
+			t, ok := item.(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("expected item at index %d to be timestamp, got %T", j, item)
+			}
+			tempSlice[j] = t
+		}
+		typedSlice = tempSlice
+	case typeDate:
+		tempSlice := make([]civil.Date, len(s))
+		for j, item := range s {
+			d, err := ConvertToNativeValue(typeDate, item)
+			if err != nil {
+				return nil, fmt.Errorf("expected item at index %d to be date: %w", j, err)
+			}
+			tempSlice[j] = d.(civil.Date)
+		}
+		typedSlice = tempSlice
+	case typeDecimal:
+		tempSlice := make([]*big.Rat, len(s))
+		for j, item := range s {
+			r, err := ConvertToNativeValue(typeDecimal, item)
+			if err != nil {
+				return nil, fmt.Errorf("expected item at index %d to be decimal: %w", j, err)
+			}
+			tempSlice[j] = r.(*big.Rat)
+		}
+		typedSlice = tempSlice
 	}
 	return typedSlice, nil
 }
 
+// ConvertToNativeValue converts the parsed value of a date or decimal parameter
+// to the civil.Date or *big.Rat that the Google Cloud client libraries bind as
+// DATE and NUMERIC. Values of other types are returned unchanged.
+func ConvertToNativeValue(paramType string, v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch paramType {
+	case typeDate:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a date string, got %T", v)
+		}
+		d, err := civil.ParseDate(s)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	case typeDecimal:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a decimal string, got %T", v)
+		}
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a decimal number", s)
+		}
+		return r, nil
+	}
+	return v, nil
+}
+
 // convertParamToJSON  is a Go template helper function to convert a parameter to JSON formatted string.
 func convertParamToJSON(param any) (string, error) {
 	jsonData, err := json.Marshal(param)
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// DictionaryToolConfig wraps a ToolConfig to add the data dictionary entries
+// of tables its tool reads to its description.
+type DictionaryToolConfig struct {
+	ToolConfig
+	// Source is the name of the tool's source, whose dictionary is used.
+	Source string
+	Tables []string
+}
+
+func (cfg DictionaryToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	if len(cfg.Tables) == 0 {
+		return nil, fmt.Errorf("dictionary must list at least one table")
+	}
+	if cfg.Source == "" {
+		return nil, fmt.Errorf("dictionary is only supported by tools with a source")
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	d := dictionary.FromSource(srcs[cfg.Source])
+	if d == nil {
+		return nil, fmt.Errorf("invalid dictionary: source %q has no data dictionary", cfg.Source)
+	}
+	description, err := d.Describe(cfg.Tables...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dictionary: %w", err)
+	}
+	return dictionaryTool{toolWrapper: toolWrapper{t}, dictionary: description}, nil
+}
+
+type dictionaryTool struct {
+	toolWrapper
+	dictionary string
+}
+
+func (t dictionaryTool) describe(description string) string {
+	if description == "" {
+		return t.dictionary
+	}
+	return description + "\n\n" + t.dictionary
+}
+
+func (t dictionaryTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Description = t.describe(m.Description)
+	return m
+}
+
+func (t dictionaryTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	m.Description = t.describe(m.Description)
+	return m
+}
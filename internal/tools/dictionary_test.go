@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+type dictionarySource struct {
+	dictionary dictionary.Dictionary
+}
+
+func (s dictionarySource) SourceKind() string                    { return "fake" }
+func (s dictionarySource) DataDictionary() dictionary.Dictionary { return s.dictionary }
+
+func TestDictionaryToolConfig(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my_source": dictionarySource{dictionary: dictionary.Dictionary{
+			"orders": {
+				Description: "One row per order.",
+				Columns:     map[string]dictionary.Column{"total": {Description: "Order total.", Unit: "USD"}},
+			},
+		}},
+		"other_source": dictionarySource{},
+	}
+	cfg := tools.DictionaryToolConfig{
+		ToolConfig: staticToolConfig{tool: fakeTool{name: "my_tool"}},
+		Source:     "my_source",
+		Tables:     []string{"orders"},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	want := "my_tool description\n\nTable orders: One row per order.\n  total: Order total. Unit: USD."
+	if got := tool.Manifest().Description; got != want {
+		t.Fatalf("incorrect description: got %q, want %q", got, want)
+	}
+	if got := tool.McpManifest().Description; got != want {
+		t.Fatalf("incorrect mcp description: got %q, want %q", got, want)
+	}
+
+	for desc, cfg := range map[string]tools.DictionaryToolConfig{
+		"no tables":     {ToolConfig: cfg.ToolConfig, Source: "my_source"},
+		"no source":     {ToolConfig: cfg.ToolConfig, Tables: []string{"orders"}},
+		"no dictionary": {ToolConfig: cfg.ToolConfig, Source: "other_source", Tables: []string{"orders"}},
+		"unknown table": {ToolConfig: cfg.ToolConfig, Source: "my_source", Tables: []string{"refunds"}},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			if _, err := cfg.Initialize(srcs); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
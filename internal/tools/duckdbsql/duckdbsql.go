@@ -74,6 +74,11 @@ func (c Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	statement, params, err := tools.BindNamedParams(c.Statement, tools.DollarPlaceholders, c.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement for %q tool: %w", kind, err)
+	}
+
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(c.TemplateParameters, c.Parameters)
 
 	mcpManifest := tools.McpManifest{
@@ -86,10 +91,10 @@ func (c Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	t := Tool{
 		Name:               c.Name,
 		Kind:               kind,
-		Parameters:         c.Parameters,
+		Parameters:         params,
 		TemplateParameters: c.TemplateParameters,
 		AllParams:          allParameters,
-		Statement:          c.Statement,
+		Statement:          statement,
 		AuthRequired:       c.AuthRequired,
 		Db:                 s.DuckDb(),
 		manifest:           tools.Manifest{Description: c.Description, Parameters: paramManifest, AuthRequired: c.AuthRequired},
@@ -126,6 +131,17 @@ func (t Tool) Authorized(verifiedAuthSources []string) bool {
 
 // Invoke implements tools.Tool.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	conn, err := t.Db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get connection: %w", err)
+	}
+	defer conn.Close()
+	return t.InvokeConn(ctx, conn, params)
+}
+
+// InvokeConn is like Invoke, but runs the statement on conn, so it can use
+// the temporary tables of the connection.
+func (t Tool) InvokeConn(ctx context.Context, conn *sql.Conn, params tools.ParamValues) (any, error) {
 	paramsMap := params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
@@ -138,58 +154,48 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 	}
 
 	sliceParams := newParams.AsSlice()
-	// Execute the SQL query with parameters
-	rows, err := t.Db.QueryContext(ctx, newStatement, sliceParams...)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+	// the driver only returns the rows of the last statement of a script, so
+	// the statements of a script are run one by one
+	if stmts := tools.SplitStatements(newStatement); len(stmts) > 1 {
+		if len(sliceParams) > 0 {
+			return nil, fmt.Errorf("parameters are not supported in statements with several queries, use template parameters instead")
+		}
+		return runScript(ctx, conn, stmts)
 	}
-	defer rows.Close()
 
-	// Get column names
-	cols, err := rows.Columns()
+	// Execute the SQL query with parameters
+	rows, err := conn.QueryContext(ctx, newStatement, sliceParams...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get column names: %w", err)
-	}
-
-	values := make([]any, len(cols))
-	valuePtrs := make([]any, len(cols))
-	for i := range values {
-		valuePtrs[i] = &values[i]
+		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
+	return tools.ScanResultSets(rows, tools.ScanRows)
+}
 
-	// Prepare the result slice
-	var result []any
-	// Iterate through the rows
-	for rows.Next() {
-		// Scan the row into the value pointers
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("unable to scan row: %w", err)
+// runScript runs the statements of a script on a single connection, so they
+// share the state of the session, and returns a result set for each.
+func runScript(ctx context.Context, conn *sql.Conn, stmts []string) (any, error) {
+	sets := make([]tools.ResultSet, 0, len(stmts))
+	for i, stmt := range stmts {
+		rows, err := conn.QueryContext(ctx, stmt)
+		if err != nil {
+			return nil, fmt.Errorf("unable to execute statement %d: %w", i+1, err)
 		}
-
-		// Create a map for this row
-		rowMap := make(map[string]interface{})
-		for i, col := range cols {
-			val := values[i]
-			// Handle nil values
-			if val == nil {
-				rowMap[col] = nil
-				continue
-			}
-			// Store the value in the map
-			rowMap[col] = val
+		cols, err := rows.ColumnTypes()
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("unable to get column types: %w", err)
 		}
-		result = append(result, rowMap)
-	}
-
-	if err = rows.Close(); err != nil {
-		return nil, fmt.Errorf("unable to close rows: %w", err)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+		out, err := tools.ScanRows(rows)
+		if err == nil {
+			err = rows.Err()
+		}
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error iterating rows of statement %d: %w", i+1, err)
+		}
+		sets = append(sets, tools.NewResultSet(i+1, cols, out))
 	}
-
-	return result, nil
+	return sets, nil
 }
 
 // Manifest implements tools.Tool.
@@ -202,9 +208,15 @@ func (t Tool) McpManifest() tools.McpManifest {
 	return t.mcpManifest
 }
 
+// Preview renders the statement the invocation would run.
+func (t Tool) Preview(ctx context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	return tools.PreviewStatement(t.TemplateParameters, t.Parameters, t.Statement, params)
+}
+
 // ParseParams implements tools.Tool.
 func (t Tool) ParseParams(data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claimsMap)
 }
 
 var _ tools.Tool = Tool{}
+var _ tools.PreviewableTool = Tool{}
@@ -15,6 +15,8 @@
 package duckdbsql_test
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 
 	"github.com/googleapis/genai-toolbox/internal/tools/duckdbsql"
@@ -22,6 +24,8 @@ import (
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/duckdb"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
@@ -85,3 +89,71 @@ func TestParseFromYamlDuckDb(t *testing.T) {
 		})
 	}
 }
+
+func TestInvokeScript(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+	srcs := map[string]sources.Source{"my-duckdb": &duckdb.Source{Name: "my-duckdb", Kind: duckdb.SourceKind, Db: db}}
+
+	cfg := duckdbsql.Config{
+		Name:        "script",
+		Kind:        "duckdb-sql",
+		Source:      "my-duckdb",
+		Description: "some description",
+		Statement: `CREATE TEMP TABLE hotels (id INTEGER, name VARCHAR);
+			INSERT INTO hotels VALUES (1, 'Hilton Basel'), (2, 'Hyatt Zurich');
+			-- the temp table is only visible if the statements share a connection
+			SELECT id, name FROM hotels ORDER BY id;
+			SELECT count(*) AS total FROM hotels;`,
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	got, err := tool.Invoke(context.Background(), tools.ParamValues{})
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	sets, ok := got.([]tools.ResultSet)
+	if !ok || len(sets) != 4 {
+		t.Fatalf("expected 4 result sets, got %#v", got)
+	}
+	want := []tools.ResultSet{
+		{
+			Label:    "resultSet3",
+			Columns:  []tools.ResultColumn{{Name: "id", Type: "INTEGER"}, {Name: "name", Type: "VARCHAR"}},
+			RowCount: 2,
+			Rows: []any{
+				map[string]any{"id": int32(1), "name": "Hilton Basel"},
+				map[string]any{"id": int32(2), "name": "Hyatt Zurich"},
+			},
+		},
+		{
+			Label:    "resultSet4",
+			Columns:  []tools.ResultColumn{{Name: "total", Type: "BIGINT"}},
+			RowCount: 1,
+			Rows:     []any{map[string]any{"total": int64(2)}},
+		},
+	}
+	if diff := cmp.Diff(want, sets[2:]); diff != "" {
+		t.Fatalf("incorrect result sets (-want +got):\n%s", diff)
+	}
+
+	// bound parameters can't be split between the statements of a script
+	cfg.Statement = "SELECT 1; SELECT $1"
+	cfg.Parameters = tools.Parameters{tools.NewIntParameter("id", "some id")}
+	tool, err = cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"id": 1}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	if _, err := tool.Invoke(context.Background(), params); err == nil {
+		t.Fatalf("expected an error for a script with bound parameters")
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duckdbvectorsearch
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/embeddings"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/duckdb"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "duckdb-vector-search"
+
+// Names of the parameters the tool adds to the configured ones.
+const (
+	embeddingParam = "embedding"
+	queryParam     = "query"
+	kParam         = "k"
+)
+
+// Distance metrics, named as in the VSS extension's HNSW index options.
+const (
+	MetricCosine string = "cosine"
+	MetricL2Sq   string = "l2sq"
+	MetricIP     string = "ip"
+)
+
+var distanceFuncs = map[string]string{
+	MetricCosine: "array_cosine_distance",
+	MetricL2Sq:   "array_distance",
+	MetricIP:     "array_negative_inner_product",
+}
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name, Metric: MetricCosine, DefaultK: 10, MaxK: 100}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	DuckDb() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &duckdb.Source{}
+var compatibleSources = [...]string{duckdb.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	// Table is the table to search, optionally qualified by its schema.
+	Table string `yaml:"table" validate:"required"`
+	// EmbeddingColumn is the FLOAT[Dimensions] column holding the embeddings.
+	EmbeddingColumn string `yaml:"embeddingColumn" validate:"required"`
+	Dimensions      int    `yaml:"dimensions" validate:"required,gt=0"`
+	Metric          string `yaml:"metric" validate:"oneof=cosine l2sq ip"`
+	DefaultK        int    `yaml:"defaultK" validate:"gt=0"`
+	MaxK            int    `yaml:"maxK" validate:"gt=0"`
+	// Filter is an optional SQL condition on the rows to search, with a `?`
+	// placeholder for each of the Parameters, in order.
+	Filter     string           `yaml:"filter"`
+	Parameters tools.Parameters `yaml:"parameters"`
+	// Embedding, if set, embeds a text query server-side, so clients send
+	// text instead of an embedding.
+	Embedding *embeddings.Config `yaml:"embedding"`
+}
+
+// Initialize implements tools.ToolConfig.
+func (c Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[c.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", c.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if c.DefaultK > c.MaxK {
+		return nil, fmt.Errorf("defaultK (%d) must not be greater than maxK (%d)", c.DefaultK, c.MaxK)
+	}
+	if c.Embedding != nil && c.Embedding.Dimensions != 0 && c.Embedding.Dimensions != c.Dimensions {
+		return nil, fmt.Errorf("embedding dimensions (%d) must match the tool's dimensions (%d)", c.Embedding.Dimensions, c.Dimensions)
+	}
+	if n := strings.Count(c.Filter, "?"); n != len(c.Parameters) {
+		return nil, fmt.Errorf("filter has %d placeholders but %d parameters are configured", n, len(c.Parameters))
+	}
+
+	var embedder embeddings.Embedder
+	vectorParam := tools.Parameter(tools.NewArrayParameter(embeddingParam, fmt.Sprintf("The embedding to find the nearest neighbors of, an array of %d numbers.", c.Dimensions), tools.NewFloatParameter("value", "A component of the embedding.")))
+	if c.Embedding != nil {
+		var err error
+		embedder, err = c.Embedding.Initialize(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize embedding model: %w", err)
+		}
+		vectorParam = tools.NewStringParameter(queryParam, "The text to find the nearest neighbors of.")
+	}
+	kp := tools.NewIntParameterWithDefault(kParam, c.DefaultK, fmt.Sprintf("The number of nearest neighbors to return, at most %d.", c.MaxK))
+	allParameters := tools.Parameters{vectorParam, kp}
+	for _, p := range c.Parameters {
+		if name := p.GetName(); name == vectorParam.GetName() || name == kParam {
+			return nil, fmt.Errorf("parameter name %q is reserved by the %q tool", name, kind)
+		}
+		allParameters = append(allParameters, p)
+	}
+
+	db := s.DuckDb()
+	if err := loadVSS(db); err != nil {
+		return nil, err
+	}
+
+	_, paramManifest, paramMcpManifest := tools.ProcessParameters(nil, allParameters)
+
+	mcpManifest := tools.McpManifest{
+		Name:        c.Name,
+		Description: c.Description,
+		InputSchema: paramMcpManifest,
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         c.Name,
+		Kind:         kind,
+		Parameters:   c.Parameters,
+		AllParams:    allParameters,
+		AuthRequired: c.AuthRequired,
+		Statement:    buildStatement(c),
+		Dimensions:   c.Dimensions,
+		MaxK:         c.MaxK,
+		Db:           db,
+		embedder:     embedder,
+		manifest:     tools.Manifest{Description: c.Description, Parameters: paramManifest, AuthRequired: c.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// loadVSS loads the VSS extension, installing it first if it isn't yet.
+func loadVSS(db *sql.DB) error {
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "LOAD vss"); err == nil {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "INSTALL vss"); err != nil {
+		return fmt.Errorf("unable to install vss extension: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, "LOAD vss"); err != nil {
+		return fmt.Errorf("unable to load vss extension: %w", err)
+	}
+	return nil
+}
+
+// buildStatement returns the nearest-neighbor query of the tool. The query
+// vector is its first placeholder, followed by the filter's and the limit.
+func buildStatement(c Config) string {
+	col := quoteIdentifier(c.EmbeddingColumn)
+	var b strings.Builder
+	fmt.Fprintf(&b, "SELECT * EXCLUDE (%s), %s(%s, ?::FLOAT[%d]) AS distance FROM %s", col, distanceFuncs[c.Metric], col, c.Dimensions, quoteQualified(c.Table))
+	if c.Filter != "" {
+		fmt.Fprintf(&b, " WHERE %s", c.Filter)
+	}
+	b.WriteString(" ORDER BY distance LIMIT ?")
+	return b.String()
+}
+
+func quoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func quoteQualified(s string) string {
+	parts := strings.Split(s, ".")
+	for i, p := range parts {
+		parts[i] = quoteIdentifier(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// ToolConfigKind implements tools.ToolConfig.
+func (c Config) ToolConfigKind() string {
+	return kind
+}
+
+var _ tools.ToolConfig = Config{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	AllParams    tools.Parameters `yaml:"allParams"`
+
+	Db          *sql.DB
+	Statement   string `yaml:"statement"`
+	Dimensions  int
+	MaxK        int
+	embedder    embeddings.Embedder
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Authorized implements tools.Tool.
+func (t Tool) Authorized(verifiedAuthSources []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthSources)
+}
+
+// Invoke implements tools.Tool.
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	paramsMap := params.AsMap()
+
+	vector, err := t.queryVector(ctx, paramsMap)
+	if err != nil {
+		return nil, err
+	}
+	if len(vector) != t.Dimensions {
+		return nil, fmt.Errorf("embedding has %d dimensions, want %d", len(vector), t.Dimensions)
+	}
+
+	k, ok := paramsMap[kParam].(int)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", kParam)
+	}
+	if k <= 0 || k > t.MaxK {
+		return nil, fmt.Errorf("%s must be between 1 and %d", kParam, t.MaxK)
+	}
+
+	filterParams, err := tools.GetParams(t.Parameters, paramsMap)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract standard params %w", err)
+	}
+
+	sliceParams := append([]any{vectorLiteral(vector)}, filterParams.AsSlice()...)
+	sliceParams = append(sliceParams, k)
+	rows, err := t.Db.QueryContext(ctx, t.Statement, sliceParams...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get column names: %w", err)
+	}
+
+	values := make([]any, len(cols))
+	valuePtrs := make([]any, len(cols))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	var result []any
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("unable to scan row: %w", err)
+		}
+		rowMap := make(map[string]interface{})
+		for i, col := range cols {
+			rowMap[col] = values[i]
+		}
+		result = append(result, rowMap)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, fmt.Errorf("unable to close rows: %w", err)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// queryVector returns the vector to search with, embedding the text query if
+// the tool has an embedding model.
+func (t Tool) queryVector(ctx context.Context, paramsMap map[string]any) ([]float32, error) {
+	if t.embedder != nil {
+		query, ok := paramsMap[queryParam].(string)
+		if !ok {
+			return nil, fmt.Errorf("unable to get cast %s", queryParam)
+		}
+		vector, err := t.embedder.Embed(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("unable to embed query: %w", err)
+		}
+		return vector, nil
+	}
+	raw, ok := paramsMap[embeddingParam].([]any)
+	if !ok {
+		return nil, fmt.Errorf("unable to get cast %s", embeddingParam)
+	}
+	vector := make([]float32, len(raw))
+	for i, v := range raw {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of numbers", embeddingParam)
+		}
+		vector[i] = float32(f)
+	}
+	return vector, nil
+}
+
+// vectorLiteral formats the vector as a DuckDB list literal, which the
+// statement casts to a fixed-size array.
+func vectorLiteral(vector []float32) string {
+	parts := make([]string, len(vector))
+	for i, f := range vector {
+		parts[i] = strconv.FormatFloat(float64(f), 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// Manifest implements tools.Tool.
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+// McpManifest implements tools.Tool.
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+// ParseParams implements tools.Tool.
+func (t Tool) ParseParams(data map[string]any, claimsMap map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.AllParams, data, claimsMap)
+}
+
+var _ tools.Tool = Tool{}
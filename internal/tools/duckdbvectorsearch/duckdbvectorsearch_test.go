@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duckdbvectorsearch_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/embeddings"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/duckdbvectorsearch"
+)
+
+func TestParseFromYamlDuckDbVectorSearch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: duckdb-vector-search
+					source: my-duckdb-instance
+					description: some description
+					table: docs
+					embeddingColumn: embedding
+					dimensions: 3
+			`,
+			want: server.ToolConfigs{
+				"example_tool": duckdbvectorsearch.Config{
+					Name:            "example_tool",
+					Kind:            "duckdb-vector-search",
+					Source:          "my-duckdb-instance",
+					Description:     "some description",
+					AuthRequired:    []string{},
+					Table:           "docs",
+					EmbeddingColumn: "embedding",
+					Dimensions:      3,
+					Metric:          "cosine",
+					DefaultK:        10,
+					MaxK:            100,
+				},
+			},
+		},
+		{
+			desc: "with filter and embedding model",
+			in: `
+			tools:
+				example_tool:
+					kind: duckdb-vector-search
+					source: my-duckdb-instance
+					description: some description
+					table: main.docs
+					embeddingColumn: embedding
+					dimensions: 768
+					metric: ip
+					defaultK: 5
+					maxK: 20
+					filter: category = ?
+					parameters:
+						- name: category
+						  type: string
+						  description: category to search in
+					embedding:
+						kind: vertexai
+						model: text-embedding-005
+						project: my-project
+			`,
+			want: server.ToolConfigs{
+				"example_tool": duckdbvectorsearch.Config{
+					Name:            "example_tool",
+					Kind:            "duckdb-vector-search",
+					Source:          "my-duckdb-instance",
+					Description:     "some description",
+					AuthRequired:    []string{},
+					Table:           "main.docs",
+					EmbeddingColumn: "embedding",
+					Dimensions:      768,
+					Metric:          "ip",
+					DefaultK:        5,
+					MaxK:            20,
+					Filter:          "category = ?",
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("category", "category to search in"),
+					},
+					Embedding: &embeddings.Config{
+						Kind:    "vertexai",
+						Model:   "text-embedding-005",
+						Project: "my-project",
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err = yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlDuckDbVectorSearch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+	}{
+		{
+			desc: "unknown metric",
+			in: `
+			tools:
+				example_tool:
+					kind: duckdb-vector-search
+					source: my-duckdb-instance
+					description: some description
+					table: docs
+					embeddingColumn: embedding
+					dimensions: 3
+					metric: manhattan
+			`,
+		},
+		{
+			desc: "missing dimensions",
+			in: `
+			tools:
+				example_tool:
+					kind: duckdb-vector-search
+					source: my-duckdb-instance
+					description: some description
+					table: docs
+					embeddingColumn: embedding
+			`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err == nil {
+				t.Fatalf("expect parsing to fail")
+			}
+		})
+	}
+}
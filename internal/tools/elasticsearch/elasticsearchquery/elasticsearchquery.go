@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	essrc "github.com/googleapis/genai-toolbox/internal/sources/elasticsearch"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "elasticsearch-query"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	ElasticsearchClient() *essrc.Client
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &essrc.Source{}
+
+var compatibleSources = [...]string{essrc.SourceKind}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Source       string           `yaml:"source" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	Index        string           `yaml:"index" validate:"required"`
+	Query        string           `yaml:"query" validate:"required"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		Index:        cfg.Index,
+		Query:        cfg.Query,
+		AuthRequired: cfg.AuthRequired,
+		Client:       s.ElasticsearchClient(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Client      *essrc.Client
+	Index       string
+	Query       string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	query, err := tools.PopulateTemplateWithJSON("ElasticsearchQuery", t.Query, params.AsMap())
+	if err != nil {
+		return nil, fmt.Errorf("error populating query: %s", err)
+	}
+	if !json.Valid([]byte(query)) {
+		return nil, fmt.Errorf("query is not valid JSON: %s", query)
+	}
+
+	resp, err := t.Client.Search(ctx, t.Index, []byte(query))
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	return hitsToRows(resp)
+}
+
+// searchResponse is the subset of a search response used to build rows.
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Index  string         `json:"_index"`
+			ID     string         `json:"_id"`
+			Score  *float64       `json:"_score"`
+			Source map[string]any `json:"_source"`
+			Fields map[string]any `json:"fields"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// hitsToRows converts the hits of a search response into row-style maps. Each
+// row holds the document's `_source` and `fields`, plus its `_id`, `_index`
+// and `_score`.
+func hitsToRows(resp []byte) ([]any, error) {
+	var r searchResponse
+	if err := json.Unmarshal(resp, &r); err != nil {
+		return nil, fmt.Errorf("unable to parse search response: %w", err)
+	}
+
+	rows := make([]any, 0, len(r.Hits.Hits))
+	for _, hit := range r.Hits.Hits {
+		row := make(map[string]any, len(hit.Source)+len(hit.Fields)+3)
+		for k, v := range hit.Fields {
+			row[k] = v
+		}
+		for k, v := range hit.Source {
+			row[k] = v
+		}
+		row["_id"] = hit.ID
+		row["_index"] = hit.Index
+		if hit.Score != nil {
+			row["_score"] = *hit.Score
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
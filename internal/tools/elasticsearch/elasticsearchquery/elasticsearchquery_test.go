@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchquery
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	essrc "github.com/googleapis/genai-toolbox/internal/sources/elasticsearch"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlElasticsearchQuery(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				search_logs:
+					kind: elasticsearch-query
+					source: my-es-instance
+					description: search logs by message
+					index: logs-*
+					query: |
+						{"query": {"match": {"message": {{json .text}}}}}
+					parameters:
+						- name: text
+						  type: string
+						  description: text to search for
+			`,
+			want: server.ToolConfigs{
+				"search_logs": Config{
+					Name:         "search_logs",
+					Kind:         "elasticsearch-query",
+					Source:       "my-es-instance",
+					Description:  "search logs by message",
+					Index:        "logs-*",
+					Query:        "{\"query\": {\"match\": {\"message\": {{json .text}}}}}\n",
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("text", "text to search for"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	var gotPath, gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version": {"number": "8.14.0"}}`))
+			return
+		}
+		gotPath = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		_, _ = w.Write([]byte(`{"hits": {"total": {"value": 1}, "hits": [
+			{"_index": "logs-1", "_id": "a1", "_score": 1.5, "_source": {"message": "disk full", "level": "error"}}
+		]}}`))
+	}))
+	defer ts.Close()
+
+	src, err := essrc.Config{Name: "es", Kind: essrc.SourceKind, Addresses: []string{ts.URL}}.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	tool, err := Config{
+		Name:        "search_logs",
+		Kind:        kind,
+		Source:      "es",
+		Description: "search logs",
+		Index:       "logs-*",
+		Query:       `{"query": {"match": {"message": {{json .text}}}}}`,
+		Parameters:  tools.Parameters{tools.NewStringParameter("text", "text to search for")},
+	}.Initialize(map[string]sources.Source{"es": src})
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	params, err := tool.ParseParams(map[string]any{"text": `disk "full"`}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+
+	if want := "/logs-*/_search"; gotPath != want {
+		t.Errorf("unexpected path: want %q, got %q", want, gotPath)
+	}
+	if want := `{"query": {"match": {"message": "disk \"full\""}}}`; gotBody != want {
+		t.Errorf("unexpected body: want %q, got %q", want, gotBody)
+	}
+	want := []any{
+		map[string]any{"_id": "a1", "_index": "logs-1", "_score": 1.5, "message": "disk full", "level": "error"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected rows: diff %v", diff)
+	}
+}
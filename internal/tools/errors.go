@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/querycost"
+)
+
+// ErrorCategory tells clients how to react to a failed tool call.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryValidation means the request was invalid; fix it and retry.
+	ErrorCategoryValidation ErrorCategory = "validation"
+	// ErrorCategoryAuth means the caller isn't allowed to invoke the tool.
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryRateLimit means the caller sent too many requests; retry later.
+	ErrorCategoryRateLimit ErrorCategory = "rate-limit"
+	// ErrorCategoryTimeout means the invocation didn't finish in time.
+	ErrorCategoryTimeout ErrorCategory = "timeout"
+	// ErrorCategorySourceUnavailable means the source couldn't be reached, or
+	// the server is shutting down.
+	ErrorCategorySourceUnavailable ErrorCategory = "source-unavailable"
+	// ErrorCategoryQuery means the source rejected or failed the operation.
+	ErrorCategoryQuery ErrorCategory = "query-error"
+	// ErrorCategoryInternal means the server failed unexpectedly.
+	ErrorCategoryInternal ErrorCategory = "internal"
+)
+
+// Machine-readable error codes. Each code belongs to a single category.
+const (
+	ErrorCodeToolNotFound         string = "TOOL_NOT_FOUND"
+	ErrorCodeToolDisabled         string = "TOOL_DISABLED"
+	ErrorCodeJobNotFound          string = "JOB_NOT_FOUND"
+	ErrorCodeInvalidRequest       string = "INVALID_REQUEST"
+	ErrorCodeInvalidParameters    string = "INVALID_PARAMETERS"
+	ErrorCodeUnauthorized         string = "UNAUTHORIZED"
+	ErrorCodePolicyDenied         string = "POLICY_DENIED"
+	ErrorCodeApprovalRejected     string = "APPROVAL_REJECTED"
+	ErrorCodeRateLimited          string = "RATE_LIMITED"
+	ErrorCodeDeadlineExceeded     string = "DEADLINE_EXCEEDED"
+	ErrorCodeCancelled            string = "CANCELLED"
+	ErrorCodeStreamStalled        string = "STREAM_STALLED"
+	ErrorCodeSourceUnavailable    string = "SOURCE_UNAVAILABLE"
+	ErrorCodeShuttingDown         string = "SHUTTING_DOWN"
+	ErrorCodeQueryFailed          string = "QUERY_FAILED"
+	ErrorCodeDeadlock             string = "DEADLOCK"
+	ErrorCodeSerializationFailure string = "SERIALIZATION_FAILURE"
+	ErrorCodeCostExceeded         string = "COST_EXCEEDED"
+	ErrorCodeInternal             string = "INTERNAL"
+)
+
+// ToolError is a classified error returned to clients. Tools may return a
+// ToolError from Invoke to classify an error themselves; any other error is
+// classified by ClassifyError.
+type ToolError struct {
+	Category ErrorCategory `json:"category"`
+	Code     string        `json:"code"`
+	// Detail is a message that is safe to show to the client.
+	Detail string `json:"detail"`
+	// Retryable reports whether the same request may succeed later.
+	Retryable bool `json:"retryable"`
+
+	Err error `json:"-"`
+}
+
+// codeDetails are the details of errors created without one, by code. The
+// messages of the errors they wrap may come from drivers and reveal
+// statements, hosts or schema names, so they are only logged.
+var codeDetails = map[string]string{
+	ErrorCodeToolNotFound:         "the tool does not exist",
+	ErrorCodeToolDisabled:         "the tool is disabled",
+	ErrorCodeJobNotFound:          "the job does not exist",
+	ErrorCodeInvalidParameters:    "the parameters of the invocation are invalid",
+	ErrorCodePolicyDenied:         "the invocation was denied by a policy",
+	ErrorCodeApprovalRejected:     "the invocation was rejected by an approver",
+	ErrorCodeStreamStalled:        "the client stopped reading the result",
+	ErrorCodeDeadlock:             "the operation was aborted to resolve a deadlock",
+	ErrorCodeSerializationFailure: "the transaction could not be serialized",
+	ErrorCodeCostExceeded:         "the estimated cost of the operation exceeds the limit of the source",
+}
+
+// categoryDetails are the details of errors created without one, whose code
+// has no detail of its own.
+var categoryDetails = map[ErrorCategory]string{
+	ErrorCategoryValidation:        "the request is invalid",
+	ErrorCategoryAuth:              "the caller is not authorized to invoke the tool",
+	ErrorCategoryRateLimit:         "too many requests, retry later",
+	ErrorCategoryTimeout:           "the invocation did not finish in time",
+	ErrorCategorySourceUnavailable: "the source is unavailable",
+	ErrorCategoryQuery:             "the source failed to run the operation",
+	ErrorCategoryInternal:          "the server failed unexpectedly",
+}
+
+// NewToolError returns a ToolError wrapping err. If detail is empty, a generic
+// detail for the code or category is used, never the message of err.
+func NewToolError(category ErrorCategory, code, detail string, err error) *ToolError {
+	if detail == "" {
+		detail = codeDetails[code]
+	}
+	if detail == "" {
+		detail = categoryDetails[category]
+	}
+	return &ToolError{Category: category, Code: code, Detail: detail, Err: err}
+}
+
+func (e *ToolError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Detail
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError returns err as a ToolError. Errors that don't wrap a ToolError
+// are classified by their cause, and fall back to the given category and code
+// if the cause isn't recognized.
+func ClassifyError(err error, category ErrorCategory, code string) *ToolError {
+	if err == nil {
+		return nil
+	}
+	var te *ToolError
+	if errors.As(err, &te) {
+		if te == err {
+			return te
+		}
+		// keep the context added by wrapping the ToolError
+		classified := *te
+		classified.Err = err
+		return &classified
+	}
+
+	var costErr *querycost.ExceededError
+	switch {
+	case errors.As(err, &costErr):
+		return NewToolError(ErrorCategoryQuery, ErrorCodeCostExceeded, costErr.Error(), err)
+	case errors.Is(err, ErrStreamStalled):
+		return NewToolError(ErrorCategoryTimeout, ErrorCodeStreamStalled, "", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		e := NewToolError(ErrorCategoryTimeout, ErrorCodeDeadlineExceeded, "the invocation did not finish in time", err)
+		e.Retryable = true
+		return e
+	case errors.Is(err, context.Canceled):
+		return NewToolError(ErrorCategoryTimeout, ErrorCodeCancelled, "the invocation was cancelled", err)
+	case errors.Is(err, invocations.ErrShuttingDown):
+		e := NewToolError(ErrorCategorySourceUnavailable, ErrorCodeShuttingDown, "the server is shutting down", err)
+		e.Retryable = true
+		return e
+	case errors.Is(err, invocations.ErrToolDisabled):
+		return NewToolError(ErrorCategoryValidation, ErrorCodeToolDisabled, "", err)
+	}
+
+	switch ClassifyRetryableError(err) {
+	case RetryOnConnection, RetryOnUnavailable:
+		e := NewToolError(ErrorCategorySourceUnavailable, ErrorCodeSourceUnavailable, "the source is unavailable", err)
+		e.Retryable = true
+		return e
+	case RetryOnDeadlock:
+		e := NewToolError(ErrorCategoryQuery, ErrorCodeDeadlock, "", err)
+		e.Retryable = true
+		return e
+	case RetryOnSerializationFailure:
+		e := NewToolError(ErrorCategoryQuery, ErrorCodeSerializationFailure, "", err)
+		e.Retryable = true
+		return e
+	}
+	return NewToolError(category, code, "", err)
+}
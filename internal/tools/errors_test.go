@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/querycost"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestClassifyError(t *testing.T) {
+	authErr := tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodeUnauthorized, "", errors.New("missing claims"))
+	tcs := []struct {
+		desc        string
+		err         error
+		want        tools.ToolError
+		wantMessage string
+	}{
+		{
+			desc:        "fallback",
+			err:         errors.New("unable to execute query: syntax error"),
+			want:        tools.ToolError{Category: tools.ErrorCategoryQuery, Code: tools.ErrorCodeQueryFailed, Detail: "the source failed to run the operation"},
+			wantMessage: "unable to execute query: syntax error",
+		},
+		{
+			desc:        "wrapped tool error",
+			err:         fmt.Errorf("error while invoking tool: %w", authErr),
+			want:        tools.ToolError{Category: tools.ErrorCategoryAuth, Code: tools.ErrorCodeUnauthorized, Detail: "the caller is not authorized to invoke the tool"},
+			wantMessage: "error while invoking tool: missing claims",
+		},
+		{
+			desc:        "deadline exceeded",
+			err:         fmt.Errorf("unable to execute query: %w", context.DeadlineExceeded),
+			want:        tools.ToolError{Category: tools.ErrorCategoryTimeout, Code: tools.ErrorCodeDeadlineExceeded, Detail: "the invocation did not finish in time", Retryable: true},
+			wantMessage: "unable to execute query: context deadline exceeded",
+		},
+		{
+			desc:        "connection reset",
+			err:         fmt.Errorf("unable to execute query: %w", syscall.ECONNRESET),
+			want:        tools.ToolError{Category: tools.ErrorCategorySourceUnavailable, Code: tools.ErrorCodeSourceUnavailable, Detail: "the source is unavailable", Retryable: true},
+			wantMessage: "unable to execute query: connection reset by peer",
+		},
+		{
+			desc:        "deadlock",
+			err:         sqlStateError("40P01"),
+			want:        tools.ToolError{Category: tools.ErrorCategoryQuery, Code: tools.ErrorCodeDeadlock, Detail: "the operation was aborted to resolve a deadlock", Retryable: true},
+			wantMessage: "sql error 40P01",
+		},
+		{
+			desc:        "cost exceeded",
+			err:         fmt.Errorf("unable to execute query: %w", &querycost.ExceededError{Estimate: querycost.Estimate{Cost: 5000, Rows: 10}, Limits: querycost.Limits{MaxCost: 1000}}),
+			want:        tools.ToolError{Category: tools.ErrorCategoryQuery, Code: tools.ErrorCodeCostExceeded, Detail: "the query is estimated to cost 5000 and return 10 rows, over the limit of a cost of 1000; narrow it, e.g. with more selective filters, aggregates or a LIMIT"},
+			wantMessage: "unable to execute query: the query is estimated to cost 5000 and return 10 rows, over the limit of a cost of 1000; narrow it, e.g. with more selective filters, aggregates or a LIMIT",
+		},
+		{
+			desc:        "shutting down",
+			err:         fmt.Errorf("%w: invocation of tool %q was rejected", invocations.ErrShuttingDown, "my-tool"),
+			want:        tools.ToolError{Category: tools.ErrorCategorySourceUnavailable, Code: tools.ErrorCodeShuttingDown, Detail: "the server is shutting down", Retryable: true},
+			wantMessage: `server is shutting down: invocation of tool "my-tool" was rejected`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := tools.ClassifyError(tc.err, tools.ErrorCategoryQuery, tools.ErrorCodeQueryFailed)
+			if diff := cmp.Diff(tc.want, *got, cmpopts.IgnoreFields(tools.ToolError{}, "Err")); diff != "" {
+				t.Fatalf("unexpected classification (-want +got):\n%s", diff)
+			}
+			if got.Error() != tc.wantMessage {
+				t.Fatalf("unexpected message: got %q, want %q", got.Error(), tc.wantMessage)
+			}
+			if !errors.Is(got, tc.err) {
+				t.Fatalf("classified error does not wrap the original error")
+			}
+		})
+	}
+}
@@ -0,0 +1,452 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// Formats a tool with an export destination can return its results in.
+const (
+	ExportFormatJSON    string = "json"
+	ExportFormatCSV     string = "csv"
+	ExportFormatParquet string = "parquet"
+)
+
+// exportFormatParam is the parameter callers choose the format with.
+const exportFormatParam = "format"
+
+var exportFormats = []string{ExportFormatCSV, ExportFormatParquet}
+
+// ExportConfig lets callers write a tool's results to a file instead of
+// receiving them inline.
+type ExportConfig struct {
+	// Destination is a local directory or a `gs://bucket/prefix` URI.
+	Destination string `yaml:"destination" validate:"required"`
+	// Formats lists the file formats callers may choose. Defaults to csv and
+	// parquet.
+	Formats []string `yaml:"formats"`
+}
+
+// ExportResult is returned in place of the results of an exported invocation.
+type ExportResult struct {
+	Location string `json:"location"`
+	Format   string `json:"format"`
+	RowCount int    `json:"rowCount"`
+	Size     int    `json:"size"`
+}
+
+// ExportToolConfig wraps a ToolConfig so the tool it initializes accepts a
+// `format` parameter, and writes its results to Export.Destination when a file
+// format is chosen.
+type ExportToolConfig struct {
+	ToolConfig
+	Export ExportConfig
+}
+
+// validate interface
+var _ ToolConfig = ExportToolConfig{}
+
+func (cfg ExportToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	formats := cfg.Export.Formats
+	if len(formats) == 0 {
+		formats = exportFormats
+	}
+	for _, f := range formats {
+		if !slices.Contains(exportFormats, f) {
+			return nil, fmt.Errorf("invalid export: unknown format %q, must be one of %q", f, exportFormats)
+		}
+	}
+	store, err := exportStoreConfig(cfg.Export.Destination).initialize()
+	if err != nil {
+		return nil, fmt.Errorf("invalid export: %w", err)
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range t.Manifest().Parameters {
+		if p.Name == exportFormatParam {
+			return nil, fmt.Errorf("invalid export: tool already has a parameter named %q", exportFormatParam)
+		}
+	}
+	return exportTool{toolWrapper: toolWrapper{t}, formats: formats, store: store}, nil
+}
+
+// exportStoreConfig returns the artifact store exports to destination are
+// written to.
+func exportStoreConfig(destination string) ArtifactStoreConfig {
+	if rest, ok := strings.CutPrefix(destination, "gs://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		return ArtifactStoreConfig{Kind: ArtifactStoreGCS, Bucket: bucket, Prefix: prefix}
+	}
+	return ArtifactStoreConfig{Kind: ArtifactStoreLocal, Path: destination}
+}
+
+type exportTool struct {
+	toolWrapper
+	formats []string
+	store   artifactStore
+}
+
+func (t exportTool) description() string {
+	return fmt.Sprintf("Format of the results: %q (default) returns them inline; %s write them to a file and return its location and row count.", ExportFormatJSON, strings.Join(t.quotedFormats(), " or "))
+}
+
+func (t exportTool) quotedFormats() []string {
+	quoted := make([]string, len(t.formats))
+	for i, f := range t.formats {
+		quoted[i] = fmt.Sprintf("%q", f)
+	}
+	return quoted
+}
+
+func (t exportTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Parameters = append(slices.Clone(m.Parameters), ParameterManifest{
+		Name:         exportFormatParam,
+		Type:         typeString,
+		Description:  t.description(),
+		AuthServices: []string{},
+	})
+	return m
+}
+
+func (t exportTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	props := make(map[string]ParameterMcpManifest, len(m.InputSchema.Properties)+1)
+	for k, v := range m.InputSchema.Properties {
+		props[k] = v
+	}
+	props[exportFormatParam] = ParameterMcpManifest{Type: typeString, Description: t.description()}
+	m.InputSchema.Properties = props
+	return m
+}
+
+func (t exportTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	params, err := t.Tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, err
+	}
+	format := ExportFormatJSON
+	if v, ok := data[exportFormatParam]; ok && v != nil {
+		s, ok := v.(string)
+		if !ok || (s != ExportFormatJSON && !slices.Contains(t.formats, s)) {
+			return nil, fmt.Errorf("unable to parse value for %q: must be %q or %s", exportFormatParam, ExportFormatJSON, strings.Join(t.quotedFormats(), " or "))
+		}
+		format = s
+	}
+	return append(params, ParamValue{Name: exportFormatParam, Value: format}), nil
+}
+
+func (t exportTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	format := ExportFormatJSON
+	if n := len(params); n > 0 && params[n-1].Name == exportFormatParam {
+		format, _ = params[n-1].Value.(string)
+		params = params[:n-1]
+	}
+	res, err := t.Tool.Invoke(ctx, params)
+	if err != nil || format == ExportFormatJSON {
+		return res, err
+	}
+
+	rows, err := tableRows(res)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	var mimeType string
+	switch format {
+	case ExportFormatCSV:
+		data, err = encodeCSV(rows)
+		mimeType = "text/csv"
+	case ExportFormatParquet:
+		data, err = encodeParquet(rows)
+		mimeType = "application/vnd.apache.parquet"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode results as %s: %w", format, err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s-%s.%s", t.McpManifest().Name, time.Now().UTC().Format("20060102T150405Z"), hex.EncodeToString(suffix), format)
+	location, err := t.store.put(ctx, name, mimeType, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to write export: %w", err)
+	}
+	return ExportResult{Location: location, Format: format, RowCount: len(rows), Size: len(data)}, nil
+}
+
+// CanStream is false since exports are written once all the rows of the
+// result were fetched, so the results of exported tools are never streamed.
+func (t exportTool) CanStream() bool {
+	return false
+}
+
+// tableRows returns the rows of a tool's result, which must be a list of
+// objects.
+func tableRows(res any) ([]map[string]any, error) {
+	switch res := res.(type) {
+	case nil:
+		return nil, nil
+	case []map[string]any:
+		return res, nil
+	case []any:
+		rows := make([]map[string]any, 0, len(res))
+		for _, r := range res {
+			row, ok := r.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("results of type %T can't be exported: rows must be objects", r)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("results of type %T can't be exported: results must be a list of rows", res)
+}
+
+// tableColumns returns the names of the columns of rows in lexical order,
+// since rows don't keep the order of their columns.
+func tableColumns(rows []map[string]any) []string {
+	seen := make(map[string]bool)
+	var cols []string
+	for _, row := range rows {
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				cols = append(cols, k)
+			}
+		}
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+func encodeCSV(rows []map[string]any) ([]byte, error) {
+	cols := tableColumns(rows)
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(cols); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(cols))
+	for _, row := range rows {
+		for i, c := range cols {
+			s, err := csvValue(row[c])
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %w", c, err)
+			}
+			record[i] = s
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func csvValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// columnType returns the Arrow type that can hold every value of a column.
+// Columns mixing types other than integers and floats are stored as strings.
+func columnType(rows []map[string]any, col string) arrow.DataType {
+	var t arrow.DataType
+	for _, row := range rows {
+		var vt arrow.DataType
+		switch row[col].(type) {
+		case nil:
+			continue
+		case int, int8, int16, int32, int64, uint8, uint16, uint32:
+			vt = arrow.PrimitiveTypes.Int64
+		case float32, float64:
+			vt = arrow.PrimitiveTypes.Float64
+		case bool:
+			vt = arrow.FixedWidthTypes.Boolean
+		case time.Time:
+			vt = arrow.FixedWidthTypes.Timestamp_us
+		case []byte:
+			vt = arrow.BinaryTypes.Binary
+		default:
+			return arrow.BinaryTypes.String
+		}
+		switch {
+		case t == nil || arrow.TypeEqual(t, vt):
+			t = vt
+		case isNumeric(t) && isNumeric(vt):
+			t = arrow.PrimitiveTypes.Float64
+		default:
+			return arrow.BinaryTypes.String
+		}
+	}
+	if t == nil {
+		return arrow.BinaryTypes.String
+	}
+	return t
+}
+
+func isNumeric(t arrow.DataType) bool {
+	return t.ID() == arrow.INT64 || t.ID() == arrow.FLOAT64
+}
+
+func encodeParquet(rows []map[string]any) ([]byte, error) {
+	cols := tableColumns(rows)
+	fields := make([]arrow.Field, len(cols))
+	for i, c := range cols {
+		fields[i] = arrow.Field{Name: c, Type: columnType(rows, c), Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	for _, row := range rows {
+		for i, c := range cols {
+			if err := appendValue(b.Field(i), row[c]); err != nil {
+				return nil, fmt.Errorf("column %q: %w", c, err)
+			}
+		}
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	w, err := pqarrow.NewFileWriter(schema, &buf, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Write(rec); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func appendValue(b array.Builder, v any) error {
+	if v == nil {
+		b.AppendNull()
+		return nil
+	}
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		i, _ := toInt64(v)
+		b.Append(i)
+	case *array.Float64Builder:
+		if f, ok := v.(float64); ok {
+			b.Append(f)
+		} else if f, ok := v.(float32); ok {
+			b.Append(float64(f))
+		} else {
+			i, _ := toInt64(v)
+			b.Append(float64(i))
+		}
+	case *array.BooleanBuilder:
+		b.Append(v.(bool))
+	case *array.TimestampBuilder:
+		ts, err := arrow.TimestampFromTime(v.(time.Time), arrow.Microsecond)
+		if err != nil {
+			return err
+		}
+		b.Append(ts)
+	case *array.BinaryBuilder:
+		b.Append(v.([]byte))
+	case *array.StringBuilder:
+		s, err := csvValue(v)
+		if err != nil {
+			return err
+		}
+		b.Append(s)
+	default:
+		return fmt.Errorf("unsupported column type %s", b.Type())
+	}
+	return nil
+}
+
+func toInt64(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// Preview forwards to the wrapped tool, without the export format.
+func (t exportTool) Preview(ctx context.Context, params ParamValues) (StatementPreview, error) {
+	if n := len(params); n > 0 && params[n-1].Name == exportFormatParam {
+		params = params[:n-1]
+	}
+	return PreviewTool(ctx, t.Tool, params)
+}
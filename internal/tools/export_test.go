@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestExportToolConfig(t *testing.T) {
+	rows := func() any {
+		return []any{
+			map[string]any{"id": 1, "name": "a, b", "score": 1.5, "at": time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+			map[string]any{"id": 2, "name": nil, "score": 2, "at": nil},
+		}
+	}
+	cfg := tools.ExportToolConfig{
+		ToolConfig: rowsToolConfig{tool: rowsTool{fakeTool: fakeTool{name: "report"}, rows: rows}},
+		Export:     tools.ExportConfig{Destination: t.TempDir()},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := tool.McpManifest().InputSchema.Properties["format"]; !ok {
+		t.Fatalf("format parameter missing from mcp manifest")
+	}
+
+	invoke := func(t *testing.T, format string) any {
+		t.Helper()
+		data := map[string]any{}
+		if format != "" {
+			data["format"] = format
+		}
+		params, err := tool.ParseParams(data, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		res, err := tool.Invoke(context.Background(), params)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return res
+	}
+	readExport := func(t *testing.T, res any) []byte {
+		t.Helper()
+		er, ok := res.(tools.ExportResult)
+		if !ok {
+			t.Fatalf("unexpected result %v", res)
+		}
+		if er.RowCount != 2 {
+			t.Fatalf("got %d rows, want 2", er.RowCount)
+		}
+		u, err := url.Parse(er.Location)
+		if err != nil || !strings.HasSuffix(u.Path, "."+er.Format) {
+			t.Fatalf("unexpected location %q", er.Location)
+		}
+		b, err := os.ReadFile(u.Path)
+		if err != nil {
+			t.Fatalf("unable to read export: %s", err)
+		}
+		return b
+	}
+
+	t.Run("json", func(t *testing.T) {
+		if res := invoke(t, ""); len(res.([]any)) != 2 {
+			t.Fatalf("unexpected result %v", res)
+		}
+	})
+	t.Run("csv", func(t *testing.T) {
+		got := string(readExport(t, invoke(t, "csv")))
+		want := "at,id,name,score\n2025-01-02T03:04:05Z,1,\"a, b\",1.5\n,2,,2\n"
+		if got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+	t.Run("parquet", func(t *testing.T) {
+		r, err := file.NewParquetReader(bytes.NewReader(readExport(t, invoke(t, "parquet"))))
+		if err != nil {
+			t.Fatalf("unable to read parquet: %s", err)
+		}
+		defer r.Close()
+		if r.NumRows() != 2 {
+			t.Fatalf("got %d rows, want 2", r.NumRows())
+		}
+		if n := r.MetaData().Schema.NumColumns(); n != 4 {
+			t.Fatalf("got %d columns, want 4", n)
+		}
+	})
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := tool.ParseParams(map[string]any{"format": "xlsx"}, nil); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestExportToolConfigInvalid(t *testing.T) {
+	tcs := []tools.ExportConfig{
+		{Destination: t.TempDir(), Formats: []string{"xlsx"}},
+		{Destination: "gs://"},
+	}
+	for _, tc := range tcs {
+		cfg := tools.ExportToolConfig{ToolConfig: rowsToolConfig{}, Export: tc}
+		if _, err := cfg.Initialize(nil); err == nil {
+			t.Errorf("expected error for %+v", tc)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package federatedsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// maxScannedRows bounds the rows of a BigQuery table that are loaded
+	// into DuckDB for an invocation.
+	maxScannedRows = 100000
+	// insertBatchSize is the number of rows inserted by one statement.
+	insertBatchSize = 100
+)
+
+// scan is a BigQuery table that's loaded into a temporary table before the
+// statement runs.
+type scan struct {
+	client  *bigqueryapi.Client
+	dataset string
+	table   string
+	// name is the name of the temporary table
+	name string
+}
+
+// load reads the table with the BigQuery client of its source, and inserts
+// its rows into a temporary table of conn.
+func (s scan) load(ctx context.Context, conn *sql.Conn) error {
+	ref := s.dataset + "." + s.table
+	table := s.client.Dataset(s.dataset).Table(s.table)
+	md, err := table.Metadata(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get metadata of BigQuery table %q: %w", ref, err)
+	}
+	// rows can only be read from tables, views have to be queried
+	if md.Type != bigqueryapi.RegularTable {
+		return fmt.Errorf("unable to scan BigQuery table %q: only tables can be scanned, got %s", ref, md.Type)
+	}
+	if md.NumRows > maxScannedRows {
+		return fmt.Errorf("unable to scan BigQuery table %q: it has %d rows, more than the %d that can be scanned", ref, md.NumRows, maxScannedRows)
+	}
+	it := table.Read(ctx)
+	next := func(row *[]bigqueryapi.Value) error { return it.Next(row) }
+	if err := loadRows(ctx, conn, s.name, md.Schema, next); err != nil {
+		return fmt.Errorf("unable to scan BigQuery table %q: %w", ref, err)
+	}
+	return nil
+}
+
+// loadRows creates the temporary table name with the columns of schema, and
+// inserts the rows returned by next until it returns iterator.Done.
+func loadRows(ctx context.Context, conn *sql.Conn, name string, schema bigqueryapi.Schema, next func(*[]bigqueryapi.Value) error) error {
+	if len(schema) == 0 {
+		return fmt.Errorf("table has no columns")
+	}
+	cols := make([]string, len(schema))
+	casts := make([]string, len(schema))
+	for i, f := range schema {
+		cols[i] = quoteIdentifier(f.Name) + " " + columnType(f)
+		casts[i] = "CAST(? AS " + columnType(f) + ")"
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE OR REPLACE TEMP TABLE %s (%s)", name, strings.Join(cols, ", "))); err != nil {
+		return err
+	}
+	placeholders := "(" + strings.Join(casts, ", ") + ")"
+
+	insert := func(args []any) error {
+		rows := len(args) / len(schema)
+		values := strings.TrimSuffix(strings.Repeat(placeholders+", ", rows), ", ")
+		_, err := conn.ExecContext(ctx, "INSERT INTO temp.main."+name+" VALUES "+values, args...)
+		return err
+	}
+	args := make([]any, 0, insertBatchSize*len(schema))
+	for count := 0; ; count++ {
+		var row []bigqueryapi.Value
+		err := next(&row)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// the number of rows of a table can grow after its metadata was read
+		if count == maxScannedRows {
+			return fmt.Errorf("table has more than the %d rows that can be scanned", maxScannedRows)
+		}
+		if len(row) != len(schema) {
+			return fmt.Errorf("row has %d values, want %d", len(row), len(schema))
+		}
+		for i, f := range schema {
+			v, err := columnValue(row[i], f)
+			if err != nil {
+				return fmt.Errorf("unable to convert column %q: %w", f.Name, err)
+			}
+			args = append(args, v)
+		}
+		if len(args) == cap(args) {
+			if err := insert(args); err != nil {
+				return err
+			}
+			args = args[:0]
+		}
+	}
+	if len(args) > 0 {
+		return insert(args)
+	}
+	return nil
+}
+
+// columnType returns the DuckDB type of the column a BigQuery field is
+// loaded into. Repeated and nested fields are loaded as JSON, and the types
+// DuckDB has no exact equivalent for as text.
+func columnType(f *bigqueryapi.FieldSchema) string {
+	if f.Repeated || f.Type == bigqueryapi.RecordFieldType {
+		return "JSON"
+	}
+	switch f.Type {
+	case bigqueryapi.IntegerFieldType:
+		return "BIGINT"
+	case bigqueryapi.FloatFieldType:
+		return "DOUBLE"
+	case bigqueryapi.BooleanFieldType:
+		return "BOOLEAN"
+	case bigqueryapi.BytesFieldType:
+		return "BLOB"
+	case bigqueryapi.NumericFieldType:
+		return "DECIMAL(38, 9)"
+	case bigqueryapi.TimestampFieldType:
+		return "TIMESTAMPTZ"
+	case bigqueryapi.DateFieldType:
+		return "DATE"
+	case bigqueryapi.TimeFieldType:
+		return "TIME"
+	case bigqueryapi.DateTimeFieldType:
+		return "TIMESTAMP"
+	case bigqueryapi.JSONFieldType:
+		return "JSON"
+	default:
+		return "VARCHAR"
+	}
+}
+
+// columnValue converts a value read from BigQuery into one that can be cast
+// to the column type of its field.
+func columnValue(v bigqueryapi.Value, f *bigqueryapi.FieldSchema) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if f.Repeated || f.Type == bigqueryapi.RecordFieldType {
+		b, err := json.Marshal(jsonValue(v, f, f.Repeated))
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+	return scalarValue(v, f), nil
+}
+
+// jsonValue converts a value read from BigQuery into one that can be
+// encoded as JSON. Records become objects.
+func jsonValue(v bigqueryapi.Value, f *bigqueryapi.FieldSchema, repeated bool) any {
+	if v == nil {
+		return nil
+	}
+	values, _ := v.([]bigqueryapi.Value)
+	switch {
+	case repeated:
+		out := make([]any, len(values))
+		for i, e := range values {
+			out[i] = jsonValue(e, f, false)
+		}
+		return out
+	case f.Type == bigqueryapi.RecordFieldType:
+		out := make(map[string]any, len(f.Schema))
+		for i, sf := range f.Schema {
+			if i < len(values) {
+				out[sf.Name] = jsonValue(values[i], sf, sf.Repeated)
+			}
+		}
+		return out
+	}
+	return scalarValue(v, f)
+}
+
+// scalarValue converts the numeric, civil and interval types of the
+// BigQuery client, which DuckDB can't bind, into their text representation.
+func scalarValue(v bigqueryapi.Value, f *bigqueryapi.FieldSchema) any {
+	switch v := v.(type) {
+	case *big.Rat:
+		if f.Type == bigqueryapi.BigNumericFieldType {
+			return bigqueryapi.BigNumericString(v)
+		}
+		return bigqueryapi.NumericString(v)
+	case time.Time:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	}
+	return v
+}
+
+// quoteIdentifier returns name as a quoted identifier.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,362 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package federatedsql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	bigqueryapi "cloud.google.com/go/bigquery"
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/bigquery"
+	"github.com/googleapis/genai-toolbox/internal/sources/duckdb"
+	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/duckdbsql"
+)
+
+const kind string = "federated-sql"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	DuckDb() *sql.DB
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &duckdb.Source{}
+var compatibleSources = [...]string{duckdb.SourceKind}
+
+// kinds of the sources that can be attached
+var attachableSources = [...]string{bigquery.SourceKind, mysql.SourceKind, postgres.SourceKind}
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Kind string `yaml:"kind" validate:"required"`
+	// Source is the DuckDB source the statement runs on.
+	Source string `yaml:"source" validate:"required"`
+	// Attach maps the alias each source is queried under to the name of the
+	// source.
+	Attach             map[string]string `yaml:"attach" validate:"required"`
+	Description        string            `yaml:"description" validate:"required"`
+	Statement          string            `yaml:"statement" validate:"required"`
+	AuthRequired       []string          `yaml:"authRequired"`
+	Parameters         tools.Parameters  `yaml:"parameters"`
+	TemplateParameters tools.Parameters  `yaml:"templateParameters"`
+}
+
+// Initialize implements tools.ToolConfig.
+func (c Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[c.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", c.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	names := attachedNames(c.Name, c.Attach)
+	stmts, clients, err := attachStatements(c.Attach, names, srcs)
+	if err != nil {
+		return nil, err
+	}
+	statement, scans, err := qualifyAliases(c.Statement, names, clients)
+	if err != nil {
+		return nil, err
+	}
+	// attached databases are shared by every connection of the DuckDB source,
+	// and kept when the source is reused by a reload
+	for _, stmt := range stmts {
+		if _, err := s.DuckDb().ExecContext(context.Background(), stmt); err != nil {
+			return nil, fmt.Errorf("unable to attach sources for %q tool: %w", kind, err)
+		}
+	}
+
+	t, err := duckdbsql.Config{
+		Name:               c.Name,
+		Kind:               c.Kind,
+		Source:             c.Source,
+		Description:        c.Description,
+		Statement:          statement,
+		AuthRequired:       c.AuthRequired,
+		Parameters:         c.Parameters,
+		TemplateParameters: c.TemplateParameters,
+	}.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	// the statement runs like that of a duckdb-sql tool once the sources are
+	// attached and the BigQuery tables scanned
+	tool := Tool{Tool: t.(duckdbsql.Tool), scans: scans}
+	tool.Kind = kind
+	return tool, nil
+}
+
+// ToolConfigKind implements tools.ToolConfig.
+func (c Config) ToolConfigKind() string {
+	return kind
+}
+
+var _ tools.ToolConfig = Config{}
+
+type Tool struct {
+	duckdbsql.Tool
+	// scans are the BigQuery tables loaded before every invocation
+	scans []scan
+}
+
+// Invoke implements tools.Tool. The BigQuery tables the statement reads are
+// loaded into temporary tables of the connection it runs on, and dropped
+// once it's done.
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	if len(t.scans) == 0 {
+		return t.Tool.Invoke(ctx, params)
+	}
+	conn, err := t.Db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get connection: %w", err)
+	}
+	defer conn.Close()
+	for _, sc := range t.scans {
+		// the connection goes back to the pool, so the table is dropped even
+		// if the invocation is cancelled
+		defer conn.ExecContext(context.WithoutCancel(ctx), "DROP TABLE IF EXISTS temp.main."+sc.name) //nolint:errcheck
+		if err := sc.load(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+	return t.InvokeConn(ctx, conn, params)
+}
+
+var _ tools.Tool = Tool{}
+var _ tools.PreviewableTool = Tool{}
+
+// attachedNames returns the names each alias of a tool is attached under.
+// The names are unique to the tool, so tools attaching different sources
+// under the same alias to one DuckDB source don't share databases.
+func attachedNames(tool string, attach map[string]string) map[string]string {
+	sum := sha256.Sum256([]byte(tool))
+	prefix := "fsql_" + hex.EncodeToString(sum[:4]) + "_"
+	names := make(map[string]string, len(attach))
+	for alias := range attach {
+		names[strings.ToLower(alias)] = prefix + strings.ToLower(alias)
+	}
+	return names
+}
+
+// attachStatements returns the statements attaching each source under the
+// name of its alias, sorted by alias. Sources are attached read-only. Their
+// credentials are passed in a secret, which DuckDB redacts, and attaching
+// again replaces the database and secret, so a reload picks up new settings.
+// BigQuery sources aren't attached: their tables are scanned with the
+// source's client, which is returned by lower case alias.
+func attachStatements(attach map[string]string, names map[string]string, srcs map[string]sources.Source) ([]string, map[string]*bigqueryapi.Client, error) {
+	if len(attach) == 0 {
+		return nil, nil, fmt.Errorf("at least one source must be attached")
+	}
+	aliases := make([]string, 0, len(attach))
+	for alias := range attach {
+		aliases = append(aliases, alias)
+	}
+	slices.Sort(aliases)
+
+	var stmts []string
+	clients := make(map[string]*bigqueryapi.Client)
+	for _, alias := range aliases {
+		if !aliasPattern.MatchString(alias) {
+			return nil, nil, fmt.Errorf("invalid alias %q: must start with a letter or underscore and contain only letters, digits and underscores", alias)
+		}
+		name := attach[alias]
+		src, ok := srcs[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("no source named %q configured", name)
+		}
+
+		var attachType, host, port, database, user, password string
+		switch s := src.(type) {
+		case *bigquery.Source:
+			clients[strings.ToLower(alias)] = s.BigQueryClient()
+			continue
+		case *postgres.Source:
+			cc := s.Pool.Config().ConnConfig
+			attachType = "postgres"
+			host, port, database, user, password = cc.Host, strconv.Itoa(int(cc.Port)), cc.Database, cc.User, cc.Password
+		case *mysql.Source:
+			cfg := s.Config
+			if _, err := strconv.Atoi(cfg.Port); err != nil {
+				return nil, nil, fmt.Errorf("invalid port %q of source %q", cfg.Port, name)
+			}
+			attachType = "mysql"
+			host, port, database, user, password = cfg.Host, cfg.Port, cfg.Database, cfg.User, cfg.Password
+		default:
+			return nil, nil, fmt.Errorf("invalid source %q for alias %q: source kind must be one of %q", name, alias, attachableSources)
+		}
+		// the postgres and mysql extensions are loaded by DuckDB on their
+		// first use
+		db := names[strings.ToLower(alias)]
+		stmts = append(stmts,
+			"DETACH DATABASE IF EXISTS "+db,
+			fmt.Sprintf("CREATE OR REPLACE SECRET %s (TYPE %s, HOST %s, PORT %s, DATABASE %s, USER %s, PASSWORD %s)",
+				db, attachType, quote(host), port, quote(database), quote(user), quote(password)),
+			fmt.Sprintf("ATTACH '' AS %s (TYPE %s, SECRET %s, READ_ONLY)", db, attachType, db),
+		)
+	}
+	return stmts, clients, nil
+}
+
+// quote returns s as an SQL string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// qualifyAliases replaces the aliases of a statement, in references such as
+// alias.schema.table, with the names they're attached under. References to
+// the tables of a BigQuery alias, which must be alias.dataset.table, are
+// replaced with the temporary tables they're scanned into. Strings, quoted
+// identifiers, comments and template actions are left alone.
+func qualifyAliases(statement string, names map[string]string, clients map[string]*bigqueryapi.Client) (string, []scan, error) {
+	var sb strings.Builder
+	var scans []scan
+	scanned := make(map[string]string)
+	last := 0
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(statement[i+1:], c)
+			if end < 0 {
+				i = len(statement)
+				break
+			}
+			i += end + 2
+		case strings.HasPrefix(statement[i:], "--"):
+			i = skipPast(statement, i, "\n")
+		case strings.HasPrefix(statement[i:], "/*"):
+			i = skipPast(statement, i, "*/")
+		case strings.HasPrefix(statement[i:], "{{"):
+			i = skipPast(statement, i, "}}")
+		case isWordChar(c, true):
+			end := i + 1
+			for end < len(statement) && isWordChar(statement[end], false) {
+				end++
+			}
+			// only unqualified words followed by a dot name databases, and
+			// $ starts a parameter name
+			prev := strings.TrimRight(statement[:i], " \t\r\n")
+			qualified := strings.HasSuffix(prev, ".") || strings.HasSuffix(statement[:i], "$")
+			alias := strings.ToLower(statement[i:end])
+			name, ok := names[alias]
+			if !ok || qualified || !strings.HasPrefix(strings.TrimLeft(statement[end:], " \t\r\n"), ".") {
+				i = end
+				break
+			}
+			sb.WriteString(statement[last:i])
+			client, ok := clients[alias]
+			if !ok {
+				sb.WriteString(name)
+				last, i = end, end
+				break
+			}
+			path, pathEnd := referencePath(statement, end)
+			if len(path) != 2 {
+				return "", nil, fmt.Errorf("invalid reference %q to BigQuery alias %q: tables must be referenced as %s.dataset.table", strings.TrimSpace(statement[i:pathEnd]), statement[i:end], statement[i:end])
+			}
+			key := alias + "." + path[0] + "." + path[1]
+			table, ok := scanned[key]
+			if !ok {
+				table = name + "_" + strconv.Itoa(len(scans))
+				scanned[key] = table
+				scans = append(scans, scan{client: client, dataset: path[0], table: path[1], name: table})
+			}
+			sb.WriteString("temp.main." + table)
+			last, i = pathEnd, pathEnd
+		default:
+			i++
+		}
+	}
+	sb.WriteString(statement[last:])
+	return sb.String(), scans, nil
+}
+
+// referencePath returns the names following the alias of a reference, which
+// ends at i, and the index the reference ends at. Names can be quoted.
+func referencePath(statement string, i int) ([]string, int) {
+	var path []string
+	for {
+		j := len(statement) - len(strings.TrimLeft(statement[i:], " \t\r\n"))
+		if j >= len(statement) || statement[j] != '.' {
+			return path, i
+		}
+		j = len(statement) - len(strings.TrimLeft(statement[j+1:], " \t\r\n"))
+		switch {
+		case j < len(statement) && statement[j] == '"':
+			end := strings.IndexByte(statement[j+1:], '"')
+			if end < 0 {
+				return path, i
+			}
+			path = append(path, statement[j+1:j+1+end])
+			i = j + end + 2
+		case j < len(statement) && isWordChar(statement[j], true):
+			end := j + 1
+			for end < len(statement) && isWordChar(statement[end], false) {
+				end++
+			}
+			path = append(path, statement[j:end])
+			i = end
+		default:
+			return path, i
+		}
+	}
+}
+
+func isWordChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// skipPast returns the index after the first occurrence of end after i, or
+// the length of s if there is none.
+func skipPast(s string, i int, end string) int {
+	if j := strings.Index(s[i+len(end):], end); j >= 0 {
+		return i + len(end) + j + len(end)
+	}
+	return len(s)
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fireboltsql_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/firebolt/fireboltsql"
+)
+
+func TestParseFromYamlFireboltSql(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: firebolt-sql
+					source: my-firebolt-instance
+					description: some description
+					statement: |
+						SELECT * FROM events WHERE user_id = ? AND event_type = ?;
+					parameters:
+						- name: user_id
+						  type: integer
+						  description: the user id
+						- name: event_type
+						  type: string
+						  description: the event type
+			`,
+			want: server.ToolConfigs{
+				"example_tool": fireboltsql.Config{
+					Name:         "example_tool",
+					Kind:         "firebolt-sql",
+					Source:       "my-firebolt-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM events WHERE user_id = ? AND event_type = ?;\n",
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewIntParameter("user_id", "the user id"),
+						tools.NewStringParameter("event_type", "the event type"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
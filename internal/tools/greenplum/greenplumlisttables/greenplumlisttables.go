@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package greenplumlisttables
+
+import (
+	"context"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/greenplum"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "greenplum-list-tables"
+
+const tableNamesKey string = "table_names"
+
+// listTablesStatement6 lists the user tables of a Greenplum 6 database with
+// their distribution policy, storage and partition key. Greenplum 6 keeps its
+// legacy partitioning in pg_partition, so partitions are excluded through
+// pg_partition_rule and keys are read from pg_partition_columns.
+const listTablesStatement6 = `
+SELECT
+	n.nspname AS schema_name,
+	c.relname AS table_name,
+	c.reltuples::bigint AS estimated_rows,
+	pg_catalog.pg_get_table_distributedby(c.oid) AS distributed_by,
+	CASE c.relstorage WHEN 'a' THEN 'ao_row' WHEN 'c' THEN 'ao_column' WHEN 'x' THEN 'external' ELSE 'heap' END AS storage,
+	(
+		SELECT string_agg(pc.columnname, ', ' ORDER BY pc.position_in_partition_key)
+		FROM pg_catalog.pg_partition_columns pc
+		WHERE pc.schemaname = n.nspname AND pc.tablename = c.relname AND pc.partitionlevel = 0
+	) AS partition_key
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'r'
+	AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_partition_rule pr WHERE pr.parchildrelid = c.oid)
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'gp_toolkit', 'pg_aoseg', 'pg_bitmapindex')
+	AND n.nspname NOT LIKE 'pg_toast%'
+	AND n.nspname NOT LIKE 'pg_temp%'
+	AND ($1 = '' OR c.relname = ANY(string_to_array($1, ',')))
+ORDER BY n.nspname, c.relname`
+
+// listTablesStatement7 is listTablesStatement6 for Greenplum 7, which uses
+// PostgreSQL's declarative partitioning and table access methods.
+const listTablesStatement7 = `
+SELECT
+	n.nspname AS schema_name,
+	c.relname AS table_name,
+	c.reltuples::bigint AS estimated_rows,
+	pg_catalog.pg_get_table_distributedby(c.oid) AS distributed_by,
+	COALESCE(am.amname, 'heap') AS storage,
+	pg_catalog.pg_get_partkeydef(c.oid) AS partition_key
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_catalog.pg_am am ON am.oid = c.relam
+WHERE c.relkind IN ('r', 'p')
+	AND NOT c.relispartition
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'gp_toolkit', 'pg_aoseg', 'pg_bitmapindex', 'pg_ext_aux')
+	AND n.nspname NOT LIKE 'pg_toast%'
+	AND n.nspname NOT LIKE 'pg_temp%'
+	AND ($1 = '' OR c.relname = ANY(string_to_array($1, ',')))
+ORDER BY n.nspname, c.relname`
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	PostgresPool() *pgxpool.Pool
+	GreenplumMajorVersion() int
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &greenplum.Source{}
+
+var compatibleSources = [...]string{greenplum.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	statement := listTablesStatement7
+	if s.GreenplumMajorVersion() < 7 {
+		statement = listTablesStatement6
+	}
+
+	tableNamesParameter := tools.NewStringParameterWithDefault(tableNamesKey, "", "Optional: a comma-separated list of table names. If empty, all tables of the database are listed.")
+	parameters := tools.Parameters{tableNamesParameter}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		AuthRequired: cfg.AuthRequired,
+		Pool:         s.PostgresPool(),
+		Statement:    statement,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Pool        *pgxpool.Pool
+	Statement   string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	tableNames, ok := params.AsMap()[tableNamesKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", tableNamesKey)
+	}
+
+	results, err := t.Pool.Query(ctx, t.Statement, tableNames)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	defer results.Close()
+
+	fields := results.FieldDescriptions()
+
+	var out []any
+	for results.Next() {
+		v, err := results.Values()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		vMap := make(map[string]any)
+		for i, f := range fields {
+			vMap[f.Name] = v[i]
+		}
+		out = append(out, vMap)
+	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+
+	return out, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hivecommon holds what the Hive tools share.
+package hivecommon
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/sources/hive"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// PartitionPruning keeps queries on large partitioned tables from scanning
+// every partition.
+type PartitionPruning struct {
+	// Tables maps partitioned tables to their partition columns, which are
+	// listed in the tool's description so queries filter on them.
+	Tables map[string][]string `yaml:"tables"`
+	// Strict makes Hive reject queries on partitioned tables that don't filter
+	// on a partition column.
+	Strict bool `yaml:"strict"`
+	// MaxScanBytes makes Impala cancel queries reading more bytes.
+	MaxScanBytes int64 `yaml:"maxScanBytes"`
+}
+
+// Describe appends the partition columns of p.Tables to a tool description.
+func (p PartitionPruning) Describe(description string) string {
+	if len(p.Tables) == 0 {
+		return description
+	}
+	names := make([]string, 0, len(p.Tables))
+	for name := range p.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(description)
+	b.WriteString("\n\nThese tables are partitioned. Filter on their partition columns to bound the data scanned:")
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n- %s: %s", name, strings.Join(p.Tables[name], ", "))
+	}
+	return b.String()
+}
+
+// Conf returns the configuration overrides enforcing p on engine.
+func (p PartitionPruning) Conf(engine string) (map[string]string, error) {
+	conf := map[string]string{}
+	if p.Strict {
+		if engine != hive.EngineHive {
+			return nil, fmt.Errorf("strict partition pruning requires the %q engine", hive.EngineHive)
+		}
+		conf["hive.strict.checks.no.partition.filter"] = "true"
+	}
+	if p.MaxScanBytes < 0 {
+		return nil, fmt.Errorf("maxScanBytes must not be negative")
+	}
+	if p.MaxScanBytes > 0 {
+		if engine != hive.EngineImpala {
+			return nil, fmt.Errorf("maxScanBytes requires the %q engine", hive.EngineImpala)
+		}
+		conf["SCAN_BYTES_LIMIT"] = strconv.FormatInt(p.MaxScanBytes, 10)
+	}
+	return conf, nil
+}
+
+// Args returns the values of params for hive.Interpolate, marking decimals so
+// they're written unquoted.
+func Args(params tools.Parameters, values tools.ParamValues) []any {
+	args := values.AsSlice()
+	for i, p := range params {
+		if s, ok := args[i].(string); ok && p.GetType() == "decimal" {
+			args[i] = hive.Decimal(s)
+		}
+	}
+	return args
+}
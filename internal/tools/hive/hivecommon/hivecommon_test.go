@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hivecommon_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources/hive"
+	"github.com/googleapis/genai-toolbox/internal/tools/hive/hivecommon"
+)
+
+func TestPartitionPruningDescribe(t *testing.T) {
+	p := hivecommon.PartitionPruning{Tables: map[string][]string{
+		"web_logs": {"dt"},
+		"sales":    {"dt", "region"},
+	}}
+	got := p.Describe("Query the warehouse.")
+	want := "Query the warehouse.\n\nThese tables are partitioned. Filter on their partition columns to bound the data scanned:\n- sales: dt, region\n- web_logs: dt"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := (hivecommon.PartitionPruning{}).Describe("Query the warehouse."); got != "Query the warehouse." {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
+
+func TestPartitionPruningConf(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		p       hivecommon.PartitionPruning
+		engine  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			desc:   "strict hive",
+			p:      hivecommon.PartitionPruning{Strict: true},
+			engine: hive.EngineHive,
+			want:   map[string]string{"hive.strict.checks.no.partition.filter": "true"},
+		},
+		{
+			desc:   "impala scan limit",
+			p:      hivecommon.PartitionPruning{MaxScanBytes: 1024},
+			engine: hive.EngineImpala,
+			want:   map[string]string{"SCAN_BYTES_LIMIT": "1024"},
+		},
+		{
+			desc:    "strict impala",
+			p:       hivecommon.PartitionPruning{Strict: true},
+			engine:  hive.EngineImpala,
+			wantErr: true,
+		},
+		{
+			desc:    "hive scan limit",
+			p:       hivecommon.PartitionPruning{MaxScanBytes: 1024},
+			engine:  hive.EngineHive,
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := tc.p.Conf(tc.engine)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect conf: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hivesql_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/hive/hivecommon"
+	"github.com/googleapis/genai-toolbox/internal/tools/hive/hivesql"
+)
+
+func TestParseFromYamlHiveSql(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: hive-sql
+					source: my-instance
+					description: some description
+					statement: |
+						SELECT * FROM sales WHERE dt = ? AND region = ?;
+					parameters:
+						- name: dt
+						  type: string
+						  description: the partition date
+						- name: region
+						  type: string
+						  description: the partition region
+					partitionPruning:
+						maxScanBytes: 1073741824
+			`,
+			want: server.ToolConfigs{
+				"example_tool": hivesql.Config{
+					Name:         "example_tool",
+					Kind:         "hive-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					Statement:    "SELECT * FROM sales WHERE dt = ? AND region = ?;\n",
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("dt", "the partition date"),
+						tools.NewStringParameter("region", "the partition region"),
+					},
+					PartitionPruning: hivecommon.PartitionPruning{MaxScanBytes: 1 << 30},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
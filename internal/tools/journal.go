@@ -0,0 +1,604 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Kinds of journal stores.
+const JournalStoreFile string = "file"
+
+// Statuses of journaled invocations.
+const (
+	JournalStarted   string = "started"
+	JournalCommitted string = "committed"
+	JournalFailed    string = "failed"
+)
+
+// idempotencyKeyParam is the parameter callers pass an idempotency key with.
+const idempotencyKeyParam = "idempotencyKey"
+
+const defaultIdempotencyKeyTTL = 24 * time.Hour
+
+// JournalConfig configures the write-ahead journal of a tool's invocations.
+type JournalConfig struct {
+	// Kind is the kind of store the journal is written to. Defaults to "file".
+	Kind string `yaml:"kind"`
+	// Path is the file of a file store. Tools may share a journal.
+	Path string `yaml:"path" validate:"required"`
+	// IdempotencyKeyTTL is how long the result of an invocation is returned
+	// again for its idempotency key, e.g. "1h". Defaults to 24h.
+	IdempotencyKeyTTL string `yaml:"idempotencyKeyTtl"`
+}
+
+// JournalEntry records a journaled invocation. An invocation is journaled
+// once before it runs and once after, so an entry that stays "started" after a
+// restart belongs to an invocation whose outcome is unknown.
+type JournalEntry struct {
+	ID             string         `json:"id"`
+	Tool           string         `json:"tool"`
+	IdempotencyKey string         `json:"idempotencyKey,omitempty"`
+	Params         map[string]any `json:"params"`
+	Status         string         `json:"status"`
+	StartedAt      time.Time      `json:"startedAt"`
+	FinishedAt     *time.Time     `json:"finishedAt,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	// Result is the result of a committed invocation with an idempotency key.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Resolved reports that an operator set the status of the invocation.
+	Resolved bool `json:"resolved,omitempty"`
+}
+
+// JournalStore persists journal entries. Append must not return before the
+// entry is durable.
+type JournalStore interface {
+	// Append writes a new version of an entry.
+	Append(e JournalEntry) error
+	// Load returns the latest version of every entry that may still be
+	// needed: unfinished entries, and finished entries with an idempotency
+	// key that finished after keepSince. A store may drop other entries.
+	Load(keepSince time.Time) ([]JournalEntry, error)
+}
+
+// Journal is a write-ahead journal of tool invocations.
+type Journal struct {
+	mu    sync.Mutex
+	name  string
+	store JournalStore
+	ttl   time.Duration
+	// entries holds the unfinished entries, and finished entries until their
+	// idempotency key expires. keys maps a tool and idempotency key to the ID
+	// of their entry.
+	entries  map[string]*JournalEntry
+	keys     map[[2]string]string
+	expiring []string
+}
+
+// NewJournal returns a Journal backed by store, with the entries the store
+// still holds. name identifies the journal to operators.
+func NewJournal(name string, store JournalStore, ttl time.Duration) (*Journal, error) {
+	loaded, err := store.Load(time.Now().Add(-ttl))
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{
+		name:    name,
+		store:   store,
+		ttl:     ttl,
+		entries: make(map[string]*JournalEntry, len(loaded)),
+		keys:    make(map[[2]string]string),
+	}
+	sort.Slice(loaded, func(a, b int) bool { return finishedAt(loaded[a]).Before(finishedAt(loaded[b])) })
+	for i := range loaded {
+		e := &loaded[i]
+		j.entries[e.ID] = e
+		if e.IdempotencyKey != "" {
+			j.keys[[2]string{e.Tool, e.IdempotencyKey}] = e.ID
+		}
+		if e.Status != JournalStarted {
+			j.expiring = append(j.expiring, e.ID)
+		}
+	}
+	return j, nil
+}
+
+func finishedAt(e JournalEntry) time.Time {
+	if e.FinishedAt != nil {
+		return *e.FinishedAt
+	}
+	return e.StartedAt
+}
+
+// Name identifies the journal, e.g. the path of its file.
+func (j *Journal) Name() string {
+	return j.name
+}
+
+// Pending returns the journaled invocations that haven't finished, oldest
+// first. After a restart, these are the invocations whose outcome is unknown.
+func (j *Journal) Pending() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := make([]JournalEntry, 0)
+	for _, e := range j.entries {
+		if e.Status == JournalStarted {
+			out = append(out, *e)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].StartedAt.Before(out[b].StartedAt) })
+	return out
+}
+
+// Resolve records the outcome of an invocation that didn't finish, once an
+// operator has checked whether its writes were committed.
+func (j *Journal) Resolve(id, status string) (JournalEntry, error) {
+	if status != JournalCommitted && status != JournalFailed {
+		return JournalEntry{}, fmt.Errorf("invalid status %q, must be %q or %q", status, JournalCommitted, JournalFailed)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e, ok := j.entries[id]
+	if !ok || e.Status != JournalStarted {
+		return JournalEntry{}, fmt.Errorf("journal %q has no unfinished invocation %q", j.name, id)
+	}
+	resolved := *e
+	now := time.Now()
+	resolved.Status, resolved.FinishedAt, resolved.Resolved = status, &now, true
+	if err := j.store.Append(resolved); err != nil {
+		return JournalEntry{}, fmt.Errorf("unable to write journal: %w", err)
+	}
+	*e = resolved
+	j.expire(e)
+	return resolved, nil
+}
+
+// begin journals the start of an invocation. If a committed invocation with
+// the same tool and idempotency key is found, its entry is returned instead
+// and the invocation must not run.
+func (j *Journal) begin(tool, key string, params map[string]any) (string, *JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.prune()
+	if key != "" {
+		if id, ok := j.keys[[2]string{tool, key}]; ok {
+			prev := j.entries[id]
+			if !sameParams(prev.Params, params) {
+				return "", nil, NewToolError(ErrorCategoryValidation, ErrorCodeInvalidRequest, "the idempotency key was already used with different parameters", fmt.Errorf("idempotency key %q was already used with different parameters", key))
+			}
+			switch prev.Status {
+			case JournalCommitted:
+				replay := *prev
+				return "", &replay, nil
+			case JournalStarted:
+				return "", nil, NewToolError(ErrorCategoryValidation, ErrorCodeInvalidRequest, "an invocation with the idempotency key hasn't finished, or its outcome is unknown", fmt.Errorf("an invocation with idempotency key %q hasn't finished, or its outcome is unknown", key))
+			}
+			// a failed invocation may be retried with the same key
+		}
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	e := &JournalEntry{
+		ID:             "jrn-" + hex.EncodeToString(b),
+		Tool:           tool,
+		IdempotencyKey: key,
+		Params:         params,
+		Status:         JournalStarted,
+		StartedAt:      time.Now(),
+	}
+	// the invocation must not run unless its start is durable
+	if err := j.store.Append(*e); err != nil {
+		return "", nil, fmt.Errorf("unable to write journal: %w", err)
+	}
+	j.entries[e.ID] = e
+	if key != "" {
+		j.keys[[2]string{tool, key}] = e.ID
+	}
+	return e.ID, nil, nil
+}
+
+// finish journals the outcome of an invocation.
+func (j *Journal) finish(ctx context.Context, id string, res any, invokeErr error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	e, ok := j.entries[id]
+	if !ok {
+		return
+	}
+	finished := *e
+	now := time.Now()
+	finished.FinishedAt = &now
+	finished.Status = JournalCommitted
+	if invokeErr != nil {
+		finished.Status = JournalFailed
+		finished.Error = invokeErr.Error()
+	} else if finished.IdempotencyKey != "" {
+		if b, err := json.Marshal(res); err == nil {
+			finished.Result = b
+		}
+	}
+	if err := j.store.Append(finished); err != nil {
+		// the entry stays unfinished, so operators can reconcile it
+		if logger, lerr := util.LoggerFromContext(ctx); lerr == nil {
+			logger.ErrorContext(ctx, fmt.Sprintf("unable to write the outcome of invocation %q to journal %q: %s", id, j.name, err))
+		}
+		return
+	}
+	*e = finished
+	j.expire(e)
+}
+
+// expire schedules a finished entry to be dropped once its idempotency key
+// expires. Must be called with the lock held.
+func (j *Journal) expire(e *JournalEntry) {
+	if e.IdempotencyKey == "" {
+		delete(j.entries, e.ID)
+		return
+	}
+	j.expiring = append(j.expiring, e.ID)
+}
+
+// prune drops finished entries whose idempotency key expired. Must be called
+// with the lock held.
+func (j *Journal) prune() {
+	cutoff := time.Now().Add(-j.ttl)
+	n := 0
+	for _, id := range j.expiring {
+		e, ok := j.entries[id]
+		if ok && finishedAt(*e).After(cutoff) {
+			break
+		}
+		n++
+		if ok {
+			delete(j.entries, id)
+			if k := [2]string{e.Tool, e.IdempotencyKey}; j.keys[k] == id {
+				delete(j.keys, k)
+			}
+		}
+	}
+	j.expiring = j.expiring[n:]
+}
+
+func sameParams(a, b map[string]any) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+// fileJournalStore appends entries to a file as JSON lines, and syncs the
+// file after every entry.
+type fileJournalStore struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+func newFileJournalStore(path string) (*fileJournalStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create journal directory: %w", err)
+	}
+	return &fileJournalStore{path: path}, nil
+}
+
+func (s *fileJournalStore) Append(e JournalEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return fmt.Errorf("journal %q is not loaded", s.path)
+	}
+	if _, err := s.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+// Load reads the file and rewrites it with only the entries that are still
+// needed, so the file doesn't grow without bounds.
+func (s *fileJournalStore) Load(keepSince time.Time) ([]JournalEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make(map[string]JournalEntry)
+	order := make([]string, 0)
+	if f, err := os.Open(s.path); err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			var e JournalEntry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				// a crash can leave the last line incomplete
+				continue
+			}
+			if _, ok := entries[e.ID]; !ok {
+				order = append(order, e.ID)
+			}
+			entries[e.ID] = e
+		}
+		err := scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read journal %q: %w", s.path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to open journal %q: %w", s.path, err)
+	}
+
+	kept := make([]JournalEntry, 0)
+	var buf bytes.Buffer
+	for _, id := range order {
+		e := entries[id]
+		if e.Status != JournalStarted && (e.IdempotencyKey == "" || !finishedAt(e).After(keepSince)) {
+			continue
+		}
+		kept = append(kept, e)
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(append(b, '\n'))
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return nil, fmt.Errorf("unable to compact journal %q: %w", s.path, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return nil, fmt.Errorf("unable to compact journal %q: %w", s.path, err)
+	}
+	if s.f != nil {
+		s.f.Close()
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open journal %q: %w", s.path, err)
+	}
+	s.f = f
+	return kept, nil
+}
+
+// journals holds the open journals by path, so tools sharing a journal, and
+// tools re-initialized on reload, write to the same one.
+var (
+	journalsMu sync.Mutex
+	journals   = make(map[string]*Journal)
+)
+
+// Journals returns every open journal, sorted by name.
+func Journals() []*Journal {
+	journalsMu.Lock()
+	defer journalsMu.Unlock()
+	out := make([]*Journal, 0, len(journals))
+	for _, j := range journals {
+		out = append(out, j)
+	}
+	slices.SortFunc(out, func(a, b *Journal) int {
+		if a.name < b.name {
+			return -1
+		}
+		if a.name > b.name {
+			return 1
+		}
+		return 0
+	})
+	return out
+}
+
+func (c JournalConfig) open() (*Journal, error) {
+	if c.Kind != "" && c.Kind != JournalStoreFile {
+		return nil, fmt.Errorf("unknown journal kind %q, must be %q", c.Kind, JournalStoreFile)
+	}
+	ttl := defaultIdempotencyKeyTTL
+	if c.IdempotencyKeyTTL != "" {
+		d, err := time.ParseDuration(c.IdempotencyKeyTTL)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid idempotencyKeyTtl %q", c.IdempotencyKeyTTL)
+		}
+		ttl = d
+	}
+	path, err := filepath.Abs(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid journal path %q: %w", c.Path, err)
+	}
+
+	journalsMu.Lock()
+	defer journalsMu.Unlock()
+	if j, ok := journals[path]; ok {
+		if j.ttl != ttl {
+			return nil, fmt.Errorf("journal %q is already used with an idempotencyKeyTtl of %s", path, j.ttl)
+		}
+		return j, nil
+	}
+	store, err := newFileJournalStore(path)
+	if err != nil {
+		return nil, err
+	}
+	j, err := NewJournal(path, store, ttl)
+	if err != nil {
+		return nil, err
+	}
+	journals[path] = j
+	return j, nil
+}
+
+// JournalToolConfig wraps a ToolConfig so invocations of the tool it
+// initializes are journaled, and accept an `idempotencyKey` parameter.
+type JournalToolConfig struct {
+	ToolConfig
+	Journal JournalConfig
+}
+
+// validate interface
+var _ ToolConfig = JournalToolConfig{}
+
+func (cfg JournalToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	j, err := cfg.Journal.open()
+	if err != nil {
+		return nil, fmt.Errorf("invalid journal: %w", err)
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range t.Manifest().Parameters {
+		if p.Name == idempotencyKeyParam {
+			return nil, fmt.Errorf("invalid journal: tool already has a parameter named %q", idempotencyKeyParam)
+		}
+	}
+	return journalTool{toolWrapper: toolWrapper{t}, journal: j}, nil
+}
+
+type journalTool struct {
+	toolWrapper
+	journal *Journal
+}
+
+const idempotencyKeyDescription = "Optional key identifying this request. Retrying a request with the same key returns the result of the first successful invocation instead of running it again."
+
+func (t journalTool) Manifest() Manifest {
+	m := t.Tool.Manifest()
+	m.Parameters = append(slices.Clone(m.Parameters), ParameterManifest{
+		Name:         idempotencyKeyParam,
+		Type:         typeString,
+		Description:  idempotencyKeyDescription,
+		AuthServices: []string{},
+	})
+	return m
+}
+
+func (t journalTool) McpManifest() McpManifest {
+	m := t.Tool.McpManifest()
+	props := make(map[string]ParameterMcpManifest, len(m.InputSchema.Properties)+1)
+	for k, v := range m.InputSchema.Properties {
+		props[k] = v
+	}
+	props[idempotencyKeyParam] = ParameterMcpManifest{Type: typeString, Description: idempotencyKeyDescription}
+	m.InputSchema.Properties = props
+	return m
+}
+
+func (t journalTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	params, err := t.Tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, err
+	}
+	key := ""
+	if v, ok := data[idempotencyKeyParam]; ok && v != nil {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse value for %q: must be a string", idempotencyKeyParam)
+		}
+		key = s
+	}
+	return append(params, ParamValue{Name: idempotencyKeyParam, Value: key}), nil
+}
+
+func (t journalTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	key := ""
+	if n := len(params); n > 0 && params[n-1].Name == idempotencyKeyParam {
+		key, _ = params[n-1].Value.(string)
+		params = params[:n-1]
+	}
+	id, replay, err := t.journal.begin(t.McpManifest().Name, key, params.AsMap())
+	if err != nil {
+		return nil, err
+	}
+	if replay != nil {
+		// an invocation resolved by an operator has no result to return
+		if len(replay.Result) == 0 {
+			return nil, nil
+		}
+		var res any
+		if err := json.Unmarshal(replay.Result, &res); err != nil {
+			return nil, fmt.Errorf("unable to read the result of invocation %q from the journal: %w", replay.ID, err)
+		}
+		return res, nil
+	}
+	res, err := t.Tool.Invoke(ctx, params)
+	t.journal.finish(ctx, id, res, err)
+	return res, err
+}
+
+// Stream is like Invoke, journaling the rows that were sent as the result.
+// Replayed invocations send the rows of their journaled result.
+func (t journalTool) Stream(ctx context.Context, params ParamValues, send func(row any) error) error {
+	key := ""
+	if n := len(params); n > 0 && params[n-1].Name == idempotencyKeyParam {
+		key, _ = params[n-1].Value.(string)
+		params = params[:n-1]
+	}
+	id, replay, err := t.journal.begin(t.McpManifest().Name, key, params.AsMap())
+	if err != nil {
+		return err
+	}
+	if replay != nil {
+		if len(replay.Result) == 0 {
+			return nil
+		}
+		var res any
+		if err := json.Unmarshal(replay.Result, &res); err != nil {
+			return fmt.Errorf("unable to read the result of invocation %q from the journal: %w", replay.ID, err)
+		}
+		// the result of an invocation that wasn't streamed may not be a list
+		rows, ok := res.([]any)
+		if !ok {
+			rows = []any{res}
+		}
+		for _, row := range rows {
+			if err := send(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// the rows are only kept to be replayed
+	rows := []any{}
+	err = streamTool(ctx, t.Tool, params, func(row any) error {
+		if key != "" {
+			rows = append(rows, row)
+		}
+		return send(row)
+	})
+	t.journal.finish(ctx, id, rows, err)
+	return err
+}
+
+// Preview forwards to the wrapped tool, without the idempotency key. Previews
+// aren't journaled.
+func (t journalTool) Preview(ctx context.Context, params ParamValues) (StatementPreview, error) {
+	if n := len(params); n > 0 && params[n-1].Name == idempotencyKeyParam {
+		params = params[:n-1]
+	}
+	return PreviewTool(ctx, t.Tool, params)
+}
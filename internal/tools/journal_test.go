@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestJournalToolConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	calls := 0
+	cfg := tools.JournalToolConfig{
+		ToolConfig: rowsToolConfig{tool: rowsTool{fakeTool: fakeTool{name: "insert_row"}, rows: func() any {
+			calls++
+			return []any{map[string]any{"inserted": calls}}
+		}}},
+		Journal: tools.JournalConfig{Path: path},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	m := tool.Manifest()
+	if n := len(m.Parameters); n != 1 || m.Parameters[0].Name != "idempotencyKey" {
+		t.Fatalf("expected an idempotencyKey parameter, got %+v", m.Parameters)
+	}
+
+	invoke := func(key string) any {
+		t.Helper()
+		params, err := tool.ParseParams(map[string]any{"idempotencyKey": key}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		res, err := tool.Invoke(context.Background(), params)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return res
+	}
+	invoke("a")
+	invoke("a")
+	if calls != 1 {
+		t.Fatalf("expected a retry with the same key to be replayed, tool was invoked %d times", calls)
+	}
+	invoke("b")
+	invoke("")
+	invoke("")
+	if calls != 4 {
+		t.Fatalf("expected 4 invocations, got %d", calls)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read journal: %s", err)
+	}
+	// every invocation is journaled when it starts and when it finishes
+	if n := strings.Count(string(b), "\n"); n != 8 {
+		t.Errorf("expected 8 journal records, got %d", n)
+	}
+}
+
+func TestJournalRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	// a crash left an invocation in doubt, and an incomplete last line
+	content := `{"id":"jrn-1","tool":"insert_row","idempotencyKey":"a","params":{},"status":"started","startedAt":"2025-01-01T00:00:00Z"}
+{"id":"jrn-2","tool":"insert_row","params":{},"status":"started","startedAt":"2025-01-01T00:00:01Z"}
+{"id":"jrn-2","tool":"insert_row","params":{},"status":"committed","startedAt":"2025-01-01T00:00:01Z","finishedAt":"2025-01-01T00:00:02Z"}
+{"id":"jrn-3","tool":"ins`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write journal: %s", err)
+	}
+	calls := 0
+	cfg := tools.JournalToolConfig{
+		ToolConfig: rowsToolConfig{tool: rowsTool{fakeTool: fakeTool{name: "insert_row"}, rows: func() any {
+			calls++
+			return nil
+		}}},
+		Journal: tools.JournalConfig{Path: path},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var journal *tools.Journal
+	for _, j := range tools.Journals() {
+		if j.Name() == path {
+			journal = j
+		}
+	}
+	if journal == nil {
+		t.Fatalf("journal %q is not open", path)
+	}
+	pending := journal.Pending()
+	if len(pending) != 1 || pending[0].ID != "jrn-1" {
+		t.Fatalf("expected jrn-1 to be pending, got %+v", pending)
+	}
+
+	params, err := tool.ParseParams(map[string]any{"idempotencyKey": "a"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := tool.Invoke(context.Background(), params); err == nil {
+		t.Fatalf("expected an error retrying an invocation in doubt")
+	}
+	if _, err := journal.Resolve("jrn-1", "unknown"); err == nil {
+		t.Fatalf("expected an error resolving with an invalid status")
+	}
+	if _, err := journal.Resolve("jrn-1", tools.JournalFailed); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := tool.Invoke(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a failed invocation to be retried, tool was invoked %d times", calls)
+	}
+	if len(journal.Pending()) != 0 {
+		t.Errorf("expected no pending invocations, got %+v", journal.Pending())
+	}
+}
+
+func TestJournalToolConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	tcs := []tools.JournalConfig{
+		{Kind: "redis", Path: filepath.Join(dir, "a.jsonl")},
+		{Path: filepath.Join(dir, "b.jsonl"), IdempotencyKeyTTL: "soon"},
+	}
+	for _, tc := range tcs {
+		cfg := tools.JournalToolConfig{ToolConfig: rowsToolConfig{}, Journal: tc}
+		if _, err := cfg.Initialize(nil); err == nil {
+			t.Errorf("expected error for %+v", tc)
+		}
+	}
+}
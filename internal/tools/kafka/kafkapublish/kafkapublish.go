@@ -0,0 +1,171 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkapublish
+
+import (
+	"context"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	kafkasrc "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/segmentio/kafka-go"
+)
+
+const kind string = "kafka-publish"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	KafkaWriter() *kafka.Writer
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &kafkasrc.Source{}
+
+var compatibleSources = [...]string{kafkasrc.SourceKind}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Source       string           `yaml:"source" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	Topic        string           `yaml:"topic" validate:"required"`
+	Key          string           `yaml:"key"`
+	Value        string           `yaml:"value" validate:"required"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		Topic:        cfg.Topic,
+		Key:          cfg.Key,
+		Value:        cfg.Value,
+		AuthRequired: cfg.AuthRequired,
+		Writer:       s.KafkaWriter(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Writer      *kafka.Writer
+	Topic       string
+	Key         string
+	Value       string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	msg, err := buildMessage(t.Topic, t.Key, t.Value, params.AsMap())
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Writer.WriteMessages(ctx, msg); err != nil {
+		return nil, fmt.Errorf("unable to publish message: %w", err)
+	}
+	return map[string]any{
+		"topic": msg.Topic,
+		"key":   string(msg.Key),
+		"bytes": len(msg.Value),
+	}, nil
+}
+
+// buildMessage renders the key and value templates into a message.
+func buildMessage(topic, keyTmpl, valueTmpl string, paramsMap map[string]any) (kafka.Message, error) {
+	msg := kafka.Message{Topic: topic}
+	if keyTmpl != "" {
+		key, err := tools.PopulateTemplateWithJSON("KafkaMessageKey", keyTmpl, paramsMap)
+		if err != nil {
+			return msg, fmt.Errorf("error populating key: %s", err)
+		}
+		msg.Key = []byte(key)
+	}
+	value, err := tools.PopulateTemplateWithJSON("KafkaMessageValue", valueTmpl, paramsMap)
+	if err != nil {
+		return msg, fmt.Errorf("error populating value: %s", err)
+	}
+	msg.Value = []byte(value)
+	return msg, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkapublish
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseFromYamlKafkaPublish(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				publish_order_event:
+					kind: kafka-publish
+					source: my-kafka-instance
+					description: publish a test order event
+					topic: orders
+					key: "{{.order_id}}"
+					value: '{"order_id": {{json .order_id}}, "status": "created"}'
+					parameters:
+						- name: order_id
+						  type: string
+						  description: id of the order
+			`,
+			want: server.ToolConfigs{
+				"publish_order_event": Config{
+					Name:         "publish_order_event",
+					Kind:         "kafka-publish",
+					Source:       "my-kafka-instance",
+					Description:  "publish a test order event",
+					Topic:        "orders",
+					Key:          "{{.order_id}}",
+					Value:        `{"order_id": {{json .order_id}}, "status": "created"}`,
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("order_id", "id of the order"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	msg, err := buildMessage("orders", "{{.order_id}}", `{"order_id": {{json .order_id}}}`, map[string]any{"order_id": `a"1`})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if msg.Topic != "orders" {
+		t.Errorf("unexpected topic: got %q", msg.Topic)
+	}
+	if got, want := string(msg.Key), `a"1`; got != want {
+		t.Errorf("unexpected key: want %q, got %q", want, got)
+	}
+	if got, want := string(msg.Value), `{"order_id": "a\"1"}`; got != want {
+		t.Errorf("unexpected value: want %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkatail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	kafkasrc "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/segmentio/kafka-go"
+)
+
+const kind string = "kafka-tail"
+
+const (
+	limitKey = "limit"
+	// maxLimit is the largest number of messages a single invocation reads.
+	maxLimit = 1000
+	// maxBatchBytes is the maximum size of a single fetch from the broker.
+	maxBatchBytes = 10 << 20
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	KafkaDialer() *kafka.Dialer
+	KafkaBrokers() []string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &kafkasrc.Source{}
+
+var compatibleSources = [...]string{kafkasrc.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	Topic        string   `yaml:"topic" validate:"required"`
+	Partition    int      `yaml:"partition"`
+	Limit        int      `yaml:"limit"`
+	Timeout      string   `yaml:"timeout"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if cfg.Partition < 0 {
+		return nil, fmt.Errorf("partition for %q tool must not be negative", kind)
+	}
+	limit := cfg.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	if limit < 0 || limit > maxLimit {
+		return nil, fmt.Errorf("limit for %q tool must be between 1 and %d", kind, maxLimit)
+	}
+	timeout := 10 * time.Second
+	if cfg.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q for %q tool: must be a positive duration", cfg.Timeout, kind)
+		}
+	}
+
+	parameters := tools.Parameters{
+		tools.NewIntParameterWithDefault(limitKey, limit, fmt.Sprintf("Number of most recent messages to return, at most %d.", maxLimit)),
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		Topic:        cfg.Topic,
+		Partition:    cfg.Partition,
+		Timeout:      timeout,
+		AuthRequired: cfg.AuthRequired,
+		Dialer:       s.KafkaDialer(),
+		Brokers:      s.KafkaBrokers(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Dialer      *kafka.Dialer
+	Brokers     []string
+	Topic       string
+	Partition   int
+	Timeout     time.Duration
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	limit, ok := params.AsMap()[limitKey].(int)
+	if !ok || limit <= 0 || limit > maxLimit {
+		return nil, fmt.Errorf("invalid %q parameter: must be between 1 and %d", limitKey, maxLimit)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	conn, err := t.dialLeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read offsets: %w", err)
+	}
+	start := max(first, last-int64(limit))
+	if start >= last {
+		return []any{}, nil
+	}
+	if _, err := conn.Seek(start, kafka.SeekAbsolute); err != nil {
+		return nil, fmt.Errorf("unable to seek to offset %d: %w", start, err)
+	}
+	return readMessages(func() messageBatch { return conn.ReadBatch(1, maxBatchBytes) }, start, last)
+}
+
+// messageBatch is implemented by *kafka.Batch.
+type messageBatch interface {
+	ReadMessage() (kafka.Message, error)
+	Close() error
+}
+
+// readMessages reads the messages from offset start up to last, using
+// readBatch to fetch the next batch. It stops early once a batch holds no
+// message: the offsets before the high watermark may only contain records
+// that aren't returned, such as the control markers of transactions.
+func readMessages(readBatch func() messageBatch, start, last int64) ([]any, error) {
+	rows := make([]any, 0, last-start)
+	for offset := start; offset < last; {
+		batch := readBatch()
+		read := 0
+		for offset < last {
+			msg, err := batch.ReadMessage()
+			if err != nil {
+				break
+			}
+			rows = append(rows, messageToRow(msg))
+			offset = msg.Offset + 1
+			read++
+		}
+		if err := batch.Close(); err != nil {
+			return nil, fmt.Errorf("unable to read messages after %d of %d: %w", len(rows), last-start, err)
+		}
+		if read == 0 {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// dialLeader connects to the leader of the tool's partition using the first
+// reachable broker.
+func (t Tool) dialLeader(ctx context.Context) (*kafka.Conn, error) {
+	var errs []error
+	for _, broker := range t.Brokers {
+		conn, err := t.Dialer.DialLeader(ctx, "tcp", broker, t.Topic, t.Partition)
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, fmt.Errorf("unable to connect to leader of %s/%d: %w", t.Topic, t.Partition, errors.Join(errs...))
+}
+
+// messageToRow converts a message into a row. Values that are valid JSON are
+// decoded, so structured events are returned as objects instead of strings.
+func messageToRow(msg kafka.Message) map[string]any {
+	var value any = string(msg.Value)
+	if json.Valid(msg.Value) {
+		var v any
+		if err := json.Unmarshal(msg.Value, &v); err == nil {
+			value = v
+		}
+	}
+	row := map[string]any{
+		"partition": msg.Partition,
+		"offset":    msg.Offset,
+		"key":       string(msg.Key),
+		"value":     value,
+		"timestamp": msg.Time.UTC().Format(time.RFC3339Nano),
+	}
+	if len(msg.Headers) > 0 {
+		headers := make(map[string]string, len(msg.Headers))
+		for _, h := range msg.Headers {
+			headers[h.Key] = string(h.Value)
+		}
+		row["headers"] = headers
+	}
+	return row
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
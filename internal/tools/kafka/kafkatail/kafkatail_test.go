@@ -0,0 +1,247 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkatail
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	kafkasrc "github.com/googleapis/genai-toolbox/internal/sources/kafka"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestParseFromYamlKafkaTail(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				tail_orders:
+					kind: kafka-tail
+					source: my-kafka-instance
+					description: show the latest order events
+					topic: orders
+					partition: 2
+					limit: 20
+					timeout: 5s
+			`,
+			want: server.ToolConfigs{
+				"tail_orders": Config{
+					Name:         "tail_orders",
+					Kind:         "kafka-tail",
+					Source:       "my-kafka-instance",
+					Description:  "show the latest order events",
+					Topic:        "orders",
+					Partition:    2,
+					Limit:        20,
+					Timeout:      "5s",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeValidation(t *testing.T) {
+	srcs := map[string]sources.Source{"k": &kafkasrc.Source{Name: "k", Kind: kafkasrc.SourceKind}}
+	tcs := []struct {
+		desc string
+		cfg  Config
+	}{
+		{desc: "negative partition", cfg: Config{Source: "k", Topic: "t", Partition: -1}},
+		{desc: "limit too large", cfg: Config{Source: "k", Topic: "t", Limit: maxLimit + 1}},
+		{desc: "invalid timeout", cfg: Config{Source: "k", Topic: "t", Timeout: "soon"}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := tc.cfg.Initialize(srcs); err == nil {
+				t.Fatalf("expected initialization to fail")
+			}
+		})
+	}
+
+	tool, err := Config{Name: "tail", Source: "k", Topic: "t"}.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := params.AsMap()[limitKey]; got != 10 {
+		t.Errorf("unexpected default limit: got %v", got)
+	}
+}
+
+func TestMessageToRow(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tcs := []struct {
+		desc string
+		msg  kafka.Message
+		want map[string]any
+	}{
+		{
+			desc: "json value",
+			msg:  kafka.Message{Partition: 1, Offset: 42, Key: []byte("k1"), Value: []byte(`{"status": "created"}`), Time: ts},
+			want: map[string]any{
+				"partition": 1,
+				"offset":    int64(42),
+				"key":       "k1",
+				"value":     map[string]any{"status": "created"},
+				"timestamp": "2025-01-02T03:04:05Z",
+			},
+		},
+		{
+			desc: "text value with headers",
+			msg: kafka.Message{Offset: 7, Value: []byte("hello"), Time: ts,
+				Headers: []kafka.Header{{Key: "trace", Value: []byte("abc")}}},
+			want: map[string]any{
+				"partition": 0,
+				"offset":    int64(7),
+				"key":       "",
+				"value":     "hello",
+				"timestamp": "2025-01-02T03:04:05Z",
+				"headers":   map[string]string{"trace": "abc"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, messageToRow(tc.msg)); diff != "" {
+				t.Fatalf("unexpected row: diff %v", diff)
+			}
+		})
+	}
+}
+
+type fakeBatch struct {
+	msgs []kafka.Message
+	err  error
+}
+
+func (b *fakeBatch) ReadMessage() (kafka.Message, error) {
+	if len(b.msgs) == 0 {
+		return kafka.Message{}, io.EOF
+	}
+	msg := b.msgs[0]
+	b.msgs = b.msgs[1:]
+	return msg, nil
+}
+
+func (b *fakeBatch) Close() error {
+	return b.err
+}
+
+func TestReadMessages(t *testing.T) {
+	msg := func(offset int64) kafka.Message {
+		return kafka.Message{Offset: offset, Value: []byte("v")}
+	}
+	tcs := []struct {
+		desc        string
+		batches     []*fakeBatch
+		start, last int64
+		wantOffsets []int64
+		wantErr     bool
+	}{
+		{
+			desc:        "several batches",
+			batches:     []*fakeBatch{{msgs: []kafka.Message{msg(3), msg(4)}}, {msgs: []kafka.Message{msg(5)}}},
+			start:       3,
+			last:        6,
+			wantOffsets: []int64{3, 4, 5},
+		},
+		{
+			desc:        "stops at the high watermark",
+			batches:     []*fakeBatch{{msgs: []kafka.Message{msg(3), msg(4), msg(5)}}},
+			start:       3,
+			last:        5,
+			wantOffsets: []int64{3, 4},
+		},
+		{
+			// a transactional producer writes a control marker after the
+			// last message, which is never returned
+			desc:        "high watermark after the last message",
+			batches:     []*fakeBatch{{msgs: []kafka.Message{msg(3), msg(4)}}, {}},
+			start:       3,
+			last:        6,
+			wantOffsets: []int64{3, 4},
+		},
+		{
+			desc:    "batch error",
+			batches: []*fakeBatch{{msgs: []kafka.Message{msg(3)}}, {err: kafka.RequestTimedOut}},
+			start:   3,
+			last:    6,
+			wantErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			next := 0
+			readBatch := func() messageBatch {
+				if next == len(tc.batches) {
+					t.Fatalf("read more than %d batches", len(tc.batches))
+				}
+				next++
+				return tc.batches[next-1]
+			}
+			rows, err := readMessages(readBatch, tc.start, tc.last)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var offsets []int64
+			for _, row := range rows {
+				offsets = append(offsets, row.(map[string]any)["offset"].(int64))
+			}
+			if diff := cmp.Diff(tc.wantOffsets, offsets); diff != "" {
+				t.Fatalf("incorrect offsets: diff %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldapsearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	ldapsrc "github.com/googleapis/genai-toolbox/internal/sources/ldap"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "ldap-search"
+
+const (
+	defaultSizeLimit = 100
+	// maxSizeLimit is the largest number of entries a single search returns.
+	maxSizeLimit = 1000
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	LDAPClient() *ldapsrc.Client
+	LDAPBaseDN() string
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &ldapsrc.Source{}
+
+var compatibleSources = [...]string{ldapsrc.SourceKind}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Source       string           `yaml:"source" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	BaseDN       string           `yaml:"baseDn"`
+	Scope        string           `yaml:"scope"`
+	Filter       string           `yaml:"filter" validate:"required"`
+	Attributes   []string         `yaml:"attributes"`
+	SizeLimit    int              `yaml:"sizeLimit"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	AuthRequired []string         `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	baseDN := cfg.BaseDN
+	if baseDN == "" {
+		baseDN = s.LDAPBaseDN()
+	}
+	if baseDN == "" {
+		return nil, fmt.Errorf("%q tool requires a baseDn, or a source with a baseDn", kind)
+	}
+	scope, err := ldapsrc.ParseScope(cfg.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scope for %q tool: %w", kind, err)
+	}
+	sizeLimit := cfg.SizeLimit
+	if sizeLimit == 0 {
+		sizeLimit = defaultSizeLimit
+	}
+	if sizeLimit < 0 || sizeLimit > maxSizeLimit {
+		return nil, fmt.Errorf("sizeLimit for %q tool must be between 1 and %d", kind, maxSizeLimit)
+	}
+
+	baseDNTemplate, err := template.New("baseDn").Option("missingkey=error").Parse(baseDN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseDn for %q tool: %w", kind, err)
+	}
+	filterTemplate, err := template.New("filter").Option("missingkey=error").Parse(cfg.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter for %q tool: %w", kind, err)
+	}
+	// render the filter with placeholder values, so a malformed filter is
+	// reported when the tool is loaded instead of when it's invoked
+	samples := make(map[string]any, len(cfg.Parameters))
+	for _, p := range cfg.Parameters {
+		samples[p.GetName()] = "x"
+		if p.GetType() == "array" {
+			samples[p.GetName()] = []any{"x"}
+		}
+	}
+	sample, err := render(filterTemplate, samples, ldapsrc.EscapeFilter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter for %q tool: %w", kind, err)
+	}
+	if err := ldapsrc.ValidateFilter(sample); err != nil {
+		return nil, fmt.Errorf("invalid filter for %q tool: %w", kind, err)
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:           cfg.Name,
+		Kind:           kind,
+		Parameters:     cfg.Parameters,
+		AuthRequired:   cfg.AuthRequired,
+		Client:         s.LDAPClient(),
+		baseDNTemplate: baseDNTemplate,
+		filterTemplate: filterTemplate,
+		scope:          scope,
+		attributes:     cfg.Attributes,
+		sizeLimit:      sizeLimit,
+		manifest:       tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:    mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Client         *ldapsrc.Client
+	baseDNTemplate *template.Template
+	filterTemplate *template.Template
+	scope          ldapsrc.Scope
+	attributes     []string
+	sizeLimit      int
+	manifest       tools.Manifest
+	mcpManifest    tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	paramsMap := params.AsMap()
+	baseDN, err := render(t.baseDNTemplate, paramsMap, ldapsrc.EscapeDN)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render baseDn: %w", err)
+	}
+	filter, err := render(t.filterTemplate, paramsMap, ldapsrc.EscapeFilter)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render filter: %w", err)
+	}
+
+	entries, err := t.Client.Search(ctx, ldapsrc.SearchRequest{
+		BaseDN:     baseDN,
+		Scope:      t.scope,
+		Filter:     filter,
+		Attributes: t.attributes,
+		SizeLimit:  t.sizeLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to search: %w", err)
+	}
+	out := make([]any, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.Row())
+	}
+	return out, nil
+}
+
+// render executes a template with the parameter values escaped, so that
+// values are always matched literally and can't change the structure of the
+// filter or DN.
+func render(t *template.Template, params map[string]any, escape func(string) string) (string, error) {
+	data := make(map[string]any, len(params))
+	for k, v := range params {
+		data[k] = escapeValue(v, escape)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func escapeValue(v any, escape func(string) string) any {
+	switch v := v.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = escapeValue(item, escape)
+		}
+		return out
+	case string:
+		return escape(v)
+	case bool:
+		// the LDAP Boolean syntax
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case nil:
+		return ""
+	default:
+		return escape(fmt.Sprint(v))
+	}
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldapsearch
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	ldapsrc "github.com/googleapis/genai-toolbox/internal/sources/ldap"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseFromYamlLDAPSearch(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				find_user:
+					kind: ldap-search
+					source: my-ldap-instance
+					description: find a user by their uid
+					baseDn: ou=people,dc=example,dc=com
+					scope: one
+					filter: (&(objectClass=person)(uid={{.uid}}))
+					attributes: [cn, mail]
+					sizeLimit: 10
+					parameters:
+						- name: uid
+						  type: string
+						  description: the uid of the user
+			`,
+			want: server.ToolConfigs{
+				"find_user": Config{
+					Name:        "find_user",
+					Kind:        "ldap-search",
+					Source:      "my-ldap-instance",
+					Description: "find a user by their uid",
+					BaseDN:      "ou=people,dc=example,dc=com",
+					Scope:       "one",
+					Filter:      "(&(objectClass=person)(uid={{.uid}}))",
+					Attributes:  []string{"cn", "mail"},
+					SizeLimit:   10,
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("uid", "the uid of the user"),
+					},
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"l": &ldapsrc.Source{Name: "l", Kind: ldapsrc.SourceKind, BaseDN: "dc=example,dc=com"},
+	}
+	uid := tools.NewStringParameter("uid", "the uid of the user")
+	tcs := []struct {
+		desc    string
+		cfg     Config
+		wantErr bool
+	}{
+		{desc: "valid", cfg: Config{Source: "l", Filter: "(uid={{.uid}})", Parameters: tools.Parameters{uid}}},
+		{desc: "unknown source", cfg: Config{Source: "x", Filter: "(uid=*)"}, wantErr: true},
+		{desc: "invalid scope", cfg: Config{Source: "l", Scope: "subtree", Filter: "(uid=*)"}, wantErr: true},
+		{desc: "invalid size limit", cfg: Config{Source: "l", Filter: "(uid=*)", SizeLimit: maxSizeLimit + 1}, wantErr: true},
+		{desc: "unbalanced filter", cfg: Config{Source: "l", Filter: "(uid={{.uid}}", Parameters: tools.Parameters{uid}}, wantErr: true},
+		{desc: "unknown parameter", cfg: Config{Source: "l", Filter: "(uid={{.name}})", Parameters: tools.Parameters{uid}}, wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.cfg.Initialize(srcs)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderEscapesValues(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"l": &ldapsrc.Source{Name: "l", Kind: ldapsrc.SourceKind},
+	}
+	cfg := Config{
+		Source: "l",
+		BaseDN: "ou={{.ou}},dc=example,dc=com",
+		Filter: "(|{{range .uids}}(uid={{.}}){{end}})",
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("ou", "the organizational unit"),
+			tools.NewArrayParameter("uids", "the uids", tools.NewStringParameter("uid", "a uid")),
+		},
+	}
+	raw, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool := raw.(Tool)
+	params := map[string]any{
+		"ou":   "people,dc=evil",
+		"uids": []any{"alice", "*)(uid=*"},
+	}
+
+	baseDN, err := render(tool.baseDNTemplate, params, ldapsrc.EscapeDN)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `ou=people\,dc=evil,dc=example,dc=com`; baseDN != want {
+		t.Errorf("incorrect baseDn: got %q, want %q", baseDN, want)
+	}
+	filter, err := render(tool.filterTemplate, params, ldapsrc.EscapeFilter)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := `(|(uid=alice)(uid=\2a\29\28uid=\2a))`; filter != want {
+		t.Errorf("incorrect filter: got %q, want %q", filter, want)
+	}
+	if err := ldapsrc.ValidateFilter(filter); err != nil {
+		t.Errorf("rendered filter is invalid: %s", err)
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocktool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"path"
+	"slices"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mock"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "mock-tool"
+
+// defaultRows is the number of rows generated when a tool doesn't set rows.
+const defaultRows = 10
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MockSeed() int64
+	Wait(ctx context.Context) error
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mock.Source{}
+
+var compatibleSources = [...]string{mock.SourceKind}
+
+// Response is a canned response, returned when the parameters of an
+// invocation match When.
+type Response struct {
+	// When maps parameter names to the values they must have. String values
+	// are glob patterns, as in path.Match. An empty When matches every
+	// invocation.
+	When map[string]any `yaml:"when"`
+	// Result is returned as the result of the invocation.
+	Result any `yaml:"result"`
+	// Error, if set, fails the invocation with this message instead.
+	Error string `yaml:"error"`
+}
+
+// Column is a column of the generated rows.
+type Column struct {
+	Name string `yaml:"name" validate:"required"`
+	// Type is the kind of value generated, one of ColumnTypes.
+	Type string `yaml:"type" validate:"required"`
+	// Values are the values an "enum" column picks from.
+	Values []any `yaml:"values"`
+	// Min and Max bound the values of "integer" and "float" columns.
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+}
+
+// ColumnTypes are the types of generated columns.
+var ColumnTypes = []string{"string", "integer", "float", "boolean", "uuid", "name", "email", "city", "country", "date", "timestamp", "enum"}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Source       string           `yaml:"source" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	// Responses are tried in order, and the first that matches is returned.
+	Responses []Response `yaml:"responses"`
+	// Schema generates rows for invocations no response matches.
+	Schema []Column `yaml:"schema"`
+	// Rows is the number of generated rows. Defaults to 10.
+	Rows *int `yaml:"rows"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %q tool %q: %w", kind, cfg.Name, err)
+	}
+	rows := defaultRows
+	if cfg.Rows != nil {
+		rows = *cfg.Rows
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		AuthRequired: cfg.AuthRequired,
+		Responses:    cfg.Responses,
+		Schema:       cfg.Schema,
+		Rows:         rows,
+		Source:       s,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+func (cfg Config) validate() error {
+	if len(cfg.Responses) == 0 && len(cfg.Schema) == 0 {
+		return fmt.Errorf("requires responses, a schema, or both")
+	}
+	for i, r := range cfg.Responses {
+		for name, want := range r.When {
+			if !slices.ContainsFunc(cfg.Parameters, func(p tools.Parameter) bool { return p.GetName() == name }) {
+				return fmt.Errorf("response %d matches unknown parameter %q", i, name)
+			}
+			if pattern, ok := want.(string); ok {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("response %d has an invalid pattern for %q: %w", i, name, err)
+				}
+			}
+		}
+	}
+	for _, c := range cfg.Schema {
+		if !slices.Contains(ColumnTypes, c.Type) {
+			return fmt.Errorf("column %q has unknown type %q, must be one of %q", c.Name, c.Type, ColumnTypes)
+		}
+		if c.Type == "enum" && len(c.Values) == 0 {
+			return fmt.Errorf("enum column %q requires values", c.Name)
+		}
+		if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+			return fmt.Errorf("column %q has a min greater than its max", c.Name)
+		}
+	}
+	if cfg.Rows != nil && *cfg.Rows < 0 {
+		return fmt.Errorf("rows must not be negative")
+	}
+	return nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Responses   []Response
+	Schema      []Column
+	Rows        int
+	Source      compatibleSource
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	if err := t.Source.Wait(ctx); err != nil {
+		return nil, err
+	}
+	paramsMap := params.AsMap()
+	for _, r := range t.Responses {
+		if !matches(r.When, paramsMap) {
+			continue
+		}
+		if r.Error != "" {
+			return nil, tools.NewToolError(tools.ErrorCategoryQuery, tools.ErrorCodeQueryFailed, r.Error, errors.New(r.Error))
+		}
+		return r.Result, nil
+	}
+	if len(t.Schema) == 0 {
+		return nil, fmt.Errorf("no mock response matches the parameters")
+	}
+	return t.generate(paramsMap)
+}
+
+// matches reports whether every value in when matches the parameter of the
+// same name.
+func matches(when map[string]any, params map[string]any) bool {
+	for name, want := range when {
+		got, ok := params[name]
+		if !ok {
+			return false
+		}
+		if pattern, ok := want.(string); ok {
+			s, ok := got.(string)
+			if !ok {
+				return false
+			}
+			if matched, _ := path.Match(pattern, s); !matched {
+				return false
+			}
+			continue
+		}
+		// compare the JSON encodings, so YAML's integer types compare equal
+		// to the parameter's
+		w, err := json.Marshal(want)
+		if err != nil {
+			return false
+		}
+		g, err := json.Marshal(got)
+		if err != nil || string(w) != string(g) {
+			return false
+		}
+	}
+	return true
+}
+
+// generate returns rows matching the schema. The rows are seeded by the
+// source seed and the parameters, so the same invocation returns the same
+// rows.
+func (t Tool) generate(params map[string]any) (any, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to seed generated rows: %w", err)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(t.Name))
+	h.Write(b)
+	r := rand.New(rand.NewPCG(uint64(t.Source.MockSeed()), h.Sum64()))
+
+	out := make([]any, 0, t.Rows)
+	for i := 0; i < t.Rows; i++ {
+		row := make(map[string]any, len(t.Schema))
+		for _, c := range t.Schema {
+			row[c.Name] = c.generate(r)
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Grace", "Hedy", "John", "Katherine", "Ken", "Linus", "Margaret", "Niklaus", "Radia", "Tim"}
+	lastNames  = []string{"Allen", "Dijkstra", "Hamilton", "Hopper", "Johnson", "Kay", "Lamarr", "Liskov", "Lovelace", "McCarthy", "Perlman", "Ritchie", "Thompson", "Turing", "Wirth"}
+	cities     = []string{"Amsterdam", "Bangalore", "Berlin", "Buenos Aires", "Cairo", "Lagos", "London", "Nairobi", "New York", "Paris", "Seoul", "Sydney", "Tokyo", "Toronto", "Zurich"}
+	countries  = []string{"Argentina", "Australia", "Canada", "Egypt", "France", "Germany", "India", "Japan", "Kenya", "Netherlands", "Nigeria", "South Korea", "Switzerland", "United Kingdom", "United States"}
+	words      = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet", "kilo", "lima", "mike", "november", "oscar"}
+)
+
+// epoch is the earliest generated date or timestamp.
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (c Column) generate(r *rand.Rand) any {
+	switch c.Type {
+	case "integer":
+		lo, hi := c.bounds()
+		return int64(lo) + r.Int64N(int64(hi)-int64(lo)+1)
+	case "float":
+		lo, hi := c.bounds()
+		return lo + r.Float64()*(hi-lo)
+	case "boolean":
+		return r.IntN(2) == 1
+	case "uuid":
+		var b [16]byte
+		for i := range b {
+			b[i] = byte(r.UintN(256))
+		}
+		// set the version and variant bits of a random UUID
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		return uuid.UUID(b).String()
+	case "name":
+		return pick(r, firstNames) + " " + pick(r, lastNames)
+	case "email":
+		return fmt.Sprintf("%s.%s@example.com", pick(r, firstNames), pick(r, lastNames))
+	case "city":
+		return pick(r, cities)
+	case "country":
+		return pick(r, countries)
+	case "date":
+		return epoch.AddDate(0, 0, r.IntN(5*365)).Format(time.DateOnly)
+	case "timestamp":
+		return epoch.Add(time.Duration(r.Int64N(int64(5 * 365 * 24 * time.Hour)))).Truncate(time.Second).Format(time.RFC3339)
+	case "enum":
+		return c.Values[r.IntN(len(c.Values))]
+	default:
+		return pick(r, words)
+	}
+}
+
+// bounds returns the range of numeric columns, which defaults to [0, 1000].
+func (c Column) bounds() (float64, float64) {
+	lo, hi := 0.0, 1000.0
+	if c.Min != nil {
+		lo = *c.Min
+	}
+	if c.Max != nil {
+		hi = *c.Max
+	}
+	if lo > hi {
+		hi = lo
+	}
+	return lo, hi
+}
+
+func pick(r *rand.Rand, values []string) string {
+	return values[r.IntN(len(values))]
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
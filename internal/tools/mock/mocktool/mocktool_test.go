@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocktool_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mock"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mock/mocktool"
+)
+
+func TestParseFromYamlMockTool(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rows := 3
+	lo, hi := 18.0, 99.0
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: mock-tool
+					source: my-mock
+					description: some description
+					parameters:
+						- name: city
+						  type: string
+						  description: some description
+					responses:
+						- when: {city: Basel}
+						  result: [{name: Hilton Basel}]
+						- when: {city: Atlantis}
+						  error: no such city
+					schema:
+						- name: name
+						  type: name
+						- name: age
+						  type: integer
+						  min: 18
+						  max: 99
+						- name: tier
+						  type: enum
+						  values: [gold, silver]
+					rows: 3
+			`,
+			want: server.ToolConfigs{
+				"example_tool": mocktool.Config{
+					Name:         "example_tool",
+					Kind:         "mock-tool",
+					Source:       "my-mock",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("city", "some description"),
+					},
+					Responses: []mocktool.Response{
+						{When: map[string]any{"city": "Basel"}, Result: []any{map[string]any{"name": "Hilton Basel"}}},
+						{When: map[string]any{"city": "Atlantis"}, Error: "no such city"},
+					},
+					Schema: []mocktool.Column{
+						{Name: "name", Type: "name"},
+						{Name: "age", Type: "integer", Min: &lo, Max: &hi},
+						{Name: "tier", Type: "enum", Values: []any{"gold", "silver"}},
+					},
+					Rows: &rows,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func newTool(t *testing.T, cfg mocktool.Config) tools.Tool {
+	t.Helper()
+	srcs := map[string]sources.Source{"my-mock": &mock.Source{Name: "my-mock", Kind: mock.SourceKind, Seed: 7}}
+	cfg.Name, cfg.Kind, cfg.Source, cfg.Description = "mock_tool", "mock-tool", "my-mock", "some description"
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	return tool
+}
+
+func invoke(t *testing.T, tool tools.Tool, data map[string]any) (any, error) {
+	t.Helper()
+	params, err := tool.ParseParams(data, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	return tool.Invoke(context.Background(), params)
+}
+
+func TestInvokeResponses(t *testing.T) {
+	tool := newTool(t, mocktool.Config{
+		Parameters: tools.Parameters{
+			tools.NewStringParameter("city", "some description"),
+			tools.NewIntParameterWithDefault("limit", 10, "some description"),
+		},
+		Responses: []mocktool.Response{
+			{When: map[string]any{"city": "Basel", "limit": uint64(1)}, Result: "one hotel in Basel"},
+			{When: map[string]any{"city": "B*"}, Result: "hotels in a city starting with B"},
+			{When: map[string]any{"city": "Atlantis"}, Error: "no such city"},
+		},
+	})
+
+	tcs := []struct {
+		desc string
+		data map[string]any
+		want any
+	}{
+		{desc: "exact match", data: map[string]any{"city": "Basel", "limit": 1}, want: "one hotel in Basel"},
+		{desc: "glob match", data: map[string]any{"city": "Bern"}, want: "hotels in a city starting with B"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := invoke(t, tool, tc.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected result: diff %v", diff)
+			}
+		})
+	}
+
+	_, err := invoke(t, tool, map[string]any{"city": "Atlantis"})
+	var te *tools.ToolError
+	if !errors.As(err, &te) || te.Code != tools.ErrorCodeQueryFailed || te.Detail != "no such city" {
+		t.Fatalf("expected a QUERY_FAILED error, got %v", err)
+	}
+
+	_, err = invoke(t, tool, map[string]any{"city": "Zurich"})
+	if err == nil || !strings.Contains(err.Error(), "no mock response matches") {
+		t.Fatalf("expected a no match error, got %v", err)
+	}
+}
+
+func TestInvokeGeneratedRows(t *testing.T) {
+	lo, hi := 18.0, 20.0
+	tool := newTool(t, mocktool.Config{
+		Parameters: tools.Parameters{tools.NewStringParameter("city", "some description")},
+		Schema: []mocktool.Column{
+			{Name: "id", Type: "uuid"},
+			{Name: "name", Type: "name"},
+			{Name: "age", Type: "integer", Min: &lo, Max: &hi},
+			{Name: "tier", Type: "enum", Values: []any{"gold", "silver"}},
+			{Name: "joined", Type: "date"},
+		},
+	})
+
+	got, err := invoke(t, tool, map[string]any{"city": "Basel"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rows := got.([]any)
+	if len(rows) != 10 {
+		t.Fatalf("expected 10 rows by default, got %d", len(rows))
+	}
+	for _, r := range rows {
+		row := r.(map[string]any)
+		if age := row["age"].(int64); age < 18 || age > 20 {
+			t.Fatalf("age %d is out of bounds", age)
+		}
+		if tier := row["tier"]; tier != "gold" && tier != "silver" {
+			t.Fatalf("unexpected tier %v", tier)
+		}
+		if len(row["id"].(string)) != 36 || len(row["joined"].(string)) != 10 {
+			t.Fatalf("unexpected row %v", row)
+		}
+	}
+
+	again, err := invoke(t, tool, map[string]any{"city": "Basel"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(got, again); diff != "" {
+		t.Fatalf("the same parameters generated different rows: diff %v", diff)
+	}
+	other, err := invoke(t, tool, map[string]any{"city": "Bern"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cmp.Equal(got, other) {
+		t.Fatalf("different parameters generated the same rows")
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	negative := -1
+	srcs := map[string]sources.Source{"my-mock": &mock.Source{Name: "my-mock", Kind: mock.SourceKind}}
+	params := tools.Parameters{tools.NewStringParameter("city", "some description")}
+	tcs := []struct {
+		desc string
+		cfg  mocktool.Config
+		want string
+	}{
+		{
+			desc: "no responses or schema",
+			cfg:  mocktool.Config{},
+			want: "requires responses, a schema, or both",
+		},
+		{
+			desc: "unknown parameter",
+			cfg:  mocktool.Config{Parameters: params, Responses: []mocktool.Response{{When: map[string]any{"country": "CH"}}}},
+			want: `unknown parameter "country"`,
+		},
+		{
+			desc: "invalid pattern",
+			cfg:  mocktool.Config{Parameters: params, Responses: []mocktool.Response{{When: map[string]any{"city": "["}}}},
+			want: "invalid pattern",
+		},
+		{
+			desc: "unknown column type",
+			cfg:  mocktool.Config{Schema: []mocktool.Column{{Name: "id", Type: "serial"}}},
+			want: `unknown type "serial"`,
+		},
+		{
+			desc: "enum without values",
+			cfg:  mocktool.Config{Schema: []mocktool.Column{{Name: "tier", Type: "enum"}}},
+			want: "requires values",
+		},
+		{
+			desc: "negative rows",
+			cfg:  mocktool.Config{Schema: []mocktool.Column{{Name: "id", Type: "uuid"}}, Rows: &negative},
+			want: "rows must not be negative",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.cfg.Name, tc.cfg.Kind, tc.cfg.Source, tc.cfg.Description = "mock_tool", "mock-tool", "my-mock", "some description"
+			_, err := tc.cfg.Initialize(srcs)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -156,6 +156,10 @@ func getOptions(sortParameters tools.Parameters, projectPayload string, limit in
 	}
 	opts = opts.SetSort(sort)
 
+	if limit > 0 {
+		opts = opts.SetLimit(limit)
+	}
+
 	if len(projectPayload) == 0 {
 		return opts, nil
 	}
@@ -174,10 +178,6 @@ func getOptions(sortParameters tools.Parameters, projectPayload string, limit in
 
 	opts = opts.SetProjection(projection)
 
-	if limit > 0 {
-		opts = opts.SetLimit(limit)
-	}
-
 	return opts, nil
 }
 
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodbfind
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetOptions(t *testing.T) {
+	ptr := func(v int64) *int64 { return &v }
+	tcs := []struct {
+		desc           string
+		projectPayload string
+		limit          int64
+		wantLimit      *int64
+		wantProjection any
+	}{
+		{
+			desc:      "limit without projection",
+			limit:     10,
+			wantLimit: ptr(10),
+		},
+		{
+			desc:           "limit with projection",
+			projectPayload: `{"name": 1}`,
+			limit:          10,
+			wantLimit:      ptr(10),
+			wantProjection: bson.D{{Key: "name", Value: int32(1)}},
+		},
+		{
+			desc: "no limit",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			sortParams := tools.Parameters{tools.NewIntParameter("age", "sort order of age")}
+			opts, err := getOptions(sortParams, tc.projectPayload, tc.limit, map[string]any{"age": -1})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.wantLimit, opts.Limit); diff != "" {
+				t.Fatalf("incorrect limit (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantProjection, opts.Projection); diff != "" {
+				t.Fatalf("incorrect projection (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(bson.M{"age": -1}, opts.Sort); diff != "" {
+				t.Fatalf("incorrect sort (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
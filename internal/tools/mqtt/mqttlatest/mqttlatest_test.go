@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqttlatest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mqttsrc "github.com/googleapis/genai-toolbox/internal/sources/mqtt"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMQTTLatest(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				device_state:
+					kind: mqtt-latest
+					source: my-mqtt-instance
+					description: latest state reported by each device
+					topic: devices/+/state
+			`,
+			want: server.ToolConfigs{
+				"device_state": Config{
+					Name:         "device_state",
+					Kind:         "mqtt-latest",
+					Source:       "my-mqtt-instance",
+					Description:  "latest state reported by each device",
+					Topic:        "devices/+/state",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	buf := mqttsrc.NewBuffer(10, 10, time.Hour)
+	now := time.Now()
+	for i, m := range []struct{ topic, payload string }{
+		{"devices/1/state", "on"},
+		{"devices/1/state", "off"},
+		{"devices/2/state", `{"on":true}`},
+		{"devices/2/battery", "80"},
+	} {
+		buf.Add(mqttsrc.Message{Topic: m.topic, Payload: []byte(m.payload), Received: now.Add(time.Duration(i) * time.Second)})
+	}
+	srcs := map[string]sources.Source{"m": &mqttsrc.Source{Name: "m", Kind: mqttsrc.SourceKind, Buffer: buf}}
+
+	if _, err := (Config{Source: "m", Topic: "devices/#/state"}).Initialize(srcs); err == nil {
+		t.Fatalf("expected an invalid topic to fail initialization")
+	}
+	tool, err := Config{Name: "state", Source: "m", Topic: "devices/+/state"}.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc string
+		in   map[string]any
+		want []string
+	}{
+		{desc: "default filter", in: map[string]any{}, want: []string{"devices/1/state", "devices/2/state"}},
+		{desc: "narrowed", in: map[string]any{"topic": "devices/2/#"}, want: []string{"devices/2/state"}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.in, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			res, err := tool.Invoke(context.Background(), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got []string
+			for _, row := range res.([]any) {
+				got = append(got, row.(map[string]any)["topic"].(string))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect topics (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
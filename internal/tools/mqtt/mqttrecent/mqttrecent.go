@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqttrecent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mqttsrc "github.com/googleapis/genai-toolbox/internal/sources/mqtt"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "mqtt-recent"
+
+const (
+	topicKey  = "topic"
+	windowKey = "window"
+	limitKey  = "limit"
+	// maxLimit is the largest number of messages a single invocation returns.
+	maxLimit = 1000
+)
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	MQTTBuffer() *mqttsrc.Buffer
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &mqttsrc.Source{}
+
+var compatibleSources = [...]string{mqttsrc.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	Topic        string   `yaml:"topic"`
+	Window       string   `yaml:"window"`
+	Limit        int      `yaml:"limit"`
+	AuthRequired []string `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	topic := cfg.Topic
+	if topic == "" {
+		topic = "#"
+	}
+	if err := mqttsrc.ValidateTopicFilter(topic); err != nil {
+		return nil, fmt.Errorf("invalid topic for %q tool: %w", kind, err)
+	}
+	window := cfg.Window
+	if window == "" {
+		window = "15m"
+	}
+	if _, err := parseWindow(window); err != nil {
+		return nil, fmt.Errorf("invalid window for %q tool: %w", kind, err)
+	}
+	limit := cfg.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	if limit < 0 || limit > maxLimit {
+		return nil, fmt.Errorf("limit for %q tool must be between 1 and %d", kind, maxLimit)
+	}
+
+	parameters := tools.Parameters{
+		tools.NewStringParameterWithDefault(topicKey, "#", `Topic filter to narrow the results, e.g. "devices/42/#". "+" matches one topic level and "#" matches all remaining levels.`),
+		tools.NewStringParameterWithDefault(windowKey, window, `How far back to look, as a duration such as "30s", "15m", or "2h".`),
+		tools.NewIntParameterWithDefault(limitKey, limit, fmt.Sprintf("Number of most recent messages to return, at most %d.", maxLimit)),
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   parameters,
+		Topic:        topic,
+		AuthRequired: cfg.AuthRequired,
+		Buffer:       s.MQTTBuffer(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+func parseWindow(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%q is not a positive duration", s)
+	}
+	return d, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Buffer      *mqttsrc.Buffer
+	Topic       string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	paramsMap := params.AsMap()
+	filter, ok := paramsMap[topicKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid %q parameter: must be a string", topicKey)
+	}
+	if err := mqttsrc.ValidateTopicFilter(filter); err != nil {
+		return nil, err
+	}
+	windowStr, _ := paramsMap[windowKey].(string)
+	window, err := parseWindow(windowStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q parameter: %w", windowKey, err)
+	}
+	limit, ok := paramsMap[limitKey].(int)
+	if !ok || limit <= 0 || limit > maxLimit {
+		return nil, fmt.Errorf("invalid %q parameter: must be between 1 and %d", limitKey, maxLimit)
+	}
+
+	rows := []any{}
+	for _, msg := range t.Buffer.Recent(time.Now().Add(-window), limit, t.Topic, filter) {
+		rows = append(rows, msg.Row())
+	}
+	return rows, nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqttrecent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mqttsrc "github.com/googleapis/genai-toolbox/internal/sources/mqtt"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+)
+
+func TestParseFromYamlMQTTRecent(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				recent_temperatures:
+					kind: mqtt-recent
+					source: my-mqtt-instance
+					description: recent temperature readings
+					topic: sensors/+/temperature
+					window: 1h
+					limit: 200
+			`,
+			want: server.ToolConfigs{
+				"recent_temperatures": Config{
+					Name:         "recent_temperatures",
+					Kind:         "mqtt-recent",
+					Source:       "my-mqtt-instance",
+					Description:  "recent temperature readings",
+					Topic:        "sensors/+/temperature",
+					Window:       "1h",
+					Limit:        200,
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInitializeValidation(t *testing.T) {
+	srcs := map[string]sources.Source{"m": &mqttsrc.Source{Name: "m", Kind: mqttsrc.SourceKind}}
+	tcs := []struct {
+		desc string
+		cfg  Config
+	}{
+		{desc: "invalid topic", cfg: Config{Source: "m", Topic: "a+"}},
+		{desc: "invalid window", cfg: Config{Source: "m", Window: "recently"}},
+		{desc: "limit too large", cfg: Config{Source: "m", Limit: maxLimit + 1}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := tc.cfg.Initialize(srcs); err == nil {
+				t.Fatalf("expected initialization to fail")
+			}
+		})
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	buf := mqttsrc.NewBuffer(10, 10, time.Hour)
+	now := time.Now()
+	for _, m := range []struct {
+		topic, payload string
+		age            time.Duration
+	}{
+		{"sensors/1/temperature", "20.5", 30 * time.Minute},
+		{"sensors/1/temperature", "21", 10 * time.Minute},
+		{"sensors/2/temperature", "19", 5 * time.Minute},
+		{"sensors/1/humidity", "40", 4 * time.Minute},
+		{"sensors/1/temperature", "21.5", time.Minute},
+	} {
+		buf.Add(mqttsrc.Message{Topic: m.topic, Payload: []byte(m.payload), Received: now.Add(-m.age)})
+	}
+	srcs := map[string]sources.Source{"m": &mqttsrc.Source{Name: "m", Kind: mqttsrc.SourceKind, Buffer: buf}}
+	tool, err := Config{Name: "temps", Source: "m", Topic: "sensors/+/temperature"}.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tcs := []struct {
+		desc    string
+		in      map[string]any
+		want    []any
+		wantErr bool
+	}{
+		{desc: "default window", in: map[string]any{}, want: []any{float64(21), float64(19), 21.5}},
+		{desc: "longer window", in: map[string]any{"window": "1h"}, want: []any{20.5, float64(21), float64(19), 21.5}},
+		{desc: "narrowed with limit", in: map[string]any{"topic": "sensors/1/#", "window": "1h", "limit": 2}, want: []any{float64(21), 21.5}},
+		{desc: "invalid window", in: map[string]any{"window": "-1m"}, wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.in, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			res, err := tool.Invoke(context.Background(), params)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected invocation to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var got []any
+			for _, row := range res.([]any) {
+				got = append(got, row.(map[string]any)["value"])
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect values (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mysqlcommon holds helpers shared by tools for MySQL-compatible
+// databases.
+package mysqlcommon
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// ScanRows reads every row of results into a map keyed by column name, and
+// closes results.
+func ScanRows(results *sql.Rows) ([]any, error) {
+	defer results.Close()
+
+	out, err := scanResultSet(results)
+	if err != nil {
+		return nil, err
+	}
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	return out, nil
+}
+
+// ScanResultSets reads every result set of results, such as those returned by
+// a statement with several queries, and closes results. See
+// tools.ScanResultSets for the shape of the result.
+func ScanResultSets(results *sql.Rows) (any, error) {
+	return tools.ScanResultSets(results, scanResultSet)
+}
+
+// scanResultSet reads the rows of the current result set of results.
+func scanResultSet(results *sql.Rows) ([]any, error) {
+	cols, err := results.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+	}
+
+	// create an array of values for each column, which can be re-used to scan each row
+	rawValues := make([]any, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+
+	colTypes, err := results.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get column types: %w", err)
+	}
+
+	var out []any
+	for results.Next() {
+		err := results.Scan(values...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		vMap := make(map[string]any)
+		for i, name := range cols {
+			val := rawValues[i]
+			if val == nil {
+				vMap[name] = nil
+				continue
+			}
+
+			// mysql driver return []uint8 type for "TEXT", "VARCHAR", and "NVARCHAR"
+			// we'll need to cast it back to string
+			b, isBytes := val.([]byte)
+			switch colTypes[i].DatabaseTypeName() {
+			case "JSON":
+				if !isBytes {
+					vMap[name] = val
+					continue
+				}
+				// unmarshal JSON data before storing to prevent double marshaling
+				var unmarshaledData any
+				err := json.Unmarshal(b, &unmarshaledData)
+				if err != nil {
+					return nil, fmt.Errorf("unable to unmarshal json data %s", val)
+				}
+				vMap[name] = unmarshaledData
+			case "TEXT", "VARCHAR", "NVARCHAR", "CHAR", "ENUM", "DECIMAL":
+				if isBytes {
+					vMap[name] = string(b)
+				} else {
+					vMap[name] = val
+				}
+			default:
+				vMap[name] = val
+			}
+		}
+		out = append(out, vMap)
+	}
+	return out, nil
+}
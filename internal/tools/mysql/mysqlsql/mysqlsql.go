@@ -24,6 +24,9 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlmysql"
 	"github.com/googleapis/genai-toolbox/internal/sources/mysql"
+	"github.com/googleapis/genai-toolbox/internal/sources/planetscale"
+	"github.com/googleapis/genai-toolbox/internal/sources/singlestore"
+	"github.com/googleapis/genai-toolbox/internal/sources/tidb"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
@@ -50,8 +53,11 @@ type compatibleSource interface {
 // validate compatible sources are still compatible
 var _ compatibleSource = &cloudsqlmysql.Source{}
 var _ compatibleSource = &mysql.Source{}
+var _ compatibleSource = &planetscale.Source{}
+var _ compatibleSource = &singlestore.Source{}
+var _ compatibleSource = &tidb.Source{}
 
-var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind}
+var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind, planetscale.SourceKind, singlestore.SourceKind, tidb.SourceKind}
 
 type Config struct {
 	Name               string           `yaml:"name" validate:"required"`
@@ -71,6 +77,17 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsPolicyPredicates reports that the tool restricts its statement to
+// the rows matched by the predicates of its policies.
+func (cfg Config) SupportsPolicyPredicates() bool {
+	return true
+}
+
+// PolicyStatement returns the statement of the tool.
+func (cfg Config) PolicyStatement() string {
+	return cfg.Statement
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -84,6 +101,11 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	statement, params, err := tools.BindNamedParams(cfg.Statement, tools.QuestionPlaceholders, cfg.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement for %q tool: %w", kind, err)
+	}
+
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
@@ -96,10 +118,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	t := Tool{
 		Name:               cfg.Name,
 		Kind:               kind,
-		Parameters:         cfg.Parameters,
+		Parameters:         params,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
-		Statement:          cfg.Statement,
+		Statement:          statement,
 		AuthRequired:       cfg.AuthRequired,
 		Pool:               s.MySQLPool(),
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
@@ -109,7 +131,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
+var _ tools.PreviewableTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -126,26 +149,62 @@ type Tool struct {
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	var out []any
+	err := t.Stream(ctx, params, func(row any) error {
+		out = append(out, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// statement returns the statement the invocation runs, and its bind values.
+func (t Tool) statement(ctx context.Context, params tools.ParamValues) (string, []any, error) {
 	paramsMap := params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
+		return "", nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
+		return "", nil, fmt.Errorf("unable to extract standard params %w", err)
 	}
 
 	sliceParams := newParams.AsSlice()
+	newStatement, sliceParams, err = tools.ApplyPolicyPredicates(ctx, newStatement, sliceParams, tools.QuestionPlaceholders)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to apply policies: %w", err)
+	}
+	return newStatement, sliceParams, nil
+}
+
+// Preview renders the statement the invocation would run, including the
+// predicates of its policies.
+func (t Tool) Preview(ctx context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	statement, args, err := t.statement(ctx, params)
+	if err != nil {
+		return tools.StatementPreview{}, err
+	}
+	return tools.NewStatementPreview(statement, t.Parameters, args), nil
+}
+
+// Stream sends each row of the result as it's read from the database.
+func (t Tool) Stream(ctx context.Context, params tools.ParamValues, send func(row any) error) error {
+	newStatement, sliceParams, err := t.statement(ctx, params)
+	if err != nil {
+		return err
+	}
 	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+		return fmt.Errorf("unable to execute query: %w", err)
 	}
 
 	cols, err := results.Columns()
 	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
+		return fmt.Errorf("unable to retrieve rows column name: %w", err)
 	}
 
 	// create an array of values for each column, which can be re-used to scan each row
@@ -158,14 +217,13 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 
 	colTypes, err := results.ColumnTypes()
 	if err != nil {
-		return nil, fmt.Errorf("unable to get column types: %w", err)
+		return fmt.Errorf("unable to get column types: %w", err)
 	}
 
-	var out []any
 	for results.Next() {
 		err := results.Scan(values...)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			return fmt.Errorf("unable to parse row: %w", err)
 		}
 		vMap := make(map[string]any)
 		for i, name := range cols {
@@ -183,7 +241,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 				var unmarshaledData any
 				err := json.Unmarshal(val.([]byte), &unmarshaledData)
 				if err != nil {
-					return nil, fmt.Errorf("unable to unmarshal json data %s", val)
+					return fmt.Errorf("unable to unmarshal json data %s", val)
 				}
 				vMap[name] = unmarshaledData
 			case "TEXT", "VARCHAR", "NVARCHAR":
@@ -192,14 +250,15 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 				vMap[name] = val
 			}
 		}
-		out = append(out, vMap)
+		if err := send(vMap); err != nil {
+			return err
+		}
 	}
 
 	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+		return fmt.Errorf("errors encountered during row iteration: %w", err)
 	}
-
-	return out, nil
+	return nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
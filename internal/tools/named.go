@@ -0,0 +1,303 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Placeholders is the way a database driver expects bind parameters to be
+// written in a statement.
+type Placeholders int
+
+const (
+	// DollarPlaceholders are numbered, e.g. "$1", as used by PostgreSQL.
+	DollarPlaceholders Placeholders = iota
+	// QuestionPlaceholders are positional, e.g. "?", as used by MySQL and
+	// SQLite.
+	QuestionPlaceholders
+	// AtPlaceholders are named, e.g. "@name", as used by SQL Server, Spanner,
+	// and BigQuery.
+	AtPlaceholders
+)
+
+// placeholder is a named placeholder, e.g. ":name", found in a statement.
+type placeholder struct {
+	name       string
+	start, end int
+}
+
+// BindNamedParams rewrites the named placeholders of a statement, written as
+// ":name", into the placeholders of the driver. It returns the rewritten
+// statement and the parameters to bind to it, in order, so they can be passed
+// to GetParams.
+//
+// Statements without named placeholders are returned unchanged, and their
+// parameters are bound in declaration order.
+func BindNamedParams(statement string, style Placeholders, params Parameters) (string, Parameters, error) {
+	named, positional := scanPlaceholders(statement, style)
+	if len(named) == 0 {
+		return statement, params, nil
+	}
+	if positional != "" {
+		return "", nil, fmt.Errorf("statement mixes named placeholders such as :%s with positional placeholders such as %s", named[0].name, positional)
+	}
+
+	byName := make(map[string]Parameter, len(params))
+	for _, p := range params {
+		byName[p.GetName()] = p
+	}
+
+	var sb strings.Builder
+	var bound Parameters
+	index := make(map[string]int)
+	last := 0
+	for _, ph := range named {
+		p, ok := byName[ph.name]
+		if !ok {
+			return "", nil, fmt.Errorf("named placeholder :%s has no matching parameter", ph.name)
+		}
+		sb.WriteString(statement[last:ph.start])
+		last = ph.end
+		switch style {
+		case DollarPlaceholders:
+			// numbered placeholders can be repeated, so each parameter is bound once
+			i, ok := index[ph.name]
+			if !ok {
+				bound = append(bound, p)
+				i = len(bound)
+				index[ph.name] = i
+			}
+			sb.WriteString("$" + strconv.Itoa(i))
+		case QuestionPlaceholders:
+			bound = append(bound, p)
+			sb.WriteString("?")
+		case AtPlaceholders:
+			sb.WriteString("@" + ph.name)
+		}
+	}
+	sb.WriteString(statement[last:])
+	if style == AtPlaceholders {
+		// named arguments are matched by name, not position
+		bound = params
+	}
+	return sb.String(), bound, nil
+}
+
+// NamedPlaceholders returns the names of the named placeholders of a
+// statement, in order of appearance.
+func NamedPlaceholders(statement string) []string {
+	named, _ := scanPlaceholders(statement, AtPlaceholders)
+	names := make([]string, 0, len(named))
+	for _, ph := range named {
+		names = append(names, ph.name)
+	}
+	return names
+}
+
+// scanPlaceholders finds the named placeholders of a statement, and the first
+// positional placeholder of the given style. Quoted strings and identifiers,
+// dollar-quoted strings, comments, and template actions are skipped, as are
+// "::" casts and the ":" of array slices such as arr[lo:hi].
+func scanPlaceholders(s string, style Placeholders) ([]placeholder, string) {
+	var named []placeholder
+	positional := ""
+	// subscripts holds, for each open bracket, whether it is a subscript
+	var subscripts []bool
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(s, i, c)
+		case c == '-' && strings.HasPrefix(s[i:], "--"):
+			i = skipPast(s, i+2, "\n")
+		case c == '/' && strings.HasPrefix(s[i:], "/*"):
+			i = skipPast(s, i+2, "*/")
+		case c == '{' && strings.HasPrefix(s[i:], "{{"):
+			i = skipPast(s, i+2, "}}")
+		case c == '[':
+			subscripts = append(subscripts, isSubscript(s, i))
+			i++
+		case c == ']':
+			if len(subscripts) > 0 {
+				subscripts = subscripts[:len(subscripts)-1]
+			}
+			i++
+		case c == ':':
+			if i+1 < len(s) && s[i+1] == ':' {
+				i += 2
+				continue
+			}
+			if len(subscripts) > 0 && subscripts[len(subscripts)-1] {
+				// a slice bound, not a placeholder
+				i++
+				continue
+			}
+			end := i + 1
+			for end < len(s) && isIdentChar(s[end], end == i+1) {
+				end++
+			}
+			if end > i+1 && (i == 0 || !isIdentChar(s[i-1], false)) {
+				named = append(named, placeholder{name: s[i+1 : end], start: i, end: end})
+			}
+			i = end
+		case c == '$':
+			if tag, ok := dollarQuoteTag(s, i); ok {
+				i = skipPast(s, i+len(tag), tag)
+				continue
+			}
+			if style == DollarPlaceholders && positional == "" && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9' {
+				end := i + 1
+				for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+					end++
+				}
+				positional = s[i:end]
+			}
+			i++
+		case c == '?':
+			if style == QuestionPlaceholders && positional == "" {
+				positional = "?"
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return named, positional
+}
+
+// SplitStatements splits a script into its statements at semicolons.
+// Semicolons in quoted strings and identifiers, dollar-quoted strings,
+// comments, and template actions don't end a statement. Statements that are
+// empty or only hold comments are dropped.
+func SplitStatements(script string) []string {
+	var stmts []string
+	start, hasCode := 0, false
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = skipPast(script, i+2, "\n")
+			continue
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipPast(script, i+2, "*/")
+			continue
+		case c == ';':
+			if hasCode {
+				stmts = append(stmts, strings.TrimSpace(script[start:i]))
+			}
+			i++
+			start, hasCode = i, false
+			continue
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			hasCode = true
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(script, i, c)
+		case c == '{' && strings.HasPrefix(script[i:], "{{"):
+			i = skipPast(script, i+2, "}}")
+		case c == '$':
+			if tag, ok := dollarQuoteTag(script, i); ok {
+				i = skipPast(script, i+len(tag), tag)
+				continue
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	if hasCode {
+		stmts = append(stmts, strings.TrimSpace(script[start:]))
+	}
+	return stmts
+}
+
+// skipQuoted returns the index after the quoted string or identifier starting
+// at i. A doubled quote character escapes it.
+func skipQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != quote {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(s)
+}
+
+// skipPast returns the index after the first occurrence of end at or after i,
+// or the length of s if there is none.
+func skipPast(s string, i int, end string) int {
+	if j := strings.Index(s[i:], end); j >= 0 {
+		return i + j + len(end)
+	}
+	return len(s)
+}
+
+// dollarQuoteTag returns the tag, e.g. "$body$", of the dollar-quoted string
+// starting at i.
+func dollarQuoteTag(s string, i int) (string, bool) {
+	if i > 0 && isIdentChar(s[i-1], false) {
+		return "", false
+	}
+	j := i + 1
+	for j < len(s) && isIdentChar(s[j], j == i+1) {
+		j++
+	}
+	if j < len(s) && s[j] == '$' {
+		return s[i : j+1], true
+	}
+	return "", false
+}
+
+// isSubscript reports whether the bracket at s[i] subscripts the expression
+// before it, as opposed to opening an ARRAY constructor.
+func isSubscript(s string, i int) bool {
+	j := i - 1
+	for j >= 0 && (s[j] == ' ' || s[j] == '\t' || s[j] == '\n' || s[j] == '\r') {
+		j--
+	}
+	if j < 0 {
+		return false
+	}
+	switch c := s[j]; {
+	case c == ']' || c == ')' || c == '"':
+		return true
+	case isIdentChar(c, false):
+		start := j
+		for start > 0 && isIdentChar(s[start-1], false) {
+			start--
+		}
+		return !strings.EqualFold(s[start:j+1], "ARRAY")
+	}
+	return false
+}
+
+func isIdentChar(c byte, first bool) bool {
+	switch {
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
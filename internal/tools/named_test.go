@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestBindNamedParams(t *testing.T) {
+	id := tools.NewIntParameter("id", "id")
+	name := tools.NewStringParameter("name", "name")
+	params := tools.Parameters{id, name}
+
+	tcs := []struct {
+		desc      string
+		statement string
+		style     tools.Placeholders
+		want      string
+		wantNames []string
+	}{
+		{
+			desc:      "positional statement unchanged",
+			statement: "SELECT * FROM t WHERE id = $1 AND name = $2",
+			style:     tools.DollarPlaceholders,
+			want:      "SELECT * FROM t WHERE id = $1 AND name = $2",
+			wantNames: []string{"id", "name"},
+		},
+		{
+			desc:      "dollar placeholders reuse numbers",
+			statement: "SELECT * FROM t WHERE name = :name OR alias = :name OR id = :id",
+			style:     tools.DollarPlaceholders,
+			want:      "SELECT * FROM t WHERE name = $1 OR alias = $1 OR id = $2",
+			wantNames: []string{"name", "id"},
+		},
+		{
+			desc:      "question placeholders repeat parameters",
+			statement: "SELECT * FROM t WHERE name = :name OR alias = :name OR id = :id",
+			style:     tools.QuestionPlaceholders,
+			want:      "SELECT * FROM t WHERE name = ? OR alias = ? OR id = ?",
+			wantNames: []string{"name", "name", "id"},
+		},
+		{
+			desc:      "at placeholders keep declaration order",
+			statement: "SELECT * FROM t WHERE name = :name AND id = :id",
+			style:     tools.AtPlaceholders,
+			want:      "SELECT * FROM t WHERE name = @name AND id = @id",
+			wantNames: []string{"id", "name"},
+		},
+		{
+			desc: "literals, comments, casts, and templates skipped",
+			statement: `SELECT ':name', ":name", $$ :name $$, id::text -- :name
+				FROM {{.table}} /* :name */ WHERE id = :id AND ts > '10:30' AND a[1:2] = x`,
+			style: tools.DollarPlaceholders,
+			want: `SELECT ':name', ":name", $$ :name $$, id::text -- :name
+				FROM {{.table}} /* :name */ WHERE id = $1 AND ts > '10:30' AND a[1:2] = x`,
+			wantNames: []string{"id"},
+		},
+		{
+			desc: "array slices and casts skipped",
+			statement: `SELECT arr[lo:hi], arr[:hi], (f(x))[2 : n], m[i][:j], x::int[], "a"[:k]
+				FROM t WHERE id = ANY(ARRAY[:id, 2]) AND name = :name::text`,
+			style: tools.DollarPlaceholders,
+			want: `SELECT arr[lo:hi], arr[:hi], (f(x))[2 : n], m[i][:j], x::int[], "a"[:k]
+				FROM t WHERE id = ANY(ARRAY[$1, 2]) AND name = $2::text`,
+			wantNames: []string{"id", "name"},
+		},
+		{
+			desc:      "escaped quotes",
+			statement: "SELECT 'it''s :name' WHERE id = :id",
+			style:     tools.QuestionPlaceholders,
+			want:      "SELECT 'it''s :name' WHERE id = ?",
+			wantNames: []string{"id"},
+		},
+		{
+			desc:      "question mark is not positional for dollar placeholders",
+			statement: "SELECT * FROM t WHERE doc ? 'key' AND id = :id",
+			style:     tools.DollarPlaceholders,
+			want:      "SELECT * FROM t WHERE doc ? 'key' AND id = $1",
+			wantNames: []string{"id"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, bound, err := tools.BindNamedParams(tc.statement, tc.style, params)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected statement: got %q, want %q", got, tc.want)
+			}
+			var names []string
+			for _, p := range bound {
+				names = append(names, p.GetName())
+			}
+			if diff := cmp.Diff(tc.wantNames, names); diff != "" {
+				t.Errorf("unexpected bound parameters (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBindNamedParamsErrors(t *testing.T) {
+	params := tools.Parameters{tools.NewIntParameter("id", "id")}
+	tcs := []struct {
+		desc      string
+		statement string
+		style     tools.Placeholders
+		err       string
+	}{
+		{
+			desc:      "unknown placeholder",
+			statement: "SELECT * FROM t WHERE id = :id AND name = :nmae",
+			style:     tools.DollarPlaceholders,
+			err:       "named placeholder :nmae has no matching parameter",
+		},
+		{
+			desc:      "mixed dollar placeholders",
+			statement: "SELECT * FROM t WHERE id = :id AND name = $2",
+			style:     tools.DollarPlaceholders,
+			err:       "statement mixes named placeholders such as :id with positional placeholders such as $2",
+		},
+		{
+			desc:      "mixed question placeholders",
+			statement: "SELECT * FROM t WHERE id = ? OR id = :id",
+			style:     tools.QuestionPlaceholders,
+			err:       "statement mixes named placeholders such as :id with positional placeholders such as ?",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, err := tools.BindNamedParams(tc.statement, tc.style, params)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestNamedPlaceholders(t *testing.T) {
+	got := tools.NamedPlaceholders("SELECT :a, ':b', x::int, :c FROM t WHERE d = :a")
+	if diff := cmp.Diff([]string{"a", "c", "a"}, got); diff != "" {
+		t.Errorf("unexpected placeholders (-want +got):\n%s", diff)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want []string
+	}{
+		{desc: "single statement", in: "SELECT 1", want: []string{"SELECT 1"}},
+		{desc: "trailing semicolon", in: "SELECT 1;\n", want: []string{"SELECT 1"}},
+		{desc: "several statements", in: "CREATE TABLE t (a INT);\nSELECT * FROM t; SELECT 2", want: []string{"CREATE TABLE t (a INT)", "SELECT * FROM t", "SELECT 2"}},
+		{desc: "quoted semicolons", in: `SELECT ';', "a;b" FROM t; SELECT $$;$$`, want: []string{`SELECT ';', "a;b" FROM t`, "SELECT $$;$$"}},
+		{desc: "comments", in: "SELECT 1; -- done; really\n/* ; */", want: []string{"SELECT 1"}},
+		{desc: "template actions", in: "SELECT * FROM {{.table}}; SELECT 2", want: []string{"SELECT * FROM {{.table}}", "SELECT 2"}},
+		{desc: "empty", in: " ; ;", want: nil},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tools.SplitStatements(tc.in)); diff != "" {
+				t.Errorf("unexpected statements (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
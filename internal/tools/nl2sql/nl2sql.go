@@ -0,0 +1,437 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nl2sql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/dictionary"
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/privacy"
+	"github.com/googleapis/genai-toolbox/internal/querycost"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/greenplum"
+	"github.com/googleapis/genai-toolbox/internal/sources/neon"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/sources/supabase"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const kind string = "nl2sql"
+
+const questionKey string = "question"
+
+// describeTablesStatement lists the columns of the allowed tables, which are
+// given as "schema.table".
+const describeTablesStatement = `
+SELECT
+	c.table_schema || '.' || c.table_name,
+	c.column_name,
+	c.data_type,
+	c.is_nullable = 'YES',
+	pg_catalog.col_description(format('%I.%I', c.table_schema, c.table_name)::regclass, c.ordinal_position::int)
+FROM information_schema.columns c
+WHERE c.table_schema || '.' || c.table_name = ANY($1)
+ORDER BY c.table_schema, c.table_name, c.ordinal_position`
+
+// responseSchema is the JSON the model responds with.
+var responseSchema = map[string]any{
+	"type": "OBJECT",
+	"properties": map[string]any{
+		"sql":         map[string]any{"type": "STRING"},
+		"explanation": map[string]any{"type": "STRING"},
+	},
+	"required": []string{"sql", "explanation"},
+}
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name, MaxRows: 100, Execute: true}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	PostgresPool() *pgxpool.Pool
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &alloydbpg.Source{}
+var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &greenplum.Source{}
+var _ compatibleSource = &neon.Source{}
+var _ compatibleSource = &postgres.Source{}
+var _ compatibleSource = &supabase.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, greenplum.SourceKind, neon.SourceKind, postgres.SourceKind, supabase.SourceKind}
+
+type Config struct {
+	Name         string   `yaml:"name" validate:"required"`
+	Kind         string   `yaml:"kind" validate:"required"`
+	Source       string   `yaml:"source" validate:"required"`
+	Description  string   `yaml:"description" validate:"required"`
+	AuthRequired []string `yaml:"authRequired"`
+	// AllowedTables are the tables generated queries may read, as
+	// "schema.table", or "table" in the public schema.
+	AllowedTables []string `yaml:"allowedTables" validate:"required,min=1"`
+	MaxRows       int      `yaml:"maxRows" validate:"gt=0"`
+	// Execute runs the generated query. Otherwise only the query is returned.
+	Execute bool `yaml:"execute"`
+	// Instructions are added to the prompt, e.g. to explain business terms.
+	Instructions string     `yaml:"instructions"`
+	Model        llm.Config `yaml:"model" validate:"required"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	model, err := cfg.Model.Initialize(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize model: %w", err)
+	}
+
+	allowed := make([]string, len(cfg.AllowedTables))
+	for i, table := range cfg.AllowedTables {
+		if !strings.Contains(table, ".") {
+			table = "public." + table
+		}
+		allowed[i] = table
+	}
+
+	questionParameter := tools.NewStringParameter(questionKey, "The question to answer from the database, in natural language.")
+	parameters := tools.Parameters{questionParameter}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:          cfg.Name,
+		Kind:          kind,
+		Parameters:    parameters,
+		AuthRequired:  cfg.AuthRequired,
+		AllowedTables: allowed,
+		MaxRows:       cfg.MaxRows,
+		Execute:       cfg.Execute,
+		Instructions:  cfg.Instructions,
+		Dictionary:    dictionary.FromSource(rawS),
+		Aggregation:   privacy.FromSource(rawS),
+		CostLimits:    querycost.FromSource(rawS),
+		Pool:          s.PostgresPool(),
+		model:         model,
+		manifest:      tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:   mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name          string           `yaml:"name"`
+	Kind          string           `yaml:"kind"`
+	AuthRequired  []string         `yaml:"authRequired"`
+	Parameters    tools.Parameters `yaml:"parameters"`
+	AllowedTables []string
+	MaxRows       int
+	Execute       bool
+	Instructions  string
+	// Dictionary of the source, which adds business descriptions, units
+	// and synonyms of the allowed tables to the prompt.
+	Dictionary dictionary.Dictionary
+	// Aggregation restricts the generated queries to aggregates, on
+	// aggregation-only sources.
+	Aggregation *privacy.AggregationConfig
+	// CostLimits reject generated queries that are estimated to be too
+	// expensive.
+	CostLimits *querycost.Limits
+
+	Pool        *pgxpool.Pool
+	model       llm.Model
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	question, ok := params.AsMap()[questionKey].(string)
+	if !ok || question == "" {
+		return nil, fmt.Errorf("invalid or missing '%s' parameter; expected a string", questionKey)
+	}
+
+	// everything runs in a read-only transaction, which is rolled back
+	tx, err := t.Pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+
+	schema, err := t.describeTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	sql, explanation, err := t.generate(ctx, question, schema)
+	if err != nil {
+		return nil, err
+	}
+	if sql == "" {
+		return map[string]any{"sql": sql, "explanation": explanation}, nil
+	}
+	query := privacy.Query{Statement: sql}
+	if t.Aggregation != nil {
+		query, err = t.Aggregation.Rewrite(sql)
+		if err != nil {
+			return nil, tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodePolicyDenied, "", fmt.Errorf("source only allows aggregate queries, generated %q: %w", sql, err))
+		}
+		sql = query.Statement
+	}
+	out := map[string]any{"sql": sql, "explanation": explanation}
+	if err := t.checkPlan(ctx, tx, sql); err != nil {
+		return nil, err
+	}
+	if !t.Execute {
+		return out, nil
+	}
+
+	// the exec mode sends the query as a single extended-protocol statement,
+	// which can't hold several statements, e.g. to end the transaction
+	results, err := tx.Query(ctx, sql, pgx.QueryExecModeExec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	defer results.Close()
+
+	fields := results.FieldDescriptions()
+	rows := []any{}
+	truncated := false
+	for results.Next() {
+		if len(rows) == t.MaxRows {
+			truncated = true
+			break
+		}
+		v, err := results.Values()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		vMap := make(map[string]any)
+		for i, f := range fields {
+			vMap[f.Name] = query.Noise(i, v[i])
+		}
+		rows = append(rows, vMap)
+	}
+	results.Close()
+	if err := results.Err(); err != nil {
+		return nil, fmt.Errorf("unable to execute query: %w", err)
+	}
+	out["rows"] = rows
+	out["truncated"] = truncated
+	return out, nil
+}
+
+// describeTables returns the columns of the allowed tables, one table per
+// paragraph, for the prompt. Comments of tables and columns are merged with
+// their entries in the data dictionary.
+func (t Tool) describeTables(ctx context.Context, tx pgx.Tx) (string, error) {
+	results, err := tx.Query(ctx, describeTablesStatement, t.AllowedTables)
+	if err != nil {
+		return "", fmt.Errorf("unable to describe tables: %w", err)
+	}
+	defer results.Close()
+
+	var b strings.Builder
+	var current string
+	var entry dictionary.Table
+	for results.Next() {
+		var table, column, dataType string
+		var nullable bool
+		var comment *string
+		if err := results.Scan(&table, &column, &dataType, &nullable, &comment); err != nil {
+			return "", fmt.Errorf("unable to describe tables: %w", err)
+		}
+		if table != current {
+			if current != "" {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "Table %s:\n", table)
+			current = table
+			entry, _ = t.Dictionary.Table(table)
+			if note := entry.Annotate(""); note != "" {
+				fmt.Fprintf(&b, "  -- %s\n", note)
+			}
+		}
+		fmt.Fprintf(&b, "  %s %s", column, dataType)
+		if !nullable {
+			b.WriteString(" NOT NULL")
+		}
+		note := ""
+		if comment != nil {
+			note = strings.ReplaceAll(*comment, "\n", " ")
+		}
+		if c, ok := entry.Column(column); ok {
+			note = c.Annotate(note)
+		}
+		if note != "" {
+			fmt.Fprintf(&b, " -- %s", note)
+		}
+		b.WriteString("\n")
+	}
+	if err := results.Err(); err != nil {
+		return "", fmt.Errorf("unable to describe tables: %w", err)
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("none of the allowed tables %q exist", t.AllowedTables)
+	}
+	return b.String(), nil
+}
+
+// generate asks the model for a query answering question, returning it with
+// the model's explanation. The query is empty if the tables can't answer the
+// question.
+func (t Tool) generate(ctx context.Context, question, schema string) (string, string, error) {
+	system := fmt.Sprintf(`You write PostgreSQL queries answering questions about a database.
+Write a single read-only SELECT statement that only reads the tables below. Unless the question asks for an aggregate, return at most %d rows.
+If the tables can't answer the question, return an empty sql and explain why.
+
+%s`, t.MaxRows, schema)
+	if t.Aggregation != nil {
+		system += fmt.Sprintf("\nThe database only answers aggregate queries: only use the aggregate functions %s, without subqueries, common table expressions, set operations or window functions. Groups of fewer than %d rows are left out of results.\n",
+			strings.Join(t.Aggregation.AllowedAggregates(), ", "), t.Aggregation.MinGroupSize)
+	}
+	if t.Instructions != "" {
+		system += "\n" + t.Instructions
+	}
+
+	resp, err := t.model.Generate(ctx, llm.Request{System: system, Prompt: question, ResponseSchema: responseSchema})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to generate sql: %w", err)
+	}
+	var out struct {
+		SQL         string `json:"sql"`
+		Explanation string `json:"explanation"`
+	}
+	if err := json.Unmarshal([]byte(resp), &out); err != nil {
+		return "", "", fmt.Errorf("unable to parse generated sql: %w", err)
+	}
+	sql := strings.TrimRight(strings.TrimSpace(out.SQL), ";")
+	return strings.TrimSpace(sql), out.Explanation, nil
+}
+
+// checkPlan rejects queries that modify data or read other tables than the
+// allowed ones, as found in their plan. Views are expanded in plans, so the
+// tables they read must be allowed. On sources with cost limits, queries
+// whose estimate exceeds them are rejected too.
+func (t Tool) checkPlan(ctx context.Context, tx pgx.Tx, sql string) error {
+	var plan string
+	if err := tx.QueryRow(ctx, "EXPLAIN (VERBOSE, FORMAT JSON) "+sql, pgx.QueryExecModeExec).Scan(&plan); err != nil {
+		return fmt.Errorf("invalid generated sql %q: %w", sql, err)
+	}
+	var explain []struct {
+		Plan map[string]any `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil {
+		return fmt.Errorf("unable to parse query plan: %w", err)
+	}
+	allowed := make(map[string]bool, len(t.AllowedTables))
+	for _, table := range t.AllowedTables {
+		allowed[table] = true
+	}
+	for _, e := range explain {
+		if err := checkNode(e.Plan, allowed); err != nil {
+			return fmt.Errorf("generated sql %q is not allowed: %w", sql, err)
+		}
+	}
+	if t.CostLimits != nil {
+		e, err := querycost.PostgresEstimate([]byte(plan))
+		if err != nil {
+			return err
+		}
+		if err := t.CostLimits.Check(e); err != nil {
+			return fmt.Errorf("generated sql %q is not allowed: %w", sql, err)
+		}
+	}
+	return nil
+}
+
+func checkNode(node map[string]any, allowed map[string]bool) error {
+	if node["Node Type"] == "ModifyTable" {
+		return fmt.Errorf("it modifies data")
+	}
+	if rel, ok := node["Relation Name"].(string); ok {
+		schema, _ := node["Schema"].(string)
+		if table := schema + "." + rel; !allowed[table] {
+			return fmt.Errorf("it reads table %s", table)
+		}
+	}
+	children, _ := node["Plans"].([]any)
+	for _, c := range children {
+		if child, ok := c.(map[string]any); ok {
+			if err := checkNode(child, allowed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nl2sql_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools/nl2sql"
+)
+
+func TestParseFromYamlNL2SQL(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: nl2sql
+					source: my-pg-instance
+					description: some description
+					allowedTables:
+						- orders
+						- sales.customers
+					model:
+						kind: vertexai
+						model: gemini-2.5-flash
+						project: my-project
+			`,
+			want: server.ToolConfigs{
+				"example_tool": nl2sql.Config{
+					Name:          "example_tool",
+					Kind:          "nl2sql",
+					Source:        "my-pg-instance",
+					Description:   "some description",
+					AuthRequired:  []string{},
+					AllowedTables: []string{"orders", "sales.customers"},
+					MaxRows:       100,
+					Execute:       true,
+					Model: llm.Config{
+						Kind:    "vertexai",
+						Model:   "gemini-2.5-flash",
+						Project: "my-project",
+					},
+				},
+			},
+		},
+		{
+			desc: "generate only",
+			in: `
+			tools:
+				example_tool:
+					kind: nl2sql
+					source: my-pg-instance
+					description: some description
+					allowedTables: [orders]
+					maxRows: 20
+					execute: false
+					instructions: Revenue is the sum of orders.amount.
+					model:
+						kind: vertexai
+						model: gemini-2.5-pro
+						project: my-project
+						location: europe-west4
+			`,
+			want: server.ToolConfigs{
+				"example_tool": nl2sql.Config{
+					Name:          "example_tool",
+					Kind:          "nl2sql",
+					Source:        "my-pg-instance",
+					Description:   "some description",
+					AuthRequired:  []string{},
+					AllowedTables: []string{"orders"},
+					MaxRows:       20,
+					Instructions:  "Revenue is the sum of orders.amount.",
+					Model: llm.Config{
+						Kind:     "vertexai",
+						Model:    "gemini-2.5-pro",
+						Project:  "my-project",
+						Location: "europe-west4",
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailParseFromYamlNL2SQL(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: nl2sql
+			source: my-pg-instance
+			description: some description
+			model:
+				kind: vertexai
+				model: gemini-2.5-flash
+				project: my-project
+	`
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err == nil {
+		t.Fatalf("expect parsing to fail without allowedTables")
+	}
+}
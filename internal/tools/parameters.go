@@ -19,10 +19,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
+	"cloud.google.com/go/civil"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
@@ -33,6 +37,10 @@ const (
 	typeBool   = "boolean"
 	typeArray  = "array"
 	typeMap    = "map"
+
+	typeTimestamp = "timestamp"
+	typeDate      = "date"
+	typeDecimal   = "decimal"
 )
 
 // ParamValues is an ordered list of ParamValue
@@ -138,7 +146,8 @@ func ParseParams(ps Parameters, data map[string]any, claimsMap map[string]map[st
 			// parse authenticated parameter
 			v, err = parseFromAuthService(paramAuthServices, claimsMap)
 			if err != nil {
-				return nil, fmt.Errorf("error parsing authenticated parameter %q: %w", name, err)
+				err = fmt.Errorf("error parsing authenticated parameter %q: %w", name, err)
+				return nil, NewToolError(ErrorCategoryAuth, ErrorCodeUnauthorized, "", err)
 			}
 		}
 		if v != nil {
@@ -379,6 +388,39 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 			a.AuthSources = nil
 		}
 		return a, nil
+	case typeTimestamp:
+		a := &TimestampParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
+	case typeDate:
+		a := &DateParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
+	case typeDecimal:
+		a := &DecimalParameter{}
+		if err := dec.DecodeContext(ctx, a); err != nil {
+			return nil, fmt.Errorf("unable to parse as %q: %w", t, err)
+		}
+		if a.AuthSources != nil {
+			logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` for parameters instead")
+			a.AuthServices = append(a.AuthServices, a.AuthSources...)
+			a.AuthSources = nil
+		}
+		return a, nil
 	}
 	return nil, fmt.Errorf("%q is not valid type for a parameter", t)
 }
@@ -425,6 +467,8 @@ type ParameterManifest struct {
 // ParameterMcpManifest represents properties when served as part of a ToolMcpManifest.
 type ParameterMcpManifest struct {
 	Type                 string                `json:"type"`
+	Format               string                `json:"format,omitempty"`
+	Pattern              string                `json:"pattern,omitempty"`
 	Description          string                `json:"description"`
 	Items                *ParameterMcpManifest `json:"items,omitempty"`
 	AdditionalProperties any                   `json:"AdditionalProperties,omitempty"`
@@ -1145,6 +1189,12 @@ func getPrototypeParameter(typeName string) (Parameter, error) {
 		return NewBooleanParameter("", ""), nil
 	case "float":
 		return NewFloatParameter("", ""), nil
+	case typeTimestamp:
+		return NewTimestampParameter("", ""), nil
+	case typeDate:
+		return NewDateParameter("", ""), nil
+	case typeDecimal:
+		return NewDecimalParameter("", ""), nil
 	default:
 		return nil, fmt.Errorf("unsupported valueType %q for map parameter", typeName)
 	}
@@ -1253,3 +1303,350 @@ func (p *MapParameter) McpManifest() ParameterMcpManifest {
 		AdditionalProperties: additionalProperties,
 	}
 }
+
+// NewTimestampParameter is a convenience function for initializing a TimestampParameter.
+func NewTimestampParameter(name string, desc string) *TimestampParameter {
+	return &TimestampParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeTimestamp,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+	}
+}
+
+// NewTimestampParameterWithDefault is a convenience function for initializing a TimestampParameter with default value.
+func NewTimestampParameterWithDefault(name string, defaultV, desc string) *TimestampParameter {
+	return &TimestampParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeTimestamp,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Default: &defaultV,
+	}
+}
+
+// NewTimestampParameterWithRequired is a convenience function for initializing a TimestampParameter.
+func NewTimestampParameterWithRequired(name string, desc string, required bool) *TimestampParameter {
+	return &TimestampParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeTimestamp,
+			Desc:         desc,
+			Required:     &required,
+			AuthServices: nil,
+		},
+	}
+}
+
+// NewTimestampParameterWithAuth is a convenience function for initializing a TimestampParameter with a list of ParamAuthService.
+func NewTimestampParameterWithAuth(name string, desc string, authServices []ParamAuthService) *TimestampParameter {
+	return &TimestampParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeTimestamp,
+			Desc:         desc,
+			AuthServices: authServices,
+		},
+	}
+}
+
+var _ Parameter = &TimestampParameter{}
+
+// TimestampParameter is a parameter representing the "timestamp" type. Values
+// are RFC 3339 strings with a time zone offset, and are parsed as a time.Time
+// in UTC.
+type TimestampParameter struct {
+	CommonParameter `yaml:",inline"`
+	Default         *string `yaml:"default"`
+}
+
+func (p *TimestampParameter) Parse(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	// time.RFC3339Nano also accepts timestamps without fractional seconds, and
+	// rejects timestamps without an offset, whose time zone would be ambiguous
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an RFC 3339 timestamp with a time zone offset, e.g. \"2006-01-02T15:04:05Z\"", s)
+	}
+	return t.UTC(), nil
+}
+
+func (p *TimestampParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+func (p *TimestampParameter) GetDefault() any {
+	if p.Default == nil {
+		return nil
+	}
+	return *p.Default
+}
+
+// Manifest returns the manifest for the TimestampParameter.
+func (p *TimestampParameter) Manifest() ParameterManifest {
+	// only list ParamAuthService names (without fields) in manifest
+	authNames := make([]string, len(p.AuthServices))
+	for i, a := range p.AuthServices {
+		authNames[i] = a.Name
+	}
+	r := CheckParamRequired(p.GetRequired(), p.GetDefault())
+	return ParameterManifest{
+		Name:         p.Name,
+		Type:         p.Type,
+		Required:     r,
+		Description:  p.Desc,
+		AuthServices: authNames,
+	}
+}
+
+// McpManifest returns the MCP manifest for the TimestampParameter.
+// json schema represents timestamps as strings with the 'date-time' format.
+func (p *TimestampParameter) McpManifest() ParameterMcpManifest {
+	return ParameterMcpManifest{
+		Type:        "string",
+		Format:      "date-time",
+		Description: p.Desc,
+	}
+}
+
+// NewDateParameter is a convenience function for initializing a DateParameter.
+func NewDateParameter(name string, desc string) *DateParameter {
+	return &DateParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDate,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+	}
+}
+
+// NewDateParameterWithDefault is a convenience function for initializing a DateParameter with default value.
+func NewDateParameterWithDefault(name string, defaultV, desc string) *DateParameter {
+	return &DateParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDate,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Default: &defaultV,
+	}
+}
+
+// NewDateParameterWithRequired is a convenience function for initializing a DateParameter.
+func NewDateParameterWithRequired(name string, desc string, required bool) *DateParameter {
+	return &DateParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDate,
+			Desc:         desc,
+			Required:     &required,
+			AuthServices: nil,
+		},
+	}
+}
+
+// NewDateParameterWithAuth is a convenience function for initializing a DateParameter with a list of ParamAuthService.
+func NewDateParameterWithAuth(name string, desc string, authServices []ParamAuthService) *DateParameter {
+	return &DateParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDate,
+			Desc:         desc,
+			AuthServices: authServices,
+		},
+	}
+}
+
+var _ Parameter = &DateParameter{}
+
+// DateParameter is a parameter representing the "date" type. Values are ISO
+// 8601 calendar dates ("2006-01-02"), and are parsed as the same string so
+// database drivers bind them without a time zone conversion.
+type DateParameter struct {
+	CommonParameter `yaml:",inline"`
+	Default         *string `yaml:"default"`
+}
+
+func (p *DateParameter) Parse(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	}
+	d, err := civil.ParseDate(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an ISO 8601 date, e.g. \"2006-01-02\"", s)
+	}
+	return d.String(), nil
+}
+
+func (p *DateParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+func (p *DateParameter) GetDefault() any {
+	if p.Default == nil {
+		return nil
+	}
+	return *p.Default
+}
+
+// Manifest returns the manifest for the DateParameter.
+func (p *DateParameter) Manifest() ParameterManifest {
+	// only list ParamAuthService names (without fields) in manifest
+	authNames := make([]string, len(p.AuthServices))
+	for i, a := range p.AuthServices {
+		authNames[i] = a.Name
+	}
+	r := CheckParamRequired(p.GetRequired(), p.GetDefault())
+	return ParameterManifest{
+		Name:         p.Name,
+		Type:         p.Type,
+		Required:     r,
+		Description:  p.Desc,
+		AuthServices: authNames,
+	}
+}
+
+// McpManifest returns the MCP manifest for the DateParameter.
+// json schema represents dates as strings with the 'date' format.
+func (p *DateParameter) McpManifest() ParameterMcpManifest {
+	return ParameterMcpManifest{
+		Type:        "string",
+		Format:      "date",
+		Description: p.Desc,
+	}
+}
+
+// NewDecimalParameter is a convenience function for initializing a DecimalParameter.
+func NewDecimalParameter(name string, desc string) *DecimalParameter {
+	return &DecimalParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDecimal,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+	}
+}
+
+// NewDecimalParameterWithDefault is a convenience function for initializing a DecimalParameter with default value.
+func NewDecimalParameterWithDefault(name string, defaultV, desc string) *DecimalParameter {
+	return &DecimalParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDecimal,
+			Desc:         desc,
+			AuthServices: nil,
+		},
+		Default: &defaultV,
+	}
+}
+
+// NewDecimalParameterWithRequired is a convenience function for initializing a DecimalParameter.
+func NewDecimalParameterWithRequired(name string, desc string, required bool) *DecimalParameter {
+	return &DecimalParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDecimal,
+			Desc:         desc,
+			Required:     &required,
+			AuthServices: nil,
+		},
+	}
+}
+
+// NewDecimalParameterWithAuth is a convenience function for initializing a DecimalParameter with a list of ParamAuthService.
+func NewDecimalParameterWithAuth(name string, desc string, authServices []ParamAuthService) *DecimalParameter {
+	return &DecimalParameter{
+		CommonParameter: CommonParameter{
+			Name:         name,
+			Type:         typeDecimal,
+			Desc:         desc,
+			AuthServices: authServices,
+		},
+	}
+}
+
+var _ Parameter = &DecimalParameter{}
+
+// decimalPattern matches the decimal numbers accepted by a DecimalParameter.
+const decimalPattern = `^[+-]?(\d+(\.\d*)?|\.\d+)$`
+
+var validDecimal = regexp.MustCompile(decimalPattern)
+
+// DecimalParameter is a parameter representing the "decimal" type. Values are
+// strings or JSON numbers, and are parsed as a string holding the exact digits
+// given so no precision is lost to float64.
+type DecimalParameter struct {
+	CommonParameter `yaml:",inline"`
+	Default         *string `yaml:"default"`
+}
+
+func (p *DecimalParameter) Parse(v any) (any, error) {
+	var s string
+	switch newV := v.(type) {
+	default:
+		return nil, &ParseTypeError{p.Name, p.Type, v}
+	case string:
+		s = strings.TrimSpace(newV)
+	case json.Number:
+		s = newV.String()
+	case int:
+		s = strconv.Itoa(newV)
+	case float64:
+		// values decoded without json.Number, e.g. from protobuf, are already
+		// floats; use the shortest representation that round-trips
+		s = strconv.FormatFloat(newV, 'f', -1, 64)
+	}
+	if !validDecimal.MatchString(s) {
+		return nil, fmt.Errorf("%q is not a decimal number, e.g. \"-12.345\"", s)
+	}
+	return s, nil
+}
+
+func (p *DecimalParameter) GetAuthServices() []ParamAuthService {
+	return p.AuthServices
+}
+
+func (p *DecimalParameter) GetDefault() any {
+	if p.Default == nil {
+		return nil
+	}
+	return *p.Default
+}
+
+// Manifest returns the manifest for the DecimalParameter.
+func (p *DecimalParameter) Manifest() ParameterManifest {
+	// only list ParamAuthService names (without fields) in manifest
+	authNames := make([]string, len(p.AuthServices))
+	for i, a := range p.AuthServices {
+		authNames[i] = a.Name
+	}
+	r := CheckParamRequired(p.GetRequired(), p.GetDefault())
+	return ParameterManifest{
+		Name:         p.Name,
+		Type:         p.Type,
+		Required:     r,
+		Description:  p.Desc,
+		AuthServices: authNames,
+	}
+}
+
+// McpManifest returns the MCP manifest for the DecimalParameter.
+// json schema numbers may lose precision, so decimals are strings with a pattern.
+func (p *DecimalParameter) McpManifest() ParameterMcpManifest {
+	return ParameterMcpManifest{
+		Type:        "string",
+		Pattern:     decimalPattern,
+		Description: p.Desc,
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
@@ -77,6 +78,33 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewIntParameter("my_integer", "this param is an int"),
 			},
 		},
+		{
+			name: "timestamp, date and decimal",
+			in: []map[string]any{
+				{
+					"name":        "my_timestamp",
+					"type":        "timestamp",
+					"description": "this param is a timestamp",
+				},
+				{
+					"name":        "my_date",
+					"type":        "date",
+					"description": "this param is a date",
+					"default":     "2025-01-02",
+				},
+				{
+					"name":        "my_decimal",
+					"type":        "decimal",
+					"description": "this param is a decimal",
+					"required":    false,
+				},
+			},
+			want: tools.Parameters{
+				tools.NewTimestampParameter("my_timestamp", "this param is a timestamp"),
+				tools.NewDateParameterWithDefault("my_date", "2025-01-02", "this param is a date"),
+				tools.NewDecimalParameterWithRequired("my_decimal", "this param is a decimal", false),
+			},
+		},
 		{
 			name: "int not required",
 			in: []map[string]any{
@@ -756,6 +784,73 @@ func TestParametersParse(t *testing.T) {
 			},
 			want: tools.ParamValues{tools.ParamValue{Name: "my_float", Value: 1.5}},
 		},
+		{
+			name: "timestamp",
+			params: tools.Parameters{
+				tools.NewTimestampParameter("my_timestamp", "this param is a timestamp"),
+			},
+			in: map[string]any{
+				"my_timestamp": "2025-01-02T15:04:05.123+02:00",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_timestamp", Value: time.Date(2025, 1, 2, 13, 4, 5, 123000000, time.UTC)}},
+		},
+		{
+			name: "timestamp without offset",
+			params: tools.Parameters{
+				tools.NewTimestampParameter("my_timestamp", "this param is a timestamp"),
+			},
+			in: map[string]any{
+				"my_timestamp": "2025-01-02T15:04:05",
+			},
+		},
+		{
+			name: "date",
+			params: tools.Parameters{
+				tools.NewDateParameter("my_date", "this param is a date"),
+			},
+			in: map[string]any{
+				"my_date": "2025-01-02",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_date", Value: "2025-01-02"}},
+		},
+		{
+			name: "not date",
+			params: tools.Parameters{
+				tools.NewDateParameter("my_date", "this param is a date"),
+			},
+			in: map[string]any{
+				"my_date": "01/02/2025",
+			},
+		},
+		{
+			name: "decimal string",
+			params: tools.Parameters{
+				tools.NewDecimalParameter("my_decimal", "this param is a decimal"),
+			},
+			in: map[string]any{
+				"my_decimal": "12345678901234567890.123456789",
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_decimal", Value: "12345678901234567890.123456789"}},
+		},
+		{
+			name: "decimal number",
+			params: tools.Parameters{
+				tools.NewDecimalParameter("my_decimal", "this param is a decimal"),
+			},
+			in: map[string]any{
+				"my_decimal": json.Number("0.1000000000000000055511151231257827"),
+			},
+			want: tools.ParamValues{tools.ParamValue{Name: "my_decimal", Value: "0.1000000000000000055511151231257827"}},
+		},
+		{
+			name: "not decimal",
+			params: tools.Parameters{
+				tools.NewDecimalParameter("my_decimal", "this param is a decimal"),
+			},
+			in: map[string]any{
+				"my_decimal": "1,5",
+			},
+		},
 		{
 			name: "not float",
 			params: tools.Parameters{
@@ -1334,6 +1429,21 @@ func TestParamMcpManifest(t *testing.T) {
 			in:   tools.NewBooleanParameter("foo-bool", "bar"),
 			want: tools.ParameterMcpManifest{Type: "boolean", Description: "bar"},
 		},
+		{
+			name: "timestamp",
+			in:   tools.NewTimestampParameter("foo-timestamp", "bar"),
+			want: tools.ParameterMcpManifest{Type: "string", Format: "date-time", Description: "bar"},
+		},
+		{
+			name: "date",
+			in:   tools.NewDateParameter("foo-date", "bar"),
+			want: tools.ParameterMcpManifest{Type: "string", Format: "date", Description: "bar"},
+		},
+		{
+			name: "decimal",
+			in:   tools.NewDecimalParameter("foo-decimal", "bar"),
+			want: tools.ParameterMcpManifest{Type: "string", Pattern: `^[+-]?(\d+(\.\d*)?|\.\d+)$`, Description: "bar"},
+		},
 		{
 			name: "array",
 			in:   tools.NewArrayParameter("foo-array", "bar", tools.NewStringParameter("foo-string", "bar")),
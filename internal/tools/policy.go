@@ -0,0 +1,367 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// PolicyConfig is a named policy that tools can reference. Policies are
+// written in CEL over the claims of the caller's auth services and the
+// parameters of the invocation, e.g.
+// `claims.my_google.tenant == params.tenant_id`.
+type PolicyConfig struct {
+	Description string `yaml:"description"`
+	// Condition must evaluate to true for the invocation to run.
+	Condition string `yaml:"condition"`
+	// Predicate is appended to the statement of SQL tools, so they only
+	// return the rows it matches.
+	Predicate *PredicateConfig `yaml:"predicate"`
+}
+
+// PredicateConfig is a mandatory SQL predicate. Values are never written into
+// the SQL: its `:name` placeholders are bound to the values of the CEL
+// expressions in Bind.
+type PredicateConfig struct {
+	SQL  string            `yaml:"sql" validate:"required"`
+	Bind map[string]string `yaml:"bind"`
+}
+
+// Policy is a compiled PolicyConfig.
+type Policy struct {
+	name      string
+	condition cel.Program
+	predicate string
+	bind      map[string]cel.Program
+}
+
+// Name is the name of the policy.
+func (p *Policy) Name() string {
+	return p.name
+}
+
+// HasPredicate reports whether the policy appends a predicate to statements.
+func (p *Policy) HasPredicate() bool {
+	return p.predicate != ""
+}
+
+func policyEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("claims", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("params", cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
+
+// CompilePolicies compiles the policies of a configuration.
+func CompilePolicies(cfgs map[string]PolicyConfig) (map[string]*Policy, error) {
+	env, err := policyEnv()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*Policy, len(cfgs))
+	for name, cfg := range cfgs {
+		p, err := compilePolicy(env, name, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy %q: %w", name, err)
+		}
+		out[name] = p
+	}
+	return out, nil
+}
+
+func compilePolicy(env *cel.Env, name string, cfg PolicyConfig) (*Policy, error) {
+	if cfg.Condition == "" && cfg.Predicate == nil {
+		return nil, fmt.Errorf("a policy requires a condition, a predicate, or both")
+	}
+	p := &Policy{name: name}
+	if cfg.Condition != "" {
+		ast, iss := env.Compile(cfg.Condition)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("invalid condition: %w", iss.Err())
+		}
+		if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+			return nil, fmt.Errorf("condition must evaluate to a bool, not %s", ast.OutputType())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition: %w", err)
+		}
+		p.condition = prg
+	}
+	if cfg.Predicate != nil {
+		if strings.TrimSpace(cfg.Predicate.SQL) == "" {
+			return nil, fmt.Errorf("predicate requires sql")
+		}
+		p.predicate = cfg.Predicate.SQL
+		p.bind = make(map[string]cel.Program, len(cfg.Predicate.Bind))
+		for _, ph := range NamedPlaceholders(cfg.Predicate.SQL) {
+			if _, ok := cfg.Predicate.Bind[ph]; !ok {
+				return nil, fmt.Errorf("predicate placeholder :%s has no bind expression", ph)
+			}
+		}
+		for k, expr := range cfg.Predicate.Bind {
+			ast, iss := env.Compile(expr)
+			if iss.Err() != nil {
+				return nil, fmt.Errorf("invalid bind expression for %q: %w", k, iss.Err())
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bind expression for %q: %w", k, err)
+			}
+			p.bind[k] = prg
+		}
+	}
+	return p, nil
+}
+
+// PolicyScope holds the predicates the policies of an invocation appended.
+type PolicyScope struct {
+	Predicates []BoundPredicate
+}
+
+// BoundPredicate is a predicate with the values of its placeholders.
+type BoundPredicate struct {
+	Policy string
+	SQL    string
+	Values map[string]any
+}
+
+// evaluate checks the condition of the policy, and binds its predicate. A
+// condition that can't be evaluated, e.g. because the caller has no claims,
+// denies the invocation.
+func (p *Policy) evaluate(claims map[string]map[string]any, params map[string]any) (*BoundPredicate, error) {
+	c := make(map[string]any, len(claims))
+	for k, v := range claims {
+		c[k] = v
+	}
+	vars := map[string]any{"claims": c, "params": params}
+	if p.condition != nil {
+		out, _, err := p.condition.Eval(vars)
+		if err != nil {
+			return nil, policyDenied(p.name, err)
+		}
+		if allowed, ok := out.Value().(bool); !ok || !allowed {
+			return nil, policyDenied(p.name, nil)
+		}
+	}
+	if p.predicate == "" {
+		return nil, nil
+	}
+	bp := &BoundPredicate{Policy: p.name, SQL: p.predicate, Values: make(map[string]any, len(p.bind))}
+	for k, prg := range p.bind {
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			return nil, policyDenied(p.name, fmt.Errorf("unable to bind %q: %w", k, err))
+		}
+		bp.Values[k] = out.Value()
+	}
+	return bp, nil
+}
+
+func policyDenied(name string, cause error) error {
+	err := fmt.Errorf("invocation denied by policy %q", name)
+	if cause != nil {
+		err = fmt.Errorf("%w: %w", err, cause)
+	}
+	// the cause may include claims, which the caller shouldn't see
+	return NewToolError(ErrorCategoryAuth, ErrorCodePolicyDenied, fmt.Sprintf("invocation denied by policy %q", name), err)
+}
+
+type policyScopeKey struct{}
+
+// WithPolicyScope returns a context carrying the policy scope of an
+// invocation.
+func WithPolicyScope(ctx context.Context, scope PolicyScope) context.Context {
+	return context.WithValue(ctx, policyScopeKey{}, scope)
+}
+
+// ApplyPolicyPredicates restricts a statement to the rows matched by the
+// predicates of the invocation's policies, by selecting from the statement as
+// a subquery. Predicates filter the rows the statement returns, not the rows
+// of its tables. The values of the predicates are appended to args, and their
+// placeholders written in the driver's style. Statements without predicates
+// are returned unchanged.
+func ApplyPolicyPredicates(ctx context.Context, statement string, args []any, style Placeholders) (string, []any, error) {
+	scope, ok := ctx.Value(policyScopeKey{}).(PolicyScope)
+	if !ok || len(scope.Predicates) == 0 {
+		return statement, args, nil
+	}
+	if style == AtPlaceholders {
+		return "", nil, fmt.Errorf("policy predicates are not supported with named bind parameters")
+	}
+	args = slices.Clone(args)
+	conds := make([]string, 0, len(scope.Predicates))
+	for _, p := range scope.Predicates {
+		named, _ := scanPlaceholders(p.SQL, style)
+		var sb strings.Builder
+		last := 0
+		for _, ph := range named {
+			v, ok := p.Values[ph.name]
+			if !ok {
+				return "", nil, fmt.Errorf("predicate of policy %q has no value for :%s", p.Policy, ph.name)
+			}
+			sb.WriteString(p.SQL[last:ph.start])
+			last = ph.end
+			args = append(args, v)
+			if style == DollarPlaceholders {
+				sb.WriteString("$" + strconv.Itoa(len(args)))
+			} else {
+				sb.WriteString("?")
+			}
+		}
+		sb.WriteString(p.SQL[last:])
+		conds = append(conds, "("+sb.String()+")")
+	}
+	inner := strings.TrimRight(strings.TrimSpace(statement), ";")
+	return fmt.Sprintf("SELECT * FROM (%s) AS policy_scope WHERE %s", inner, strings.Join(conds, " AND ")), args, nil
+}
+
+// PolicyPredicateSupport is implemented by the configs of tools that apply
+// policy predicates to their statements with ApplyPolicyPredicates.
+type PolicyPredicateSupport interface {
+	SupportsPolicyPredicates() bool
+	// PolicyStatement returns the statement the predicates are applied to.
+	PolicyStatement() string
+}
+
+// policyScopeParam is the name of the ParamValue that carries the policy
+// scope from ParseParams to Invoke.
+const policyScopeParam = "\x00policyScope"
+
+// PolicyToolConfig wraps a ToolConfig so the policies it names are enforced
+// on every invocation of the tool it initializes. Policies are bound by name
+// with BindPolicies before the tool is initialized.
+type PolicyToolConfig struct {
+	ToolConfig
+	Names []string
+	// PredicatesSupported reports whether the wrapped tool applies the
+	// predicates of policies.
+	PredicatesSupported bool
+	// Statement is the statement of the wrapped tool, which predicates are
+	// checked against.
+	Statement string
+	// Policies are the policies bound by BindPolicies.
+	Policies []*Policy
+}
+
+// validate interface
+var _ ToolConfig = PolicyToolConfig{}
+
+// BindPolicies binds the policies named by a PolicyToolConfig. Other configs
+// are returned unchanged.
+func BindPolicies(tc ToolConfig, policies map[string]*Policy) (ToolConfig, error) {
+	cfg, ok := tc.(PolicyToolConfig)
+	if !ok {
+		return tc, nil
+	}
+	cfg.Policies = make([]*Policy, 0, len(cfg.Names))
+	for _, name := range cfg.Names {
+		p, ok := policies[name]
+		if !ok {
+			return nil, fmt.Errorf("no policy named %q configured", name)
+		}
+		if p.HasPredicate() && !cfg.PredicatesSupported {
+			return nil, fmt.Errorf("policy %q has a predicate, which %q tools don't support", name, cfg.ToolConfigKind())
+		}
+		cfg.Policies = append(cfg.Policies, p)
+	}
+	return cfg, nil
+}
+
+func (cfg PolicyToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	if len(cfg.Policies) != len(cfg.Names) {
+		return nil, fmt.Errorf("policies %q are not bound", cfg.Names)
+	}
+	// predicates that can't apply to the statement would fail every
+	// invocation, so they're rejected when the tool is loaded
+	for _, p := range cfg.Policies {
+		if p.HasPredicate() {
+			if err := checkPredicateStatement(cfg.Statement, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return policyTool{toolWrapper: toolWrapper{t}, policies: cfg.Policies}, nil
+}
+
+type policyTool struct {
+	toolWrapper
+	policies []*Policy
+}
+
+// ParseParams evaluates the policies once the parameters are parsed, since
+// it's the only step of an invocation that sees the caller's claims.
+func (t policyTool) ParseParams(data map[string]any, claims map[string]map[string]any) (ParamValues, error) {
+	params, err := t.Tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, err
+	}
+	paramsMap := params.AsMap()
+	var scope PolicyScope
+	for _, p := range t.policies {
+		bp, err := p.evaluate(claims, paramsMap)
+		if err != nil {
+			return nil, err
+		}
+		if bp != nil {
+			scope.Predicates = append(scope.Predicates, *bp)
+		}
+	}
+	sort.SliceStable(scope.Predicates, func(i, j int) bool { return scope.Predicates[i].Policy < scope.Predicates[j].Policy })
+	return append(params, ParamValue{Name: policyScopeParam, Value: scope}), nil
+}
+
+func (t policyTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	n := len(params)
+	if n == 0 || params[n-1].Name != policyScopeParam {
+		// never run without the policies having been evaluated
+		return nil, fmt.Errorf("policies of the tool were not evaluated")
+	}
+	scope, _ := params[n-1].Value.(PolicyScope)
+	return t.Tool.Invoke(WithPolicyScope(ctx, scope), params[:n-1])
+}
+
+// Stream forwards to the wrapped tool with the policy scope, like Invoke.
+func (t policyTool) Stream(ctx context.Context, params ParamValues, send func(row any) error) error {
+	n := len(params)
+	if n == 0 || params[n-1].Name != policyScopeParam {
+		return fmt.Errorf("policies of the tool were not evaluated")
+	}
+	scope, _ := params[n-1].Value.(PolicyScope)
+	return streamTool(WithPolicyScope(ctx, scope), t.Tool, params[:n-1], send)
+}
+
+// Preview forwards to the wrapped tool with the policy scope, so the preview
+// includes the predicates of the policies.
+func (t policyTool) Preview(ctx context.Context, params ParamValues) (StatementPreview, error) {
+	n := len(params)
+	if n == 0 || params[n-1].Name != policyScopeParam {
+		return StatementPreview{}, fmt.Errorf("policies of the tool were not evaluated")
+	}
+	scope, _ := params[n-1].Value.(PolicyScope)
+	return PreviewTool(WithPolicyScope(ctx, scope), t.Tool, params[:n-1])
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// scopedTool returns the statement and arguments it would run once the
+// policies of the invocation are applied.
+type scopedTool struct {
+	fakeTool
+	style tools.Placeholders
+}
+
+func (t scopedTool) ParseParams(data map[string]any, _ map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParamValues{{Name: "tenant_id", Value: data["tenant_id"]}}, nil
+}
+
+func (t scopedTool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	stmt, args, err := tools.ApplyPolicyPredicates(ctx, "SELECT * FROM orders WHERE tenant_id = $1;", params.AsSlice(), t.style)
+	if err != nil {
+		return nil, err
+	}
+	return []any{stmt, args}, nil
+}
+
+type scopedToolConfig struct {
+	tool scopedTool
+}
+
+func (c scopedToolConfig) ToolConfigKind() string         { return "scoped" }
+func (c scopedToolConfig) SupportsPolicyPredicates() bool { return true }
+func (c scopedToolConfig) PolicyStatement() string {
+	return "SELECT * FROM orders WHERE tenant_id = $1;"
+}
+func (c scopedToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return c.tool, nil
+}
+
+func TestPolicyToolConfig(t *testing.T) {
+	policies, err := tools.CompilePolicies(map[string]tools.PolicyConfig{
+		"same_tenant": {Condition: "claims.google.tenant == params.tenant_id"},
+		"own_rows": {Predicate: &tools.PredicateConfig{
+			SQL:  "owner = :user OR :admin",
+			Bind: map[string]string{"user": "claims.google.email", "admin": "claims.google.admin == true"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg, err := tools.BindPolicies(tools.PolicyToolConfig{
+		ToolConfig:          scopedToolConfig{},
+		Names:               []string{"same_tenant", "own_rows"},
+		PredicatesSupported: true,
+		Statement:           scopedToolConfig{}.PolicyStatement(),
+	}, policies)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	alice := map[string]map[string]any{"google": {"tenant": "acme", "email": "alice@acme.com", "admin": false}}
+	tcs := []struct {
+		desc   string
+		data   map[string]any
+		claims map[string]map[string]any
+		want   []any
+		denied bool
+	}{
+		{
+			desc:   "allowed",
+			data:   map[string]any{"tenant_id": "acme"},
+			claims: alice,
+			want: []any{
+				"SELECT * FROM (SELECT * FROM orders WHERE tenant_id = $1) AS policy_scope WHERE (owner = $2 OR $3)",
+				[]any{"acme", "alice@acme.com", false},
+			},
+		},
+		{desc: "condition is false", data: map[string]any{"tenant_id": "other"}, claims: alice, denied: true},
+		{desc: "caller has no claims", data: map[string]any{"tenant_id": "acme"}, denied: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			params, err := tool.ParseParams(tc.data, tc.claims)
+			if tc.denied {
+				var te *tools.ToolError
+				if !errors.As(err, &te) || te.Code != tools.ErrorCodePolicyDenied {
+					t.Fatalf("expected a POLICY_DENIED error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := tool.Invoke(context.Background(), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected statement: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestPolicyPredicateStatements(t *testing.T) {
+	policies, err := tools.CompilePolicies(map[string]tools.PolicyConfig{
+		"own_rows": {Predicate: &tools.PredicateConfig{
+			SQL:  `o.owner = :user AND CAST("Region" AS text) IN (SELECT name FROM regions WHERE lead = :user) AND lower(owner) <> ''`,
+			Bind: map[string]string{"user": "claims.google.email"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc      string
+		statement string
+		wantErr   string
+	}{
+		{desc: "wildcard", statement: "SELECT * FROM orders"},
+		{desc: "qualified wildcard", statement: "SELECT o.id, o.* FROM orders o"},
+		{desc: "columns", statement: "SELECT id, o.owner, region AS \"Region\" FROM orders o WHERE id > ?;"},
+		{desc: "casts and aliases", statement: "SELECT DISTINCT ON (id) id, owner::text, upper(r) region FROM orders"},
+		{desc: "common table expressions", statement: "WITH recent AS (SELECT * FROM orders) SELECT owner, region FROM recent"},
+		{desc: "template", statement: "SELECT {{.columns}} FROM orders"},
+		{desc: "update", statement: "UPDATE orders SET status = 'done' WHERE id = ?", wantErr: "SELECT statement"},
+		{desc: "delete after expressions", statement: "WITH old AS (SELECT id FROM orders) DELETE FROM orders WHERE id IN (SELECT id FROM old)", wantErr: "SELECT statement"},
+		{desc: "several statements", statement: "SELECT owner, region FROM a; SELECT owner, region FROM b", wantErr: "several queries"},
+		{desc: "aggregate", statement: "SELECT region, COUNT(*) AS orders FROM orders GROUP BY region", wantErr: `column "owner"`},
+		{desc: "projection", statement: "SELECT id, owner_id FROM orders", wantErr: `column "owner"`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg, err := tools.BindPolicies(tools.PolicyToolConfig{
+				ToolConfig:          rowsToolConfig{},
+				Names:               []string{"own_rows"},
+				PredicatesSupported: true,
+				Statement:           tc.statement,
+			}, policies)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			_, err = cfg.Initialize(nil)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyPolicyPredicatesWithoutScope(t *testing.T) {
+	stmt, args, err := tools.ApplyPolicyPredicates(context.Background(), "SELECT 1", []any{1}, tools.QuestionPlaceholders)
+	if err != nil || stmt != "SELECT 1" || len(args) != 1 {
+		t.Fatalf("expected the statement to be unchanged, got %q %v %v", stmt, args, err)
+	}
+}
+
+func TestPolicyErrors(t *testing.T) {
+	compileErrs := map[string]tools.PolicyConfig{
+		"empty":          {},
+		"invalid cel":    {Condition: "claims.google.tenant =="},
+		"not a bool":     {Condition: "'tenant'"},
+		"unbound":        {Predicate: &tools.PredicateConfig{SQL: "tenant_id = :tenant"}},
+		"empty sql":      {Predicate: &tools.PredicateConfig{SQL: " "}},
+		"invalid binder": {Predicate: &tools.PredicateConfig{SQL: "tenant_id = :t", Bind: map[string]string{"t": "claims."}}},
+	}
+	for desc, cfg := range compileErrs {
+		if _, err := tools.CompilePolicies(map[string]tools.PolicyConfig{desc: cfg}); err == nil {
+			t.Errorf("%s: expected error", desc)
+		}
+	}
+
+	policies, err := tools.CompilePolicies(map[string]tools.PolicyConfig{
+		"scoped": {Predicate: &tools.PredicateConfig{SQL: "tenant_id = 1"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	bindErrs := []tools.PolicyToolConfig{
+		{ToolConfig: rowsToolConfig{}, Names: []string{"missing"}},
+		{ToolConfig: rowsToolConfig{}, Names: []string{"scoped"}},
+	}
+	for _, cfg := range bindErrs {
+		if _, err := tools.BindPolicies(cfg, policies); err == nil {
+			t.Errorf("expected error binding %q", cfg.Names)
+		}
+	}
+	if _, err := (tools.PolicyToolConfig{ToolConfig: rowsToolConfig{}, Names: []string{"scoped"}}).Initialize(nil); err == nil {
+		t.Errorf("expected error initializing a tool with unbound policies")
+	}
+}
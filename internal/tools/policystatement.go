@@ -0,0 +1,296 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sqlToken is a word or a punctuation character of a statement. Strings and
+// comments are skipped, as they can't name columns.
+type sqlToken struct {
+	text string
+	// word is set for identifiers and keywords; quoted is set for quoted
+	// identifiers, whose text is unquoted, and template actions.
+	word, quoted bool
+	// template is set for template actions, e.g. {{.columns}}
+	template bool
+	// depth is the number of parentheses the token is in
+	depth int
+}
+
+// isKeyword reports whether the token is the unquoted keyword kw.
+func (t sqlToken) isKeyword(kw string) bool {
+	return t.word && !t.quoted && strings.EqualFold(t.text, kw)
+}
+
+// tokenizeSQL splits a statement into words and punctuation characters.
+func tokenizeSQL(s string) []sqlToken {
+	var out []sqlToken
+	depth := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'':
+			i = skipQuoted(s, i, c)
+			out = append(out, sqlToken{text: "'", depth: depth})
+		case c == '"' || c == '`':
+			j := skipQuoted(s, i, c)
+			name := strings.TrimSuffix(s[i+1:j], string(c))
+			name = strings.ReplaceAll(name, string([]byte{c, c}), string(c))
+			out = append(out, sqlToken{text: name, word: true, quoted: true, depth: depth})
+			i = j
+		case c == '-' && strings.HasPrefix(s[i:], "--"):
+			i = skipPast(s, i+2, "\n")
+		case c == '/' && strings.HasPrefix(s[i:], "/*"):
+			i = skipPast(s, i+2, "*/")
+		case c == '{' && strings.HasPrefix(s[i:], "{{"):
+			j := skipPast(s, i+2, "}}")
+			out = append(out, sqlToken{text: s[i:j], word: true, quoted: true, template: true, depth: depth})
+			i = j
+		case c == '$':
+			if tag, ok := dollarQuoteTag(s, i); ok {
+				i = skipPast(s, i+len(tag), tag)
+				out = append(out, sqlToken{text: "'", depth: depth})
+				continue
+			}
+			out = append(out, sqlToken{text: "$", depth: depth})
+			i++
+		case isIdentChar(c, false):
+			j := i + 1
+			for j < len(s) && isIdentChar(s[j], false) {
+				j++
+			}
+			// numbers are kept as words that are never column names
+			out = append(out, sqlToken{text: s[i:j], word: isIdentChar(c, true), depth: depth})
+			i = j
+		case c == '(':
+			out = append(out, sqlToken{text: "(", depth: depth})
+			depth++
+			i++
+		case c == ')':
+			if depth > 0 {
+				depth--
+			}
+			out = append(out, sqlToken{text: ")", depth: depth})
+			i++
+		default:
+			out = append(out, sqlToken{text: string(c), depth: depth})
+			i++
+		}
+	}
+	return out
+}
+
+// checkPredicateStatement reports whether the predicate of a policy can be
+// applied to statement by ApplyPolicyPredicates: the statement must be a
+// single query, and return the columns the predicate references. Columns
+// aren't checked when they can't be known from the statement, e.g. for
+// SELECT *.
+func checkPredicateStatement(statement string, p *Policy) error {
+	tokens := tokenizeSQL(statement)
+	// the trailing semicolon is trimmed before the statement is wrapped
+	for len(tokens) > 0 && tokens[len(tokens)-1].text == ";" && !tokens[len(tokens)-1].word {
+		tokens = tokens[:len(tokens)-1]
+	}
+	sel := mainSelect(tokens)
+	if sel < 0 {
+		return fmt.Errorf("policy %q has a predicate, which can only filter the rows of a SELECT statement", p.name)
+	}
+	for _, t := range tokens {
+		if t.depth == 0 && t.text == ";" && !t.word {
+			return fmt.Errorf("policy %q has a predicate, which can't filter a statement with several queries", p.name)
+		}
+	}
+	columns, ok := outputColumns(tokens[sel+1:])
+	if !ok {
+		return nil
+	}
+	for _, name := range predicateColumns(p.predicate) {
+		if !columns[strings.ToLower(name)] {
+			return fmt.Errorf("predicate of policy %q references column %q, which the statement doesn't return", p.name, name)
+		}
+	}
+	return nil
+}
+
+// mainSelect returns the index of the SELECT keyword of the query, after its
+// common table expressions, or -1 if the statement isn't a query.
+func mainSelect(tokens []sqlToken) int {
+	if len(tokens) == 0 {
+		return -1
+	}
+	if tokens[0].isKeyword("SELECT") {
+		return 0
+	}
+	if !tokens[0].isKeyword("WITH") {
+		return -1
+	}
+	// the common table expressions are in parentheses, so the first
+	// statement keyword outside of them starts the main statement
+	for i, t := range tokens {
+		if t.depth != 0 {
+			continue
+		}
+		switch {
+		case t.isKeyword("SELECT"):
+			return i
+		case t.isKeyword("INSERT"), t.isKeyword("UPDATE"), t.isKeyword("DELETE"), t.isKeyword("MERGE"):
+			return -1
+		}
+	}
+	return -1
+}
+
+// selectListEnd are the keywords ending the select list of a query.
+var selectListEnd = []string{
+	"FROM", "INTO", "WHERE", "GROUP", "HAVING", "WINDOW", "QUALIFY", "ORDER",
+	"LIMIT", "OFFSET", "FETCH", "FOR", "UNION", "INTERSECT", "EXCEPT",
+}
+
+// outputColumns returns the lowercased names of the columns returned by a
+// query, given the tokens after its SELECT keyword. It returns false if they
+// can't be known, because the select list has a wildcard or a template
+// action.
+func outputColumns(tokens []sqlToken) (map[string]bool, bool) {
+	i := 0
+	if i < len(tokens) && (tokens[i].isKeyword("DISTINCT") || tokens[i].isKeyword("ALL")) {
+		i++
+		// DISTINCT ON (expressions)
+		if i < len(tokens) && tokens[i].isKeyword("ON") {
+			i++
+			for i < len(tokens) && !(tokens[i].depth == 0 && tokens[i].text == ")") {
+				i++
+			}
+			i++
+		}
+	}
+	columns := make(map[string]bool)
+	var item []sqlToken
+	addItem := func() bool {
+		if len(item) == 0 {
+			return true
+		}
+		last := item[len(item)-1]
+		if last.template || (last.text == "*" && !last.word) {
+			return false
+		}
+		for _, t := range item {
+			if t.template {
+				return false
+			}
+		}
+		// an expression is named by its alias, or by the column it is,
+		// including when it's cast with "::"
+		expr := item
+		if len(item) < 2 || !item[len(item)-2].isKeyword("AS") {
+			for j, t := range item {
+				if t.depth == 0 && t.text == ":" && !t.word {
+					expr = item[:j]
+					break
+				}
+			}
+		}
+		if len(expr) > 0 {
+			if last := expr[len(expr)-1]; last.word && !isPredicateKeyword(last) {
+				columns[strings.ToLower(last.text)] = true
+			}
+		}
+		item = item[:0]
+		return true
+	}
+	for ; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.depth == 0 && isAnyKeyword(t, selectListEnd) {
+			break
+		}
+		if t.depth == 0 && t.text == "," && !t.word {
+			if !addItem() {
+				return nil, false
+			}
+			continue
+		}
+		item = append(item, t)
+	}
+	if !addItem() {
+		return nil, false
+	}
+	return columns, true
+}
+
+// predicateKeywords are the keywords that may appear in a predicate, which
+// aren't column names.
+var predicateKeywords = []string{
+	"AND", "OR", "NOT", "IN", "IS", "NULL", "TRUE", "FALSE", "UNKNOWN", "LIKE",
+	"ILIKE", "SIMILAR", "TO", "ESCAPE", "BETWEEN", "SYMMETRIC", "ANY", "ALL",
+	"SOME", "EXISTS", "CASE", "WHEN", "THEN", "ELSE", "END", "AS", "DISTINCT",
+	"FROM", "COLLATE", "INTERVAL", "DATE", "TIME", "TIMESTAMP", "ARRAY",
+	"CURRENT_DATE", "CURRENT_TIME", "CURRENT_TIMESTAMP", "CURRENT_USER",
+	"SESSION_USER", "LOCALTIME", "LOCALTIMESTAMP",
+}
+
+func isPredicateKeyword(t sqlToken) bool {
+	return isAnyKeyword(t, predicateKeywords)
+}
+
+func isAnyKeyword(t sqlToken, keywords []string) bool {
+	for _, kw := range keywords {
+		if t.isKeyword(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// predicateColumns returns the names of the columns a predicate references.
+// Placeholders, functions, types and the columns of subqueries are skipped,
+// and qualified names are reduced to their column.
+func predicateColumns(predicate string) []string {
+	tokens := tokenizeSQL(predicate)
+	var names []string
+	seen := make(map[string]bool)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.text == "(" && !t.word && i+1 < len(tokens) && (tokens[i+1].isKeyword("SELECT") || tokens[i+1].isKeyword("WITH")) {
+			// skip the subquery, whose columns come from its own tables
+			for i+1 < len(tokens) && !(tokens[i+1].depth == t.depth && tokens[i+1].text == ")") {
+				i++
+			}
+			continue
+		}
+		if !t.word || t.template || isPredicateKeyword(t) {
+			continue
+		}
+		if i > 0 {
+			prev := tokens[i-1]
+			// placeholders, "::" casts and CAST(x AS type)
+			if (prev.text == ":" && !prev.word) || prev.isKeyword("AS") {
+				continue
+			}
+		}
+		if i+1 < len(tokens) && !tokens[i+1].word && (tokens[i+1].text == "(" || tokens[i+1].text == ".") {
+			// functions and qualifiers
+			continue
+		}
+		if key := strings.ToLower(t.text); !seen[key] {
+			seen[key] = true
+			names = append(names, t.text)
+		}
+	}
+	return names
+}
@@ -19,10 +19,15 @@ import (
 	"fmt"
 
 	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/privacy"
+	"github.com/googleapis/genai-toolbox/internal/querycost"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/greenplum"
+	"github.com/googleapis/genai-toolbox/internal/sources/neon"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/sources/supabase"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -50,9 +55,12 @@ type compatibleSource interface {
 // validate compatible sources are still compatible
 var _ compatibleSource = &alloydbpg.Source{}
 var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &greenplum.Source{}
+var _ compatibleSource = &neon.Source{}
 var _ compatibleSource = &postgres.Source{}
+var _ compatibleSource = &supabase.Source{}
 
-var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, greenplum.SourceKind, neon.SourceKind, postgres.SourceKind, supabase.SourceKind}
 
 type Config struct {
 	Name         string   `yaml:"name" validate:"required"`
@@ -98,6 +106,9 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		Parameters:   parameters,
 		AuthRequired: cfg.AuthRequired,
 		Pool:         s.PostgresPool(),
+		queryTags:    tools.SourceQueryTags(rawS),
+		aggregation:  privacy.FromSource(rawS),
+		costLimits:   querycost.FromSource(rawS),
 		manifest:     tools.Manifest{Description: cfg.Description, Parameters: parameters.Manifest(), AuthRequired: cfg.AuthRequired},
 		mcpManifest:  mcpManifest,
 	}
@@ -114,6 +125,9 @@ type Tool struct {
 	Parameters   tools.Parameters `yaml:"parameters"`
 
 	Pool        *pgxpool.Pool
+	queryTags   map[string]string
+	aggregation *privacy.AggregationConfig
+	costLimits  *querycost.Limits
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
@@ -125,7 +139,22 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
 	}
 
-	results, err := t.Pool.Query(ctx, sql)
+	var query privacy.Query
+	if t.aggregation != nil {
+		var err error
+		query, err = t.aggregation.Rewrite(sql)
+		if err != nil {
+			return nil, tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodePolicyDenied, "", fmt.Errorf("source only allows aggregate queries: %w", err))
+		}
+		sql = query.Statement
+	}
+	if t.costLimits != nil {
+		if err := t.costLimits.CheckPostgres(ctx, t.Pool, sql); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := t.Pool.Query(ctx, tools.TagStatement(sql, tools.QueryTags(ctx, t.queryTags)))
 	if err != nil {
 		return nil, fmt.Errorf("unable to execute query: %w", err)
 	}
@@ -140,7 +169,7 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 		}
 		vMap := make(map[string]any)
 		for i, f := range fields {
-			vMap[f.Name] = v[i]
+			vMap[f.Name] = query.Noise(i, v[i])
 		}
 		out = append(out, vMap)
 	}
@@ -19,10 +19,14 @@ import (
 	"fmt"
 
 	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/privacy"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
 	"github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/greenplum"
+	"github.com/googleapis/genai-toolbox/internal/sources/neon"
 	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
+	"github.com/googleapis/genai-toolbox/internal/sources/supabase"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -50,9 +54,17 @@ type compatibleSource interface {
 // validate compatible sources are still compatible
 var _ compatibleSource = &alloydbpg.Source{}
 var _ compatibleSource = &cloudsqlpg.Source{}
+var _ compatibleSource = &greenplum.Source{}
+var _ compatibleSource = &neon.Source{}
 var _ compatibleSource = &postgres.Source{}
+var _ compatibleSource = &supabase.Source{}
 
-var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
+// validate sources that tag queries still do
+var _ tools.QueryTagger = &alloydbpg.Source{}
+var _ tools.QueryTagger = &cloudsqlpg.Source{}
+var _ tools.QueryTagger = &postgres.Source{}
+
+var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, greenplum.SourceKind, neon.SourceKind, postgres.SourceKind, supabase.SourceKind}
 
 type Config struct {
 	Name               string           `yaml:"name" validate:"required"`
@@ -72,6 +84,17 @@ func (cfg Config) ToolConfigKind() string {
 	return kind
 }
 
+// SupportsPolicyPredicates reports that the tool restricts its statement to
+// the rows matched by the predicates of its policies.
+func (cfg Config) SupportsPolicyPredicates() bool {
+	return true
+}
+
+// PolicyStatement returns the statement of the tool.
+func (cfg Config) PolicyStatement() string {
+	return cfg.Statement
+}
+
 func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
 	// verify source exists
 	rawS, ok := srcs[cfg.Source]
@@ -85,6 +108,19 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	statement, params, err := tools.BindNamedParams(cfg.Statement, tools.DollarPlaceholders, cfg.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement for %q tool: %w", kind, err)
+	}
+
+	aggregation := privacy.FromSource(rawS)
+	if aggregation != nil && len(cfg.TemplateParameters) == 0 {
+		// the statement is fixed, so it can be checked once and for all
+		if _, err := aggregation.Rewrite(statement); err != nil {
+			return nil, fmt.Errorf("invalid statement for %q tool: source only allows aggregate queries: %w", kind, err)
+		}
+	}
+
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
@@ -97,12 +133,14 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	t := Tool{
 		Name:               cfg.Name,
 		Kind:               kind,
-		Parameters:         cfg.Parameters,
+		Parameters:         params,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
-		Statement:          cfg.Statement,
+		Statement:          statement,
 		AuthRequired:       cfg.AuthRequired,
 		Pool:               s.PostgresPool(),
+		queryTags:          tools.SourceQueryTags(rawS),
+		aggregation:        aggregation,
 		manifest:           tools.Manifest{Description: cfg.Description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
 		mcpManifest:        mcpManifest,
 	}
@@ -110,7 +148,8 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
+var _ tools.PreviewableTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -122,43 +161,96 @@ type Tool struct {
 
 	Pool        *pgxpool.Pool
 	Statement   string
+	queryTags   map[string]string
+	aggregation *privacy.AggregationConfig
 	manifest    tools.Manifest
 	mcpManifest tools.McpManifest
 }
 
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	var out []any
+	err := t.Stream(ctx, params, func(row any) error {
+		out = append(out, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// statement returns the statement the invocation runs, and its bind values.
+// On aggregation-only sources, the statement is restricted to groups of the
+// minimum size before the predicates of policies filter its result.
+func (t Tool) statement(ctx context.Context, params tools.ParamValues) (privacy.Query, []any, error) {
 	paramsMap := params.AsMap()
 	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
+		return privacy.Query{}, nil, fmt.Errorf("unable to extract template params %w", err)
 	}
 
 	newParams, err := tools.GetParams(t.Parameters, paramsMap)
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
+		return privacy.Query{}, nil, fmt.Errorf("unable to extract standard params %w", err)
+	}
+	query := privacy.Query{Statement: newStatement}
+	if t.aggregation != nil {
+		query, err = t.aggregation.Rewrite(newStatement)
+		if err != nil {
+			return privacy.Query{}, nil, tools.NewToolError(tools.ErrorCategoryAuth, tools.ErrorCodePolicyDenied, "", fmt.Errorf("source only allows aggregate queries: %w", err))
+		}
 	}
 	sliceParams := newParams.AsSlice()
+	query.Statement, sliceParams, err = tools.ApplyPolicyPredicates(ctx, query.Statement, sliceParams, tools.DollarPlaceholders)
+	if err != nil {
+		return privacy.Query{}, nil, fmt.Errorf("unable to apply policies: %w", err)
+	}
+	return query, sliceParams, nil
+}
+
+// Preview renders the statement the invocation would run, including the
+// predicates of its policies.
+func (t Tool) Preview(ctx context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	query, args, err := t.statement(ctx, params)
+	if err != nil {
+		return tools.StatementPreview{}, err
+	}
+	return tools.NewStatementPreview(query.Statement, t.Parameters, args), nil
+}
+
+// Stream sends each row of the result as it's read from the database.
+func (t Tool) Stream(ctx context.Context, params tools.ParamValues, send func(row any) error) error {
+	query, sliceParams, err := t.statement(ctx, params)
+	if err != nil {
+		return err
+	}
+	newStatement := tools.TagStatement(query.Statement, tools.QueryTags(ctx, t.queryTags))
 	results, err := t.Pool.Query(ctx, newStatement, sliceParams...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
+		return fmt.Errorf("unable to execute query: %w", err)
 	}
+	defer results.Close()
 
 	fields := results.FieldDescriptions()
 
-	var out []any
 	for results.Next() {
 		v, err := results.Values()
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			return fmt.Errorf("unable to parse row: %w", err)
 		}
 		vMap := make(map[string]any)
 		for i, f := range fields {
-			vMap[f.Name] = v[i]
+			vMap[f.Name] = query.Noise(i, v[i])
+		}
+		if err := send(vMap); err != nil {
+			return err
 		}
-		out = append(out, vMap)
 	}
 
-	return out, nil
+	if err := results.Err(); err != nil {
+		return fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	return nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -19,7 +19,10 @@ import (
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/privacy"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgressql"
@@ -165,3 +168,33 @@ func TestParseFromYamlWithTemplateParamsPostgres(t *testing.T) {
 	}
 
 }
+
+func TestInitializeAggregationOnly(t *testing.T) {
+	srcs := map[string]sources.Source{
+		"my-pg-instance": &postgres.Source{Aggregation: &privacy.AggregationConfig{MinGroupSize: 10}},
+	}
+	tcs := []struct {
+		desc      string
+		statement string
+		wantErr   bool
+	}{
+		{desc: "aggregate", statement: "SELECT region, count(*) FROM orders GROUP BY region"},
+		{desc: "subquery", statement: "SELECT count(*) FROM (SELECT * FROM orders) o", wantErr: true},
+		{desc: "row level", statement: "SELECT * FROM orders UNION SELECT * FROM archive", wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := postgressql.Config{
+				Name:        "example_tool",
+				Kind:        "postgres-sql",
+				Source:      "my-pg-instance",
+				Description: "some description",
+				Statement:   tc.statement,
+			}
+			_, err := cfg.Initialize(srcs)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected result: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPreviewUnsupported is returned when previewing a tool that doesn't run a
+// statement.
+var ErrPreviewUnsupported = errors.New("the tool does not support previews")
+
+// StatementPreview is the statement an invocation would run, with
+// placeholders for the bind values.
+type StatementPreview struct {
+	Statement string `json:"statement"`
+	// Parameters are the values bound to the placeholders, in order.
+	Parameters []PreviewParameter `json:"parameters"`
+}
+
+// PreviewParameter is a value bound to a placeholder of a statement.
+type PreviewParameter struct {
+	// Name is the name of the parameter, or empty for values that don't
+	// come from a parameter, like the values of policy predicates.
+	Name  string `json:"name,omitempty"`
+	Value any    `json:"value"`
+}
+
+// PreviewableTool is a tool that can render the statement an invocation
+// would run without running it.
+type PreviewableTool interface {
+	Preview(ctx context.Context, params ParamValues) (StatementPreview, error)
+}
+
+// PreviewTool renders the statement t would run for params, or returns
+// ErrPreviewUnsupported if t doesn't run a statement.
+func PreviewTool(ctx context.Context, t Tool, params ParamValues) (StatementPreview, error) {
+	p, ok := t.(PreviewableTool)
+	if !ok {
+		return StatementPreview{}, ErrPreviewUnsupported
+	}
+	return p.Preview(ctx, params)
+}
+
+// PreviewStatement resolves the template parameters of statement, and pairs
+// the values of params with their placeholders, as tools do before running
+// a statement.
+func PreviewStatement(templateParams, params Parameters, statement string, paramValues ParamValues) (StatementPreview, error) {
+	paramsMap := paramValues.AsMap()
+	newStatement, err := ResolveTemplateParams(templateParams, statement, paramsMap)
+	if err != nil {
+		return StatementPreview{}, err
+	}
+	newParams, err := GetParams(params, paramsMap)
+	if err != nil {
+		return StatementPreview{}, err
+	}
+	return NewStatementPreview(newStatement, params, newParams.AsSlice()), nil
+}
+
+// NewStatementPreview pairs args with the names of params, in order. Args
+// beyond params have no name.
+func NewStatementPreview(statement string, params Parameters, args []any) StatementPreview {
+	p := StatementPreview{Statement: statement, Parameters: make([]PreviewParameter, 0, len(args))}
+	for i, v := range args {
+		pp := PreviewParameter{Value: v}
+		if i < len(params) {
+			pp.Name = params[i].GetName()
+		}
+		p.Parameters = append(p.Parameters, pp)
+	}
+	return p
+}
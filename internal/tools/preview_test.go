@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestPreviewStatement(t *testing.T) {
+	templateParams := tools.Parameters{tools.NewStringParameter("table", "some description")}
+	params := tools.Parameters{
+		tools.NewStringParameter("city", "some description"),
+		tools.NewIntParameter("stars", "some description"),
+	}
+	values := tools.ParamValues{{Name: "table", Value: "hotels"}, {Name: "city", Value: "Basel"}, {Name: "stars", Value: 4}}
+	got, err := tools.PreviewStatement(templateParams, params, "SELECT * FROM {{.table}} WHERE city = $1 AND stars >= $2", values)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.StatementPreview{
+		Statement:  "SELECT * FROM hotels WHERE city = $1 AND stars >= $2",
+		Parameters: []tools.PreviewParameter{{Name: "city", Value: "Basel"}, {Name: "stars", Value: 4}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected preview: diff %v", diff)
+	}
+
+	if _, err := tools.PreviewTool(context.Background(), fakeTool{name: "no_statement"}, nil); !errors.Is(err, tools.ErrPreviewUnsupported) {
+		t.Fatalf("expected ErrPreviewUnsupported, got %v", err)
+	}
+}
+
+// previewScopedTool previews the statement of a scopedTool.
+type previewScopedTool struct {
+	scopedTool
+}
+
+func (t previewScopedTool) Preview(ctx context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	stmt, args, err := tools.ApplyPolicyPredicates(ctx, "SELECT * FROM orders WHERE tenant_id = $1", params.AsSlice(), tools.DollarPlaceholders)
+	if err != nil {
+		return tools.StatementPreview{}, err
+	}
+	return tools.NewStatementPreview(stmt, tools.Parameters{tools.NewStringParameter("tenant_id", "some description")}, args), nil
+}
+
+type previewScopedToolConfig struct {
+	scopedToolConfig
+}
+
+func (c previewScopedToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return previewScopedTool{scopedTool: scopedTool{style: tools.DollarPlaceholders}}, nil
+}
+
+func TestPreviewIncludesPolicyPredicates(t *testing.T) {
+	policies, err := tools.CompilePolicies(map[string]tools.PolicyConfig{
+		"own_rows": {Predicate: &tools.PredicateConfig{
+			SQL:  "owner = :user",
+			Bind: map[string]string{"user": "claims.google.email"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg, err := tools.BindPolicies(tools.PolicyToolConfig{
+		ToolConfig:          previewScopedToolConfig{},
+		Names:               []string{"own_rows"},
+		PredicatesSupported: true,
+		Statement:           scopedToolConfig{}.PolicyStatement(),
+	}, policies)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"tenant_id": "acme"}, map[string]map[string]any{"google": {"email": "alice@acme.com"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := tools.PreviewTool(context.Background(), tool, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := tools.StatementPreview{
+		Statement:  "SELECT * FROM (SELECT * FROM orders WHERE tenant_id = $1) AS policy_scope WHERE (owner = $2)",
+		Parameters: []tools.PreviewParameter{{Name: "tenant_id", Value: "acme"}, {Value: "alice@acme.com"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected preview: diff %v", diff)
+	}
+}
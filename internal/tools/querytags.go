@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+// Tags added to the tags of every tagged query, so that the database can
+// attribute it to a tool and the identity that invoked it.
+const (
+	QueryTagTool   string = "toolbox_tool"
+	QueryTagCaller string = "toolbox_caller"
+)
+
+// QueryTagger is implemented by sources that tag the queries their tools run.
+type QueryTagger interface {
+	QueryTags() map[string]string
+}
+
+// SourceQueryTags returns the query tags of a source, if it has any.
+func SourceQueryTags(s sources.Source) map[string]string {
+	if qt, ok := s.(QueryTagger); ok {
+		return qt.QueryTags()
+	}
+	return nil
+}
+
+type queryTagsKey struct{}
+
+// WithQueryTags returns a context carrying tags for the queries of an
+// invocation, in addition to the tags it already carries.
+func WithQueryTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := maps.Clone(queryTagsFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(tags))
+	}
+	maps.Copy(merged, tags)
+	return context.WithValue(ctx, queryTagsKey{}, merged)
+}
+
+func queryTagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(queryTagsKey{}).(map[string]string)
+	return tags
+}
+
+// QueryTags returns the tags of the queries an invocation runs: the tags of
+// its source, overridden by the tags of its tool, along with the tool and
+// caller of the invocation. Queries are only tagged if their source or tool
+// has tags, so nil is returned otherwise.
+func QueryTags(ctx context.Context, sourceTags map[string]string) map[string]string {
+	toolTags := queryTagsFromContext(ctx)
+	if len(sourceTags) == 0 && len(toolTags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(sourceTags)+len(toolTags)+2)
+	maps.Copy(tags, sourceTags)
+	maps.Copy(tags, toolTags)
+	if inv, ok := invocations.FromContext(ctx); ok {
+		tags[QueryTagTool] = inv.Tool
+		if inv.Caller != "" {
+			tags[QueryTagCaller] = inv.Caller
+		}
+	}
+	return tags
+}
+
+// TagStatement prefixes statement with a comment holding tags in the
+// sqlcommenter format, e.g. /*team='finance',toolbox_tool='search'*/, which
+// databases show along with the statement in their activity views and logs.
+func TagStatement(statement string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return statement
+	}
+	pairs := make([]string, 0, len(tags))
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		pairs = append(pairs, fmt.Sprintf("%s='%s'", sqlcommenterEscape(k), sqlcommenterEscape(tags[k])))
+	}
+	return "/*" + strings.Join(pairs, ",") + "*/ " + statement
+}
+
+// sqlcommenterEscape URL-encodes s, so that it can't end the comment it's
+// written in.
+func sqlcommenterEscape(s string) string {
+	s = url.QueryEscape(s)
+	// sqlcommenter encodes spaces as %20 rather than +
+	return strings.ReplaceAll(s, "+", "%20")
+}
+
+// QueryTagsToolConfig wraps a ToolConfig to tag the queries of its tool.
+type QueryTagsToolConfig struct {
+	ToolConfig
+	Tags map[string]string
+}
+
+func (cfg QueryTagsToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	for k := range cfg.Tags {
+		if k == "" {
+			return nil, fmt.Errorf("query tags must not have an empty key")
+		}
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return queryTagsTool{toolWrapper: toolWrapper{t}, tags: cfg.Tags}, nil
+}
+
+type queryTagsTool struct {
+	toolWrapper
+	tags map[string]string
+}
+
+func (t queryTagsTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	return t.Tool.Invoke(WithQueryTags(ctx, t.tags), params)
+}
+
+// Stream forwards to the wrapped tool with the query tags.
+func (t queryTagsTool) Stream(ctx context.Context, params ParamValues, send func(row any) error) error {
+	return streamTool(WithQueryTags(ctx, t.tags), t.Tool, params, send)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestTagStatement(t *testing.T) {
+	tcs := []struct {
+		desc string
+		tags map[string]string
+		want string
+	}{
+		{
+			desc: "no tags",
+			want: "SELECT 1",
+		},
+		{
+			desc: "sorted tags",
+			tags: map[string]string{"toolbox_tool": "search", "team": "finance"},
+			want: "/*team='finance',toolbox_tool='search'*/ SELECT 1",
+		},
+		{
+			desc: "escaped tags",
+			tags: map[string]string{"note": "it's */ done"},
+			want: "/*note='it%27s%20%2A%2F%20done'*/ SELECT 1",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tools.TagStatement("SELECT 1", tc.tags); got != tc.want {
+				t.Fatalf("incorrect statement: got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// tagsTool returns the query tags its invocation would run with.
+type tagsTool struct {
+	fakeTool
+	sourceTags map[string]string
+}
+
+func (t tagsTool) Invoke(ctx context.Context, _ tools.ParamValues) (any, error) {
+	return tools.QueryTags(ctx, t.sourceTags), nil
+}
+
+func TestQueryTags(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		sourceTags map[string]string
+		toolTags   map[string]string
+		want       map[string]string
+	}{
+		{
+			desc: "untagged",
+		},
+		{
+			desc:       "source tags",
+			sourceTags: map[string]string{"team": "finance"},
+			want:       map[string]string{"team": "finance", "toolbox_tool": "my_tool", "toolbox_caller": "alice"},
+		},
+		{
+			desc:       "tool tags override source tags",
+			sourceTags: map[string]string{"team": "finance", "env": "prod"},
+			toolTags:   map[string]string{"team": "growth"},
+			want:       map[string]string{"team": "growth", "env": "prod", "toolbox_tool": "my_tool", "toolbox_caller": "alice"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool, err := tools.QueryTagsToolConfig{
+				ToolConfig: staticToolConfig{tool: tagsTool{sourceTags: tc.sourceTags}},
+				Tags:       tc.toolTags,
+			}.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			ctx, done, err := invocations.NewTracker(0).BeginTool(context.Background(), "my_tool", tool, "alice")
+			if err != nil {
+				t.Fatalf("unable to begin invocation: %s", err)
+			}
+			defer done()
+			got, err := tool.Invoke(ctx, nil)
+			if err != nil {
+				t.Fatalf("unable to invoke tool: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.(map[string]string)); diff != "" {
+				t.Fatalf("incorrect tags (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryTagsEmptyKey(t *testing.T) {
+	_, err := tools.QueryTagsToolConfig{
+		ToolConfig: staticToolConfig{tool: fakeTool{name: "my_tool"}},
+		Tags:       map[string]string{"": "finance"},
+	}.Initialize(nil)
+	if err == nil {
+		t.Fatalf("expected an error for an empty tag key")
+	}
+}
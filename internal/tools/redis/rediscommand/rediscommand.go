@@ -0,0 +1,320 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rediscommand
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	redissrc "github.com/googleapis/genai-toolbox/internal/sources/redis"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "redis-command"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type compatibleSource interface {
+	RedisClient() redissrc.RedisClient
+}
+
+// validate compatible sources are still compatible
+var _ compatibleSource = &redissrc.Source{}
+
+var compatibleSources = [...]string{redissrc.SourceKind}
+
+type Config struct {
+	Name         string           `yaml:"name" validate:"required"`
+	Kind         string           `yaml:"kind" validate:"required"`
+	Source       string           `yaml:"source" validate:"required"`
+	Description  string           `yaml:"description" validate:"required"`
+	Command      []string         `yaml:"command" validate:"required"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(compatibleSource)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
+	}
+
+	if len(cfg.Command) == 0 {
+		return nil, fmt.Errorf("command for %q tool must not be empty", kind)
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	// finish tool setup
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		Command:      cfg.Command,
+		AuthRequired: cfg.AuthRequired,
+		Client:       s.RedisClient(),
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+
+	Client      redissrc.RedisClient
+	Command     []string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	cmd, err := bindCommandParams(t.Command, t.Parameters, params)
+	if err != nil {
+		return nil, fmt.Errorf("error binding command parameters: %s", err)
+	}
+
+	val, err := t.Client.Do(ctx, cmd...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error executing command: %s", err)
+	}
+
+	if isSearchCommand(t.Command[0]) {
+		if rows, ok := searchResultToRows(val); ok {
+			return rows, nil
+		}
+	}
+	return normalize(val), nil
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
+
+// bindCommandParams replaces `$name` arguments of the command with parameter
+// values. Arrays of floats are bound as a single FLOAT32 vector blob (as
+// expected by RediSearch vector fields), other arrays are expanded into
+// multiple arguments.
+func bindCommandParams(command []string, params tools.Parameters, paramValues tools.ParamValues) ([]any, error) {
+	paramMap := paramValues.AsMapWithDollarPrefix()
+	paramDefs := make(map[string]tools.Parameter, len(params))
+	for _, p := range params {
+		paramDefs["$"+p.GetName()] = p
+	}
+
+	out := make([]any, 0, len(command))
+	for _, part := range command {
+		p, ok := paramDefs[part]
+		if !ok {
+			// Command part is not a Parameter placeholder
+			out = append(out, part)
+			continue
+		}
+		v := paramMap[part]
+		if v == nil {
+			return nil, fmt.Errorf("no value provided for parameter %q", p.GetName())
+		}
+		arrayParam, ok := p.(*tools.ArrayParameter)
+		if !ok {
+			out = append(out, fmt.Sprintf("%v", v))
+			continue
+		}
+		items, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected parameter %q to be an array, got %T", p.GetName(), v)
+		}
+		if arrayParam.GetItems().GetType() == "float" {
+			blob, err := float32Blob(items)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encode parameter %q as a vector: %w", p.GetName(), err)
+			}
+			out = append(out, blob)
+			continue
+		}
+		for _, item := range items {
+			out = append(out, fmt.Sprintf("%v", item))
+		}
+	}
+	return out, nil
+}
+
+// float32Blob encodes a list of floats as little-endian FLOAT32 values.
+func float32Blob(items []any) ([]byte, error) {
+	buf := make([]byte, 4*len(items))
+	for i, item := range items {
+		f, ok := item.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected item at index %d to be float, got %T", i, item)
+		}
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(float32(f)))
+	}
+	return buf, nil
+}
+
+func isSearchCommand(name string) bool {
+	switch strings.ToUpper(name) {
+	case "FT.SEARCH", "FT.AGGREGATE":
+		return true
+	default:
+		return false
+	}
+}
+
+// searchResultToRows converts a RediSearch reply into a list of row-style
+// maps. Both the RESP2 (flat array) and RESP3 (map) reply shapes are handled.
+func searchResultToRows(val any) ([]any, bool) {
+	switch v := val.(type) {
+	case map[any]any:
+		// RESP3: {"total_results": n, "results": [{"id": ..., "extra_attributes": {...}}]}
+		results, ok := v["results"].([]any)
+		if !ok {
+			return nil, false
+		}
+		rows := make([]any, 0, len(results))
+		for _, r := range results {
+			res, ok := r.(map[any]any)
+			if !ok {
+				return nil, false
+			}
+			row := map[string]any{}
+			if id, ok := res["id"]; ok {
+				row["id"] = id
+			}
+			if attrs, ok := res["extra_attributes"].(map[any]any); ok {
+				for k, a := range attrs {
+					row[fmt.Sprintf("%v", k)] = a
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows, true
+	case []any:
+		// RESP2: [total, id1, [field, value, ...], id2, [...], ...]
+		if len(v) == 0 {
+			return nil, false
+		}
+		if _, ok := v[0].(int64); !ok {
+			return nil, false
+		}
+		rows := make([]any, 0, len(v)/2)
+		for i := 1; i < len(v); i++ {
+			row := map[string]any{}
+			if fields, ok := v[i].([]any); ok {
+				// FT.AGGREGATE replies don't include document ids
+				addFieldPairs(row, fields)
+				rows = append(rows, row)
+				continue
+			}
+			row["id"] = v[i]
+			if i+1 < len(v) {
+				if fields, ok := v[i+1].([]any); ok {
+					addFieldPairs(row, fields)
+					i++
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows, true
+	default:
+		return nil, false
+	}
+}
+
+func addFieldPairs(row map[string]any, fields []any) {
+	for j := 0; j+1 < len(fields); j += 2 {
+		row[fmt.Sprintf("%v", fields[j])] = fields[j+1]
+	}
+}
+
+// normalize converts map[any]any replies (e.g. HGETALL over RESP3) to
+// map[string]any, since Go's built-in json/encoding marshalling doesn't support
+// map[any]any as an input. Maps nested in arrays and maps, such as the
+// results of FT.SEARCH or FT.INFO over RESP3, are converted too.
+func normalize(val any) any {
+	switch v := val.(type) {
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[fmt.Sprint(k)] = normalize(e)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = normalize(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = normalize(e)
+		}
+		return out
+	default:
+		return val
+	}
+}
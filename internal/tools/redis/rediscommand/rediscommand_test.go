@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package rediscommand
+
+import (
+	"encoding/json"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseFromYamlRedisCommand(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				redis_tool:
+					kind: redis-command
+					source: my-redis-instance
+					description: some description
+					command: [HGETALL, $key]
+					parameters:
+						- name: key
+						  type: string
+						  description: hash key
+			`,
+			want: server.ToolConfigs{
+				"redis_tool": Config{
+					Name:         "redis_tool",
+					Kind:         "redis-command",
+					Source:       "my-redis-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Command:      []string{"HGETALL", "$key"},
+					Parameters: []tools.Parameter{
+						tools.NewStringParameter("key", "hash key"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestBindCommandParams(t *testing.T) {
+	params := tools.Parameters{
+		tools.NewIntParameter("k", "number of neighbors"),
+		tools.NewArrayParameter("vec", "query embedding", tools.NewFloatParameter("item", "dimension")),
+		tools.NewArrayParameter("keys", "keys to fetch", tools.NewStringParameter("item", "key")),
+	}
+	values := tools.ParamValues{
+		{Name: "k", Value: 3},
+		{Name: "vec", Value: []any{1.0, 0.5}},
+		{Name: "keys", Value: []any{"a", "b"}},
+	}
+	got, err := bindCommandParams([]string{"FT.SEARCH", "idx", "*=>[KNN $k @v $vec]", "PARAMS", "2", "vec", "$vec", "LIMIT", "0", "$k", "$keys"}, params, values)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []any{
+		"FT.SEARCH", "idx", "*=>[KNN $k @v $vec]", "PARAMS", "2", "vec",
+		[]byte{0x00, 0x00, 0x80, 0x3f, 0x00, 0x00, 0x00, 0x3f},
+		"LIMIT", "0", "3", "a", "b",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect binding: diff %v", diff)
+	}
+}
+
+func TestSearchResultToRows(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   any
+		want []any
+	}{
+		{
+			desc: "resp2",
+			in:   []any{int64(2), "doc:1", []any{"name", "a", "score", "0.1"}, "doc:2", []any{"name", "b"}},
+			want: []any{
+				map[string]any{"id": "doc:1", "name": "a", "score": "0.1"},
+				map[string]any{"id": "doc:2", "name": "b"},
+			},
+		},
+		{
+			desc: "resp2 nocontent",
+			in:   []any{int64(2), "doc:1", "doc:2"},
+			want: []any{
+				map[string]any{"id": "doc:1"},
+				map[string]any{"id": "doc:2"},
+			},
+		},
+		{
+			desc: "resp3",
+			in: map[any]any{
+				"total_results": int64(1),
+				"results": []any{
+					map[any]any{"id": "doc:1", "extra_attributes": map[any]any{"name": "a"}},
+				},
+			},
+			want: []any{map[string]any{"id": "doc:1", "name": "a"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, ok := searchResultToRows(tc.in)
+			if !ok {
+				t.Fatalf("unable to convert search result")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect rows: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   any
+		want any
+	}{
+		{
+			desc: "scalar",
+			in:   "OK",
+			want: "OK",
+		},
+		{
+			desc: "map",
+			in:   map[any]any{"name": "a", int64(1): "b"},
+			want: map[string]any{"name": "a", "1": "b"},
+		},
+		{
+			desc: "nested maps",
+			in: map[any]any{
+				"total_results": int64(1),
+				"attributes":    []any{map[any]any{"identifier": "name", "type": "TEXT"}},
+				"format":        map[string]any{"values": map[any]any{"a": []any{map[any]any{"b": "c"}}}},
+			},
+			want: map[string]any{
+				"total_results": int64(1),
+				"attributes":    []any{map[string]any{"identifier": "name", "type": "TEXT"}},
+				"format":        map[string]any{"values": map[string]any{"a": []any{map[string]any{"b": "c"}}}},
+			},
+		},
+		{
+			desc: "array of maps",
+			in:   []any{map[any]any{"a": int64(1)}, "b"},
+			want: []any{map[string]any{"a": int64(1)}, "b"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := normalize(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result (-want +got):\n%s", diff)
+			}
+			if _, err := json.Marshal(got); err != nil {
+				t.Fatalf("unable to marshal result: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// ResultSet is one of the sets of rows returned by a statement that returns
+// several, such as a stored procedure or a script.
+type ResultSet struct {
+	// Label names the set by its position, e.g. "resultSet1".
+	Label    string         `json:"label"`
+	Columns  []ResultColumn `json:"columns"`
+	RowCount int            `json:"rowCount"`
+	Rows     []any          `json:"rows"`
+}
+
+// ResultColumn describes a column of a ResultSet.
+type ResultColumn struct {
+	Name string `json:"name"`
+	// Type is the database type of the column, if the driver reports it.
+	Type string `json:"type,omitempty"`
+}
+
+// NewResultSet returns the index-th result set of a statement, counting from
+// one, with the columns and rows the driver returned for it.
+func NewResultSet(index int, cols []*sql.ColumnType, rows []any) ResultSet {
+	set := ResultSet{
+		Label:    "resultSet" + strconv.Itoa(index),
+		Columns:  make([]ResultColumn, 0, len(cols)),
+		RowCount: len(rows),
+		Rows:     rows,
+	}
+	if set.Rows == nil {
+		set.Rows = []any{}
+	}
+	for _, c := range cols {
+		set.Columns = append(set.Columns, ResultColumn{Name: c.Name(), Type: c.DatabaseTypeName()})
+	}
+	return set
+}
+
+// ScanResultSets scans every set of rows returned by a statement with scan,
+// which scans the rows of the current set, and closes rows. A statement that
+// returns a single set returns its rows, as before. A statement that returns
+// several returns a []ResultSet, in order.
+func ScanResultSets(rows *sql.Rows, scan func(*sql.Rows) ([]any, error)) (any, error) {
+	defer rows.Close()
+	var sets []ResultSet
+	for {
+		cols, err := rows.ColumnTypes()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get column types: %w", err)
+		}
+		out, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, NewResultSet(len(sets)+1, cols, out))
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+	}
+	if len(sets) == 1 {
+		if sets[0].RowCount == 0 {
+			return nil, nil
+		}
+		return sets[0].Rows, nil
+	}
+	return sets, nil
+}
+
+// ScanRows scans the rows of the current result set into maps of column
+// names to the values returned by the driver. It doesn't close rows.
+func ScanRows(rows *sql.Rows) ([]any, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get column names: %w", err)
+	}
+
+	// create an array of values for each column, which can be re-used to scan each row
+	rawValues := make([]any, len(cols))
+	values := make([]any, len(cols))
+	for i := range rawValues {
+		values[i] = &rawValues[i]
+	}
+
+	var out []any
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("unable to parse row: %w", err)
+		}
+		vMap := make(map[string]any, len(cols))
+		for i, name := range cols {
+			vMap[name] = rawValues[i]
+		}
+		out = append(out, vMap)
+	}
+	return out, nil
+}
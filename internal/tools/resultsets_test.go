@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	_ "modernc.org/sqlite"
+)
+
+func TestScanResultSets(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+
+	tcs := []struct {
+		desc      string
+		statement string
+		want      any
+	}{
+		{
+			desc:      "single result set",
+			statement: "SELECT 1 AS id, 'Basel' AS city UNION ALL SELECT 2, 'Bern'",
+			want: []any{
+				map[string]any{"id": int64(1), "city": "Basel"},
+				map[string]any{"id": int64(2), "city": "Bern"},
+			},
+		},
+		{
+			desc:      "empty result set",
+			statement: "SELECT 1 AS id WHERE 1 = 0",
+			want:      nil,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			rows, err := db.Query(tc.statement)
+			if err != nil {
+				t.Fatalf("unable to run query: %s", err)
+			}
+			got, err := tools.ScanResultSets(rows, tools.ScanRows)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect result (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewResultSet(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+	rows, err := db.Query("SELECT 1 AS id")
+	if err != nil {
+		t.Fatalf("unable to run query: %s", err)
+	}
+	defer rows.Close()
+	cols, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("unable to get column types: %s", err)
+	}
+
+	got := tools.NewResultSet(2, cols, nil)
+	want := tools.ResultSet{Label: "resultSet2", Columns: []tools.ResultColumn{{Name: "id"}}, RowCount: 0, Rows: []any{}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect result set (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Classes of errors that a RetryPolicy can retry.
+const (
+	RetryOnDeadlock             string = "deadlock"
+	RetryOnSerializationFailure string = "serializationFailure"
+	RetryOnConnection           string = "connection"
+	RetryOnUnavailable          string = "unavailable"
+)
+
+var retryClasses = []string{RetryOnDeadlock, RetryOnSerializationFailure, RetryOnConnection, RetryOnUnavailable}
+
+// defaultRetryClasses are the classes of errors retried by default. A
+// dropped connection may have happened after the statement ran, so the
+// `connection` class must be opted into.
+var defaultRetryClasses = []string{RetryOnDeadlock, RetryOnSerializationFailure, RetryOnUnavailable}
+
+// RetryPolicy configures how a tool's invocations are retried when they fail
+// with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int `yaml:"maxAttempts"`
+	// InitialBackoff is the wait before the first retry, e.g. "100ms".
+	InitialBackoff string `yaml:"initialBackoff"`
+	// MaxBackoff caps the wait between two attempts, e.g. "2s".
+	MaxBackoff string `yaml:"maxBackoff"`
+	// Multiplier grows the wait after every attempt.
+	Multiplier float64 `yaml:"multiplier"`
+	// RetryOn lists the error classes that are retried. Defaults to deadlock,
+	// serializationFailure and unavailable.
+	RetryOn []string `yaml:"retryOn"`
+	// Idempotent declares that the tool's statement is safe to run more than
+	// once, which is required to retry connection errors.
+	Idempotent bool `yaml:"idempotent"`
+}
+
+type retrySettings struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	retryOn        []string
+	idempotent     bool
+}
+
+func (p RetryPolicy) settings() (retrySettings, error) {
+	s := retrySettings{
+		maxAttempts:    3,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     2 * time.Second,
+		multiplier:     2,
+		retryOn:        defaultRetryClasses,
+		idempotent:     p.Idempotent,
+	}
+	if p.MaxAttempts < 0 {
+		return s, fmt.Errorf("maxAttempts must not be negative, got %d", p.MaxAttempts)
+	}
+	if p.MaxAttempts > 0 {
+		s.maxAttempts = p.MaxAttempts
+	}
+	if p.InitialBackoff != "" {
+		d, err := time.ParseDuration(p.InitialBackoff)
+		if err != nil || d < 0 {
+			return s, fmt.Errorf("invalid initialBackoff %q", p.InitialBackoff)
+		}
+		s.initialBackoff = d
+	}
+	if p.MaxBackoff != "" {
+		d, err := time.ParseDuration(p.MaxBackoff)
+		if err != nil || d < 0 {
+			return s, fmt.Errorf("invalid maxBackoff %q", p.MaxBackoff)
+		}
+		s.maxBackoff = d
+	}
+	if s.maxBackoff < s.initialBackoff {
+		return s, fmt.Errorf("maxBackoff %s must not be less than initialBackoff %s", s.maxBackoff, s.initialBackoff)
+	}
+	if p.Multiplier < 0 || (p.Multiplier > 0 && p.Multiplier < 1) {
+		return s, fmt.Errorf("multiplier must be at least 1, got %v", p.Multiplier)
+	}
+	if p.Multiplier > 0 {
+		s.multiplier = p.Multiplier
+	}
+	if len(p.RetryOn) > 0 {
+		for _, c := range p.RetryOn {
+			if !slices.Contains(retryClasses, c) {
+				return s, fmt.Errorf("invalid retryOn class %q: must be one of %q", c, retryClasses)
+			}
+		}
+		s.retryOn = p.RetryOn
+	}
+	return s, nil
+}
+
+// backoff returns the wait before the given (1-based) retry.
+func (s retrySettings) backoff(retry int) time.Duration {
+	d := float64(s.initialBackoff)
+	for i := 1; i < retry; i++ {
+		d *= s.multiplier
+		if d >= float64(s.maxBackoff) {
+			d = float64(s.maxBackoff)
+			break
+		}
+	}
+	// jitter between 50% and 100% of the computed wait
+	return time.Duration(d * (0.5 + rand.Float64()/2))
+}
+
+// ClassifyRetryableError returns the retry class of a transient error, or an
+// empty string if the error should not be retried.
+func ClassifyRetryableError(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ""
+	}
+	var sqlState interface{ SQLState() string }
+	if errors.As(err, &sqlState) {
+		switch sqlState.SQLState() {
+		case "40P01":
+			return RetryOnDeadlock
+		case "40001":
+			return RetryOnSerializationFailure
+		}
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, driver.ErrBadConn) {
+		return RetryOnConnection
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Aborted:
+			return RetryOnSerializationFailure
+		case codes.Unavailable:
+			return RetryOnUnavailable
+		}
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "deadlock"):
+		return RetryOnDeadlock
+	case strings.Contains(msg, "could not serialize access"), strings.Contains(msg, "serialization failure"):
+		return RetryOnSerializationFailure
+	case strings.Contains(msg, "connection reset"), strings.Contains(msg, "broken pipe"), strings.Contains(msg, "connection refused"):
+		return RetryOnConnection
+	}
+	return ""
+}
+
+// RetryToolConfig wraps a ToolConfig so the tool it initializes retries
+// transient errors according to Retry.
+type RetryToolConfig struct {
+	ToolConfig
+	Retry RetryPolicy
+}
+
+// validate interface
+var _ ToolConfig = RetryToolConfig{}
+
+func (cfg RetryToolConfig) Initialize(srcs map[string]sources.Source) (Tool, error) {
+	s, err := cfg.Retry.settings()
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry policy: %w", err)
+	}
+	if slices.Contains(s.retryOn, RetryOnConnection) && !s.idempotent {
+		return nil, fmt.Errorf("invalid retry policy: retrying %q errors requires the tool to be idempotent, since the statement may have run before the connection dropped", RetryOnConnection)
+	}
+	t, err := cfg.ToolConfig.Initialize(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return retryTool{toolWrapper: toolWrapper{t}, settings: s}, nil
+}
+
+type retryTool struct {
+	toolWrapper
+	settings retrySettings
+}
+
+func (t retryTool) Invoke(ctx context.Context, params ParamValues) (any, error) {
+	return t.retry(ctx, func() (any, error) { return t.Tool.Invoke(ctx, params) }, nil)
+}
+
+// Stream forwards to the wrapped tool, retrying like Invoke as long as no row
+// was sent, since the client would otherwise receive rows twice.
+func (t retryTool) Stream(ctx context.Context, params ParamValues, send func(row any) error) error {
+	sent := false
+	_, err := t.retry(ctx, func() (any, error) {
+		return nil, streamTool(ctx, t.Tool, params, func(row any) error {
+			sent = true
+			return send(row)
+		})
+	}, func() bool { return !sent })
+	return err
+}
+
+// retry calls call until it succeeds, fails with an error that isn't retried,
+// or runs out of attempts. If retryable isn't nil, a failed call is only
+// retried if it returns true.
+func (t retryTool) retry(ctx context.Context, call func() (any, error), retryable func() bool) (any, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := call()
+		if err == nil || attempt >= t.settings.maxAttempts || (retryable != nil && !retryable()) {
+			return res, err
+		}
+		class := ClassifyRetryableError(err)
+		if class == "" || !slices.Contains(t.settings.retryOn, class) {
+			return res, err
+		}
+		wait := t.settings.backoff(attempt)
+		if logger, lerr := util.LoggerFromContext(ctx); lerr == nil {
+			logger.DebugContext(ctx, fmt.Sprintf("attempt %d of %d failed with a %s error, retrying in %s: %s", attempt, t.settings.maxAttempts, class, wait.Round(time.Millisecond), err))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
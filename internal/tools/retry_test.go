@@ -0,0 +1,166 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sql error " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+type flakyTool struct {
+	fakeTool
+	errs  []error
+	calls *int
+}
+
+func (t flakyTool) Invoke(context.Context, tools.ParamValues) (any, error) {
+	i := *t.calls
+	*t.calls++
+	if i < len(t.errs) {
+		return nil, t.errs[i]
+	}
+	return "ok", nil
+}
+
+type flakyToolConfig struct {
+	tool flakyTool
+}
+
+func (c flakyToolConfig) ToolConfigKind() string { return "flaky" }
+func (c flakyToolConfig) Initialize(map[string]sources.Source) (tools.Tool, error) {
+	return c.tool, nil
+}
+
+func TestClassifyRetryableError(t *testing.T) {
+	tcs := []struct {
+		err  error
+		want string
+	}{
+		{err: fmt.Errorf("unable to execute query: %w", sqlStateError("40P01")), want: tools.RetryOnDeadlock},
+		{err: sqlStateError("40001"), want: tools.RetryOnSerializationFailure},
+		{err: sqlStateError("42601"), want: ""},
+		{err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: tools.RetryOnConnection},
+		{err: errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), want: tools.RetryOnDeadlock},
+		{err: fmt.Errorf("query cancelled: %w", context.Canceled), want: ""},
+		{err: errors.New("column \"foo\" does not exist"), want: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			if got := tools.ClassifyRetryableError(tc.err); got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryToolConfig(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		retry     tools.RetryPolicy
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			desc:      "retries transient errors",
+			retry:     tools.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms"},
+			errs:      []error{sqlStateError("40001"), sqlStateError("40P01")},
+			wantCalls: 3,
+		},
+		{
+			desc:      "does not retry connection errors by default",
+			retry:     tools.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms"},
+			errs:      []error{syscall.ECONNRESET},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			desc:      "retries connection errors of idempotent tools",
+			retry:     tools.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms", RetryOn: []string{tools.RetryOnConnection}, Idempotent: true},
+			errs:      []error{syscall.ECONNRESET},
+			wantCalls: 2,
+		},
+		{
+			desc:      "gives up after max attempts",
+			retry:     tools.RetryPolicy{MaxAttempts: 2, InitialBackoff: "1ms"},
+			errs:      []error{sqlStateError("40001"), sqlStateError("40001")},
+			wantCalls: 2,
+			wantErr:   true,
+		},
+		{
+			desc:      "does not retry other errors",
+			retry:     tools.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms"},
+			errs:      []error{errors.New("syntax error")},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			desc:      "only retries configured classes",
+			retry:     tools.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms", RetryOn: []string{tools.RetryOnDeadlock}},
+			errs:      []error{syscall.ECONNRESET},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			calls := 0
+			cfg := tools.RetryToolConfig{
+				ToolConfig: flakyToolConfig{tool: flakyTool{errs: tc.errs, calls: &calls}},
+				Retry:      tc.retry,
+			}
+			tool, err := cfg.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			_, err = tool.Invoke(context.Background(), nil)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if calls != tc.wantCalls {
+				t.Fatalf("got %d calls, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryToolConfigInvalid(t *testing.T) {
+	tcs := []tools.RetryPolicy{
+		{MaxAttempts: -1},
+		{InitialBackoff: "soon"},
+		{InitialBackoff: "2s", MaxBackoff: "1s"},
+		{Multiplier: 0.5},
+		{RetryOn: []string{"timeout"}},
+		// connection errors are only retried for idempotent tools
+		{RetryOn: []string{tools.RetryOnConnection}},
+	}
+	for _, retry := range tcs {
+		cfg := tools.RetryToolConfig{ToolConfig: flakyToolConfig{}, Retry: retry}
+		if _, err := cfg.Initialize(nil); err == nil {
+			t.Errorf("expected error for %+v", retry)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/robfig/cron/v3"
+)
+
+// Kinds of schedule sinks.
+const (
+	SinkWebhook string = "webhook"
+	SinkFile    string = "file"
+	SinkTool    string = "tool"
+)
+
+// DefaultScheduleTimeout bounds a scheduled run, including its delivery, if
+// its schedule sets no timeout.
+const DefaultScheduleTimeout = 30 * time.Minute
+
+// ScheduleConfig runs a tool on a cron schedule and delivers the outcome of
+// each run to a sink.
+type ScheduleConfig struct {
+	// Tool is the name of the tool to run.
+	Tool string `yaml:"tool" validate:"required"`
+	// Cron is a five-field cron expression, e.g. "0 3 * * *", or a
+	// descriptor such as "@daily" or "@every 15m". It's evaluated in the
+	// local time zone unless prefixed with e.g. "CRON_TZ=Europe/Paris ".
+	Cron string `yaml:"cron" validate:"required"`
+	// Params are the parameters the tool is invoked with.
+	Params map[string]any `yaml:"params"`
+	// Timeout bounds each run, e.g. "10m".
+	Timeout string `yaml:"timeout"`
+	// Sink receives the outcome of each run. Without a sink, outcomes are
+	// only logged.
+	Sink *SinkConfig `yaml:"sink"`
+}
+
+// SinkConfig configures where the outcomes of a schedule's runs are
+// delivered.
+type SinkConfig struct {
+	// Kind is "webhook", "file" or "tool".
+	Kind string `yaml:"kind" validate:"required"`
+	// URL a webhook sink POSTs each outcome to as JSON, with Headers.
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	// Destination of a file sink, either "gs://bucket/prefix" or a local
+	// directory. Each outcome is written to its own JSON file.
+	Destination string `yaml:"destination"`
+	// Tool a tool sink invokes with Params, and the JSON encoded result of
+	// each successful run in the parameter named Param.
+	Tool   string         `yaml:"tool"`
+	Param  string         `yaml:"param"`
+	Params map[string]any `yaml:"params"`
+}
+
+// ScheduleRun is the outcome of a scheduled run.
+type ScheduleRun struct {
+	Schedule   string     `json:"schedule"`
+	Tool       string     `json:"tool"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt time.Time  `json:"finishedAt"`
+	Result     any        `json:"result,omitempty"`
+	Error      *ToolError `json:"error,omitempty"`
+}
+
+// ScheduleCaller is the caller of the invocations of a schedule, e.g. in
+// journals and query tags.
+func ScheduleCaller(name string) string {
+	return "schedule:" + name
+}
+
+type schedule struct {
+	name    string
+	cfg     ScheduleConfig
+	cron    cron.Schedule
+	timeout time.Duration
+	store   artifactStore
+	running atomic.Bool
+}
+
+// compileSchedule checks a schedule against the tools it invokes.
+func compileSchedule(name string, cfg ScheduleConfig, toolsMap map[string]Tool) (*schedule, error) {
+	sc := &schedule{name: name, cfg: cfg, timeout: DefaultScheduleTimeout}
+	var err error
+	if sc.cron, err = cron.ParseStandard(cfg.Cron); err != nil {
+		return nil, fmt.Errorf("invalid cron %q: %w", cfg.Cron, err)
+	}
+	if cfg.Timeout != "" {
+		if sc.timeout, err = time.ParseDuration(cfg.Timeout); err != nil || sc.timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q: must be a positive duration", cfg.Timeout)
+		}
+	}
+	if err := checkScheduledTool(cfg.Tool, cfg.Params, toolsMap); err != nil {
+		return nil, err
+	}
+	if cfg.Sink == nil {
+		return sc, nil
+	}
+	switch s := cfg.Sink; s.Kind {
+	case SinkWebhook:
+		if s.URL == "" {
+			return nil, fmt.Errorf("a %q sink requires a url", SinkWebhook)
+		}
+	case SinkFile:
+		if s.Destination == "" {
+			return nil, fmt.Errorf("a %q sink requires a destination", SinkFile)
+		}
+	case SinkTool:
+		if s.Param == "" {
+			return nil, fmt.Errorf("a %q sink requires a param", SinkTool)
+		}
+		// the result is only known when the schedule runs
+		params := maps.Clone(s.Params)
+		if params == nil {
+			params = make(map[string]any, 1)
+		}
+		params[s.Param] = "{}"
+		if err := checkScheduledTool(s.Tool, params, toolsMap); err != nil {
+			return nil, fmt.Errorf("invalid sink: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown sink kind %q, must be %q, %q or %q", s.Kind, SinkWebhook, SinkFile, SinkTool)
+	}
+	return sc, nil
+}
+
+// checkScheduledTool checks that a scheduled run can invoke the named tool
+// with params. Scheduled runs have no caller, so the tool can't require
+// authentication.
+func checkScheduledTool(name string, params map[string]any, toolsMap map[string]Tool) error {
+	t, ok := toolsMap[name]
+	if !ok {
+		return fmt.Errorf("no tool named %q configured", name)
+	}
+	if !t.Authorized([]string{}) {
+		return fmt.Errorf("tool %q requires authentication, which scheduled runs don't have", name)
+	}
+	if _, err := t.ParseParams(params, map[string]map[string]any{}); err != nil {
+		return fmt.Errorf("invalid params for tool %q: %w", name, err)
+	}
+	return nil
+}
+
+// ValidateSchedules checks schedules against the tools they invoke, without
+// connecting to their sinks.
+func ValidateSchedules(cfgs map[string]ScheduleConfig, toolsMap map[string]Tool) error {
+	for name, cfg := range cfgs {
+		if _, err := compileSchedule(name, cfg, toolsMap); err != nil {
+			return fmt.Errorf("invalid schedule %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Scheduler runs the tools of schedules at the times of their cron
+// expressions. A run that is still going when its next time comes is
+// skipped.
+type Scheduler struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	cron      *cron.Cron
+	schedules map[string]*schedule
+	lookup    func(name string) (Tool, bool)
+	tracker   *invocations.Tracker
+}
+
+// NewScheduler returns a Scheduler of schedules. Tools are looked up when
+// they run, so that a reload of the tools is picked up. Runs use the logger
+// of ctx, and are cancelled when the scheduler stops.
+func NewScheduler(ctx context.Context, cfgs map[string]ScheduleConfig, toolsMap map[string]Tool, lookup func(name string) (Tool, bool), tracker *invocations.Tracker) (*Scheduler, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Scheduler{
+		ctx:       ctx,
+		cancel:    cancel,
+		cron:      cron.New(),
+		schedules: make(map[string]*schedule, len(cfgs)),
+		lookup:    lookup,
+		tracker:   tracker,
+	}
+	for name, cfg := range cfgs {
+		sc, err := compileSchedule(name, cfg, toolsMap)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid schedule %q: %w", name, err)
+		}
+		if cfg.Sink != nil && cfg.Sink.Kind == SinkFile {
+			if sc.store, err = exportStoreConfig(cfg.Sink.Destination).initialize(); err != nil {
+				cancel()
+				return nil, fmt.Errorf("invalid schedule %q: unable to initialize sink: %w", name, err)
+			}
+		}
+		s.schedules[name] = sc
+		s.cron.Schedule(sc.cron, cron.FuncJob(func() { s.runScheduled(name) }))
+	}
+	return s, nil
+}
+
+// Start starts running schedules in the background.
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop stops scheduling runs and waits for the running ones to finish. If
+// ctx is done first, the running runs are cancelled.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	done := s.cron.Stop()
+	select {
+	case <-done.Done():
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) runScheduled(name string) {
+	run, err := s.Run(s.ctx, name)
+	logger, lerr := util.LoggerFromContext(s.ctx)
+	if lerr != nil {
+		return
+	}
+	switch {
+	case err != nil:
+		logger.ErrorContext(s.ctx, fmt.Sprintf("schedule %q failed: %s", name, err))
+	case run.Error != nil:
+		logger.ErrorContext(s.ctx, fmt.Sprintf("schedule %q failed to run tool %q: %s", name, run.Tool, run.Error))
+	default:
+		logger.InfoContext(s.ctx, fmt.Sprintf("schedule %q ran tool %q in %s", name, run.Tool, run.FinishedAt.Sub(run.StartedAt)))
+	}
+}
+
+// Run runs a schedule now and delivers its outcome to its sink. A failure of
+// the tool is reported in the outcome; the error is for failing to run or
+// deliver it.
+func (s *Scheduler) Run(ctx context.Context, name string) (ScheduleRun, error) {
+	sc, ok := s.schedules[name]
+	if !ok {
+		return ScheduleRun{}, fmt.Errorf("no schedule named %q", name)
+	}
+	if !sc.running.CompareAndSwap(false, true) {
+		return ScheduleRun{}, fmt.Errorf("schedule %q is still running", name)
+	}
+	defer sc.running.Store(false)
+
+	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
+	defer cancel()
+
+	run := ScheduleRun{Schedule: name, Tool: sc.cfg.Tool, StartedAt: time.Now()}
+	res, err := s.invoke(ctx, sc.cfg.Tool, sc.cfg.Params, name)
+	run.FinishedAt = time.Now()
+	if err != nil {
+		run.Error = ClassifyError(err, ErrorCategoryQuery, ErrorCodeQueryFailed)
+	} else {
+		run.Result = res
+	}
+	if sc.cfg.Sink != nil {
+		if err := s.deliver(ctx, sc, run); err != nil {
+			return run, fmt.Errorf("unable to deliver to %q sink: %w", sc.cfg.Sink.Kind, err)
+		}
+	}
+	return run, nil
+}
+
+// invoke invokes the named tool on behalf of a schedule.
+func (s *Scheduler) invoke(ctx context.Context, name string, data map[string]any, schedule string) (any, error) {
+	t, ok := s.lookup(name)
+	if !ok {
+		return nil, NewToolError(ErrorCategoryValidation, ErrorCodeToolNotFound, "", fmt.Errorf("no tool named %q configured", name))
+	}
+	params, err := t.ParseParams(data, map[string]map[string]any{})
+	if err != nil {
+		return nil, NewToolError(ErrorCategoryValidation, ErrorCodeInvalidParameters, "", err)
+	}
+	if s.tracker != nil {
+		var done func()
+		ctx, done, err = s.tracker.BeginTool(ctx, name, t, ScheduleCaller(schedule))
+		if err != nil {
+			return nil, err
+		}
+		defer done()
+	}
+	res, err := t.Invoke(ctx, params)
+	if s.tracker != nil {
+		s.tracker.RecordError(ctx, err)
+	}
+	return res, err
+}
+
+func (s *Scheduler) deliver(ctx context.Context, sc *schedule, run ScheduleRun) error {
+	sink := sc.cfg.Sink
+	switch sink.Kind {
+	case SinkWebhook:
+		body, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for k, v := range sink.Headers {
+			req.Header.Set(k, v)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, respBody)
+		}
+		return nil
+	case SinkFile:
+		body, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%s-%s.json", run.Schedule, run.StartedAt.UTC().Format("20060102T150405.000Z"))
+		_, err = sc.store.put(ctx, name, "application/json", body)
+		return err
+	case SinkTool:
+		// only results are delivered to tools; failures are logged
+		if run.Error != nil {
+			return nil
+		}
+		result, err := json.Marshal(run.Result)
+		if err != nil {
+			return err
+		}
+		params := maps.Clone(sink.Params)
+		if params == nil {
+			params = make(map[string]any, 1)
+		}
+		params[sink.Param] = string(result)
+		_, err = s.invoke(ctx, sink.Tool, params, run.Schedule)
+		return err
+	default:
+		return fmt.Errorf("unknown sink kind %q", sink.Kind)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// recordingTool records the parameters of its invocations.
+type recordingTool struct {
+	fakeTool
+	params   tools.Parameters
+	recorded *[]map[string]any
+}
+
+func (t recordingTool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.params, data, claims)
+}
+
+func (t recordingTool) Invoke(_ context.Context, params tools.ParamValues) (any, error) {
+	*t.recorded = append(*t.recorded, params.AsMap())
+	return nil, nil
+}
+
+type authRequiredTool struct {
+	fakeTool
+}
+
+func (t authRequiredTool) Authorized([]string) bool { return false }
+
+func newTestScheduler(t *testing.T, cfgs map[string]tools.ScheduleConfig, toolsMap map[string]tools.Tool) *tools.Scheduler {
+	t.Helper()
+	lookup := func(name string) (tools.Tool, bool) {
+		tool, ok := toolsMap[name]
+		return tool, ok
+	}
+	s, err := tools.NewScheduler(context.Background(), cfgs, toolsMap, lookup, nil)
+	if err != nil {
+		t.Fatalf("unable to create scheduler: %s", err)
+	}
+	return s
+}
+
+func TestValidateSchedules(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"my_tool":     fakeTool{name: "my_tool"},
+		"secret_tool": authRequiredTool{fakeTool{name: "secret_tool"}},
+	}
+	tcs := []struct {
+		desc string
+		cfg  tools.ScheduleConfig
+	}{
+		{desc: "invalid cron", cfg: tools.ScheduleConfig{Tool: "my_tool", Cron: "every day"}},
+		{desc: "invalid timeout", cfg: tools.ScheduleConfig{Tool: "my_tool", Cron: "@daily", Timeout: "-1m"}},
+		{desc: "unknown tool", cfg: tools.ScheduleConfig{Tool: "other_tool", Cron: "@daily"}},
+		{desc: "tool requires auth", cfg: tools.ScheduleConfig{Tool: "secret_tool", Cron: "@daily"}},
+		{desc: "unknown sink kind", cfg: tools.ScheduleConfig{Tool: "my_tool", Cron: "@daily", Sink: &tools.SinkConfig{Kind: "email"}}},
+		{desc: "webhook without url", cfg: tools.ScheduleConfig{Tool: "my_tool", Cron: "@daily", Sink: &tools.SinkConfig{Kind: tools.SinkWebhook}}},
+		{desc: "file without destination", cfg: tools.ScheduleConfig{Tool: "my_tool", Cron: "@daily", Sink: &tools.SinkConfig{Kind: tools.SinkFile}}},
+		{desc: "tool sink without param", cfg: tools.ScheduleConfig{Tool: "my_tool", Cron: "@daily", Sink: &tools.SinkConfig{Kind: tools.SinkTool, Tool: "my_tool"}}},
+		{desc: "unknown sink tool", cfg: tools.ScheduleConfig{Tool: "my_tool", Cron: "@daily", Sink: &tools.SinkConfig{Kind: tools.SinkTool, Tool: "other_tool", Param: "result"}}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := tools.ValidateSchedules(map[string]tools.ScheduleConfig{"nightly": tc.cfg}, toolsMap); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+
+	valid := map[string]tools.ScheduleConfig{
+		"nightly": {Tool: "my_tool", Cron: "CRON_TZ=Europe/Paris 0 3 * * *", Timeout: "10m"},
+	}
+	if err := tools.ValidateSchedules(valid, toolsMap); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestScheduleWebhookSink(t *testing.T) {
+	var got tools.ScheduleRun
+	var auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("unable to parse webhook body: %s", err)
+		}
+	}))
+	defer ts.Close()
+
+	s := newTestScheduler(t, map[string]tools.ScheduleConfig{
+		"nightly": {
+			Tool: "my_tool",
+			Cron: "@daily",
+			Sink: &tools.SinkConfig{Kind: tools.SinkWebhook, URL: ts.URL, Headers: map[string]string{"Authorization": "Bearer token"}},
+		},
+	}, map[string]tools.Tool{
+		"my_tool": resultTool{fakeTool: fakeTool{name: "my_tool"}, result: []any{"a", "b"}},
+	})
+	run, err := s.Run(context.Background(), "nightly")
+	if err != nil {
+		t.Fatalf("unable to run schedule: %s", err)
+	}
+	if run.Error != nil {
+		t.Fatalf("unexpected run error: %s", run.Error)
+	}
+	if got.Schedule != "nightly" || got.Tool != "my_tool" || !cmp.Equal(got.Result, []any{"a", "b"}) {
+		t.Fatalf("incorrect delivery: %+v", got)
+	}
+	if auth != "Bearer token" {
+		t.Fatalf("incorrect Authorization header: %q", auth)
+	}
+}
+
+func TestScheduleFileSink(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestScheduler(t, map[string]tools.ScheduleConfig{
+		"nightly": {Tool: "my_tool", Cron: "@daily", Sink: &tools.SinkConfig{Kind: tools.SinkFile, Destination: dir}},
+	}, map[string]tools.Tool{
+		"my_tool": resultTool{fakeTool: fakeTool{name: "my_tool"}, result: "done"},
+	})
+	if _, err := s.Run(context.Background(), "nightly"); err != nil {
+		t.Fatalf("unable to run schedule: %s", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "nightly-*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected a single delivered file, got %q (%v)", files, err)
+	}
+	b, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("unable to read delivered file: %s", err)
+	}
+	var run tools.ScheduleRun
+	if err := json.Unmarshal(b, &run); err != nil || run.Result != "done" {
+		t.Fatalf("incorrect delivered file %s: %v", b, err)
+	}
+}
+
+func TestScheduleToolSink(t *testing.T) {
+	var recorded []map[string]any
+	s := newTestScheduler(t, map[string]tools.ScheduleConfig{
+		"nightly": {
+			Tool: "my_tool",
+			Cron: "@daily",
+			Sink: &tools.SinkConfig{Kind: tools.SinkTool, Tool: "store_summary", Param: "summary", Params: map[string]any{"name": "orders"}},
+		},
+	}, map[string]tools.Tool{
+		"my_tool": resultTool{fakeTool: fakeTool{name: "my_tool"}, result: map[string]any{"total": 3}},
+		"store_summary": recordingTool{
+			fakeTool: fakeTool{name: "store_summary"},
+			params: tools.Parameters{
+				tools.NewStringParameter("name", "Name of the summary."),
+				tools.NewStringParameter("summary", "The summary."),
+			},
+			recorded: &recorded,
+		},
+	})
+	if _, err := s.Run(context.Background(), "nightly"); err != nil {
+		t.Fatalf("unable to run schedule: %s", err)
+	}
+	want := []map[string]any{{"name": "orders", "summary": `{"total":3}`}}
+	if diff := cmp.Diff(want, recorded); diff != "" {
+		t.Fatalf("incorrect sink invocations (-want +got):\n%s", diff)
+	}
+}
@@ -85,6 +85,16 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 		return nil, fmt.Errorf("invalid source for %q tool: source kind must be one of %q", kind, compatibleSources)
 	}
 
+	// the PostgreSQL dialect binds numbered placeholders, e.g. $1
+	placeholders := tools.AtPlaceholders
+	if strings.ToLower(s.DatabaseDialect()) == "postgresql" {
+		placeholders = tools.DollarPlaceholders
+	}
+	statement, params, err := tools.BindNamedParams(cfg.Statement, placeholders, cfg.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement for %q tool: %w", kind, err)
+	}
+
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
@@ -97,10 +107,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	t := Tool{
 		Name:               cfg.Name,
 		Kind:               kind,
-		Parameters:         cfg.Parameters,
+		Parameters:         params,
 		TemplateParameters: cfg.TemplateParameters,
 		AllParams:          allParameters,
-		Statement:          cfg.Statement,
+		Statement:          statement,
 		AuthRequired:       cfg.AuthRequired,
 		ReadOnly:           cfg.ReadOnly,
 		Client:             s.SpannerClient(),
@@ -113,6 +123,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 // validate interface
 var _ tools.Tool = Tool{}
+var _ tools.PreviewableTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -195,6 +206,23 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 			if err != nil {
 				return nil, fmt.Errorf("unable to convert parameter `%s` from []any to typed slice: %w", name, err)
 			}
+		case *tools.DecimalParameter:
+			// the PostgreSQL dialect binds NUMERIC as PGNumeric instead of big.Rat
+			if s, ok := value.(string); ok && strings.ToLower(t.dialect) == "postgresql" {
+				value = spanner.PGNumeric{Numeric: s, Valid: true}
+				break
+			}
+			var err error
+			value, err = tools.ConvertToNativeValue(p.GetType(), value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert parameter `%s`: %w", name, err)
+			}
+		case *tools.DateParameter:
+			var err error
+			value, err = tools.ConvertToNativeValue(p.GetType(), value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert parameter `%s`: %w", name, err)
+			}
 		}
 		newParams[i] = tools.ParamValue{Name: name, Value: value}
 	}
@@ -232,6 +260,11 @@ func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error)
 	return results, nil
 }
 
+// Preview renders the statement the invocation would run.
+func (t Tool) Preview(ctx context.Context, params tools.ParamValues) (tools.StatementPreview, error) {
+	return tools.PreviewStatement(t.TemplateParameters, t.Parameters, t.Statement, params)
+}
+
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
 	return tools.ParseParams(t.AllParams, data, claims)
 }
@@ -15,11 +15,15 @@
 package sqlitesql_test
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/sqlitesql"
@@ -174,3 +178,52 @@ func TestParseFromYamlWithTemplateSqlite(t *testing.T) {
 		})
 	}
 }
+
+func TestInvokeNamedPlaceholders(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("unable to open database: %s", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE hotels (id INTEGER, name TEXT, city TEXT);
+		INSERT INTO hotels VALUES (1, 'Hilton Basel', 'Basel'), (2, 'Hyatt Zurich', 'Zurich'), (3, 'Basel Inn', 'Bern')`); err != nil {
+		t.Fatalf("unable to create table: %s", err)
+	}
+	srcs := map[string]sources.Source{"my-sqlite-db": &sqlite.Source{Name: "my-sqlite-db", Kind: sqlite.SourceKind, Db: db}}
+
+	cfg := sqlitesql.Config{
+		Name:        "search_hotels",
+		Kind:        "sqlite-sql",
+		Source:      "my-sqlite-db",
+		Description: "some description",
+		// placeholders are written in a different order than the parameters,
+		// and used more than once
+		Statement: "SELECT id FROM hotels WHERE (city = :city OR name LIKE :city || '%') AND id >= :minId ORDER BY id",
+		Parameters: []tools.Parameter{
+			tools.NewIntParameter("minId", "smallest id"),
+			tools.NewStringParameter("city", "city"),
+		},
+	}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"minId": 2, "city": "Basel"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	want := []any{map[string]any{"id": int64(3)}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect result (-want +got):\n%s", diff)
+	}
+
+	cfg.Statement = "SELECT id FROM hotels WHERE city = :cty"
+	if _, err := cfg.Initialize(srcs); err == nil || err.Error() != `invalid statement for "sqlite-sql" tool: named placeholder :cty has no matching parameter` {
+		t.Fatalf("unexpected error for unknown placeholder: %v", err)
+	}
+}
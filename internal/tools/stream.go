@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrStreamStalled is returned when a client stops reading a streamed result
+// for longer than the server's stall timeout.
+var ErrStreamStalled = errors.New("the client stopped reading the streamed result")
+
+// StreamingTool is a Tool that can send the rows of its result as they are
+// fetched, rather than returning them all at once. send blocks while the
+// client is slow to read, which pauses fetching. If send returns an error,
+// Stream must stop and return it.
+type StreamingTool interface {
+	Tool
+	Stream(ctx context.Context, params ParamValues, send func(row any) error) error
+}
+
+// AsStreamingTool returns t if it can stream its result. Tool wrappers
+// implement Stream whatever the tool they wrap, and report whether it can
+// stream with CanStream.
+func AsStreamingTool(t Tool) (StreamingTool, bool) {
+	st, ok := t.(StreamingTool)
+	if !ok {
+		return nil, false
+	}
+	if c, ok := t.(interface{ CanStream() bool }); ok && !c.CanStream() {
+		return nil, false
+	}
+	return st, true
+}
+
+// canStream reports whether t can stream its result, for the CanStream
+// methods of tool wrappers.
+func canStream(t Tool) bool {
+	_, ok := AsStreamingTool(t)
+	return ok
+}
+
+// streamTool streams the result of t, which wrappers only do when the tool
+// they wrap can stream.
+func streamTool(ctx context.Context, t Tool, params ParamValues, send func(row any) error) error {
+	st, ok := AsStreamingTool(t)
+	if !ok {
+		return fmt.Errorf("tool %q can't stream its result", t.McpManifest().Name)
+	}
+	return st.Stream(ctx, params, send)
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// streamingTool streams its rows, failing with the next of errs once failAt
+// rows were sent.
+type streamingTool struct {
+	fakeTool
+	rows   []any
+	failAt int
+	errs   []error
+	calls  *int
+}
+
+func (t streamingTool) Stream(_ context.Context, _ tools.ParamValues, send func(row any) error) error {
+	i := *t.calls
+	*t.calls++
+	for n, row := range t.rows {
+		if n == t.failAt && i < len(t.errs) {
+			return t.errs[i]
+		}
+		if err := send(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect streams the rows of t.
+func collect(t *testing.T, tool tools.Tool, params tools.ParamValues) ([]any, error) {
+	t.Helper()
+	st, ok := tools.AsStreamingTool(tool)
+	if !ok {
+		t.Fatalf("expected tool to stream its result")
+	}
+	rows := []any{}
+	err := st.Stream(context.Background(), params, func(row any) error {
+		rows = append(rows, row)
+		return nil
+	})
+	return rows, err
+}
+
+func TestAsStreamingTool(t *testing.T) {
+	calls := 0
+	streaming := streamingTool{fakeTool: fakeTool{name: "my_tool"}, calls: &calls}
+	tcs := []struct {
+		desc string
+		cfg  tools.ToolConfig
+		want bool
+	}{
+		{desc: "unwrapped", cfg: staticToolConfig{tool: streaming}, want: true},
+		{desc: "with query tags", cfg: tools.QueryTagsToolConfig{ToolConfig: staticToolConfig{tool: streaming}, Tags: map[string]string{"team": "data"}}, want: true},
+		{desc: "with query tags and retries", cfg: tools.QueryTagsToolConfig{ToolConfig: tools.RetryToolConfig{ToolConfig: staticToolConfig{tool: streaming}}}, want: true},
+		{desc: "wrapping a tool that doesn't stream", cfg: tools.QueryTagsToolConfig{ToolConfig: staticToolConfig{tool: fakeTool{name: "my_tool"}}}, want: false},
+		{desc: "with a budget", cfg: tools.BudgetToolConfig{ToolConfig: staticToolConfig{tool: streaming}, Budget: tools.ResponseBudget{MaxBytes: 1024}}, want: false},
+		{desc: "as a job", cfg: tools.AsyncToolConfig{ToolConfig: staticToolConfig{tool: streaming}}, want: false},
+		{desc: "requiring approval", cfg: tools.ApprovalToolConfig{ToolConfig: staticToolConfig{tool: streaming}}, want: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tool, err := tc.cfg.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			if _, got := tools.AsStreamingTool(tool); got != tc.want {
+				t.Fatalf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryToolStream(t *testing.T) {
+	rows := []any{map[string]any{"id": 1}, map[string]any{"id": 2}}
+	tcs := []struct {
+		desc      string
+		failAt    int
+		want      []any
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			desc:      "retries errors before the first row",
+			failAt:    0,
+			want:      rows,
+			wantCalls: 2,
+		},
+		{
+			desc:      "does not retry errors after the first row",
+			failAt:    1,
+			want:      rows[:1],
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			calls := 0
+			inner := streamingTool{fakeTool: fakeTool{name: "my_tool"}, rows: rows, failAt: tc.failAt, errs: []error{sqlStateError("40001")}, calls: &calls}
+			tool, err := tools.RetryToolConfig{ToolConfig: staticToolConfig{tool: inner}, Retry: tools.RetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms"}}.Initialize(nil)
+			if err != nil {
+				t.Fatalf("unable to initialize tool: %s", err)
+			}
+			got, err := collect(t, tool, nil)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+			}
+			if calls != tc.wantCalls {
+				t.Fatalf("got %d calls, want %d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPolicyToolStreamRequiresScope(t *testing.T) {
+	calls := 0
+	// the policy scope is only added by ParseParams
+	tool, err := tools.PolicyToolConfig{ToolConfig: staticToolConfig{tool: streamingTool{fakeTool: fakeTool{name: "my_tool"}, rows: []any{"row"}, calls: &calls}}}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	if _, err := collect(t, tool, nil); err == nil || calls != 0 {
+		t.Fatalf("expected the stream to fail without running the tool, got %v after %d calls", err, calls)
+	}
+	params, err := tool.ParseParams(map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	rows, err := collect(t, tool, params)
+	if err != nil {
+		t.Fatalf("unable to stream: %s", err)
+	}
+	if diff := cmp.Diff([]any{"row"}, rows); diff != "" {
+		t.Fatalf("incorrect rows (-want +got):\n%s", diff)
+	}
+}
@@ -15,19 +15,36 @@
 package tools
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
 type ToolsetConfig struct {
-	Name      string   `yaml:"name"`
-	ToolNames []string `yaml:",inline"`
+	Name      string     `yaml:"name"`
+	ToolNames []string   `yaml:",inline"`
+	Server    ServerInfo `yaml:"server"`
+}
+
+// ServerInfo overrides how the MCP server serving a toolset introduces itself
+// to clients. Empty fields keep the defaults of Toolbox.
+type ServerInfo struct {
+	Name         string `yaml:"name"`
+	Version      string `yaml:"version"`
+	Instructions string `yaml:"instructions"`
 }
 
 type Toolset struct {
 	Name        string          `yaml:"name"`
+	Server      ServerInfo      `yaml:"server"`
 	Tools       []*Tool         `yaml:",inline"`
 	Manifest    ToolsetManifest `yaml:",inline"`
 	McpManifest []McpManifest   `yaml:",inline"`
+
+	// Serialized manifests, precomputed when the toolset is initialized so
+	// they are not re-encoded on every request.
+	ManifestJSON      json.RawMessage            `yaml:"-"`
+	McpManifestJSON   json.RawMessage            `yaml:"-"`
+	ToolManifestsJSON map[string]json.RawMessage `yaml:"-"`
 }
 
 type ToolsetManifest struct {
@@ -40,6 +57,7 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 	// Check each declared tool name exists
 	var toolset Toolset
 	toolset.Name = t.Name
+	toolset.Server = t.Server
 	if !IsValidName(toolset.Name) {
 		return toolset, fmt.Errorf("invalid toolset name: %s", t)
 	}
@@ -58,5 +76,29 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 		toolset.McpManifest = append(toolset.McpManifest, tool.McpManifest())
 	}
 
+	var err error
+	if toolset.ManifestJSON, err = json.Marshal(toolset.Manifest); err != nil {
+		return toolset, fmt.Errorf("unable to serialize manifest of toolset %q: %w", t.Name, err)
+	}
+	if toolset.McpManifestJSON, err = json.Marshal(toolset.McpManifest); err != nil {
+		return toolset, fmt.Errorf("unable to serialize mcp manifest of toolset %q: %w", t.Name, err)
+	}
+	toolset.ToolManifestsJSON = make(map[string]json.RawMessage, len(toolset.Manifest.ToolsManifest))
+	for name, m := range toolset.Manifest.ToolsManifest {
+		single := ToolsetManifest{ServerVersion: serverVersion, ToolsManifest: map[string]Manifest{name: m}}
+		if toolset.ToolManifestsJSON[name], err = json.Marshal(single); err != nil {
+			return toolset, fmt.Errorf("unable to serialize manifest of tool %q: %w", name, err)
+		}
+	}
+
 	return toolset, nil
 }
+
+// SerializedMcpManifest returns the serialized MCP manifest of the toolset,
+// encoding it if it was not precomputed.
+func (t Toolset) SerializedMcpManifest() (json.RawMessage, error) {
+	if t.McpManifestJSON != nil {
+		return t.McpManifestJSON, nil
+	}
+	return json.Marshal(t.McpManifest)
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+type fakeTool struct {
+	name string
+}
+
+func (t fakeTool) Invoke(context.Context, tools.ParamValues) (any, error) { return nil, nil }
+func (t fakeTool) ParseParams(map[string]any, map[string]map[string]any) (tools.ParamValues, error) {
+	return nil, nil
+}
+func (t fakeTool) Manifest() tools.Manifest {
+	return tools.Manifest{Description: t.name + " description", Parameters: []tools.ParameterManifest{}}
+}
+func (t fakeTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{Name: t.name, Description: t.name + " description"}
+}
+func (t fakeTool) Authorized([]string) bool { return true }
+
+func TestToolsetSerializedManifests(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"tool_a": fakeTool{name: "tool_a"},
+		"tool_b": fakeTool{name: "tool_b"},
+	}
+	toolset, err := tools.ToolsetConfig{Name: "my_toolset", ToolNames: []string{"tool_a", "tool_b"}}.Initialize("1.2.3", toolsMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var m tools.ToolsetManifest
+	if err := json.Unmarshal(toolset.ManifestJSON, &m); err != nil {
+		t.Fatalf("unable to parse serialized manifest: %s", err)
+	}
+	if diff := cmp.Diff(toolset.Manifest, m); diff != "" {
+		t.Errorf("serialized manifest does not match manifest: diff %v", diff)
+	}
+
+	var mcp []tools.McpManifest
+	b, err := toolset.SerializedMcpManifest()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := json.Unmarshal(b, &mcp); err != nil {
+		t.Fatalf("unable to parse serialized mcp manifest: %s", err)
+	}
+	if diff := cmp.Diff(toolset.McpManifest, mcp); diff != "" {
+		t.Errorf("serialized mcp manifest does not match mcp manifest: diff %v", diff)
+	}
+
+	for name := range toolsMap {
+		var single tools.ToolsetManifest
+		if err := json.Unmarshal(toolset.ToolManifestsJSON[name], &single); err != nil {
+			t.Fatalf("unable to parse serialized manifest of %q: %s", name, err)
+		}
+		want := tools.ToolsetManifest{ServerVersion: "1.2.3", ToolsManifest: map[string]tools.Manifest{name: toolsMap[name].Manifest()}}
+		if diff := cmp.Diff(want, single); diff != "" {
+			t.Errorf("unexpected serialized manifest of %q: diff %v", name, diff)
+		}
+	}
+}
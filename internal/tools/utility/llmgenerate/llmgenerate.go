@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmgenerate
+
+import (
+	"context"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "llm-generate"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name        string `yaml:"name" validate:"required"`
+	Kind        string `yaml:"kind" validate:"required"`
+	Description string `yaml:"description" validate:"required"`
+	// Prompt is a Go template of the prompt, which reads the parameters,
+	// e.g. {{.rows}} or {{json .rows}}.
+	Prompt string `yaml:"prompt" validate:"required"`
+	// System is the system instruction of the prompt.
+	System string `yaml:"system"`
+	// Model is called when the client can't be sampled, e.g. over
+	// streamable HTTP or in scheduled runs.
+	Model *llm.Config `yaml:"model"`
+	// MaxTokens limits the length of the response.
+	MaxTokens    int              `yaml:"maxTokens"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	AuthRequired []string         `yaml:"authRequired"`
+}
+
+// validate interface
+var _ tools.ToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize(_ map[string]sources.Source) (tools.Tool, error) {
+	if cfg.MaxTokens < 0 {
+		return nil, fmt.Errorf("maxTokens must not be negative, got %d", cfg.MaxTokens)
+	}
+	var model llm.Model
+	if cfg.Model != nil {
+		var err error
+		model, err = cfg.Model.Initialize(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("unable to initialize model: %w", err)
+		}
+	}
+
+	mcpManifest := tools.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		InputSchema: cfg.Parameters.McpManifest(),
+	}
+
+	t := Tool{
+		Name:         cfg.Name,
+		Kind:         kind,
+		Parameters:   cfg.Parameters,
+		Prompt:       cfg.Prompt,
+		System:       cfg.System,
+		MaxTokens:    cfg.MaxTokens,
+		AuthRequired: cfg.AuthRequired,
+		model:        model,
+		manifest:     tools.Manifest{Description: cfg.Description, Parameters: cfg.Parameters.Manifest(), AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}
+	return t, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string           `yaml:"name"`
+	Kind         string           `yaml:"kind"`
+	AuthRequired []string         `yaml:"authRequired"`
+	Parameters   tools.Parameters `yaml:"parameters"`
+	Prompt       string
+	System       string
+	MaxTokens    int
+
+	model       llm.Model
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Invoke renders the prompt and generates the response with the client's
+// model, through MCP sampling, or the configured model if the client can't
+// be sampled.
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) (any, error) {
+	prompt, err := tools.PopulateTemplateWithJSON("LLMGeneratePrompt", t.Prompt, params.AsMap())
+	if err != nil {
+		return nil, fmt.Errorf("unable to render prompt: %w", err)
+	}
+
+	model, ok := llm.ClientModelFromContext(ctx)
+	if !ok {
+		model = t.model
+	}
+	if model == nil {
+		return nil, tools.NewToolError(tools.ErrorCategoryValidation, tools.ErrorCodeInvalidRequest, "the client doesn't support sampling and the tool has no model configured", nil)
+	}
+	return model.Generate(ctx, llm.Request{System: t.System, Prompt: prompt, MaxTokens: t.MaxTokens})
+}
+
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.Parameters, data, claims)
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmgenerate_test
+
+import (
+	"context"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/llm"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/llmgenerate"
+)
+
+func TestParseFromYamlLLMGenerate(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: llm-generate
+					description: some description
+					prompt: Summarize {{json .rows}}
+					parameters:
+						- name: rows
+							type: string
+							description: the rows
+			`,
+			want: server.ToolConfigs{
+				"example_tool": llmgenerate.Config{
+					Name:         "example_tool",
+					Kind:         "llm-generate",
+					Description:  "some description",
+					Prompt:       "Summarize {{json .rows}}",
+					Parameters:   tools.Parameters{tools.NewStringParameter("rows", "the rows")},
+					AuthRequired: []string{},
+				},
+			},
+		},
+		{
+			desc: "with model",
+			in: `
+			tools:
+				example_tool:
+					kind: llm-generate
+					description: some description
+					prompt: Classify the ticket.
+					system: Answer with one word.
+					maxTokens: 16
+					model:
+						kind: vertexai
+						model: gemini-2.5-flash
+						project: my-project
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": llmgenerate.Config{
+					Name:         "example_tool",
+					Kind:         "llm-generate",
+					Description:  "some description",
+					Prompt:       "Classify the ticket.",
+					System:       "Answer with one word.",
+					MaxTokens:    16,
+					Model:        &llm.Config{Kind: "vertexai", Model: "gemini-2.5-flash", Project: "my-project"},
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+type fakeModel struct {
+	got *llm.Request
+}
+
+func (m fakeModel) Generate(_ context.Context, req llm.Request) (string, error) {
+	*m.got = req
+	return "two orders shipped", nil
+}
+
+func TestInvoke(t *testing.T) {
+	cfg := llmgenerate.Config{
+		Name:        "summarize",
+		Kind:        "llm-generate",
+		Description: "Summarizes rows.",
+		Prompt:      "Summarize {{json .rows}} for {{.region}}.",
+		System:      "Be brief.",
+		MaxTokens:   64,
+		Parameters: tools.Parameters{
+			tools.NewArrayParameter("rows", "the rows", tools.NewStringParameter("row", "a row")),
+			tools.NewStringParameter("region", "the region"),
+		},
+	}
+	tool, err := cfg.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize: %s", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"rows": []any{"a", "b"}, "region": "emea"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+
+	// without a client model or a configured one, the tool can't run
+	if _, err := tool.Invoke(context.Background(), params); err == nil {
+		t.Fatalf("expected error without a model")
+	}
+
+	var got llm.Request
+	ctx := llm.WithClientModel(context.Background(), fakeModel{got: &got})
+	out, err := tool.Invoke(ctx, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out != "two orders shipped" {
+		t.Fatalf("unexpected result: %v", out)
+	}
+	want := llm.Request{System: "Be brief.", Prompt: `Summarize ["a","b"] for emea.`, MaxTokens: 64}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected request: diff %v", diff)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queuestatus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/queuestatus"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestParseFromYamlQueueStatus(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			tools:
+				example_tool:
+					kind: queue-status
+					description: some description
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"example_tool": queuestatus.Config{
+					Name:         "example_tool",
+					Kind:         "queue-status",
+					Description:  "some description",
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestInvokeQueueStatus(t *testing.T) {
+	tracker := invocations.NewTracker(1)
+	ctx := util.WithInvocationTracker(context.Background(), tracker)
+
+	// one invocation of alice runs, another one waits for its slot
+	_, done, err := tracker.Begin(ctx, "slow_tool", "alice")
+	if err != nil {
+		t.Fatalf("unable to begin invocation: %s", err)
+	}
+	defer done()
+	queued := make(chan func())
+	go func() {
+		_, done, err := tracker.Begin(ctx, "other_tool", "alice")
+		if err != nil {
+			t.Errorf("unable to begin invocation: %s", err)
+		}
+		queued <- done
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for tracker.SnapshotAll().Queued != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the invocation to be queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	tool, err := queuestatus.Config{Name: "queue_status", Kind: "queue-status", Description: "some description"}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	tcs := []struct {
+		desc   string
+		caller string
+		want   []invocations.InvocationStatus
+	}{
+		{
+			desc:   "caller sees its invocations",
+			caller: "alice",
+			want: []invocations.InvocationStatus{
+				{Tool: "slow_tool", Caller: "alice", Status: invocations.StatusRunning},
+				{Tool: "other_tool", Caller: "alice", Status: invocations.StatusQueued, QueuePosition: 1},
+			},
+		},
+		{
+			desc:   "other caller sees none",
+			caller: "bob",
+			want:   []invocations.InvocationStatus{},
+		},
+		{
+			desc: "anonymous caller sees none",
+			want: []invocations.InvocationStatus{},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			// the status is still reported while the server is at capacity
+			invCtx, invDone, err := tracker.BeginTool(ctx, "queue_status", tool, tc.caller)
+			if err != nil {
+				t.Fatalf("unable to begin invocation: %s", err)
+			}
+			defer invDone()
+			res, err := tool.Invoke(invCtx, nil)
+			if err != nil {
+				t.Fatalf("unable to invoke tool: %s", err)
+			}
+			got, ok := res.(invocations.Snapshot)
+			if !ok {
+				t.Fatalf("unexpected result type %T", res)
+			}
+			if got.MaxConcurrent != 1 || got.Running != 1 || got.Queued != 1 {
+				t.Fatalf("unexpected counts: %#v", got)
+			}
+			opts := cmp.FilterPath(func(p cmp.Path) bool {
+				name := p.Last().String()
+				return name == ".ID" || name == ".Elapsed" || name == ".EstimatedWait"
+			}, cmp.Ignore())
+			if diff := cmp.Diff(tc.want, got.Invocations, opts); diff != "" {
+				t.Fatalf("incorrect invocations (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	done()
+	(<-queued)()
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usagestats_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/utility/usagestats"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+func TestParseFromYamlUsageStats(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	in := `
+	tools:
+		example_tool:
+			kind: usage-stats
+			description: some description
+			authRequired:
+				- my-google-auth-service
+	`
+	want := server.ToolConfigs{
+		"example_tool": usagestats.Config{
+			Name:         "example_tool",
+			Kind:         "usage-stats",
+			Description:  "some description",
+			AuthRequired: []string{"my-google-auth-service"},
+		},
+	}
+	got := struct {
+		Tools server.ToolConfigs `yaml:"tools"`
+	}{}
+	if err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(in), &got); err != nil {
+		t.Fatalf("unable to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(want, got.Tools); diff != "" {
+		t.Fatalf("incorrect parse: diff %v", diff)
+	}
+}
+
+func TestInvokeUsageStats(t *testing.T) {
+	tool, err := usagestats.Config{Name: "stats", Kind: "usage-stats", Description: "d"}.Initialize(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tracker := invocations.NewTracker(0)
+	for _, caller := range []string{"alice", "bob", "alice"} {
+		_, done, err := tracker.Begin(context.Background(), "search", caller)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		done()
+	}
+	ctx := util.WithInvocationTracker(context.Background(), tracker)
+
+	params, err := tool.ParseParams(map[string]any{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res, err := tool.Invoke(ctx, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	stats := res.(invocations.Stats)
+	if stats.Window != "1h0m0s" || len(stats.Tools) != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if got := stats.Tools[0]; got.Tool != "search" || got.Invocations != 3 || got.UniqueCallers != 2 {
+		t.Errorf("unexpected stats of tool: %+v", got)
+	}
+
+	params, err = tool.ParseParams(map[string]any{"window": "-1h"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = tool.Invoke(ctx, params)
+	var te *tools.ToolError
+	if !errors.As(err, &te) || te.Code != tools.ErrorCodeInvalidParameters {
+		t.Errorf("expected an INVALID_PARAMETERS error, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import "context"
+
+// toolWrapper is embedded by the tools that wrap another tool to change how
+// it's configured or invoked. It forwards the optional methods of the wrapped
+// tool, so a wrapper only overrides the ones its behavior affects. Wrappers
+// whose result can't be sent row by row override CanStream.
+type toolWrapper struct {
+	Tool
+}
+
+// QueueExempt forwards the wrapped tool's queue exemption, if any.
+func (t toolWrapper) QueueExempt() bool {
+	e, ok := t.Tool.(interface{ QueueExempt() bool })
+	return ok && e.QueueExempt()
+}
+
+// RequiresApproval forwards whether the wrapped tool requires approval.
+func (t toolWrapper) RequiresApproval() bool {
+	return RequiresApproval(t.Tool)
+}
+
+// Preview forwards to the wrapped tool.
+func (t toolWrapper) Preview(ctx context.Context, params ParamValues) (StatementPreview, error) {
+	return PreviewTool(ctx, t.Tool, params)
+}
+
+// Stream forwards to the wrapped tool.
+func (t toolWrapper) Stream(ctx context.Context, params ParamValues, send func(row any) error) error {
+	return streamTool(ctx, t.Tool, params, send)
+}
+
+// CanStream reports whether the wrapped tool can stream its result.
+func (t toolWrapper) CanStream() bool {
+	return canStream(t.Tool)
+}
@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/invocations"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 )
@@ -162,3 +164,37 @@ func InstrumentationFromContext(ctx context.Context) (*telemetry.Instrumentation
 	}
 	return nil, fmt.Errorf("unable to retrieve instrumentation")
 }
+
+const invocationTrackerKey contextKey = "invocationTracker"
+
+// WithInvocationTracker adds an invocation tracker into the context as a value
+func WithInvocationTracker(ctx context.Context, tracker *invocations.Tracker) context.Context {
+	return context.WithValue(ctx, invocationTrackerKey, tracker)
+}
+
+// InvocationTrackerFromContext retrieves the invocation tracker or return an error
+func InvocationTrackerFromContext(ctx context.Context) (*invocations.Tracker, error) {
+	if tracker, ok := ctx.Value(invocationTrackerKey).(*invocations.Tracker); ok && tracker != nil {
+		return tracker, nil
+	}
+	return nil, fmt.Errorf("unable to retrieve invocation tracker")
+}
+
+const invocationCallerKey contextKey = "invocationCaller"
+
+// WithInvocationCaller adds a function resolving the caller invocations are
+// tracked under into the context. It is only called once a tool is invoked,
+// at most once per context.
+func WithInvocationCaller(ctx context.Context, caller func() string) context.Context {
+	return context.WithValue(ctx, invocationCallerKey, sync.OnceValue(caller))
+}
+
+// InvocationCallerFromContext resolves the caller invocations are tracked
+// under, "" for anonymous callers
+func InvocationCallerFromContext(ctx context.Context) string {
+	caller, ok := ctx.Value(invocationCallerKey).(func() string)
+	if !ok {
+		return ""
+	}
+	return caller()
+}
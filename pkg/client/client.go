@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client invokes the tools of a running Toolbox server from Go.
+//
+// A Client loads the manifests of tools from the server, and returns a Tool
+// for each of them. Tools marshal their parameters, attach the auth tokens
+// they require, and decode the results of their invocations:
+//
+//	c, err := client.New("http://127.0.0.1:5000")
+//	if err != nil {
+//		return err
+//	}
+//	tools, err := c.LoadToolset(ctx, "my-toolset")
+//	if err != nil {
+//		return err
+//	}
+//	var hotels []Hotel
+//	err = tools[0].InvokeInto(ctx, map[string]any{"location": "Basel"}, &hotels)
+//
+// The package only depends on the standard library, so importing it doesn't
+// pull in the dependencies of the server.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// TokenSource returns the token sent to an auth service. It's called before
+// every request, so it can refresh expired tokens.
+type TokenSource func(ctx context.Context) (string, error)
+
+// StaticToken returns a TokenSource that always returns token.
+func StaticToken(token string) TokenSource {
+	return func(context.Context) (string, error) { return token, nil }
+}
+
+// Option configures a Client or a Tool.
+type Option func(*options)
+
+type options struct {
+	httpClient   *http.Client
+	header       http.Header
+	tokenSources map[string]TokenSource
+}
+
+func (o *options) clone() *options {
+	c := &options{
+		httpClient:   o.httpClient,
+		header:       o.header.Clone(),
+		tokenSources: make(map[string]TokenSource, len(o.tokenSources)),
+	}
+	for k, v := range o.tokenSources {
+		c.tokenSources[k] = v
+	}
+	return c
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with. Defaults to
+// http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) { o.httpClient = c }
+}
+
+// WithHeader adds a header to every request, e.g. to authenticate with a
+// proxy in front of the server.
+func WithHeader(key, value string) Option {
+	return func(o *options) { o.header.Add(key, value) }
+}
+
+// WithAuthToken sends token to the auth service of the given name.
+func WithAuthToken(authService, token string) Option {
+	return WithAuthTokenSource(authService, StaticToken(token))
+}
+
+// WithAuthTokenSource sends the token returned by ts to the auth service of
+// the given name.
+func WithAuthTokenSource(authService string, ts TokenSource) Option {
+	return func(o *options) { o.tokenSources[authService] = ts }
+}
+
+// Client loads tools from a Toolbox server.
+type Client struct {
+	baseURL *url.URL
+	opts    *options
+}
+
+// New returns a Client for the server at baseURL, e.g.
+// "http://127.0.0.1:5000". Options apply to every tool the client loads.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	o := &options{
+		httpClient:   http.DefaultClient,
+		header:       make(http.Header),
+		tokenSources: make(map[string]TokenSource),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Client{baseURL: u, opts: o}, nil
+}
+
+// LoadToolset loads the tools of a toolset, sorted by name. An empty name
+// loads the default toolset, which has every tool of the server. Options
+// apply to the loaded tools in addition to the options of the client.
+func (c *Client) LoadToolset(ctx context.Context, name string, opts ...Option) ([]*Tool, error) {
+	path := "/api/toolset/"
+	if name != "" {
+		path += url.PathEscape(name)
+	}
+	m, err := c.getManifest(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load toolset %q: %w", name, err)
+	}
+	names := make([]string, 0, len(m.Tools))
+	for n := range m.Tools {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	out := make([]*Tool, 0, len(names))
+	for _, n := range names {
+		out = append(out, c.newTool(n, m.Tools[n], opts))
+	}
+	return out, nil
+}
+
+// LoadTool loads a single tool. Options apply to the tool in addition to the
+// options of the client.
+func (c *Client) LoadTool(ctx context.Context, name string, opts ...Option) (*Tool, error) {
+	m, err := c.getManifest(ctx, "/api/tool/"+url.PathEscape(name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load tool %q: %w", name, err)
+	}
+	tm, ok := m.Tools[name]
+	if !ok {
+		return nil, fmt.Errorf("unable to load tool %q: the server returned no manifest for it", name)
+	}
+	return c.newTool(name, tm, opts), nil
+}
+
+func (c *Client) newTool(name string, m ToolManifest, opts []Option) *Tool {
+	o := c.opts.clone()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Tool{client: c, name: name, manifest: m, opts: o}
+}
+
+func (c *Client) getManifest(ctx context.Context, path string) (ToolsetManifest, error) {
+	var m ToolsetManifest
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String()+path, nil)
+	if err != nil {
+		return m, err
+	}
+	body, err := c.do(req, c.opts)
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return m, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+	return m, nil
+}
+
+// do sends req with the headers and auth tokens of o, and returns the body of
+// a successful response.
+func (c *Client) do(req *http.Request, o *options) ([]byte, error) {
+	for k, v := range o.header {
+		req.Header[k] = v
+	}
+	for name, ts := range o.tokenSources {
+		token, err := ts(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("unable to get token for auth service %q: %w", name, err)
+		}
+		req.Header.Set(name+"_token", token)
+	}
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError(resp.StatusCode, body)
+	}
+	return body, nil
+}
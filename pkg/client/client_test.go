@@ -0,0 +1,262 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/pkg/client"
+)
+
+const manifest = `{
+	"serverVersion": "0.0.0",
+	"tools": {
+		"search_hotels": {
+			"description": "Search for hotels.",
+			"parameters": [
+				{"name": "location", "type": "string", "required": true, "description": "City of the hotels.", "authSources": []},
+				{"name": "stars", "type": "integer", "required": false, "description": "Minimum stars.", "authSources": []},
+				{"name": "user_id", "type": "string", "required": true, "description": "Id of the user.", "authSources": ["my-google-auth"]}
+			],
+			"authRequired": ["my-google-auth"]
+		},
+		"list_cities": {
+			"description": "List cities.",
+			"parameters": [],
+			"authRequired": []
+		}
+	}
+}`
+
+// fakeServer serves the manifest, and echoes the body and auth token of
+// invocations of search_hotels.
+func fakeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/toolset/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != "hotels" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":"Not Found","error":"toolset \"unknown\" does not exist"}`))
+			return
+		}
+		_, _ = w.Write([]byte(manifest))
+	})
+	mux.HandleFunc("GET /api/toolset/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(manifest))
+	})
+	mux.HandleFunc("GET /api/tool/search_hotels", func(w http.ResponseWriter, r *http.Request) {
+		var m client.ToolsetManifest
+		_ = json.Unmarshal([]byte(manifest), &m)
+		m.Tools = map[string]client.ToolManifest{"search_hotels": m.Tools["search_hotels"]}
+		_ = json.NewEncoder(w).Encode(m)
+	})
+	mux.HandleFunc("POST /api/tool/search_hotels/invoke", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("my-google-auth_token") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"status":"Unauthorized","error":"tool invocation not authorized","details":{"category":"auth","code":"UNAUTHORIZED","detail":"tool invocation not authorized","retryable":false}}`))
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		res, _ := json.Marshal([]any{map[string]any{"params": body, "token": r.Header.Get("my-google-auth_token"), "proxy": r.Header.Get("X-Proxy")}})
+		_ = json.NewEncoder(w).Encode(map[string]string{"result": string(res)})
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestLoadToolset(t *testing.T) {
+	ts := fakeServer(t)
+	c, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tools, err := c.LoadToolset(context.Background(), "hotels")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name())
+	}
+	if diff := cmp.Diff([]string{"list_cities", "search_hotels"}, names); diff != "" {
+		t.Fatalf("unexpected tools: diff %v", diff)
+	}
+	if got := tools[1].Description(); got != "Search for hotels." {
+		t.Fatalf("unexpected description: %q", got)
+	}
+
+	all, err := c.LoadToolset(context.Background(), "")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("unable to load the default toolset: %v, %d tools", err, len(all))
+	}
+
+	_, err = c.LoadToolset(context.Background(), "unknown")
+	var cerr *client.Error
+	if !errors.As(err, &cerr) || cerr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	ts := fakeServer(t)
+	c, err := client.New(ts.URL, client.WithHeader("X-Proxy", "secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool, err := c.LoadTool(context.Background(), "search_hotels", client.WithAuthToken("my-google-auth", "token-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	type params struct {
+		Location string `json:"location"`
+		Stars    int    `json:"stars,omitempty"`
+	}
+	var got []map[string]any
+	if err := tool.InvokeInto(context.Background(), params{Location: "Basel", Stars: 4}, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []map[string]any{{"params": map[string]any{"location": "Basel", "stars": float64(4)}, "token": "token-1", "proxy": "secret"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected result: diff %v", diff)
+	}
+
+	// tokens are fetched for every request
+	calls := 0
+	refreshing, err := c.LoadTool(context.Background(), "search_hotels", client.WithAuthTokenSource("my-google-auth", func(context.Context) (string, error) {
+		calls++
+		return "token-2", nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := refreshing.Invoke(context.Background(), map[string]any{"location": "Bern"}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected the token source to be called twice, got %d", calls)
+	}
+
+	// the server classifies failed invocations
+	unauthenticated, err := c.LoadTool(context.Background(), "search_hotels")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = unauthenticated.Invoke(context.Background(), map[string]any{"location": "Basel"})
+	var cerr *client.Error
+	if !errors.As(err, &cerr) || cerr.Code != "UNAUTHORIZED" || cerr.Category != "auth" || cerr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected an UNAUTHORIZED error, got %v", err)
+	}
+}
+
+func TestInvokeInvalidParams(t *testing.T) {
+	ts := fakeServer(t)
+	c, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool, err := c.LoadTool(context.Background(), "search_hotels")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc   string
+		params any
+		want   string
+	}{
+		{desc: "missing required parameter", params: map[string]any{"stars": 3}, want: `missing required parameter "location"`},
+		{desc: "unknown parameter", params: map[string]any{"location": "Basel", "city": "Basel"}, want: `unknown parameter "city"`},
+		{desc: "parameter filled from claims", params: map[string]any{"location": "Basel", "user_id": "alice"}, want: `"user_id" is filled from auth claims`},
+		{desc: "not an object", params: []string{"Basel"}, want: "must marshal to a JSON object"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tool.Invoke(context.Background(), tc.params)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestInputSchema(t *testing.T) {
+	ts := fakeServer(t)
+	c, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool, err := c.LoadTool(context.Background(), "search_hotels")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]any{"type": "string", "description": "City of the hotels."},
+			"stars":    map[string]any{"type": "integer", "description": "Minimum stars."},
+		},
+		"required": []string{"location"},
+	}
+	if diff := cmp.Diff(want, tool.InputSchema()); diff != "" {
+		t.Fatalf("unexpected schema: diff %v", diff)
+	}
+}
+
+func TestLangChainTool(t *testing.T) {
+	ts := fakeServer(t)
+	c, err := client.New(ts.URL, client.WithAuthToken("my-google-auth", "token-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tool, err := c.LoadTool(context.Background(), "search_hotels")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	lc := tool.LangChain()
+	if lc.Name() != "search_hotels" || !strings.Contains(lc.Description(), `"location"`) {
+		t.Fatalf("unexpected name or description: %q, %q", lc.Name(), lc.Description())
+	}
+
+	got, err := lc.Call(context.Background(), `{"location": "Basel"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(got, `"location":"Basel"`) {
+		t.Fatalf("unexpected result: %s", got)
+	}
+
+	// search_hotels has two parameters, so plain text is ambiguous
+	if _, err := lc.Call(context.Background(), "Basel"); err == nil {
+		t.Fatalf("expected plain text input to be rejected")
+	}
+}
+
+func TestNewInvalidURL(t *testing.T) {
+	if _, err := client.New("127.0.0.1:5000"); err == nil {
+		t.Fatalf("expected an error for a URL without a scheme")
+	}
+}
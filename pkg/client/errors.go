@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Error is an error returned by the server. Failed tool invocations are
+// classified, so callers can tell a request they should fix apart from one
+// they may retry.
+type Error struct {
+	StatusCode int
+	// Message is the error message of the server.
+	Message string
+	// Category and Code classify the error of a failed invocation, e.g.
+	// "validation" and "INVALID_PARAMETERS". They are empty for other errors.
+	Category string
+	Code     string
+	// Detail is a message about the failed invocation that is safe to show
+	// to an agent.
+	Detail string
+	// Retryable reports whether the same request may succeed later.
+	Retryable bool
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if e.Detail != "" {
+		msg = e.Detail
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("%s (%s): %s", http.StatusText(e.StatusCode), e.Code, msg)
+	}
+	return fmt.Sprintf("%s: %s", http.StatusText(e.StatusCode), msg)
+}
+
+func newError(statusCode int, body []byte) *Error {
+	e := &Error{StatusCode: statusCode}
+	var resp struct {
+		Error   string `json:"error"`
+		Details *struct {
+			Category  string `json:"category"`
+			Code      string `json:"code"`
+			Detail    string `json:"detail"`
+			Retryable bool   `json:"retryable"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		e.Message = string(body)
+		return e
+	}
+	e.Message = resp.Error
+	if d := resp.Details; d != nil {
+		e.Category, e.Code, e.Detail, e.Retryable = d.Category, d.Code, d.Detail, d.Retryable
+	}
+	return e
+}
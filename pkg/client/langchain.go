@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LangChainTool adapts a Tool to the tools.Tool interface of langchaingo,
+// which agents call with the text the model wrote as input.
+type LangChainTool struct {
+	tool *Tool
+}
+
+// LangChain returns the tool as a langchaingo tool.
+func (t *Tool) LangChain() LangChainTool {
+	return LangChainTool{tool: t}
+}
+
+// Name returns the name of the tool.
+func (l LangChainTool) Name() string {
+	return l.tool.Name()
+}
+
+// Description returns the description of the tool, followed by the JSON
+// Schema of its input, so the model knows how to write it.
+func (l LangChainTool) Description() string {
+	schema, err := json.Marshal(l.tool.InputSchema())
+	if err != nil {
+		return l.tool.Description()
+	}
+	return fmt.Sprintf("%s\nThe input must be a JSON object matching this schema: %s", l.tool.Description(), schema)
+}
+
+// Call invokes the tool with input, a JSON object of parameters, and returns
+// the result as JSON. A tool with a single parameter also accepts its value
+// as plain text.
+func (l LangChainTool) Call(ctx context.Context, input string) (string, error) {
+	input = strings.TrimSpace(input)
+	var params map[string]any
+	if err := json.Unmarshal([]byte(input), &params); err != nil || params == nil {
+		name, ok := l.singleParam()
+		if !ok {
+			return "", fmt.Errorf("input of tool %q must be a JSON object", l.tool.Name())
+		}
+		params = map[string]any{name: input}
+	}
+	res, err := l.tool.Invoke(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return string(res), nil
+}
+
+// singleParam returns the name of the only parameter callers send, if the
+// tool has exactly one, and it's a string.
+func (l LangChainTool) singleParam() (string, bool) {
+	var sent []ParameterManifest
+	for _, p := range l.tool.manifest.Parameters {
+		if len(p.AuthServices) == 0 {
+			sent = append(sent, p)
+		}
+	}
+	if len(sent) != 1 || sent[0].Type != "string" {
+		return "", false
+	}
+	return sent[0].Name, true
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+// ToolsetManifest is the manifest of a toolset, as served by the server.
+type ToolsetManifest struct {
+	ServerVersion string                  `json:"serverVersion"`
+	Tools         map[string]ToolManifest `json:"tools"`
+}
+
+// ToolManifest describes a tool and its parameters.
+type ToolManifest struct {
+	Description  string              `json:"description"`
+	Parameters   []ParameterManifest `json:"parameters"`
+	AuthRequired []string            `json:"authRequired"`
+}
+
+// ParameterManifest describes a parameter of a tool.
+type ParameterManifest struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+	// AuthServices are the auth services whose claims fill the parameter.
+	// The server fills these parameters, so callers don't send them.
+	AuthServices []string           `json:"authSources"`
+	Items        *ParameterManifest `json:"items,omitempty"`
+}
+
+// jsonSchemaTypes maps parameter types that aren't JSON Schema types to the
+// type of their JSON values.
+var jsonSchemaTypes = map[string]string{
+	"float":     "number",
+	"timestamp": "string",
+	"date":      "string",
+	"decimal":   "string",
+	"map":       "object",
+}
+
+// schema returns the JSON Schema of the parameter's values.
+func (p ParameterManifest) schema() map[string]any {
+	t := p.Type
+	if js, ok := jsonSchemaTypes[t]; ok {
+		t = js
+	}
+	s := map[string]any{"type": t, "description": p.Description}
+	switch p.Type {
+	case "timestamp":
+		s["format"] = "date-time"
+	case "date":
+		s["format"] = "date"
+	}
+	if p.Items != nil {
+		s["items"] = p.Items.schema()
+	}
+	return s
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// Tool is a tool of a Toolbox server.
+type Tool struct {
+	client   *Client
+	name     string
+	manifest ToolManifest
+	opts     *options
+}
+
+// Name returns the name of the tool.
+func (t *Tool) Name() string {
+	return t.name
+}
+
+// Description returns the description of the tool.
+func (t *Tool) Description() string {
+	return t.manifest.Description
+}
+
+// Manifest returns the manifest the tool was loaded with.
+func (t *Tool) Manifest() ToolManifest {
+	return t.manifest
+}
+
+// InputSchema returns the JSON Schema of the parameters callers send. Params
+// filled from auth claims are left out, as the server fills them.
+func (t *Tool) InputSchema() map[string]any {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	for _, p := range t.manifest.Parameters {
+		if len(p.AuthServices) > 0 {
+			continue
+		}
+		properties[p.Name] = p.schema()
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+// Invoke invokes the tool and returns its result as JSON. params is a
+// map[string]any, or any value that marshals to a JSON object, such as a
+// struct with json tags.
+func (t *Tool) Invoke(ctx context.Context, params any) (json.RawMessage, error) {
+	data, err := t.marshalParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for tool %q: %w", t.name, err)
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal parameters: %w", err)
+	}
+	u := t.client.baseURL.String() + "/api/tool/" + url.PathEscape(t.name) + "/invoke"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	body, err := t.client.do(req, t.opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to invoke tool %q: %w", t.name, err)
+	}
+	var resp struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("unable to parse response of tool %q: %w", t.name, err)
+	}
+	return json.RawMessage(resp.Result), nil
+}
+
+// InvokeInto invokes the tool and decodes its result into out, as
+// json.Unmarshal does.
+func (t *Tool) InvokeInto(ctx context.Context, params any, out any) error {
+	res, err := t.Invoke(ctx, params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(res, out); err != nil {
+		return fmt.Errorf("unable to decode result of tool %q: %w", t.name, err)
+	}
+	return nil
+}
+
+// Call invokes the tool with params, and returns its decoded result. Its
+// signature fits the tool functions of agent frameworks, such as Genkit.
+func (t *Tool) Call(ctx context.Context, params map[string]any) (any, error) {
+	var out any
+	if err := t.InvokeInto(ctx, params, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// marshalParams converts params to the JSON object sent to the server, and
+// checks it against the parameters of the tool.
+func (t *Tool) marshalParams(params any) (map[string]any, error) {
+	var data map[string]any
+	switch p := params.(type) {
+	case nil:
+		data = map[string]any{}
+	case map[string]any:
+		data = p
+	default:
+		b, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &data); err != nil || data == nil {
+			return nil, fmt.Errorf("parameters must marshal to a JSON object")
+		}
+	}
+
+	for name := range data {
+		i := slices.IndexFunc(t.manifest.Parameters, func(p ParameterManifest) bool { return p.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+		if len(t.manifest.Parameters[i].AuthServices) > 0 {
+			return nil, fmt.Errorf("parameter %q is filled from auth claims and can't be sent", name)
+		}
+	}
+	for _, p := range t.manifest.Parameters {
+		if _, ok := data[p.Name]; !ok && p.Required && len(p.AuthServices) == 0 {
+			return nil, fmt.Errorf("missing required parameter %q", p.Name)
+		}
+	}
+	return data, nil
+}
@@ -379,7 +379,7 @@ func runAINLMCPToolCallMethod(t *testing.T) {
 					"arguments": map[string]any{},
 				},
 			},
-			want: `{"jsonrpc":"2.0","id":"invalid-tool","error":{"code":-32602,"message":"invalid tool name: tool with name \"foo\" does not exist"}}`,
+			want: `{"jsonrpc":"2.0","id":"invalid-tool","error":{"code":-32602,"message":"invalid tool name: tool with name \"foo\" does not exist","data":{"category":"validation","code":"TOOL_NOT_FOUND","detail":"invalid tool name: tool with name \"foo\" does not exist","retryable":false}}}`,
 		},
 		{
 			name:          "MCP Invoke my-auth-tool without parameters",
@@ -396,7 +396,7 @@ func runAINLMCPToolCallMethod(t *testing.T) {
 					"arguments": map[string]any{},
 				},
 			},
-			want: `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter question is required"}}`,
+			want: `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter question is required","data":{"category":"validation","code":"INVALID_PARAMETERS","detail":"provided parameters were invalid: parameter question is required","retryable":false}}}`,
 		},
 	}
 	for _, tc := range invokeTcs {
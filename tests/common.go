@@ -410,7 +410,7 @@ func GetNonSpannerInvokeParamWant() (string, string, string, string) {
 // GetPostgresWants return the expected wants for postgres
 func GetPostgresWants() (string, string, string) {
 	select1Want := "[{\"?column?\":1}]"
-	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"content":[{"type":"text","text":"unable to execute query: ERROR: syntax error at or near \"SELEC\" (SQLSTATE 42601)"}],"isError":true}}`
+	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"_meta":{"toolbox/error":{"category":"query-error","code":"QUERY_FAILED","detail":"unable to execute query: ERROR: syntax error at or near \"SELEC\" (SQLSTATE 42601)","retryable":false}},"content":[{"type":"text","text":"unable to execute query: ERROR: syntax error at or near \"SELEC\" (SQLSTATE 42601)"}],"isError":true}}`
 	createTableStatement := `"CREATE TABLE t (id SERIAL PRIMARY KEY, name TEXT)"`
 	return select1Want, failInvocationWant, createTableStatement
 }
@@ -418,7 +418,7 @@ func GetPostgresWants() (string, string, string) {
 // GetMSSQLWants return the expected wants for mssql
 func GetMSSQLWants() (string, string, string) {
 	select1Want := "[{\"\":1}]"
-	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"content":[{"type":"text","text":"unable to execute query: mssql: Could not find stored procedure 'SELEC'."}],"isError":true}}`
+	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"_meta":{"toolbox/error":{"category":"query-error","code":"QUERY_FAILED","detail":"unable to execute query: mssql: Could not find stored procedure 'SELEC'.","retryable":false}},"content":[{"type":"text","text":"unable to execute query: mssql: Could not find stored procedure 'SELEC'."}],"isError":true}}`
 	createTableStatement := `"CREATE TABLE t (id INT IDENTITY(1,1) PRIMARY KEY, name NVARCHAR(MAX))"`
 	return select1Want, failInvocationWant, createTableStatement
 }
@@ -426,7 +426,7 @@ func GetMSSQLWants() (string, string, string) {
 // GetMySQLWants return the expected wants for mysql
 func GetMySQLWants() (string, string, string) {
 	select1Want := "[{\"1\":1}]"
-	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"content":[{"type":"text","text":"unable to execute query: Error 1064 (42000): You have an error in your SQL syntax; check the manual that corresponds to your MySQL server version for the right syntax to use near 'SELEC 1' at line 1"}],"isError":true}}`
+	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"_meta":{"toolbox/error":{"category":"query-error","code":"QUERY_FAILED","detail":"unable to execute query: Error 1064 (42000): You have an error in your SQL syntax; check the manual that corresponds to your MySQL server version for the right syntax to use near 'SELEC 1' at line 1","retryable":false}},"content":[{"type":"text","text":"unable to execute query: Error 1064 (42000): You have an error in your SQL syntax; check the manual that corresponds to your MySQL server version for the right syntax to use near 'SELEC 1' at line 1"}],"isError":true}}`
 	createTableStatement := `"CREATE TABLE t (id SERIAL PRIMARY KEY, name TEXT)"`
 	return select1Want, failInvocationWant, createTableStatement
 }
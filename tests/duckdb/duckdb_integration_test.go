@@ -146,7 +146,7 @@ func GetDuckDbAuthToolInfo(tableName string) (string, string, string, []any) {
 
 func GetDuckDbWants() (string, string, string) {
 	select1Want := "[{\"1\":1}]"
-	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"content":[{"type":"text","text":"unable to execute query: Parser Error: syntax error at or near \"SELEC\""}],"isError":true}}`
+	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"_meta":{"toolbox/error":{"category":"query-error","code":"QUERY_FAILED","detail":"unable to execute query: Parser Error: syntax error at or near \"SELEC\"","retryable":false}},"content":[{"type":"text","text":"unable to execute query: Parser Error: syntax error at or near \"SELEC\""}],"isError":true}}`
 	createTableStatement := `"CREATE TABLE t (id SERIAL PRIMARY KEY, name TEXT)"`
 	return select1Want, failInvocationWant, createTableStatement
 }
@@ -156,7 +156,7 @@ func TestSQLiteToolEndpoint(t *testing.T) {
 	tests.RunToolGetTest(t)
 
 	select1Want := "[{\"1\":1}]"
-	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"content":[{"type":"text","text":"unable to execute query: SQL logic error: near \"SELEC\": syntax error (1)"}],"isError":true}}`
+	failInvocationWant := `{"jsonrpc":"2.0","id":"invoke-fail-tool","result":{"_meta":{"toolbox/error":{"category":"query-error","code":"QUERY_FAILED","detail":"unable to execute query: SQL logic error: near \"SELEC\": syntax error (1)","retryable":false}},"content":[{"type":"text","text":"unable to execute query: SQL logic error: near \"SELEC\": syntax error (1)"}],"isError":true}}`
 	invokeParamWant, invokeIdNullWant, nullWant, mcpInvokeParamWant := tests.GetNonSpannerInvokeParamWant()
 	tests.RunToolInvokeTest(t, select1Want, invokeParamWant, invokeIdNullWant, nullWant, true, false)
 	tests.RunMCPToolCallMethod(t, mcpInvokeParamWant, failInvocationWant)
@@ -847,7 +847,7 @@ func RunMCPToolCallMethod(t *testing.T, invokeParamWant, failInvocationWant stri
 					"arguments": map[string]any{},
 				},
 			},
-			want: `{"jsonrpc":"2.0","id":"invalid-tool","error":{"code":-32602,"message":"invalid tool name: tool with name \"foo\" does not exist"}}`,
+			want: `{"jsonrpc":"2.0","id":"invalid-tool","error":{"code":-32602,"message":"invalid tool name: tool with name \"foo\" does not exist","data":{"category":"validation","code":"TOOL_NOT_FOUND","detail":"invalid tool name: tool with name \"foo\" does not exist","retryable":false}}}`,
 		},
 		{
 			name:          "MCP Invoke my-tool without parameters",
@@ -864,7 +864,7 @@ func RunMCPToolCallMethod(t *testing.T, invokeParamWant, failInvocationWant stri
 					"arguments": map[string]any{},
 				},
 			},
-			want: `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"id\" is required"}}`,
+			want: `{"jsonrpc":"2.0","id":"invoke-without-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"id\" is required","data":{"category":"validation","code":"INVALID_PARAMETERS","detail":"provided parameters were invalid: parameter \"id\" is required","retryable":false}}}`,
 		},
 		{
 			name:          "MCP Invoke my-tool with insufficient parameters",
@@ -881,7 +881,7 @@ func RunMCPToolCallMethod(t *testing.T, invokeParamWant, failInvocationWant stri
 					"arguments": map[string]any{"id": 1},
 				},
 			},
-			want: `{"jsonrpc":"2.0","id":"invoke-insufficient-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"name\" is required"}}`,
+			want: `{"jsonrpc":"2.0","id":"invoke-insufficient-parameter","error":{"code":-32602,"message":"provided parameters were invalid: parameter \"name\" is required","data":{"category":"validation","code":"INVALID_PARAMETERS","detail":"provided parameters were invalid: parameter \"name\" is required","retryable":false}}}`,
 		},
 		{
 			name:          "MCP Invoke my-auth-required-tool",
@@ -898,7 +898,7 @@ func RunMCPToolCallMethod(t *testing.T, invokeParamWant, failInvocationWant stri
 					"arguments": map[string]any{},
 				},
 			},
-			want: "{\"jsonrpc\":\"2.0\",\"id\":\"invoke my-auth-required-tool\",\"error\":{\"code\":-32600,\"message\":\"unauthorized Tool call: `authRequired` is set for the target Tool\"}}",
+			want: "{\"jsonrpc\":\"2.0\",\"id\":\"invoke my-auth-required-tool\",\"error\":{\"code\":-32600,\"message\":\"unauthorized Tool call: `authRequired` is set for the target Tool\",\"data\":{\"category\":\"auth\",\"code\":\"UNAUTHORIZED\",\"detail\":\"unauthorized Tool call: `authRequired` is set for the target Tool\",\"retryable\":false}}}",
 		},
 		{
 			name:          "MCP Invoke my-fail-tool",